@@ -160,7 +160,18 @@ func ApplyBaseTypes(types map[string]*GoType, config *AugmentConfig) {
 		return
 	}
 
-	for baseName, baseCfg := range config.BaseTypes {
+	// Sort base type names for deterministic processing order. This matters
+	// when a type is the source of more than one base type: the embeds are
+	// prepended in processing order, and fields extracted by one base type
+	// are no longer available to the next.
+	baseNames := make([]string, 0, len(config.BaseTypes))
+	for baseName := range config.BaseTypes {
+		baseNames = append(baseNames, baseName)
+	}
+	sort.Strings(baseNames)
+
+	for _, baseName := range baseNames {
+		baseCfg := config.BaseTypes[baseName]
 		if len(baseCfg.Sources) == 0 || len(baseCfg.Fields) == 0 {
 			continue
 		}
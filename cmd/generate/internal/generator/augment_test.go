@@ -3,6 +3,7 @@ package generator
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 )
 
@@ -109,3 +110,45 @@ func TestLoadAndApplyAugmentations(t *testing.T) {
 		t.Errorf("ref = %q, want NewName", types["Referrer"].Fields[0].Type.Named)
 	}
 }
+
+func TestApplyBaseTypesSharedSource(t *testing.T) {
+	// Shared is a source of both base types, so the result depends on the
+	// order in which the base types are applied.
+	config := &AugmentConfig{
+		BaseTypes: map[string]AugmentBaseType{
+			"Beta":  {Sources: []string{"Shared"}, Fields: []string{"b"}},
+			"Alpha": {Sources: []string{"Shared"}, Fields: []string{"a"}},
+			"Gamma": {Sources: []string{"Shared"}, Fields: []string{"c"}},
+		},
+	}
+
+	apply := func() []string {
+		types := map[string]*GoType{
+			"Shared": {
+				Name: "Shared",
+				Kind: GoTypeStruct,
+				Fields: []GoField{
+					{Name: "A", JSONName: "a", Type: GoTypeRef{Builtin: "string"}},
+					{Name: "B", JSONName: "b", Type: GoTypeRef{Builtin: "string"}},
+					{Name: "C", JSONName: "c", Type: GoTypeRef{Builtin: "string"}},
+					{Name: "D", JSONName: "d", Type: GoTypeRef{Builtin: "string"}},
+				},
+			},
+		}
+		ApplyBaseTypes(types, config)
+
+		var names []string
+		for _, f := range types["Shared"].Fields {
+			names = append(names, f.Name)
+		}
+		return names
+	}
+
+	// Base types are applied in name order, each prepending its embed.
+	want := []string{"Gamma", "Beta", "Alpha", "D"}
+	for range 20 {
+		if got := apply(); !slices.Equal(got, want) {
+			t.Fatalf("Shared fields = %v, want %v", got, want)
+		}
+	}
+}
@@ -1,11 +1,15 @@
 package generator
 
 import (
+	"bytes"
 	"fmt"
+	"go/format"
+	"io"
 	"os"
 	"path/filepath"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 
 	. "github.com/dave/jennifer/jen"
@@ -24,6 +28,7 @@ type Emitter struct {
 	pkgName     string
 	outputDir   string
 	specVersion string
+	schemaIDs   map[string]string // type name → source schema $id
 }
 
 // NewEmitter creates an Emitter targeting the given package name and directory.
@@ -35,12 +40,54 @@ func NewEmitter(pkgName, outputDir, specVersion string) *Emitter {
 	}
 }
 
-// Emit generates all Go source files for the given types.
+// SetSchemaIDs sets the type name to schema $id mapping emitted as the
+// SchemaIDs variable in version.go.
+func (e *Emitter) SetSchemaIDs(ids map[string]string) {
+	e.schemaIDs = ids
+}
+
+// Emit generates all Go source files for the given types and writes them
+// to the output directory.
 func (e *Emitter) Emit(types []*GoType) error {
-	if err := os.MkdirAll(e.outputDir, 0o755); err != nil {
-		return fmt.Errorf("creating output directory: %w", err)
+	files, err := e.Render(types)
+	if err != nil {
+		return err
+	}
+	return writeFiles(e.outputDir, files)
+}
+
+// Render generates all Go source files for the given types, including
+// metadata.go and version.go. It returns the gofmt-formatted contents
+// keyed by file name without writing anything to disk.
+func (e *Emitter) Render(types []*GoType) (map[string][]byte, error) {
+	files, err := e.renderTypes(types)
+	if err != nil {
+		return nil, err
 	}
 
+	// Always emit metadata.go.
+	if files["metadata.go"], err = renderFile(e.metadataFile()); err != nil {
+		return nil, fmt.Errorf("emitting metadata.go: %w", err)
+	}
+
+	// Always emit version.go.
+	if files["version.go"], err = renderFile(e.versionFile()); err != nil {
+		return nil, fmt.Errorf("emitting version.go: %w", err)
+	}
+
+	return files, nil
+}
+
+// RenderTypes renders the given types into gofmt-formatted Go source for
+// package pkgName, grouped by each type's OutputFile. Types without an
+// OutputFile are placed in types.go. The returned map is keyed by file
+// name and never touches disk, which makes it suitable for tests.
+func RenderTypes(types []*GoType, pkgName string) (map[string][]byte, error) {
+	return NewEmitter(pkgName, "", "").renderTypes(types)
+}
+
+// renderTypes groups types by output file and renders each file.
+func (e *Emitter) renderTypes(types []*GoType) (map[string][]byte, error) {
 	// Group types by output file, skipping excluded types.
 	fileTypes := make(map[string][]*GoType)
 	for _, t := range types {
@@ -61,28 +108,65 @@ func (e *Emitter) Emit(types []*GoType) error {
 		})
 	}
 
-	// Emit each file.
+	// Render each file.
+	files := make(map[string][]byte, len(fileTypes)+2)
 	for filename, tt := range fileTypes {
-		if err := e.emitFile(filename, tt); err != nil {
-			return fmt.Errorf("emitting %s: %w", filename, err)
+		src, err := renderFile(e.typesFile(tt))
+		if err != nil {
+			return nil, fmt.Errorf("emitting %s: %w", filename, err)
 		}
+		files[filename] = src
 	}
+	return files, nil
+}
 
-	// Always emit metadata.go.
-	if err := e.emitMetadata(); err != nil {
-		return fmt.Errorf("emitting metadata.go: %w", err)
+// renderFile renders f and returns the gofmt-formatted source. Output that
+// cannot be formatted is reported as an error rather than returned.
+func renderFile(f *File) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := writeFormatted(&buf, f); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeFormatted renders f, runs it through go/format, and writes the
+// result to w.
+func writeFormatted(w io.Writer, f *File) error {
+	var buf bytes.Buffer
+	if err := f.Render(&buf); err != nil {
+		return fmt.Errorf("rendering source: %w", err)
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("formatting source: %w", err)
 	}
+	_, err = w.Write(src)
+	return err
+}
 
-	// Always emit version.go.
-	if err := e.emitVersion(); err != nil {
-		return fmt.Errorf("emitting version.go: %w", err)
+// writeFiles writes each rendered file into dir, creating dir if needed.
+func writeFiles(dir string, files map[string][]byte) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("creating output directory: %w", err)
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
 	}
+	sort.Strings(names)
 
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(dir, name), files[name], 0o644); err != nil {
+			return fmt.Errorf("writing %s: %w", name, err)
+		}
+	}
 	return nil
 }
 
-// emitFile generates a single Go source file containing the given types.
-func (e *Emitter) emitFile(filename string, types []*GoType) error {
+// typesFile builds a Go source file containing the given types.
+func (e *Emitter) typesFile(types []*GoType) *File {
 	f := NewFile(e.pkgName)
 	f.HeaderComment("Code generated by cmd/generate; DO NOT EDIT.")
 
@@ -95,8 +179,7 @@ func (e *Emitter) emitFile(filename string, types []*GoType) error {
 		}
 	}
 
-	path := filepath.Join(e.outputDir, filename)
-	return f.Save(path)
+	return f
 }
 
 // emitStruct generates a struct type declaration with fields and UnmarshalYAML.
@@ -137,7 +220,9 @@ func (e *Emitter) emitStruct(f *File, goType *GoType) {
 		e.emitUnmarshalYAML(f, goType)
 	}
 
-	// Generate MarshalJSON for types with additional properties.
+	// Generate MarshalJSON for types with additional properties. No
+	// MarshalYAML is needed because yaml.v3 already encodes the ",inline"
+	// AdditionalProperties map alongside the declared fields.
 	if goType.HasAdditionalProperties {
 		e.emitMarshalJSON(f, goType)
 	}
@@ -348,30 +433,47 @@ func (e *Emitter) emitMarshalJSON(f *File, goType *GoType) {
 	f.Line()
 }
 
-// emitEnum generates a string type and const block for enum values.
+// emitEnum generates a string or int type and const block for enum values.
 func (e *Emitter) emitEnum(f *File, goType *GoType) {
 	if goType.Doc != "" {
 		for _, line := range wrapComment(formatDocComment(goType.Name, goType.Doc), 100) {
 			f.Comment(line)
 		}
 	}
-	f.Type().Id(goType.Name).String()
+	if goType.EnumBase == "int" {
+		f.Type().Id(goType.Name).Int()
+	} else {
+		f.Type().Id(goType.Name).String()
+	}
 	f.Line()
 
 	if len(goType.EnumValues) > 0 {
 		f.Comment(fmt.Sprintf("Enum values for %s.", goType.Name))
 		consts := make([]Code, len(goType.EnumValues))
 		for i, ev := range goType.EnumValues {
-			consts[i] = Id(ev.GoName).Id(goType.Name).Op("=").Lit(ev.Value)
+			consts[i] = Id(ev.GoName).Id(goType.Name).Op("=").Add(enumLit(goType, ev))
 		}
 		f.Const().Defs(consts...)
 		f.Line()
 	}
 }
 
-// emitMetadata generates the metadata.go file with FileMetadata and
+// enumLit returns the literal for an enum constant. Integer enum values
+// are validated by the type mapper, so a parse failure falls back to the
+// raw text.
+func enumLit(goType *GoType, ev GoEnumVal) Code {
+	if goType.EnumBase == "int" {
+		if n, err := strconv.Atoi(ev.Value); err == nil {
+			return Lit(n)
+		}
+		return Op(ev.Value)
+	}
+	return Lit(ev.Value)
+}
+
+// metadataFile builds the metadata.go file with FileMetadata and
 // annotateFileMetadata.
-func (e *Emitter) emitMetadata() error {
+func (e *Emitter) metadataFile() *File {
 	f := NewFile(e.pkgName)
 	f.HeaderComment("Code generated by cmd/generate; DO NOT EDIT.")
 
@@ -474,12 +576,12 @@ func (e *Emitter) emitMetadata() error {
 	)
 	f.Line()
 
-	path := filepath.Join(e.outputDir, "metadata.go")
-	return f.Save(path)
+	return f
 }
 
-// emitVersion generates the version.go file with a SpecVersion constant.
-func (e *Emitter) emitVersion() error {
+// versionFile builds the version.go file with a SpecVersion constant and a
+// SchemaIDs map recording the source schema of each generated type.
+func (e *Emitter) versionFile() *File {
 	f := NewFile(e.pkgName)
 	f.HeaderComment("Code generated by cmd/generate; DO NOT EDIT.")
 
@@ -487,8 +589,18 @@ func (e *Emitter) emitVersion() error {
 	f.Const().Id("SpecVersion").Op("=").Lit(e.specVersion)
 	f.Line()
 
-	path := filepath.Join(e.outputDir, "version.go")
-	return f.Save(path)
+	if len(e.schemaIDs) > 0 {
+		ids := Dict{}
+		for name, id := range e.schemaIDs {
+			ids[Lit(name)] = Lit(id)
+		}
+		f.Comment("SchemaIDs maps each generated type name to the $id of the JSON schema")
+		f.Comment("it was generated from (or the schema file path when it has no $id).")
+		f.Var().Id("SchemaIDs").Op("=").Map(String()).String().Values(ids)
+		f.Line()
+	}
+
+	return f
 }
 
 var mdLinkRe = regexp.MustCompile(`\[([^\]]+)\]\(([^)]+)\)`)
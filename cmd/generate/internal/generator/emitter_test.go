@@ -0,0 +1,157 @@
+package generator
+
+import (
+	"go/format"
+	"strings"
+	"testing"
+)
+
+func TestRenderTypes(t *testing.T) {
+	types := []*GoType{
+		{
+			Name:       "Widget",
+			Doc:        "A widget.",
+			Kind:       GoTypeStruct,
+			OutputFile: "widget.go",
+			EmbedMeta:  true,
+			Fields: []GoField{
+				{Name: "Name", JSONName: "name", Type: GoTypeRef{Builtin: "string"}, Required: true},
+				{Name: "Size", JSONName: "size", Type: GoTypeRef{Builtin: "int"}},
+				{Name: "Color", JSONName: "color", Type: GoTypeRef{Named: "Color"}},
+			},
+		},
+		{
+			Name:       "Color",
+			Kind:       GoTypeEnum,
+			OutputFile: "widget.go",
+			EnumValues: []GoEnumVal{
+				{GoName: "ColorRed", Value: "red"},
+				{GoName: "ColorBlue", Value: "blue"},
+			},
+		},
+		{
+			Name: "Gadget",
+			Kind: GoTypeStruct,
+			Fields: []GoField{
+				{Name: "ID", JSONName: "id", Type: GoTypeRef{Builtin: "string"}},
+			},
+		},
+		{
+			Name:       "Hidden",
+			Kind:       GoTypeStruct,
+			OutputFile: ExcludeFile,
+		},
+	}
+
+	files, err := RenderTypes(types, "example")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(files) != 2 {
+		names := make([]string, 0, len(files))
+		for name := range files {
+			names = append(names, name)
+		}
+		t.Fatalf("got files %v, want widget.go and types.go", names)
+	}
+
+	widget := string(files["widget.go"])
+	for _, want := range []string{
+		"// Code generated by cmd/generate; DO NOT EDIT.",
+		"package example",
+		"// Widget A widget.",
+		"type Widget struct {",
+		"\tFileMetadata `json:\"-\" yaml:\"-\"`",
+		"\tName         string `json:\"name\" yaml:\"name\"`",
+		"\tSize         int    `json:\"size,omitempty\" yaml:\"size,omitempty\"`",
+		"func (v *Widget) UnmarshalYAML(node *yamlv3.Node) error {",
+		"\tColorRed  Color = \"red\"",
+	} {
+		if !strings.Contains(widget, want) {
+			t.Errorf("widget.go missing %q\n%s", want, widget)
+		}
+	}
+
+	// Types are sorted by name within a file.
+	if strings.Index(widget, "type Color string") > strings.Index(widget, "type Widget struct") {
+		t.Error("expected Color to be emitted before Widget")
+	}
+
+	if !strings.Contains(string(files["types.go"]), "type Gadget struct {") {
+		t.Errorf("types.go missing Gadget:\n%s", files["types.go"])
+	}
+
+	// Output must already be gofmt-formatted.
+	for name, src := range files {
+		formatted, err := format.Source(src)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if string(formatted) != string(src) {
+			t.Errorf("%s is not gofmt-formatted", name)
+		}
+	}
+}
+
+func TestRenderTypesAdditionalProperties(t *testing.T) {
+	types := []*GoType{
+		{
+			Name:                    "Rule",
+			Kind:                    GoTypeStruct,
+			OutputFile:              "rule.go",
+			EmbedMeta:               true,
+			HasAdditionalProperties: true,
+			Fields: []GoField{
+				{Name: "Name", JSONName: "name", Type: GoTypeRef{Builtin: "string"}, Required: true},
+				{
+					Name:     "AdditionalProperties",
+					JSONName: "-",
+					Type: GoTypeRef{
+						Map:      true,
+						MapKey:   &GoTypeRef{Builtin: "string"},
+						MapValue: &GoTypeRef{Builtin: "any"},
+					},
+					JSONTag: "-",
+					YAMLTag: ",inline",
+				},
+			},
+		},
+	}
+
+	files, err := RenderTypes(types, "example")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rule := string(files["rule.go"])
+	for _, want := range []string{
+		"AdditionalProperties map[string]any `json:\"-\" yaml:\",inline\"`",
+		"func (v Rule) MarshalJSON() ([]byte, error) {",
+		"for k, val := range v.AdditionalProperties {",
+	} {
+		if !strings.Contains(rule, want) {
+			t.Errorf("rule.go missing %q\n%s", want, rule)
+		}
+	}
+
+	// YAML marshaling relies on the inline tag.
+	if strings.Contains(rule, "MarshalYAML") {
+		t.Errorf("rule.go unexpectedly defines MarshalYAML\n%s", rule)
+	}
+}
+
+func TestEmitterRender(t *testing.T) {
+	e := NewEmitter("example", "", "1.2.3")
+	files, err := e.Render(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(files["version.go"]), `const SpecVersion = "1.2.3"`) {
+		t.Errorf("version.go missing SpecVersion:\n%s", files["version.go"])
+	}
+	if !strings.Contains(string(files["metadata.go"]), "type FileMetadata struct {") {
+		t.Errorf("metadata.go missing FileMetadata:\n%s", files["metadata.go"])
+	}
+}
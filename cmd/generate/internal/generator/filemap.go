@@ -2,7 +2,10 @@ package generator
 
 import (
 	"fmt"
+	"go/build"
+	"io"
 	"os"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -15,6 +18,9 @@ const ExcludeFile = "_"
 type FileMap struct {
 	Files   map[string][]string `yaml:"files"`   // filename → list of type names
 	Exclude []string            `yaml:"exclude"` // type names to exclude from generation
+	// SplitByType writes each type to its own <snake_name>.go file instead of
+	// using Files. Excluded types are still skipped.
+	SplitByType bool `yaml:"split_by_type"`
 	// Reverse lookup: type name → filename.
 	lookup map[string]string
 }
@@ -61,9 +67,109 @@ func (fm *FileMap) OutputFileFor(typeName, defaultFile string) string {
 }
 
 // AssignOutputFiles sets the OutputFile field on each GoType based on
-// the file map. Types not in the map get assigned to "types.go".
+// the file map. Types not in the map get assigned to "types.go". When
+// SplitByType is set, every type gets its own file instead.
 func (fm *FileMap) AssignOutputFiles(types map[string]*GoType) {
+	if fm.SplitByType {
+		fm.assignFilePerType(types)
+		return
+	}
 	for _, goType := range types {
 		goType.OutputFile = fm.OutputFileFor(goType.Name, "types.go")
 	}
 }
+
+// assignFilePerType places each type in <snake_name>.go. An enum used by
+// exactly one struct is kept in that struct's file since the two are only
+// ever read together. A name that the go tool would treat specially or
+// that belongs to the emitter gets a "_type" suffix (see typeFileName).
+func (fm *FileMap) assignFilePerType(types map[string]*GoType) {
+	// Count the structs that reference each enum.
+	owners := make(map[string][]string) // enum name → referencing struct names
+	for _, t := range types {
+		if t.Kind != GoTypeStruct || fm.OutputFileFor(t.Name, "") == ExcludeFile {
+			continue
+		}
+		seen := make(map[string]bool)
+		for _, f := range t.Fields {
+			for _, name := range namedRefs(f.Type) {
+				if ref, ok := types[name]; ok && ref.Kind == GoTypeEnum && !seen[name] {
+					seen[name] = true
+					owners[name] = append(owners[name], t.Name)
+				}
+			}
+		}
+	}
+
+	for _, t := range types {
+		if fm.OutputFileFor(t.Name, "") == ExcludeFile {
+			t.OutputFile = ExcludeFile
+			continue
+		}
+		name := t.Name
+		if t.Kind == GoTypeEnum && len(owners[name]) == 1 {
+			name = owners[name][0]
+		}
+		t.OutputFile = typeFileName(name)
+	}
+}
+
+// emitterFiles are the files that the Emitter always writes. A type file
+// with one of these names would be overwritten.
+var emitterFiles = map[string]bool{
+	"metadata.go": true,
+	"version.go":  true,
+}
+
+// typeFileName returns the file name for a type in split-by-type mode. The
+// snake_case name gets a "_type" suffix when it would otherwise be a test
+// file (FooTest), carry a GOOS or GOARCH build constraint (FooWindows,
+// FooArm64), or replace one of the emitterFiles (Metadata, Version).
+func typeFileName(typeName string) string {
+	base := snakeCase(typeName)
+	if emitterFiles[base+".go"] || strings.HasSuffix(base, "_test") || isConstrainedFile(base+".go") {
+		base += "_type"
+	}
+	return base + ".go"
+}
+
+// isConstrainedFile reports whether the go tool applies a GOOS or GOARCH
+// build constraint to a file based on its name (e.g. foo_windows.go or
+// foo_linux_arm64.go). It asks go/build whether the file matches a context
+// for a nonexistent OS and architecture, supplying empty file contents so
+// that only the name is considered.
+func isConstrainedFile(name string) bool {
+	ctxt := build.Default
+	ctxt.GOOS, ctxt.GOARCH = "none", "none"
+	ctxt.OpenFile = func(string) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader("package p\n")), nil
+	}
+	ok, err := ctxt.MatchFile(".", name)
+	return err != nil || !ok
+}
+
+// namedRefs returns the names of all generated types referenced by ref,
+// including those nested in slice elements and map values.
+func namedRefs(ref GoTypeRef) []string {
+	var names []string
+	if ref.Named != "" {
+		names = append(names, ref.Named)
+	}
+	if ref.Element != nil {
+		names = append(names, namedRefs(*ref.Element)...)
+	}
+	if ref.MapValue != nil {
+		names = append(names, namedRefs(*ref.MapValue)...)
+	}
+	return names
+}
+
+// snakeCase converts a Go identifier (e.g. "ECSFieldDefinition") into
+// snake_case (e.g. "ecs_field_definition").
+func snakeCase(name string) string {
+	words := splitWords(name)
+	for i, w := range words {
+		words[i] = strings.ToLower(w)
+	}
+	return strings.Join(words, "_")
+}
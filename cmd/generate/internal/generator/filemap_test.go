@@ -0,0 +1,93 @@
+package generator
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestAssignOutputFilesSplitByType(t *testing.T) {
+	types := map[string]*GoType{
+		"IntegrationManifest": {
+			Name: "IntegrationManifest",
+			Kind: GoTypeStruct,
+			Fields: []GoField{
+				{Name: "Type", Type: GoTypeRef{Named: "IntegrationManifestType"}},
+				{Name: "Owner", Type: GoTypeRef{Named: "Owner", Pointer: true}},
+				{Name: "Categories", Type: GoTypeRef{Slice: true, Element: &GoTypeRef{Named: "Category"}}},
+			},
+		},
+		"IntegrationManifestType": {Name: "IntegrationManifestType", Kind: GoTypeEnum},
+		"Owner": {
+			Name: "Owner",
+			Kind: GoTypeStruct,
+			Fields: []GoField{
+				{Name: "Type", Type: GoTypeRef{Named: "OwnerType"}},
+			},
+		},
+		"OwnerType": {Name: "OwnerType", Kind: GoTypeEnum},
+		"InputManifest": {
+			Name: "InputManifest",
+			Kind: GoTypeStruct,
+			Fields: []GoField{
+				{Name: "Categories", Type: GoTypeRef{Slice: true, Element: &GoTypeRef{Named: "Category"}}},
+			},
+		},
+		// Shared by two structs, so it gets its own file.
+		"Category":  {Name: "Category", Kind: GoTypeEnum},
+		"ECSField":  {Name: "ECSField", Kind: GoTypeStruct},
+		"Processor": {Name: "Processor", Kind: GoTypeStruct},
+	}
+
+	fm := &FileMap{
+		Files:       map[string][]string{"manifest.go": {"IntegrationManifest"}},
+		Exclude:     []string{"Processor"},
+		SplitByType: true,
+	}
+	fm.buildLookup()
+	fm.AssignOutputFiles(types)
+
+	got := make(map[string]string, len(types))
+	for name, typ := range types {
+		got[name] = typ.OutputFile
+	}
+	want := map[string]string{
+		"IntegrationManifest":     "integration_manifest.go",
+		"IntegrationManifestType": "integration_manifest.go",
+		"Owner":                   "owner.go",
+		"OwnerType":               "owner.go",
+		"InputManifest":           "input_manifest.go",
+		"Category":                "category.go",
+		"ECSField":                "ecs_field.go",
+		"Processor":               ExcludeFile,
+	}
+	if !maps.Equal(got, want) {
+		for _, name := range slices.Sorted(maps.Keys(want)) {
+			if got[name] != want[name] {
+				t.Errorf("%s: got %q, want %q", name, got[name], want[name])
+			}
+		}
+	}
+}
+
+func TestTypeFileName(t *testing.T) {
+	tests := map[string]string{
+		"Owner":            "owner.go",
+		"Windows":          "windows.go", // a lone GOOS name is not a constraint
+		"FooUnix":          "foo_unix.go",
+		"FooTest":          "foo_test_type.go",
+		"FooWindows":       "foo_windows_type.go",
+		"FooArm64":         "foo_arm64_type.go",
+		"FooLinuxAmd64":    "foo_linux_amd64_type.go",
+		"Metadata":         "metadata_type.go",
+		"Version":          "version_type.go",
+		"ManifestVersion":  "manifest_version.go",
+		"TestConfig":       "test_config.go",
+		"SystemTestConfig": "system_test_config.go",
+	}
+	for name, want := range tests {
+		if got := typeFileName(name); got != want {
+			t.Errorf("typeFileName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
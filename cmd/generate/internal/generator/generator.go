@@ -2,6 +2,9 @@ package generator
 
 import (
 	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
 	"strings"
 )
 
@@ -90,12 +93,7 @@ func Run(cfg Config) error {
 
 	// 5. Auto-detect spec version from schema $id if not provided.
 	if cfg.SpecVersion == "" {
-		for _, s := range registry.schemas {
-			if v := extractSpecVersion(s.ID); v != "" {
-				cfg.SpecVersion = v
-				break
-			}
-		}
+		cfg.SpecVersion = detectSpecVersion(registry)
 	}
 	if cfg.SpecVersion == "" {
 		return fmt.Errorf("could not determine spec version: use -spec-version flag or ensure schemas contain a $id with a version")
@@ -128,9 +126,40 @@ func Run(cfg Config) error {
 	emitter := NewEmitter(pkgName, cfg.OutputDir, cfg.SpecVersion)
 
 	allTypes := mapper.Types()
+	emitter.SetSchemaIDs(schemaIDs(allTypes, registry))
 	return emitter.Emit(allTypes)
 }
 
+// detectSpecVersion returns the spec version from the first loaded schema
+// (in path order) whose $id contains one, or "" if none does.
+func detectSpecVersion(registry *SchemaRegistry) string {
+	for _, path := range slices.Sorted(maps.Keys(registry.schemas)) {
+		if v := extractSpecVersion(registry.schemas[path].ID); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// schemaIDs maps each type name to the $id of the schema file it was
+// generated from. Schema files without a $id are identified by their path
+// relative to the schema directory. Types not derived from a schema file
+// are omitted.
+func schemaIDs(types []*GoType, registry *SchemaRegistry) map[string]string {
+	ids := make(map[string]string, len(types))
+	for _, t := range types {
+		if t.SchemaFile == "" {
+			continue
+		}
+		id := t.SchemaFile
+		if s, ok := registry.schemas[filepath.Clean(t.SchemaFile)]; ok && s.ID != "" {
+			id = s.ID
+		}
+		ids[t.Name] = id
+	}
+	return ids
+}
+
 // extractSpecVersion extracts the version from a JSON Schema $id URL.
 // The expected format is "https://schemas.elastic.dev/package-spec/{VERSION}/...".
 func extractSpecVersion(id string) string {
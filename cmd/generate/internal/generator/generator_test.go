@@ -1,6 +1,11 @@
 package generator
 
-import "testing"
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
 
 func TestExtractSpecVersion(t *testing.T) {
 	tests := []struct {
@@ -38,3 +43,98 @@ func TestExtractSpecVersion(t *testing.T) {
 		})
 	}
 }
+
+func TestVersionFileFromDetectedSpecVersion(t *testing.T) {
+	dir := t.TempDir()
+	const id = "https://schemas.elastic.dev/package-spec/3.5.7/integration/manifest.jsonschema.json"
+	schema := `{
+  "$id": "` + id + `",
+  "type": "object",
+  "properties": {
+    "owner": {
+      "type": "object",
+      "properties": {"github": {"type": "string"}}
+    }
+  }
+}`
+	schemaPath := filepath.Join("integration", "manifest.jsonschema.json")
+	if err := os.MkdirAll(filepath.Join(dir, "integration"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, schemaPath), []byte(schema), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	registry := NewSchemaRegistry(dir)
+	mapper := NewTypeMapper(registry)
+	mapper.RegisterEntryPoint(schemaPath, "IntegrationManifest")
+	if err := mapper.ProcessEntryPoint(schemaPath); err != nil {
+		t.Fatal(err)
+	}
+
+	version := detectSpecVersion(registry)
+	if version != "3.5.7" {
+		t.Fatalf("detectSpecVersion() = %q, want 3.5.7", version)
+	}
+
+	types := mapper.Types()
+	e := NewEmitter("example", "", version)
+	e.SetSchemaIDs(schemaIDs(types, registry))
+	files, err := e.Render(types)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src := string(files["version.go"])
+	for _, want := range []string{
+		`const SpecVersion = "3.5.7"`,
+		`var SchemaIDs = map[string]string{`,
+		`"IntegrationManifest":`,
+		`"` + id + `"`,
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("version.go missing %q:\n%s", want, src)
+		}
+	}
+}
+
+func TestRunEmitsSchemaIDs(t *testing.T) {
+	schemaDir := t.TempDir()
+	const idPrefix = "https://schemas.elastic.dev/package-spec/3.6.5/"
+	for _, ep := range DefaultEntryPoints() {
+		schema := `{
+  "$id": "` + idPrefix + ep.SchemaPath + `",
+  "type": "object",
+  "properties": {"name": {"type": "string"}}
+}`
+		p := filepath.Join(schemaDir, filepath.FromSlash(ep.SchemaPath))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(schema), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	outDir := t.TempDir()
+	if err := Run(Config{SchemaDir: schemaDir, OutputDir: outDir, PackageName: "example"}); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(outDir, "version.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Collapse whitespace so that gofmt's alignment of the map does not matter.
+	src := strings.Join(strings.Fields(string(data)), " ")
+	for _, want := range []string{
+		`const SpecVersion = "3.6.5"`,
+		`var SchemaIDs = map[string]string{`,
+		`"IntegrationManifest": "` + idPrefix + `integration/manifest.jsonschema.json",`,
+		`"SystemTestConfig": "` + idPrefix + `integration/data_stream/_dev/test/system/config.jsonschema.json",`,
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("version.go missing %q:\n%s", want, src)
+		}
+	}
+}
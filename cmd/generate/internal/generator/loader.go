@@ -1,6 +1,7 @@
 package generator
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -43,9 +44,12 @@ type Schema struct {
 	Required             []string              `json:"required,omitempty"`
 
 	// Array
-	Items    *Schema `json:"items,omitempty"`
-	MinItems *int    `json:"minItems,omitempty"`
-	MaxItems *int    `json:"maxItems,omitempty"`
+	Items *Schema `json:"items,omitempty"`
+	// PrefixItems holds positional (tuple) item schemas. It is populated
+	// from "prefixItems" or from the older array form of "items".
+	PrefixItems []*Schema `json:"prefixItems,omitempty"`
+	MinItems    *int      `json:"minItems,omitempty"`
+	MaxItems    *int      `json:"maxItems,omitempty"`
 
 	// Numeric
 	Minimum          *float64 `json:"minimum,omitempty"`
@@ -90,13 +94,31 @@ func (s *Schema) UnmarshalJSON(data []byte) error {
 		return nil
 	}
 
-	// Use an alias to avoid infinite recursion.
+	// Use an alias to avoid infinite recursion. Items is decoded separately
+	// because it may be either a schema or an array of schemas (tuple form).
 	type schemaAlias Schema
-	var sa schemaAlias
+	var sa struct {
+		*schemaAlias
+		Items json.RawMessage `json:"items,omitempty"`
+	}
+	sa.schemaAlias = (*schemaAlias)(s)
 	if err := json.Unmarshal(data, &sa); err != nil {
 		return err
 	}
-	*s = Schema(sa)
+
+	items := bytes.TrimSpace(sa.Items)
+	switch {
+	case len(items) == 0:
+	case items[0] == '[':
+		if err := json.Unmarshal(items, &s.PrefixItems); err != nil {
+			return fmt.Errorf("decoding items array: %w", err)
+		}
+	default:
+		s.Items = new(Schema)
+		if err := json.Unmarshal(items, s.Items); err != nil {
+			return fmt.Errorf("decoding items: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -344,10 +366,31 @@ func walkJSONPointer(schema *Schema, pointer string) (*Schema, error) {
 			current = next
 
 		case "items":
-			if current.Items == nil {
+			switch {
+			case current.Items != nil:
+				current = current.Items
+			case len(current.PrefixItems) > 0 && i+1 < len(parts):
+				// Tuple form: /items/<index> addresses a positional schema.
+				i++
+				idx, err := strconv.Atoi(parts[i])
+				if err != nil || idx < 0 || idx >= len(current.PrefixItems) {
+					return nil, fmt.Errorf("invalid tuple index %q in /items", parts[i])
+				}
+				current = current.PrefixItems[idx]
+			default:
 				return nil, fmt.Errorf("items is nil")
 			}
-			current = current.Items
+
+		case "prefixItems":
+			if i+1 >= len(parts) {
+				return nil, fmt.Errorf("incomplete pointer: missing index after /prefixItems")
+			}
+			i++
+			idx, err := strconv.Atoi(parts[i])
+			if err != nil || idx < 0 || idx >= len(current.PrefixItems) {
+				return nil, fmt.Errorf("invalid index %q in /prefixItems", parts[i])
+			}
+			current = current.PrefixItems[idx]
 
 		case "allOf", "anyOf", "oneOf":
 			if i+1 >= len(parts) {
@@ -1,6 +1,7 @@
 package generator
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"testing"
@@ -35,6 +36,34 @@ func TestSchemaType_UnmarshalJSON(t *testing.T) {
 	}
 }
 
+func TestSchema_UnmarshalJSON_TupleItems(t *testing.T) {
+	var s Schema
+	if err := json.Unmarshal([]byte(`{"type": "array", "items": [{"type": "string"}, {"type": "integer"}]}`), &s); err != nil {
+		t.Fatal(err)
+	}
+	if s.Items != nil {
+		t.Errorf("Items = %+v, want nil for tuple form", s.Items)
+	}
+	if len(s.PrefixItems) != 2 {
+		t.Fatalf("got %d prefix items, want 2", len(s.PrefixItems))
+	}
+	if s.PrefixItems[1].Type.Single() != "integer" {
+		t.Errorf("prefix item 1 type = %q, want integer", s.PrefixItems[1].Type.Single())
+	}
+
+	// The single-schema form still populates Items.
+	s = Schema{}
+	if err := json.Unmarshal([]byte(`{"type": "array", "items": {"type": "string"}}`), &s); err != nil {
+		t.Fatal(err)
+	}
+	if s.Items == nil || s.Items.Type.Single() != "string" {
+		t.Errorf("Items = %+v, want string schema", s.Items)
+	}
+	if s.Type.Single() != "array" {
+		t.Errorf("type = %q, want array", s.Type.Single())
+	}
+}
+
 func TestAdditionalProperties_UnmarshalJSON(t *testing.T) {
 	t.Run("false", func(t *testing.T) {
 		var ap AdditionalProperties
@@ -2,7 +2,9 @@ package generator
 
 import (
 	"fmt"
+	"go/token"
 	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -25,6 +27,7 @@ type GoType struct {
 	Kind                    GoTypeKind
 	Fields                  []GoField
 	EnumValues              []GoEnumVal
+	EnumBase                string // Underlying type of an enum: "string" (default) or "int"
 	AliasOf                 GoTypeRef
 	OutputFile              string
 	EmbedMeta               bool // Whether to embed FileMetadata
@@ -220,6 +223,11 @@ func (m *TypeMapper) processSchema(
 		return m.createEnumType(schema, contextFile, jsonPointer, suggestedName)
 	}
 
+	// Handle enum with an integer type → generate integer enum type.
+	if len(schema.Enum) > 0 && typ == "integer" && suggestedName != "" {
+		return m.createIntEnumType(schema, contextFile, jsonPointer, suggestedName)
+	}
+
 	// Handle anyOf/oneOf that are just type unions (e.g. string | boolean → any).
 	if typ == "" && schema.AnyOf != nil && !schema.HasProperties() {
 		return m.processAnyOf(schema, contextFile, jsonPointer, suggestedName)
@@ -390,36 +398,47 @@ func (m *TypeMapper) processObject(
 		pi := allProps[propName]
 		fieldName := ToGoName(propName)
 
-		// Use the property's original context file for resolving any nested $refs.
-		fieldRef, err := m.processSchema(
-			pi.schema,
-			pi.contextFile,
-			jsonPointer+"/properties/"+propName,
-			name+fieldName,
-			false,
-		)
-		if err != nil {
-			return GoTypeRef{}, fmt.Errorf("processing field %s.%s: %w", name, propName, err)
+		doc := pi.schema.Description
+		if doc == "" && pi.schema.Ref != "" {
+			if resolved, _, err := m.registry.ResolveRef(pi.schema.Ref, pi.contextFile); err == nil && resolved != nil {
+				doc = resolved.Description
+			}
+		}
+		doc, hints := parseDocHints(doc)
+		if hints.Name != "" {
+			fieldName = hints.Name
 		}
 
 		isRequired := allRequired[propName]
 
-		// Use pointer for optional boolean fields.
-		if !isRequired && fieldRef.Builtin == "bool" {
-			fieldRef.Pointer = true
-		}
+		var fieldRef GoTypeRef
+		if hints.Type != "" {
+			// An inline @type directive replaces the schema type as is,
+			// so the property schema is not processed.
+			fieldRef = parseTypeRef(hints.Type)
+		} else {
+			// Use the property's original context file for resolving any nested $refs.
+			fieldRef, err = m.processSchema(
+				pi.schema,
+				pi.contextFile,
+				jsonPointer+"/properties/"+propName,
+				name+fieldName,
+				false,
+			)
+			if err != nil {
+				return GoTypeRef{}, fmt.Errorf("processing field %s.%s: %w", name, propName, err)
+			}
 
-		doc := pi.schema.Description
-		if doc == "" && pi.schema.Ref != "" {
-			if resolved, _, err := m.registry.ResolveRef(pi.schema.Ref, pi.contextFile); err == nil && resolved != nil {
-				doc = resolved.Description
+			// Use pointer for optional boolean fields.
+			if !isRequired && fieldRef.Builtin == "bool" {
+				fieldRef.Pointer = true
 			}
 		}
 
 		goType.Fields = append(goType.Fields, GoField{
 			Name:     fieldName,
 			JSONName: propName,
-			Doc:      cleanDoc(doc),
+			Doc:      doc,
 			Type:     fieldRef,
 			Required: isRequired,
 		})
@@ -549,7 +568,11 @@ func (m *TypeMapper) processArray(
 	suggestedName string,
 	isEntryPoint bool,
 ) (GoTypeRef, error) {
-	if schema.Items == nil {
+	// Tuple schemas ("prefixItems", or "items" given as an array) map to
+	// []any. Positional structs would need custom YAML/JSON unmarshalers to
+	// decode from a sequence, and the element schemas usually differ in
+	// type, so there is no single element type to use.
+	if schema.Items == nil || len(schema.PrefixItems) > 0 {
 		return GoTypeRef{
 			Slice:   true,
 			Element: &GoTypeRef{Builtin: "any"},
@@ -732,6 +755,47 @@ func (m *TypeMapper) createEnumType(
 	return GoTypeRef{Named: name}, nil
 }
 
+// createIntEnumType creates a named int type with enum constants.
+func (m *TypeMapper) createIntEnumType(
+	schema *Schema,
+	contextFile string,
+	jsonPointer string,
+	suggestedName string,
+) (GoTypeRef, error) {
+	values := schema.EnumStrings()
+	for _, v := range values {
+		if _, err := strconv.Atoi(v); err != nil {
+			return GoTypeRef{}, fmt.Errorf("integer enum %s has non-integer value %s", suggestedName, v)
+		}
+	}
+
+	name := m.uniqueName(suggestedName)
+
+	cacheKey := contextFile + "#" + jsonPointer
+	if jsonPointer != "" {
+		m.seen[cacheKey] = name
+	}
+
+	goType := &GoType{
+		Name:       name,
+		Doc:        cleanDoc(schema.Description),
+		SchemaFile: contextFile,
+		SchemaPath: jsonPointer,
+		Kind:       GoTypeEnum,
+		EnumBase:   "int",
+	}
+
+	for _, v := range values {
+		goType.EnumValues = append(goType.EnumValues, GoEnumVal{
+			GoName: enumIntConstName(name, v),
+			Value:  v,
+		})
+	}
+
+	m.types[name] = goType
+	return GoTypeRef{Named: name}, nil
+}
+
 // uniqueName returns a unique Go type name, appending a number if needed.
 func (m *TypeMapper) uniqueName(name string) string {
 	if name == "" {
@@ -749,11 +813,49 @@ func (m *TypeMapper) uniqueName(name string) string {
 }
 
 // cleanDoc cleans up a schema description for use as a Go doc comment.
+// Trailing generator directives are removed.
 func cleanDoc(s string) string {
-	s = strings.TrimSpace(s)
+	s, _ = parseDocHints(s)
 	return s
 }
 
+// docHints holds generator directives embedded at the end of a schema
+// description. They override a property's generated field in the same way
+// as the fields section of augment.yml.
+type docHints struct {
+	Type string // @type:<go type>, e.g. @type:any or @type:[]string
+	Name string // @name:<Go field name>
+}
+
+// parseDocHints strips trailing "@type:<value>" and "@name:<value>"
+// directives from a schema description and returns the cleaned, trimmed
+// description along with the directives. Parsing is conservative: only
+// whitespace-separated tokens at the very end of the description are
+// considered, each directive may appear once, a @name value must be an
+// exported Go identifier, and the first token that is not a valid directive
+// ends the scan so that text such as "@timestamp" is left untouched.
+func parseDocHints(s string) (string, docHints) {
+	var hints docHints
+	s = strings.TrimSpace(s)
+	for s != "" {
+		i := strings.LastIndexAny(s, " \t\r\n")
+		key, value, ok := strings.Cut(s[i+1:], ":")
+		if !ok || value == "" {
+			break
+		}
+		switch {
+		case key == "@type" && hints.Type == "":
+			hints.Type = value
+		case key == "@name" && hints.Name == "" && token.IsIdentifier(value) && token.IsExported(value):
+			hints.Name = value
+		default:
+			return s, hints
+		}
+		s = strings.TrimSpace(s[:i+1])
+	}
+	return s, hints
+}
+
 // enumConstName generates a valid Go constant name for an enum value.
 func enumConstName(typeName, value string) string {
 	// Handle special characters.
@@ -774,6 +876,16 @@ func enumConstName(typeName, value string) string {
 	return typeName + ToGoName(cleaned)
 }
 
+// enumIntConstName generates a valid Go constant name for an integer enum
+// value. Negative values are spelled with a "Minus" prefix because "-" is
+// not valid in an identifier (e.g. -1 → SeverityMinus1).
+func enumIntConstName(typeName, value string) string {
+	if rest, ok := strings.CutPrefix(value, "-"); ok {
+		return typeName + "Minus" + rest
+	}
+	return typeName + strings.TrimPrefix(value, "+")
+}
+
 // singularize attempts to produce a singular form of a plural name.
 func singularize(s string) string {
 	if strings.HasSuffix(s, "ies") && len(s) > 3 {
@@ -233,6 +233,82 @@ func TestTypeMapper_Enum(t *testing.T) {
 	}
 }
 
+func TestTypeMapper_IntEnum(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "enum.json", `{
+		"type": "object",
+		"properties": {
+			"severity": {
+				"type": "integer",
+				"enum": [-1, 0, 3],
+				"description": "The severity level."
+			}
+		}
+	}`)
+
+	reg := NewSchemaRegistry(dir)
+	mapper := NewTypeMapper(reg)
+	mapper.RegisterEntryPoint("enum.json", "Item")
+
+	if err := mapper.ProcessEntryPoint("enum.json"); err != nil {
+		t.Fatal(err)
+	}
+
+	enum := mapper.TypesByName()["ItemSeverity"]
+	if enum == nil {
+		t.Fatal("expected ItemSeverity enum type")
+	}
+	if enum.Kind != GoTypeEnum {
+		t.Errorf("kind = %v, want GoTypeEnum", enum.Kind)
+	}
+	if enum.EnumBase != "int" {
+		t.Errorf("EnumBase = %q, want int", enum.EnumBase)
+	}
+
+	wantNames := []string{"ItemSeverityMinus1", "ItemSeverity0", "ItemSeverity3"}
+	if len(enum.EnumValues) != len(wantNames) {
+		t.Fatalf("got %d enum values, want %d", len(enum.EnumValues), len(wantNames))
+	}
+	for i, want := range wantNames {
+		if got := enum.EnumValues[i].GoName; got != want {
+			t.Errorf("EnumValues[%d].GoName = %q, want %q", i, got, want)
+		}
+	}
+
+	files, err := RenderTypes([]*GoType{enum}, "example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := string(files["types.go"])
+	for _, want := range []string{
+		"type ItemSeverity int",
+		"ItemSeverityMinus1 ItemSeverity = -1",
+		"ItemSeverity3      ItemSeverity = 3",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("generated source missing %q:\n%s", want, src)
+		}
+	}
+}
+
+func TestTypeMapper_IntEnumInvalidValue(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "enum.json", `{
+		"type": "object",
+		"properties": {
+			"level": {"type": "integer", "enum": [1, 2.5]}
+		}
+	}`)
+
+	reg := NewSchemaRegistry(dir)
+	mapper := NewTypeMapper(reg)
+	mapper.RegisterEntryPoint("enum.json", "Item")
+
+	if err := mapper.ProcessEntryPoint("enum.json"); err == nil {
+		t.Fatal("expected error for non-integer enum value")
+	}
+}
+
 func TestTypeMapper_Array(t *testing.T) {
 	dir := t.TempDir()
 	writeFile(t, dir, "array.json", `{
@@ -260,6 +336,46 @@ func TestTypeMapper_Array(t *testing.T) {
 	}
 }
 
+func TestTypeMapper_TupleArray(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "tuple.json", `{
+		"type": "object",
+		"additionalProperties": false,
+		"properties": {
+			"range": {
+				"type": "array",
+				"items": [{"type": "string"}, {"type": "integer"}]
+			},
+			"pair": {
+				"type": "array",
+				"prefixItems": [{"type": "string"}, {"type": "boolean"}]
+			}
+		}
+	}`)
+
+	reg := NewSchemaRegistry(dir)
+	mapper := NewTypeMapper(reg)
+	mapper.RegisterEntryPoint("tuple.json", "Tuple")
+
+	if err := mapper.ProcessEntryPoint("tuple.json"); err != nil {
+		t.Fatal(err)
+	}
+
+	types := mapper.Types()
+	if len(types) != 1 {
+		t.Fatalf("got %d types, want 1", len(types))
+	}
+
+	for _, f := range types[0].Fields {
+		if !f.Type.Slice || f.Type.Element == nil || f.Type.Element.Builtin != "any" {
+			t.Errorf("%s type = %+v, want []any", f.Name, f.Type)
+		}
+	}
+	if len(types[0].Fields) != 2 {
+		t.Errorf("got %d fields, want 2", len(types[0].Fields))
+	}
+}
+
 func TestTypeMapper_Ref(t *testing.T) {
 	dir := t.TempDir()
 	writeFile(t, dir, "main.json", `{
@@ -41,6 +41,10 @@ type TableConfig struct {
 	// Columns provides per-column overrides (comment, type, etc.).
 	Columns map[string]*ColumnOverride `yaml:"columns"`
 
+	// Unique lists columns that together form a table-level UNIQUE
+	// constraint (e.g. the two foreign keys of a join table).
+	Unique []string `yaml:"unique"`
+
 	// Flatten indicates the type should be flattened before insertion
 	// (e.g. fields via FlattenFields, processors via FlattenProcessors).
 	Flatten bool `yaml:"flatten"`
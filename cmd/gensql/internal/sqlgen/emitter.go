@@ -1,7 +1,11 @@
 package sqlgen
 
 import (
+	"bytes"
 	"fmt"
+	"go/format"
+	"io"
+	"os"
 	"path/filepath"
 	"strings"
 
@@ -15,7 +19,8 @@ const (
 	encodingJSONPkg = "encoding/json"
 )
 
-// EmitTablesGo generates tables.go with CREATE TABLE constants and a creates slice.
+// EmitTablesGo generates tables.go with CREATE TABLE constants, a creates
+// slice, and a tableInfos slice describing each table's columns.
 func EmitTablesGo(pkgName, outputDir string, tables []*TableDef) error {
 	f := NewFile(pkgName)
 	f.HeaderComment("Code generated by cmd/gensql; DO NOT EDIT.")
@@ -45,8 +50,55 @@ func EmitTablesGo(pkgName, outputDir string, tables []*TableDef) error {
 	f.Var().Id("creates").Op("=").Index().String().Values(sliceItems...)
 	f.Line()
 
-	path := filepath.Join(outputDir, "tables.go")
-	return f.Save(path)
+	emitTableInfos(f, tables)
+
+	return saveFile(f, filepath.Join(outputDir, "tables.go"))
+}
+
+// emitTableInfos generates the tableColumn and tableInfo types and a
+// tableInfos slice holding the same table and column metadata used for the
+// CREATE TABLE statements, so the package can describe its schema without
+// parsing SQL.
+func emitTableInfos(f *File, tables []*TableDef) {
+	f.Comment("tableColumn describes a column of a generated table.")
+	f.Type().Id("tableColumn").Struct(
+		Id("name").String(),
+		Id("sqlType").String(),
+		Id("notNull").Bool(),
+		Id("comment").String(),
+	)
+	f.Line()
+
+	f.Comment("tableInfo describes a generated table and its columns.")
+	f.Type().Id("tableInfo").Struct(
+		Id("name").String(),
+		Id("comment").String(),
+		Id("columns").Index().Id("tableColumn"),
+	)
+	f.Line()
+
+	multi := Options{Open: "{", Close: "}", Separator: ",", Multi: true}
+	var infos []Code
+	for _, td := range tables {
+		var cols []Code
+		for _, col := range td.Columns {
+			cols = append(cols, Values(
+				Id("name").Op(":").Lit(col.Name),
+				Id("sqlType").Op(":").Lit(col.SQLType),
+				Id("notNull").Op(":").Lit(col.NotNull || col.PK),
+				Id("comment").Op(":").Lit(col.Comment),
+			))
+		}
+		infos = append(infos, Custom(multi,
+			Id("name").Op(":").Lit(td.Name),
+			Id("comment").Op(":").Lit(td.Comment),
+			Id("columns").Op(":").Index().Id("tableColumn").Custom(multi, cols...),
+		))
+	}
+
+	f.Comment("tableInfos describes every table in creates, in the same order.")
+	f.Var().Id("tableInfos").Op("=").Index().Id("tableInfo").Custom(multi, infos...)
+	f.Line()
 }
 
 // EmitInsertGo generates insert.go with type→sqlc param mapping functions
@@ -66,8 +118,32 @@ func EmitInsertGo(pkgName, outputDir string, tables []*TableDef) error {
 		emitInsertFunc(f, td)
 	}
 
-	path := filepath.Join(outputDir, "insert.go")
-	return f.Save(path)
+	return saveFile(f, filepath.Join(outputDir, "insert.go"))
+}
+
+// saveFile renders f through go/format and writes it to path. Output that
+// cannot be formatted is reported as an error instead of being written.
+func saveFile(f *File, path string) error {
+	var buf bytes.Buffer
+	if err := writeFormatted(&buf, f); err != nil {
+		return fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
+
+// writeFormatted renders f, runs it through go/format, and writes the
+// result to w.
+func writeFormatted(w io.Writer, f *File) error {
+	var buf bytes.Buffer
+	if err := f.Render(&buf); err != nil {
+		return fmt.Errorf("rendering source: %w", err)
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("formatting source: %w", err)
+	}
+	_, err = w.Write(src)
+	return err
 }
 
 // emitHelpers generates shared nullable conversion helpers.
@@ -194,9 +270,10 @@ func emitInsertFunc(f *File, td *TableDef) {
 	funcName := "map" + goName + "Params"
 	paramsType := "Insert" + goName + "Params"
 
-	// Build parameter list.
+	// Build parameter list. The source type is qualified by the package
+	// it was registered from so pkgreader types work as well as pkgspec.
 	var funcParams []Code
-	funcParams = append(funcParams, Id("v").Op("*").Qual(pkgspecImport, td.GoType))
+	funcParams = append(funcParams, Id("v").Op("*").Add(goTypeQual(td.GoType)))
 
 	// Add parent ID parameter if there's a parent.
 	if td.Parent != "" {
@@ -244,6 +321,15 @@ func emitInsertFunc(f *File, td *TableDef) {
 	f.Line()
 }
 
+// goTypeQual returns the qualified type reference for a registered type
+// name. Unregistered names fall back to the pkgspec package.
+func goTypeQual(typeName string) *Statement {
+	if rt, ok := LookupType(typeName); ok && rt.PkgPath() != "" {
+		return Qual(rt.PkgPath(), rt.Name())
+	}
+	return Qual(pkgspecImport, typeName)
+}
+
 // buildFieldAssignment generates the RHS expression for a field assignment
 // in the insert mapping function.
 func buildFieldAssignment(col ColumnDef) *Statement {
@@ -260,8 +346,6 @@ func buildFieldAssignment(col ColumnDef) *Statement {
 		}
 	}
 
-	goPath := "v." + col.GoField
-
 	// Split the field path for nested access.
 	parts := strings.Split(col.GoField, ".")
 	accessor := Id("v")
@@ -273,16 +357,15 @@ func buildFieldAssignment(col ColumnDef) *Statement {
 		return Id("jsonNullString").Call(accessor)
 	}
 
-	if col.IsPointer {
-		if col.SQLType == "BOOLEAN" {
-			return Id("toNullBool").Call(accessor)
-		}
-		if col.SQLType == "TEXT" {
-			// *time.Time
-			if strings.Contains(goPath, "Time") || strings.Contains(goPath, "Date") {
-				return Id("timeNullString").Call(accessor)
-			}
+	// time.Time values are always written as RFC3339 strings.
+	if col.IsTime {
+		if col.IsPointer {
+			return Id("timeNullString").Call(accessor)
 		}
+		return Id("timeNullString").Call(Op("&").Add(accessor))
+	}
+
+	if col.IsPointer {
 		return Id("toNullBool").Call(accessor)
 	}
 
@@ -0,0 +1,108 @@
+package sqlgen
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/dave/jennifer/jen"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestEmitInsertFuncGolden(t *testing.T) {
+	tc := &TableConfig{
+		Type:    "Icon",
+		Parent:  "packages",
+		Comment: "Icon definitions for a package.",
+	}
+	cols, err := ResolveColumns("package_icons", tc, DocMap{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	td := &TableDef{
+		Name:    "package_icons",
+		Comment: tc.Comment,
+		Columns: cols,
+		Parent:  tc.Parent,
+		Config:  tc,
+		GoType:  tc.Type,
+	}
+
+	f := NewFile("pkgsql")
+	emitInsertFunc(f, td)
+
+	var buf bytes.Buffer
+	if err := writeFormatted(&buf, f); err != nil {
+		t.Fatal(err)
+	}
+
+	golden := filepath.Join("testdata", "package_icons_mapper.golden")
+	if *update {
+		if err := os.WriteFile(golden, buf.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("generated mapper does not match %s (run with -update to regenerate)\ngot:\n%s\nwant:\n%s", golden, buf.Bytes(), want)
+	}
+}
+
+func TestEmitTableInfos(t *testing.T) {
+	tc := &TableConfig{
+		Type:    "Icon",
+		Parent:  "packages",
+		Comment: "Icon definitions for a package.",
+	}
+	cols, err := ResolveColumns("package_icons", tc, DocMap{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	td := &TableDef{Name: "package_icons", Comment: tc.Comment, Columns: cols, Parent: tc.Parent, Config: tc, GoType: tc.Type}
+
+	f := NewFile("pkgsql")
+	emitTableInfos(f, []*TableDef{td})
+
+	var buf bytes.Buffer
+	if err := writeFormatted(&buf, f); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"type tableColumn struct {",
+		"var tableInfos = []tableInfo{",
+		`name:    "package_icons",`,
+		`comment: "Icon definitions for a package.",`,
+		`{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},`,
+	} {
+		if !bytes.Contains(buf.Bytes(), []byte(want)) {
+			t.Errorf("generated code missing %q\n%s", want, buf.Bytes())
+		}
+	}
+}
+
+func TestGoTypeQual(t *testing.T) {
+	tests := []struct {
+		typeName string
+		want     string
+	}{
+		{"Icon", "pkgspec.Icon"},
+		{"DataStream", "pkgreader.DataStream"},
+		{"Unregistered", "pkgspec.Unregistered"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.typeName, func(t *testing.T) {
+			got := (&Statement{}).Add(goTypeQual(tt.typeName)).GoString()
+			if got != tt.want {
+				t.Errorf("goTypeQual(%q) = %q, want %q", tt.typeName, got, tt.want)
+			}
+		})
+	}
+}
@@ -12,6 +12,7 @@ type Config struct {
 	OutputDir    string // Output directory for generated files
 	SQLOutputDir string // Output directory for schema.sql and query.sql (default: <OutputDir>/internal/db)
 	PackageName  string // Go package name
+	TimeCheck    bool   // Emit CHECK constraints requiring ISO-8601 values in time columns
 }
 
 // Run executes the full SQL generation pipeline.
@@ -62,7 +63,12 @@ func Run(cfg Config) error {
 		}
 	}
 
-	// 5. Create output directories.
+	// 5. Add CHECK constraints for time columns.
+	if cfg.TimeCheck {
+		applyTimeChecks(sortedTables)
+	}
+
+	// 6. Create output directories.
 	if err := os.MkdirAll(cfg.OutputDir, 0o755); err != nil {
 		return fmt.Errorf("creating output directory: %w", err)
 	}
@@ -74,19 +80,19 @@ func Run(cfg Config) error {
 		return fmt.Errorf("creating SQL output directory: %w", err)
 	}
 
-	// 6. Generate schema.sql.
+	// 7. Generate schema.sql.
 	schemaSQL := GenerateSchemaSQL(sortedTables)
 	if err := os.WriteFile(filepath.Join(sqlOutputDir, "schema.sql"), []byte(schemaSQL), 0o644); err != nil {
 		return fmt.Errorf("writing schema.sql: %w", err)
 	}
 
-	// 7. Generate query.sql.
+	// 8. Generate query.sql.
 	querySQL := GenerateQuerySQL(sortedTables)
 	if err := os.WriteFile(filepath.Join(sqlOutputDir, "query.sql"), []byte(querySQL), 0o644); err != nil {
 		return fmt.Errorf("writing query.sql: %w", err)
 	}
 
-	// 8. Generate tables.go.
+	// 9. Generate tables.go.
 	pkgName := cfg.PackageName
 	if pkgName == "" {
 		pkgName = "pkgsql"
@@ -95,7 +101,7 @@ func Run(cfg Config) error {
 		return fmt.Errorf("writing tables.go: %w", err)
 	}
 
-	// 9. Generate insert.go.
+	// 10. Generate insert.go.
 	if err := EmitInsertGo(pkgName, cfg.OutputDir, sortedTables); err != nil {
 		return fmt.Errorf("writing insert.go: %w", err)
 	}
@@ -5,19 +5,25 @@ import (
 	"strings"
 )
 
-// GenerateQuerySQL generates the query.sql content with named INSERT queries
-// for sqlc. Entity tables use :one with RETURNING id. Join tables use :exec.
+// GenerateQuerySQL generates the query.sql content with named queries for
+// sqlc. Every table gets an INSERT; entity tables use :one with RETURNING id
+// and join tables use :exec. Tables with an id primary key also get a DELETE
+// by id, and tables with a parent get a DELETE by parent id.
 func GenerateQuerySQL(tables []*TableDef) string {
-	var b strings.Builder
-
-	for i, td := range tables {
-		if i > 0 {
-			b.WriteString("\n")
+	var queries []string
+	for _, td := range tables {
+		for _, q := range []string{
+			generateInsertQuery(td),
+			generateDeleteQuery(td),
+			generateDeleteByParentQuery(td),
+		} {
+			if q != "" {
+				queries = append(queries, q)
+			}
 		}
-		b.WriteString(generateInsertQuery(td))
 	}
 
-	return b.String()
+	return strings.Join(queries, "\n")
 }
 
 func generateInsertQuery(td *TableDef) string {
@@ -72,6 +78,43 @@ func generateInsertQuery(td *TableDef) string {
 	return b.String()
 }
 
+func generateDeleteQuery(td *TableDef) string {
+	if !hasIDColumn(td) {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("-- name: Delete%s :exec\n", sqlNameToGoName(td.Name)))
+	b.WriteString(fmt.Sprintf("DELETE FROM %s WHERE id = ?;\n", td.Name))
+
+	return b.String()
+}
+
+// generateDeleteByParentQuery emits a DELETE of all rows belonging to one
+// parent row. Only tables with a configured parent get one; multi-FK join
+// tables have no single owner to delete by.
+func generateDeleteByParentQuery(td *TableDef) string {
+	if td.Parent == "" {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("-- name: Delete%sByParent :exec\n", sqlNameToGoName(td.Name)))
+	b.WriteString(fmt.Sprintf("DELETE FROM %s WHERE %s = ?;\n", td.Name, quoteName(td.Parent+"_id")))
+
+	return b.String()
+}
+
+// hasIDColumn reports whether the table has an "id" primary key column.
+func hasIDColumn(td *TableDef) bool {
+	for _, col := range td.Columns {
+		if col.PK && col.Name == "id" {
+			return true
+		}
+	}
+	return false
+}
+
 // sqlNameToGoName converts a SQL table name (e.g. "policy_templates") to a
 // Go identifier (e.g. "PolicyTemplate"). It singularizes trailing "s".
 func sqlNameToGoName(sqlName string) string {
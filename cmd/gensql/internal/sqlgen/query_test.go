@@ -0,0 +1,33 @@
+package sqlgen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateQuerySQLDeleteQueries(t *testing.T) {
+	tc := &TableConfig{Type: "Changelog", Parent: "packages"}
+	cols, err := ResolveColumns("changelogs", tc, DocMap{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	td := &TableDef{Name: "changelogs", Columns: cols, Parent: tc.Parent, Config: tc, GoType: tc.Type}
+
+	sql := GenerateQuerySQL([]*TableDef{td})
+
+	for _, want := range []string{
+		"-- name: InsertChangelogs :one\n",
+		"-- name: DeleteChangelogs :exec\nDELETE FROM changelogs WHERE id = ?;\n",
+		"-- name: DeleteChangelogsByParent :exec\nDELETE FROM changelogs WHERE packages_id = ?;\n",
+	} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("query.sql missing %q:\n%s", want, sql)
+		}
+	}
+
+	// A table without a parent has no DELETE by parent.
+	td.Parent = ""
+	if sql := GenerateQuerySQL([]*TableDef{td}); strings.Contains(sql, "ByParent") {
+		t.Errorf("unexpected DELETE by parent for table without parent:\n%s", sql)
+	}
+}
@@ -25,6 +25,8 @@ type ColumnDef struct {
 	IsPointer bool   // Go type is a pointer (always nullable)
 	IsSlice   bool   // Go type is a slice (JSON serialized)
 	IsMethod  bool   // value accessed via method call, not field
+	IsTime    bool   // Go type is time.Time (stored as RFC3339 TEXT)
+	Check     string // CHECK constraint expression (without the CHECK keyword)
 }
 
 // TableDef describes a SQL table.
@@ -334,6 +336,7 @@ func goTypeToColumn(t reflect.Type, sqlName, goField, docComment string, omitemp
 		// Check for time.Time.
 		if t.PkgPath() == "time" && t.Name() == "Time" {
 			col.SQLType = "TEXT"
+			col.IsTime = true
 			if isPointer {
 				// *time.Time is always nullable
 			} else if !omitempty {
@@ -68,6 +68,24 @@ func quoteName(name string) string {
 	return name
 }
 
+// timeCheck returns a CHECK constraint expression that requires a time
+// column to be NULL or to begin with an ISO-8601 date (YYYY-MM-DD).
+func timeCheck(name string) string {
+	col := quoteName(name)
+	return fmt.Sprintf("%s IS NULL OR %s GLOB '[0-9][0-9][0-9][0-9]-[0-9][0-9]-[0-9][0-9]*'", col, col)
+}
+
+// applyTimeChecks adds an ISO-8601 CHECK constraint to every time column.
+func applyTimeChecks(tables []*TableDef) {
+	for _, td := range tables {
+		for i := range td.Columns {
+			if td.Columns[i].IsTime {
+				td.Columns[i].Check = timeCheck(td.Columns[i].Name)
+			}
+		}
+	}
+}
+
 func generateCreateTable(td *TableDef) string {
 	var b strings.Builder
 
@@ -78,6 +96,11 @@ func generateCreateTable(td *TableDef) string {
 		b.WriteString(fmt.Sprintf("  -- %s\n", td.Comment))
 	}
 
+	var unique []string
+	if td.Config != nil {
+		unique = td.Config.Unique
+	}
+
 	for i, col := range td.Columns {
 		b.WriteString("  ")
 		b.WriteString(quoteName(col.Name))
@@ -99,9 +122,12 @@ func generateCreateTable(td *TableDef) string {
 		if col.FK != "" {
 			b.WriteString(fmt.Sprintf(" REFERENCES %s(id)", col.FK))
 		}
+		if col.Check != "" {
+			b.WriteString(fmt.Sprintf(" CHECK (%s)", col.Check))
+		}
 
-		// Trailing comma unless last column.
-		if i < len(td.Columns)-1 {
+		// Trailing comma unless last column and no table constraint follows.
+		if i < len(td.Columns)-1 || len(unique) > 0 {
 			b.WriteString(",")
 		}
 
@@ -113,6 +139,14 @@ func generateCreateTable(td *TableDef) string {
 		b.WriteString("\n")
 	}
 
+	if len(unique) > 0 {
+		quoted := make([]string, len(unique))
+		for i, name := range unique {
+			quoted[i] = quoteName(name)
+		}
+		b.WriteString(fmt.Sprintf("  UNIQUE (%s)\n", strings.Join(quoted, ", ")))
+	}
+
 	b.WriteString(");\n")
 	return b.String()
 }
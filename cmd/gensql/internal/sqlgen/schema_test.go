@@ -0,0 +1,58 @@
+package sqlgen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateCreateTableTimeCheck(t *testing.T) {
+	tc := &TableConfig{Type: "Changelog", Parent: "packages"}
+	cols, err := ResolveColumns("changelogs", tc, DocMap{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	td := &TableDef{Name: "changelogs", Columns: cols, Parent: tc.Parent, Config: tc, GoType: tc.Type}
+
+	const want = "date TEXT CHECK (date IS NULL OR date GLOB '[0-9][0-9][0-9][0-9]-[0-9][0-9]-[0-9][0-9]*')"
+
+	// Without the option no constraint is emitted.
+	if sql := generateCreateTable(td); strings.Contains(sql, "CHECK") {
+		t.Errorf("unexpected CHECK constraint without TimeCheck:\n%s", sql)
+	}
+
+	applyTimeChecks([]*TableDef{td})
+	sql := generateCreateTable(td)
+	if !strings.Contains(sql, want) {
+		t.Errorf("CREATE TABLE missing %q:\n%s", want, sql)
+	}
+
+	// Only time columns are constrained.
+	if n := strings.Count(sql, "CHECK"); n != 1 {
+		t.Errorf("got %d CHECK constraints, want 1:\n%s", n, sql)
+	}
+}
+
+func TestGenerateCreateTableUnique(t *testing.T) {
+	tc := &TableConfig{
+		ExtraColumns: map[string]*ExtraColumnConfig{
+			"package_id": {Type: "INTEGER", NotNull: true, FK: "packages", Comment: "foreign key to packages"},
+			"var_id":     {Type: "INTEGER", NotNull: true, FK: "vars", Comment: "foreign key to vars"},
+		},
+		Unique: []string{"package_id", "var_id"},
+	}
+	cols, err := ResolveColumns("package_vars", tc, DocMap{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	td := &TableDef{Name: "package_vars", Columns: cols, Config: tc}
+
+	sql := generateCreateTable(td)
+	for _, want := range []string{
+		"var_id INTEGER NOT NULL REFERENCES vars(id), -- foreign key to vars\n",
+		"  UNIQUE (package_id, var_id)\n);\n",
+	} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("CREATE TABLE missing %q:\n%s", want, sql)
+		}
+	}
+}
@@ -17,6 +17,7 @@ func main() {
 	flag.StringVar(&cfg.OutputDir, "output", "pkgsql", "Output directory for generated files")
 	flag.StringVar(&cfg.SQLOutputDir, "sql-output", "", "Output directory for schema.sql and query.sql (default: <output>/internal/db)")
 	flag.StringVar(&cfg.PackageName, "package", "pkgsql", "Go package name for generated files")
+	flag.BoolVar(&cfg.TimeCheck, "time-check", false, "Emit CHECK constraints requiring ISO-8601 values in time columns")
 	flag.Parse()
 
 	if cfg.TablesFile == "" {
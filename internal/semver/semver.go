@@ -0,0 +1,115 @@
+// Package semver parses and compares semantic versions (https://semver.org)
+// for pkgreader and pkgsql.
+package semver
+
+import (
+	"cmp"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a parsed semantic version. Build metadata is discarded because
+// it does not affect precedence.
+type Version struct {
+	major, minor, patch uint64
+	prerelease          []string
+}
+
+// Parse parses a version of the form MAJOR.MINOR.PATCH with an optional
+// -PRERELEASE and +BUILD suffix.
+func Parse(s string) (Version, error) {
+	var v Version
+	core, _, _ := strings.Cut(s, "+")
+	core, pre, hasPre := strings.Cut(core, "-")
+
+	parts := strings.Split(core, ".")
+	if len(parts) != 3 {
+		return v, fmt.Errorf("invalid semantic version %q", s)
+	}
+	nums := []*uint64{&v.major, &v.minor, &v.patch}
+	for i, p := range parts {
+		n, err := strconv.ParseUint(p, 10, 64)
+		if err != nil || (len(p) > 1 && p[0] == '0') {
+			return v, fmt.Errorf("invalid semantic version %q", s)
+		}
+		*nums[i] = n
+	}
+
+	if hasPre {
+		if pre == "" {
+			return v, fmt.Errorf("invalid semantic version %q", s)
+		}
+		v.prerelease = strings.Split(pre, ".")
+		for _, id := range v.prerelease {
+			if id == "" {
+				return v, fmt.Errorf("invalid semantic version %q", s)
+			}
+		}
+	}
+	return v, nil
+}
+
+// Compare returns -1, 0, or +1 depending on whether the version string a
+// has lower, equal, or higher precedence than b. A version that is not
+// valid semver has lower precedence than any valid one, and two invalid
+// versions are compared as strings.
+func Compare(a, b string) int {
+	v, vErr := Parse(a)
+	w, wErr := Parse(b)
+	switch {
+	case vErr == nil && wErr == nil:
+		return v.Compare(w)
+	case vErr == nil:
+		return 1
+	case wErr == nil:
+		return -1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+// Compare returns -1, 0, or +1 depending on whether v has lower, equal, or
+// higher precedence than w.
+func (v Version) Compare(w Version) int {
+	if c := cmp.Compare(v.major, w.major); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(v.minor, w.minor); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(v.patch, w.patch); c != 0 {
+		return c
+	}
+
+	// A version without a prerelease has higher precedence.
+	switch {
+	case len(v.prerelease) == 0 && len(w.prerelease) == 0:
+		return 0
+	case len(v.prerelease) == 0:
+		return 1
+	case len(w.prerelease) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(v.prerelease) && i < len(w.prerelease); i++ {
+		a, b := v.prerelease[i], w.prerelease[i]
+		an, aErr := strconv.ParseUint(a, 10, 64)
+		bn, bErr := strconv.ParseUint(b, 10, 64)
+		var c int
+		switch {
+		case aErr == nil && bErr == nil:
+			c = cmp.Compare(an, bn)
+		case aErr == nil:
+			c = -1 // numeric identifiers sort before alphanumeric ones
+		case bErr == nil:
+			c = 1
+		default:
+			c = strings.Compare(a, b)
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(v.prerelease), len(w.prerelease))
+}
@@ -0,0 +1,183 @@
+package pkgreader
+
+import (
+	"fmt"
+	"io/fs"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// gitChangedFiles returns the package-relative paths of files under the
+// package directory dir that differ between baseRef and the working tree,
+// as reported by git diff. Untracked files are not included.
+func gitChangedFiles(dir, baseRef string) ([]string, error) {
+	root, err := gitToplevel(dir)
+	if err != nil {
+		return nil, err
+	}
+	pkgDir, err := repoRelativeDir(root, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command("git", "diff", "--name-only", "--no-renames", baseRef, "--", ".")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git diff %s: %w", baseRef, err)
+	}
+	return changedPackageFiles(strings.Split(string(out), "\n"), pkgDir), nil
+}
+
+// repoRelativeDir returns dir relative to the repository root, in slash
+// form. Both paths are resolved through symlinks so that a package reached
+// via a symlinked path still matches the paths reported by git.
+func repoRelativeDir(root, dir string) (string, error) {
+	resolve := func(p string) (string, error) {
+		p, err := filepath.Abs(p)
+		if err != nil {
+			return "", err
+		}
+		return filepath.EvalSymlinks(p)
+	}
+	root, err := resolve(root)
+	if err != nil {
+		return "", err
+	}
+	dir, err = resolve(dir)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(root, dir)
+	if err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// changedPackageFiles converts repo-relative paths from a git diff listing
+// into sorted, deduplicated paths relative to the package directory pkgDir.
+// Blank lines and paths outside the package are dropped.
+func changedPackageFiles(diff []string, pkgDir string) []string {
+	var files []string
+	for _, line := range diff {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		rel := line
+		if pkgDir != "." && pkgDir != "" {
+			var ok bool
+			rel, ok = strings.CutPrefix(line, pkgDir+"/")
+			if !ok {
+				continue
+			}
+		}
+		files = append(files, rel)
+	}
+	slices.Sort(files)
+	return slices.Compact(files)
+}
+
+// changedUnit returns the component directory that must be loaded as a whole
+// when file changes, or "" if file can be loaded on its own. A data stream or
+// transform is only readable with its manifest and sibling files, so a change
+// to any of its files makes the whole directory visible.
+func changedUnit(file string) string {
+	parts := strings.Split(file, "/")
+	switch {
+	case len(parts) > 2 && parts[0] == "data_stream":
+		return path.Join(parts[:2]...)
+	case len(parts) > 3 && parts[0] == "elasticsearch" && parts[1] == "transform":
+		return path.Join(parts[:3]...)
+	}
+	return ""
+}
+
+// changedFS is an [fs.FS] that hides the files of a package that have not
+// changed. The package manifest is always visible so the package can still
+// be identified. Directories are visible when they contain a visible file.
+type changedFS struct {
+	fsys  fs.FS
+	root  string          // package directory within fsys
+	files map[string]bool // changed package-relative file paths
+	units []string        // package-relative directories loaded as a whole
+}
+
+func newChangedFS(fsys fs.FS, root string, changed []string) *changedFS {
+	c := &changedFS{fsys: fsys, root: root, files: make(map[string]bool, len(changed))}
+	for _, f := range changed {
+		c.files[f] = true
+		if u := changedUnit(f); u != "" && !slices.Contains(c.units, u) {
+			c.units = append(c.units, u)
+		}
+	}
+	return c
+}
+
+// visible reports whether the entry at name is exposed. Paths outside the
+// package root are passed through unchanged.
+func (c *changedFS) visible(name string, isDir bool) bool {
+	rel := name
+	if c.root != "." {
+		if name == c.root {
+			return true
+		}
+		var ok bool
+		if rel, ok = strings.CutPrefix(name, c.root+"/"); !ok {
+			return true
+		}
+	}
+	if rel == "." || (!isDir && rel == "manifest.yml") || c.files[rel] {
+		return true
+	}
+	for _, u := range c.units {
+		if rel == u || strings.HasPrefix(rel, u+"/") {
+			return true
+		}
+	}
+	if isDir {
+		for f := range c.files {
+			if strings.HasPrefix(f, rel+"/") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Open implements [fs.FS].
+func (c *changedFS) Open(name string) (fs.File, error) {
+	f, err := c.fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if !c.visible(name, info.IsDir()) {
+		f.Close()
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return f, nil
+}
+
+// ReadDir implements [fs.ReadDirFS]. Entries for unchanged files and
+// directories without changes are omitted.
+func (c *changedFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !c.visible(name, true) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+	entries, err := fs.ReadDir(c.fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(entries, func(e fs.DirEntry) bool {
+		return !c.visible(path.Join(name, e.Name()), e.IsDir())
+	}), nil
+}
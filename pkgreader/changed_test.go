@@ -0,0 +1,195 @@
+package pkgreader
+
+import (
+	"io/fs"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestChangedPackageFiles(t *testing.T) {
+	diff := []string{
+		"packages/nginx/manifest.yml",
+		"packages/nginx/data_stream/access/fields/fields.yml",
+		"packages/nginx_ingress/manifest.yml",
+		"packages/apache/changelog.yml",
+		"packages/nginx/data_stream/access/fields/fields.yml",
+		"",
+		"go.mod",
+	}
+
+	got := changedPackageFiles(diff, "packages/nginx")
+	want := []string{"data_stream/access/fields/fields.yml", "manifest.yml"}
+	if !slices.Equal(got, want) {
+		t.Errorf("changedPackageFiles = %v, want %v", got, want)
+	}
+
+	// A package at the repository root keeps every path.
+	got = changedPackageFiles([]string{"changelog.yml", "docs/README.md"}, ".")
+	want = []string{"changelog.yml", "docs/README.md"}
+	if !slices.Equal(got, want) {
+		t.Errorf("changedPackageFiles at root = %v, want %v", got, want)
+	}
+
+	if got := changedPackageFiles([]string{""}, "packages/nginx"); len(got) != 0 {
+		t.Errorf("changedPackageFiles of empty diff = %v, want none", got)
+	}
+}
+
+func TestChangedFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"pkg/manifest.yml":                              {Data: []byte("name: test\n")},
+		"pkg/changelog.yml":                             {Data: []byte("[]\n")},
+		"pkg/data_stream/a/manifest.yml":                {Data: []byte("title: A\n")},
+		"pkg/data_stream/a/fields/fields.yml":           {Data: []byte("[]\n")},
+		"pkg/data_stream/b/manifest.yml":                {Data: []byte("title: B\n")},
+		"pkg/elasticsearch/transform/t/manifest.yml":    {Data: []byte("{}\n")},
+		"pkg/elasticsearch/transform/t/transform.yml":   {Data: []byte("{}\n")},
+		"pkg/elasticsearch/transform/t/fields/base.yml": {Data: []byte("[]\n")},
+		"pkg/kibana/dashboard/one.json":                 {Data: []byte("{}\n")},
+		"pkg/kibana/dashboard/two.json":                 {Data: []byte("{}\n")},
+		"pkg/kibana/tags.yml":                           {Data: []byte("[]\n")},
+	}
+	cfs := newChangedFS(fsys, "pkg", []string{
+		"data_stream/a/fields/fields.yml",
+		"elasticsearch/transform/t/fields/base.yml",
+		"kibana/dashboard/two.json",
+	})
+
+	for name, want := range map[string]bool{
+		"pkg/manifest.yml":                            true,
+		"pkg/changelog.yml":                           false,
+		"pkg/data_stream/a/manifest.yml":              true,
+		"pkg/data_stream/a/fields/fields.yml":         true,
+		"pkg/data_stream/b/manifest.yml":              false,
+		"pkg/elasticsearch/transform/t/transform.yml": true,
+		"pkg/kibana/dashboard/one.json":               false,
+		"pkg/kibana/dashboard/two.json":               true,
+		"pkg/kibana/tags.yml":                         false,
+	} {
+		_, err := fs.ReadFile(cfs, name)
+		if got := err == nil; got != want {
+			t.Errorf("ReadFile(%s) visible = %v, want %v (err %v)", name, got, want, err)
+		}
+	}
+
+	for dir, want := range map[string][]string{
+		"pkg":                  {"data_stream", "elasticsearch", "kibana", "manifest.yml"},
+		"pkg/data_stream":      {"a"},
+		"pkg/kibana":           {"dashboard"},
+		"pkg/kibana/dashboard": {"two.json"},
+	} {
+		entries, err := fs.ReadDir(cfs, dir)
+		if err != nil {
+			t.Fatalf("ReadDir(%s): %v", dir, err)
+		}
+		var got []string
+		for _, e := range entries {
+			got = append(got, e.Name())
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("ReadDir(%s) = %v, want %v", dir, got, want)
+		}
+	}
+
+	if _, err := fs.ReadDir(cfs, "pkg/data_stream/b"); err == nil {
+		t.Error("ReadDir of unchanged data stream succeeded, want not exist")
+	}
+}
+
+// TestWithChangedSince documents the behavior against a real git repository.
+// It builds a repository fixture from testdata and is skipped when git is
+// not installed.
+func TestWithChangedSince(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	repo := t.TempDir()
+	pkgDir := filepath.Join(repo, "packages", "test_pkg")
+	if err := os.CopyFS(pkgDir, os.DirFS("testdata/integration_pkg")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.CopyFS(filepath.Join(pkgDir, "data_stream", "other"), os.DirFS("testdata/integration_pkg/data_stream/logs")); err != nil {
+		t.Fatal(err)
+	}
+
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "-q")
+	git("add", "-A")
+	git("commit", "-q", "-m", "initial")
+
+	// Change one file in the logs data stream and one dashboard.
+	for _, name := range []string{"data_stream/logs/fields/fields.yml", "kibana/dashboard/overview.json"} {
+		p := filepath.Join(pkgDir, filepath.FromSlash(name))
+		f, err := os.OpenFile(p, os.O_APPEND|os.O_WRONLY, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.WriteString("\n"); err != nil {
+			t.Fatal(err)
+		}
+		f.Close()
+	}
+
+	pkg, err := Read(pkgDir, WithChangedSince("HEAD"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantChanged := []string{"data_stream/logs/fields/fields.yml", "kibana/dashboard/overview.json"}
+	if !slices.Equal(pkg.ChangedFiles, wantChanged) {
+		t.Errorf("ChangedFiles = %v, want %v", pkg.ChangedFiles, wantChanged)
+	}
+
+	// The manifest is always loaded.
+	if m := pkg.Manifest(); m == nil || m.Name == "" {
+		t.Fatal("manifest not loaded")
+	}
+
+	// The changed data stream is loaded in full; the unchanged one is omitted.
+	if got := slices.Sorted(maps.Keys(pkg.DataStreams)); !slices.Equal(got, []string{"logs"}) {
+		t.Errorf("DataStreams = %v, want [logs]", got)
+	}
+	if ds := pkg.DataStreams["logs"]; ds == nil || len(ds.Pipelines) == 0 {
+		t.Error("logs data stream pipelines not loaded")
+	}
+
+	if len(pkg.KibanaObjects["dashboard"]) != 1 {
+		t.Errorf("dashboards = %d, want 1", len(pkg.KibanaObjects["dashboard"]))
+	}
+
+	// Unchanged components are left nil.
+	if pkg.Changelog != nil || pkg.Validation != nil || pkg.Build != nil || pkg.Tags != nil {
+		t.Error("unchanged root files were loaded")
+	}
+	if pkg.Pipelines != nil || pkg.Transforms != nil {
+		t.Error("unchanged pipelines or transforms were loaded")
+	}
+
+	// Nothing changed since HEAD after committing.
+	git("add", "-A")
+	git("commit", "-q", "-m", "update")
+	pkg, err = Read(pkgDir, WithChangedSince("HEAD"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pkg.ChangedFiles) != 0 || len(pkg.DataStreams) != 0 {
+		t.Errorf("ChangedFiles = %v, DataStreams = %d, want none", pkg.ChangedFiles, len(pkg.DataStreams))
+	}
+}
@@ -76,20 +76,21 @@ func parseCodeowners(path string) (*codeownersFile, error) {
 	return &codeownersFile{rules: rules}, nil
 }
 
-// matchOwner returns the first owner from the last matching rule for the
-// given file path, or an empty string if no rule matches.
+// matchOwner returns all owners from the last matching rule for the given
+// file path, in the order they appear on the line, or nil if no rule
+// matches.
 //
 // CODEOWNERS uses last-match-wins semantics. Pattern matching covers
 // simple path prefixes used in the integrations CODEOWNERS file
 // (e.g. "/packages/aws" matches "/packages/aws/data_stream/cloudtrail").
-func (cf *codeownersFile) matchOwner(filePath string) string {
-	var lastOwner string
+func (cf *codeownersFile) matchOwner(filePath string) []string {
+	var lastOwners []string
 	for _, rule := range cf.rules {
 		if matchPattern(rule.pattern, filePath) && len(rule.owners) > 0 {
-			lastOwner = rule.owners[0]
+			lastOwners = rule.owners
 		}
 	}
-	return lastOwner
+	return lastOwners
 }
 
 // matchPattern checks if filePath matches a CODEOWNERS pattern.
@@ -3,6 +3,7 @@ package pkgreader
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 )
 
@@ -64,39 +65,39 @@ func TestMatchOwner(t *testing.T) {
 	tests := []struct {
 		name     string
 		path     string
-		expected string
+		expected []string
 	}{
 		{
 			name:     "exact data stream match",
 			path:     "/packages/aws/data_stream/cloudtrail",
-			expected: "elastic/security-service-integrations",
+			expected: []string{"elastic/security-service-integrations"},
 		},
 		{
 			name:     "prefix match for other data stream falls back to package rule",
 			path:     "/packages/aws/data_stream/s3access",
-			expected: "elastic/obs-ds-hosted-services",
+			expected: []string{"elastic/obs-ds-hosted-services", "elastic/security-service-integrations"},
 		},
 		{
 			name:     "prefix match with deeper path",
 			path:     "/packages/aws/data_stream/cloudtrail/fields/base.yml",
-			expected: "elastic/security-service-integrations",
+			expected: []string{"elastic/security-service-integrations"},
 		},
 		{
 			name:     "nginx data stream",
 			path:     "/packages/nginx/data_stream/access",
-			expected: "elastic/obs-ds-hosted-services",
+			expected: []string{"elastic/obs-ds-hosted-services"},
 		},
 		{
 			name:     "no match returns empty",
 			path:     "/some/other/path",
-			expected: "",
+			expected: nil,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			got := cf.matchOwner(tt.path)
-			if got != tt.expected {
+			if !slices.Equal(got, tt.expected) {
 				t.Errorf("matchOwner(%q) = %q, want %q", tt.path, got, tt.expected)
 			}
 		})
@@ -183,6 +184,35 @@ func TestReadAppliesCodeownersForFlatPackage(t *testing.T) {
 	}
 }
 
+func TestReadAppliesCodeownersWithMultipleOwners(t *testing.T) {
+	pkgDir := t.TempDir()
+	writePackageWithDataStream(t, pkgDir, "aws", "cloudtrail")
+	codeowners := writeTemp(t, `
+* @elastic/integrations
+/packages/aws/data_stream/cloudtrail @elastic/security-team @elastic/obs-team
+`)
+
+	pkg, err := Read(
+		pkgDir,
+		WithCodeowners(codeowners),
+		WithRepoRelativePath("packages/aws"),
+	)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+
+	ds := pkg.DataStreams["cloudtrail"]
+	if ds == nil {
+		t.Fatal("data stream cloudtrail not loaded")
+	}
+	if got, want := ds.Manifest.GithubCodeOwners, []string{"elastic/security-team", "elastic/obs-team"}; !slices.Equal(got, want) {
+		t.Errorf("GithubCodeOwners = %q, want %q", got, want)
+	}
+	if got, want := ds.Manifest.GithubCodeOwner, "elastic/security-team"; got != want {
+		t.Errorf("GithubCodeOwner = %q, want %q", got, want)
+	}
+}
+
 func TestReadAppliesCodeownersForNestedPackage(t *testing.T) {
 	// A nested package layout — packages/microsoft/defender_endpoint —
 	// must look up CODEOWNERS using the full repo-relative path, not the
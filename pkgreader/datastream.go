@@ -5,8 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"io/fs"
+	"maps"
+	"os"
 	"path"
+	"path/filepath"
+	"reflect"
+	"slices"
 	"strings"
+	"sync"
 
 	"github.com/andrewkroh/go-package-spec/pkgspec"
 )
@@ -31,11 +37,59 @@ func (ds *DataStream) Path() string {
 	return ds.path
 }
 
+// Dataset returns the effective dataset name of the data stream. This is the
+// dataset declared in the data stream manifest when set, otherwise
+// "<packageName>.<data stream directory name>".
+func (ds *DataStream) Dataset(packageName string) string {
+	if ds.Manifest.Dataset != "" {
+		return ds.Manifest.Dataset
+	}
+	return packageName + "." + path.Base(ds.path)
+}
+
+// StreamType returns the data stream type declared in the manifest (e.g.
+// "logs", "metrics", or "traces"). It returns "" when the manifest does not
+// declare a type.
+func (ds *DataStream) StreamType() string {
+	return string(ds.Manifest.Type)
+}
+
+// IndexTemplateName returns the name of the index template Fleet installs
+// for the data stream, "<type>-<dataset>" (e.g. "logs-nginx.access"). The
+// dataset is resolved as by [DataStream.Dataset]. It returns "" when the
+// manifest does not declare a type.
+func (ds *DataStream) IndexTemplateName(packageName string) string {
+	if ds.Manifest.Type == "" {
+		return ""
+	}
+	return string(ds.Manifest.Type) + "-" + ds.Dataset(packageName)
+}
+
+// StreamTemplatePath returns the package-relative path of the agent template
+// used by the stream at streamIndex in the manifest's streams list (e.g.
+// "data_stream/logs/agent/stream/stream.yml.hbs"). The stream's
+// template_path is resolved within the data stream's agent/stream/ directory
+// and defaults to "stream.yml.hbs" when unset. It returns "" when
+// streamIndex is out of range.
+func (ds *DataStream) StreamTemplatePath(streamIndex int) string {
+	if streamIndex < 0 || streamIndex >= len(ds.Manifest.Streams) {
+		return ""
+	}
+	templatePath := ds.Manifest.Streams[streamIndex].TemplatePath
+	if templatePath == "" {
+		templatePath = "stream.yml.hbs"
+	}
+	return path.Join("data_stream", path.Base(ds.path), "agent", "stream", templatePath)
+}
+
 // AllFields returns all fields from all field files in the data stream.
+// Files are visited in sorted filename order (e.g. base-fields.yml, ecs.yml,
+// fields.yml) and fields keep their declaration order within each file, so
+// the result is the same on every call.
 func (ds *DataStream) AllFields() []pkgspec.Field {
 	var all []pkgspec.Field
-	for _, ff := range ds.Fields {
-		all = append(all, ff.Fields...)
+	for _, name := range slices.Sorted(maps.Keys(ds.Fields)) {
+		all = append(all, ds.Fields[name].Fields...)
 	}
 	return all
 }
@@ -104,6 +158,90 @@ func readDataStreams(fsys fs.FS, root string, cfg *config) (map[string]*DataStre
 	return result, nil
 }
 
+// ReadDataStream loads a single data stream directory without reading the
+// enclosing package, for tools that operate on one data stream at a time.
+// dsPath is the data stream directory containing its manifest.yml. The data
+// stream is read as if it were data_stream/<name> of a package, where name
+// is the base name of dsPath, so Path and all FileMetadata file paths match
+// those produced by [Read] (plus the WithPathPrefix prefix, if set).
+//
+// Options are applied as in [Read]. Options that need the whole package
+// (WithGitMetadata, WithCodeowners, WithNodePositions, WithChangedSince,
+// WithImageMetadata, WithDevConfigs, and WithTypeInference) have no effect.
+func ReadDataStream(dsPath string, opts ...Option) (*DataStream, error) {
+	cfg := &config{
+		packagePath: dsPath,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	var root string
+	switch {
+	case cfg.fsys != nil:
+		root = dsPath
+	case cfg.followSymlinks:
+		fsys, err := newSymlinkFS(dsPath, cfg.symlinkRoot)
+		if err != nil {
+			return nil, err
+		}
+		cfg.fsys = fsys
+		root = "."
+	default:
+		cfg.fsys = os.DirFS(dsPath)
+		root = "."
+	}
+
+	name := filepath.Base(filepath.Clean(dsPath))
+	mount := path.Join("data_stream", name)
+	ds, err := readDataStream(&mountFS{fsys: cfg.fsys, root: root, mount: mount}, mount, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("reading data stream %s: %w", name, err)
+	}
+
+	if cfg.pathPrefix != "" {
+		pkgspec.PrefixFileMetadata(cfg.pathPrefix, ds)
+	}
+	return ds, nil
+}
+
+// mountFS exposes the directory root of fsys at the path mount. Paths
+// outside of mount do not exist.
+type mountFS struct {
+	fsys  fs.FS
+	root  string
+	mount string
+}
+
+// resolve maps name to the corresponding path in the underlying fsys.
+func (m *mountFS) resolve(op, name string) (string, error) {
+	if name == m.mount {
+		return m.root, nil
+	}
+	if rel, ok := strings.CutPrefix(name, m.mount+"/"); ok {
+		return path.Join(m.root, rel), nil
+	}
+	return "", &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+}
+
+// Open implements [fs.FS].
+func (m *mountFS) Open(name string) (fs.File, error) {
+	p, err := m.resolve("open", name)
+	if err != nil {
+		return nil, err
+	}
+	return m.fsys.Open(p)
+}
+
+// ReadDir implements [fs.ReadDirFS].
+func (m *mountFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	p, err := m.resolve("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+	return fs.ReadDir(m.fsys, p)
+}
+
 func readDataStream(fsys fs.FS, dsPath string, cfg *config) (*DataStream, error) {
 	ds := &DataStream{
 		path: dsPath,
@@ -164,14 +302,14 @@ func readDataStream(fsys fs.FS, dsPath string, cfg *config) (*DataStream, error)
 
 	// Read sample event (optional).
 	sampleEventPath := path.Join(dsPath, "sample_event.json")
-	sampleEvent, err := readOptionalFile(fsys, sampleEventPath)
+	sampleEvent, err := readSampleEvent(fsys, sampleEventPath, cfg.strictSampleEvent)
 	if err != nil {
 		return nil, fmt.Errorf("reading sample event: %w", err)
 	}
 	ds.SampleEvent = sampleEvent
 
 	// Read named sample events: sample_event_<name>.json (optional).
-	namedSampleEvents, err := readNamedSampleEvents(fsys, dsPath)
+	namedSampleEvents, err := readNamedSampleEvents(fsys, dsPath, cfg.strictSampleEvent)
 	if err != nil {
 		return nil, fmt.Errorf("reading named sample events: %w", err)
 	}
@@ -208,7 +346,7 @@ func readFieldsDir(fsys fs.FS, dir string, cfg *config) (map[string]*FieldsFile,
 		return nil, fmt.Errorf("reading fields directory %s: %w", dir, err)
 	}
 
-	result := make(map[string]*FieldsFile, len(entries))
+	var names []string
 	for _, entry := range entries {
 		if entry.IsDir() {
 			continue
@@ -217,13 +355,41 @@ func readFieldsDir(fsys fs.FS, dir string, cfg *config) (map[string]*FieldsFile,
 		if !strings.HasSuffix(name, ".yml") && !strings.HasSuffix(name, ".yaml") {
 			continue
 		}
+		names = append(names, name)
+	}
 
-		filePath := path.Join(dir, name)
-		ff, err := readFieldsFile(fsys, filePath, cfg)
-		if err != nil {
-			return nil, fmt.Errorf("reading fields file %s: %w", name, err)
+	files := make([]*FieldsFile, len(names))
+	errs := make([]error, len(names))
+	read := func(i int) {
+		files[i], errs[i] = readFieldsFile(fsys, path.Join(dir, names[i]), cfg)
+	}
+	if cfg.concurrency <= 1 || len(names) <= 1 {
+		for i := range names {
+			if read(i); errs[i] != nil {
+				break
+			}
+		}
+	} else {
+		sem := make(chan struct{}, cfg.concurrency)
+		var wg sync.WaitGroup
+		for i := range names {
+			sem <- struct{}{}
+			wg.Go(func() {
+				defer func() { <-sem }()
+				read(i)
+			})
+		}
+		wg.Wait()
+	}
+
+	// Report the first failure in directory order so errors do not depend
+	// on scheduling.
+	result := make(map[string]*FieldsFile, len(names))
+	for i, name := range names {
+		if errs[i] != nil {
+			return nil, fmt.Errorf("reading fields file %s: %w", name, errs[i])
 		}
-		result[name] = ff
+		result[name] = files[i]
 	}
 
 	return result, nil
@@ -236,6 +402,9 @@ func readFieldsFile(fsys fs.FS, filePath string, cfg *config) (*FieldsFile, erro
 	}
 
 	pkgspec.AnnotateFileMetadata(filePath, &fields)
+	if cfg.fieldResolver != nil {
+		resolveExternalFields(fields, "", cfg.fieldResolver)
+	}
 	pkgspec.AnnotateFieldPointers(fields)
 
 	return &FieldsFile{
@@ -244,6 +413,93 @@ func readFieldsFile(fsys fs.FS, filePath string, cfg *config) (*FieldsFile, erro
 	}, nil
 }
 
+// resolveExternalFields walks fields and merges the definition returned by
+// resolve into each field that declares an external source. The prefix is
+// the dotted name of the enclosing group.
+func resolveExternalFields(fields []pkgspec.Field, prefix string, resolve func(ref string) (*pkgspec.Field, bool)) {
+	for i := range fields {
+		f := &fields[i]
+		name := f.Name
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+
+		if f.External != "" {
+			if ref, ok := resolve(name); ok && ref != nil {
+				mergeField(f, ref)
+			}
+		}
+
+		if len(f.Fields) > 0 {
+			resolveExternalFields(f.Fields, name, resolve)
+		}
+	}
+}
+
+// mergeField copies each attribute of src into dst where dst holds the zero
+// value. Identity and location attributes (name, external, nested fields,
+// file metadata, and JSON pointer) are never copied. Values are deep-copied
+// so that dst does not share pointers, slices, or maps with src.
+func mergeField(dst, src *pkgspec.Field) {
+	dv := reflect.ValueOf(dst).Elem()
+	sv := reflect.ValueOf(src).Elem()
+	for i := range dv.NumField() {
+		switch dv.Type().Field(i).Name {
+		case "FileMetadata", "Name", "External", "Fields", "JsonPointer":
+			continue
+		}
+
+		d, s := dv.Field(i), sv.Field(i)
+		if !d.IsZero() || s.IsZero() {
+			continue
+		}
+		d.Set(deepCopy(s))
+	}
+}
+
+// deepCopy returns a copy of v that shares no pointers, slices, or maps with
+// it. Unexported struct fields, such as those of FileMetadata, are copied
+// shallowly.
+func deepCopy(v reflect.Value) reflect.Value {
+	c := reflect.New(v.Type()).Elem()
+	switch v.Kind() {
+	case reflect.Pointer:
+		if !v.IsNil() {
+			p := reflect.New(v.Type().Elem())
+			p.Elem().Set(deepCopy(v.Elem()))
+			c.Set(p)
+		}
+	case reflect.Interface:
+		if !v.IsNil() {
+			c.Set(deepCopy(v.Elem()))
+		}
+	case reflect.Slice:
+		if !v.IsNil() {
+			c.Set(reflect.MakeSlice(v.Type(), v.Len(), v.Len()))
+			for i := range v.Len() {
+				c.Index(i).Set(deepCopy(v.Index(i)))
+			}
+		}
+	case reflect.Map:
+		if !v.IsNil() {
+			c.Set(reflect.MakeMapWithSize(v.Type(), v.Len()))
+			for iter := v.MapRange(); iter.Next(); {
+				c.SetMapIndex(iter.Key(), deepCopy(iter.Value()))
+			}
+		}
+	case reflect.Struct:
+		c.Set(v)
+		for i := range v.NumField() {
+			if v.Type().Field(i).IsExported() {
+				c.Field(i).Set(deepCopy(v.Field(i)))
+			}
+		}
+	default:
+		c.Set(v)
+	}
+	return c
+}
+
 func readPipelines(fsys fs.FS, dir string) (map[string]*PipelineFile, error) {
 	entries, err := fs.ReadDir(fsys, dir)
 	if err != nil {
@@ -333,8 +589,9 @@ func readILMPolicies(fsys fs.FS, dir string) (map[string]*ILMPolicy, error) {
 
 // readNamedSampleEvents scans dir for files named "sample_event_<name>.json"
 // and returns a map keyed by the name suffix. The unnamed "sample_event.json"
-// (handled separately) is not included.
-func readNamedSampleEvents(fsys fs.FS, dir string) (map[string]json.RawMessage, error) {
+// (handled separately) is not included. When strict is set, each file must
+// contain valid JSON.
+func readNamedSampleEvents(fsys fs.FS, dir string, strict bool) (map[string]json.RawMessage, error) {
 	entries, err := fs.ReadDir(fsys, dir)
 	if err != nil {
 		if isNotExist(err) {
@@ -364,6 +621,11 @@ func readNamedSampleEvents(fsys fs.FS, dir string) (map[string]json.RawMessage,
 		if err != nil {
 			return nil, fmt.Errorf("reading %s: %w", name, err)
 		}
+		if strict {
+			if err := validateJSON(path.Join(dir, name), data); err != nil {
+				return nil, err
+			}
+		}
 		if result == nil {
 			result = make(map[string]json.RawMessage)
 		}
@@ -373,6 +635,29 @@ func readNamedSampleEvents(fsys fs.FS, dir string) (map[string]json.RawMessage,
 	return result, nil
 }
 
+// readSampleEvent reads an optional sample event file. When strict is set,
+// the file must contain valid JSON.
+func readSampleEvent(fsys fs.FS, filePath string, strict bool) (json.RawMessage, error) {
+	data, err := readOptionalFile(fsys, filePath)
+	if err != nil || data == nil || !strict {
+		return data, err
+	}
+	if err := validateJSON(filePath, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// validateJSON returns an error identifying filePath if data is not valid
+// JSON.
+func validateJSON(filePath string, data []byte) error {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("%s is not valid JSON: %w", filePath, err)
+	}
+	return nil
+}
+
 func readOptionalFile(fsys fs.FS, filePath string) ([]byte, error) {
 	data, err := fs.ReadFile(fsys, filePath)
 	if err != nil {
@@ -0,0 +1,57 @@
+package pkgreader
+
+import (
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// modeledDevDirs are the _dev/ subdirectories whose contents are already
+// loaded into typed fields (Build, Docs, and the test configs), so they are
+// not listed in DevConfigs.
+var modeledDevDirs = map[string]bool{
+	"build": true,
+	"test":  true,
+}
+
+// readDevConfigs lists the files under root/_dev/, grouped by the name of
+// their top-level subdirectory (e.g. "benchmark", "deploy", "profile").
+// Files directly in _dev/ and the subdirectories in modeledDevDirs are
+// skipped. Paths within each group are sorted. It returns nil, nil if there
+// is no _dev/ directory or it holds no other files.
+func readDevConfigs(fsys fs.FS, root string) (map[string][]string, error) {
+	devDir := path.Join(root, "_dev")
+
+	var result map[string][]string
+	err := fs.WalkDir(fsys, devDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == devDir && isNotExist(err) {
+				return fs.SkipAll
+			}
+			return err
+		}
+
+		rel := strings.TrimPrefix(p, devDir+"/")
+		kind, _, nested := strings.Cut(rel, "/")
+		if d.IsDir() {
+			if p != devDir && modeledDevDirs[kind] {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !nested {
+			return nil
+		}
+
+		if result == nil {
+			result = make(map[string][]string)
+		}
+		// WalkDir visits entries in lexical order, so each group is sorted.
+		result[kind] = append(result[kind], p)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -18,11 +18,16 @@ const (
 
 	// DocContentTypeKnowledgeBase is a file in the docs/knowledge_base/ directory.
 	DocContentTypeKnowledgeBase DocContentType = "knowledge_base"
+
+	// DocContentTypeTemplate is a source template in the _dev/build/docs/
+	// directory. The files in docs/ with the same name are rendered from
+	// these templates, so edits belong in the template.
+	DocContentTypeTemplate DocContentType = "template"
 )
 
 // DocFile represents a documentation file within a package.
 type DocFile struct {
-	// ContentType classifies the file (readme, doc, or knowledge_base).
+	// ContentType classifies the file (readme, doc, knowledge_base, or template).
 	ContentType DocContentType
 	path        string // display path (may include WithPathPrefix)
 	fsPath      string // original path within the fs.FS (for reading content)
@@ -37,11 +42,26 @@ func (d *DocFile) Path() string { return d.path }
 // of any WithPathPrefix setting.
 func (d *DocFile) FSPath() string { return d.fsPath }
 
-// readDocs discovers markdown documentation files under root/docs/.
-// It returns nil, nil if the docs/ directory does not exist.
+// readDocs discovers markdown documentation files under root/docs/ and
+// their source templates under root/_dev/build/docs/. It returns nil, nil if
+// neither directory exists.
 func readDocs(fsys fs.FS, root string) ([]*DocFile, error) {
-	docsDir := path.Join(root, "docs")
+	docs, err := readDocsDir(fsys, path.Join(root, "docs"))
+	if err != nil {
+		return nil, err
+	}
+
+	templates, err := readTemplateDocs(fsys, path.Join(root, "_dev", "build", "docs"))
+	if err != nil {
+		return nil, err
+	}
 
+	return append(docs, templates...), nil
+}
+
+// readDocsDir reads markdown files from the docs/ directory, recursing only
+// into docs/knowledge_base/.
+func readDocsDir(fsys fs.FS, docsDir string) ([]*DocFile, error) {
 	entries, err := fs.ReadDir(fsys, docsDir)
 	if err != nil {
 		if isNotExist(err) {
@@ -85,6 +105,17 @@ func readDocs(fsys fs.FS, root string) ([]*DocFile, error) {
 
 // readKnowledgeBaseDocs reads markdown files from the docs/knowledge_base/ directory.
 func readKnowledgeBaseDocs(fsys fs.FS, dir string) ([]*DocFile, error) {
+	return readFlatDocs(fsys, dir, DocContentTypeKnowledgeBase)
+}
+
+// readTemplateDocs reads markdown templates from the _dev/build/docs/ directory.
+func readTemplateDocs(fsys fs.FS, dir string) ([]*DocFile, error) {
+	return readFlatDocs(fsys, dir, DocContentTypeTemplate)
+}
+
+// readFlatDocs reads the markdown files directly within dir, classifying
+// each with ct. Subdirectories are not recursed.
+func readFlatDocs(fsys fs.FS, dir string, ct DocContentType) ([]*DocFile, error) {
 	entries, err := fs.ReadDir(fsys, dir)
 	if err != nil {
 		if isNotExist(err) {
@@ -100,7 +131,7 @@ func readKnowledgeBaseDocs(fsys fs.FS, dir string) ([]*DocFile, error) {
 		}
 		p := path.Join(dir, entry.Name())
 		docs = append(docs, &DocFile{
-			ContentType: DocContentTypeKnowledgeBase,
+			ContentType: ct,
 			path:        p,
 			fsPath:      p,
 		})
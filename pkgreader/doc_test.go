@@ -83,3 +83,46 @@ func TestReadDocsNoDocs(t *testing.T) {
 		t.Errorf("expected nil docs when docs/ doesn't exist, got %v", docs)
 	}
 }
+
+func TestReadDocsTemplates(t *testing.T) {
+	fsys := fstest.MapFS{
+		"docs/README.md":                 {Data: []byte("# Rendered\n")},
+		"_dev/build/docs/README.md":      {Data: []byte("# Template\n{{fields \"logs\"}}\n")},
+		"_dev/build/docs/extra.md":       {Data: []byte("# Extra template\n")},
+		"_dev/build/docs/notes.txt":      {Data: []byte("ignored")},
+		"_dev/build/docs/subdir/deep.md": {Data: []byte("ignored")},
+	}
+
+	docs, err := readDocs(fsys, ".")
+	if err != nil {
+		t.Fatalf("readDocs: %v", err)
+	}
+
+	got := make(map[string]DocContentType, len(docs))
+	for _, d := range docs {
+		got[d.Path()] = d.ContentType
+	}
+	want := map[string]DocContentType{
+		"docs/README.md":            DocContentTypeReadme,
+		"_dev/build/docs/README.md": DocContentTypeTemplate,
+		"_dev/build/docs/extra.md":  DocContentTypeTemplate,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got docs %v, want %v", got, want)
+	}
+	for p, ct := range want {
+		if got[p] != ct {
+			t.Errorf("%s: content type = %q, want %q", p, got[p], ct)
+		}
+	}
+
+	// Templates are found even without a docs/ directory.
+	delete(fsys, "docs/README.md")
+	docs, err = readDocs(fsys, ".")
+	if err != nil {
+		t.Fatalf("readDocs: %v", err)
+	}
+	if len(docs) != 2 {
+		t.Errorf("expected 2 template docs, got %d", len(docs))
+	}
+}
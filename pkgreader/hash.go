@@ -0,0 +1,63 @@
+package pkgreader
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ContentHash returns a hex-encoded SHA-256 digest of the package manifest,
+// changelog, fields files (of data streams, input packages, and
+// transforms), and ingest pipelines. Build systems can use it to skip
+// reprocessing a package whose content has not changed.
+//
+// Each file contributes the digest of its loaded value re-encoded as YAML
+// together with its package-relative path, and the file digests are
+// combined in path order, so the hash is deterministic across runs and
+// does not depend on map iteration order or WithPathPrefix. Because the
+// loaded values are hashed rather than the raw bytes, comment and
+// formatting changes do not alter the hash, while options that modify the
+// loaded fields (WithFieldResolver, WithTypeInference) do.
+func (p *Package) ContentHash() string {
+	files := map[string]any{
+		"manifest.yml":  p.manifest,
+		"changelog.yml": p.Changelog,
+	}
+	addFields := func(fields map[string]*FieldsFile) {
+		for _, ff := range fields {
+			files[ff.Path()] = ff.Fields
+		}
+	}
+	addPipelines := func(pipelines map[string]*PipelineFile) {
+		for _, pf := range pipelines {
+			files[pf.Path()] = &pf.Pipeline
+		}
+	}
+	for _, ds := range p.DataStreams {
+		addFields(ds.Fields)
+		addPipelines(ds.Pipelines)
+	}
+	addFields(p.Fields)
+	addPipelines(p.Pipelines)
+	for _, td := range p.Transforms {
+		addFields(td.Fields)
+	}
+
+	h := sha256.New()
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		data, err := yaml.Marshal(files[name])
+		if err != nil {
+			// The values were decoded from YAML and encode back to it.
+			panic(fmt.Sprintf("encoding %s for content hash: %v", name, err))
+		}
+		sum := sha256.Sum256(data)
+		h.Write([]byte(name))
+		h.Write([]byte{0})
+		h.Write(sum[:])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
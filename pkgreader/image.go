@@ -9,8 +9,11 @@ import (
 	"path"
 	"strings"
 
+	_ "image/gif"  // Register GIF decoder.
 	_ "image/jpeg" // Register JPEG decoder.
 	_ "image/png"  // Register PNG decoder.
+
+	_ "golang.org/x/image/webp" // Register WebP decoder.
 )
 
 // ImageFile represents an image file with metadata extracted from its contents.
@@ -22,21 +25,35 @@ type ImageFile struct {
 	path     string
 }
 
-// Path returns the file path relative to the package root.
+// Path returns the file path relative to the package root (e.g.
+// img/icon.png). Unlike other file paths it is never prefixed by
+// WithPathPrefix or the package directory within the filesystem, so that
+// "/" + Path() always matches the src of manifest icons and screenshots.
 func (img *ImageFile) Path() string {
 	return img.path
 }
 
-// supportedImageExt reports whether the file extension is a supported image format.
-func supportedImageExt(name string) bool {
-	lower := strings.ToLower(name)
-	return strings.HasSuffix(lower, ".png") ||
-		strings.HasSuffix(lower, ".jpg") ||
-		strings.HasSuffix(lower, ".jpeg") ||
-		strings.HasSuffix(lower, ".svg")
+// imageExtensions lists the file extensions that readImages treats as
+// images. Only some have a registered decoder; the others are recorded
+// without dimensions.
+var imageExtensions = map[string]bool{
+	".avif": true,
+	".bmp":  true,
+	".gif":  true,
+	".ico":  true,
+	".jpeg": true,
+	".jpg":  true,
+	".png":  true,
+	".svg":  true,
+	".tif":  true,
+	".tiff": true,
+	".webp": true,
 }
 
-func readImages(fsys fs.FS, dir string) (map[string]*ImageFile, error) {
+// readImages reads the img/ directory of the package at root within fsys.
+// Files without an image extension (e.g. a README or license) are skipped.
+func readImages(fsys fs.FS, root string) (map[string]*ImageFile, error) {
+	dir := path.Join(root, "img")
 	entries, err := fs.ReadDir(fsys, dir)
 	if err != nil {
 		if isNotExist(err) {
@@ -51,7 +68,7 @@ func readImages(fsys fs.FS, dir string) (map[string]*ImageFile, error) {
 			continue
 		}
 		name := entry.Name()
-		if !supportedImageExt(name) {
+		if strings.HasPrefix(name, ".") || !imageExtensions[strings.ToLower(path.Ext(name))] {
 			continue
 		}
 
@@ -64,7 +81,7 @@ func readImages(fsys fs.FS, dir string) (map[string]*ImageFile, error) {
 
 		img := &ImageFile{
 			ByteSize: info.Size(),
-			path:     filePath,
+			path:     path.Join("img", name),
 		}
 
 		// Compute SHA-256 hash.
@@ -74,7 +91,8 @@ func readImages(fsys fs.FS, dir string) (map[string]*ImageFile, error) {
 		}
 		img.SHA256 = hash
 
-		// Decode dimensions for raster formats (not SVG).
+		// Decode dimensions for raster formats (not SVG). Formats without a
+		// registered decoder keep zero dimensions.
 		if !strings.HasSuffix(strings.ToLower(name), ".svg") {
 			w, h, decodeErr := decodeImageDimensions(fsys, filePath)
 			if decodeErr == nil {
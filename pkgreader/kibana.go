@@ -6,13 +6,20 @@ import (
 	"io/fs"
 	"path"
 	"strings"
+
+	"github.com/andrewkroh/go-package-spec/pkgspec"
 )
 
 // KibanaSavedObject represents a Kibana saved object loaded from a JSON file
 // in the kibana/ directory. Common top-level fields are typed, while
 // type-specific attributes are partially decoded to capture title and
 // description with remaining fields stored in Extras.
+//
+// The embedded FileMetadata records the source file path. Line and column
+// are not tracked because the objects are decoded from JSON.
 type KibanaSavedObject struct {
+	pkgspec.FileMetadata `json:"-"`
+
 	// ID is the unique identifier for the saved object.
 	ID string `json:"id"`
 	// Type is the object type (e.g. "dashboard", "visualization", "search").
@@ -45,7 +52,10 @@ type KibanaSavedObject struct {
 	Namespaces []string `json:"namespaces,omitempty"`
 	// OriginID is the identifier for the original object in cross-space copies.
 	OriginID string `json:"originId,omitempty"`
-	path     string
+	// Raw is the original content of the JSON file, retained so that the
+	// object can be re-exported without reading the file again.
+	Raw  json.RawMessage `json:"-"`
+	path string
 }
 
 // Path returns the file path relative to the package root.
@@ -53,6 +63,94 @@ func (o *KibanaSavedObject) Path() string {
 	return o.path
 }
 
+// SecurityRule decodes the attributes of a detection rule (object type
+// "security-rule") into a [pkgspec.SecurityRule]. It returns false if the
+// attributes have no rule_id. The object type is not checked, so callers
+// decide which objects hold rules (e.g. those in kibana/security_rule).
+//
+// Each attribute is decoded on its own and a value that does not match the
+// expected type is left unset, so one malformed attribute does not drop the
+// rest of the rule. Elements of list attributes (index, tags, threat,
+// required_fields) are decoded individually and mismatched ones are
+// skipped.
+func (o *KibanaSavedObject) SecurityRule() (*pkgspec.SecurityRule, bool) {
+	attrs := o.Attributes.Extras
+	ruleID, _ := attrs["rule_id"].(string)
+	if ruleID == "" {
+		return nil, false
+	}
+
+	rule := &pkgspec.SecurityRule{
+		RuleID:         ruleID,
+		Description:    o.Attributes.Description,
+		Index:          decodeEach[string](attrs["index"]),
+		Tags:           decodeEach[string](attrs["tags"]),
+		Threat:         decodeEach[pkgspec.SecurityRuleThreat](attrs["threat"]),
+		RequiredFields: decodeEach[pkgspec.SecurityRuleRequiredField](attrs["required_fields"]),
+	}
+	rule.Name, _ = attrs["name"].(string)
+	rule.Type, _ = attrs["type"].(string)
+	rule.Language, _ = attrs["language"].(string)
+	rule.Query, _ = attrs["query"].(string)
+	rule.Severity, _ = attrs["severity"].(string)
+	if v, ok := attrs["risk_score"].(float64); ok {
+		rule.RiskScore = &v
+	}
+	return rule, true
+}
+
+// decodeEach decodes each element of a JSON array value into a T, skipping
+// elements whose shape does not match T. It returns nil if v is not an
+// array.
+func decodeEach[T any](v any) []T {
+	items, ok := v.([]any)
+	if !ok {
+		return nil
+	}
+	var out []T
+	for _, item := range items {
+		data, err := json.Marshal(item)
+		if err != nil {
+			continue
+		}
+		var t T
+		if err := json.Unmarshal(data, &t); err != nil {
+			continue
+		}
+		out = append(out, t)
+	}
+	return out
+}
+
+// ExceptionList decodes the attributes of an exception list or exception
+// list item (object type "exception-list" or "exception-list-agnostic") into
+// a [pkgspec.ExceptionList]. When the object does not declare list_type, an
+// object with an item_id is classified as an item and any other as a list.
+// It returns false if the object is not an exception list, has no list_id,
+// or its attributes do not match the expected types.
+func (o *KibanaSavedObject) ExceptionList() (*pkgspec.ExceptionList, bool) {
+	if (o.Type != "exception-list" && o.Type != "exception-list-agnostic") || o.Attributes.Extras == nil {
+		return nil, false
+	}
+
+	data, err := json.Marshal(o.Attributes.Extras)
+	if err != nil {
+		return nil, false
+	}
+	var list pkgspec.ExceptionList
+	if err := json.Unmarshal(data, &list); err != nil || list.ListID == "" {
+		return nil, false
+	}
+	list.Description = o.Attributes.Description
+	if list.ListType == "" {
+		list.ListType = pkgspec.ExceptionListTypeList
+		if list.ItemID != "" {
+			list.ListType = pkgspec.ExceptionListTypeItem
+		}
+	}
+	return &list, true
+}
+
 // KibanaSavedObjectAttributes holds the common attributes shared across all
 // Kibana saved object types. The Title and Description fields are extracted
 // from the attributes object, and all other fields are stored in Extras.
@@ -138,7 +236,9 @@ func readKibanaObjects(fsys fs.FS, root string) (map[string][]*KibanaSavedObject
 			if err := json.Unmarshal(data, &obj); err != nil {
 				return nil, fmt.Errorf("parsing kibana/%s/%s: %w", assetType, f.Name(), err)
 			}
+			obj.Raw = data
 			obj.path = filePath
+			pkgspec.AnnotateFileMetadata(filePath, &obj)
 
 			if result == nil {
 				result = make(map[string][]*KibanaSavedObject)
@@ -0,0 +1,100 @@
+package pkgreader
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Position is a 1-based line and column in a source file.
+type Position struct {
+	Line   int
+	Column int
+}
+
+// Positions records the source position of every YAML node in a set of
+// files. The outer key is the file path as reported by
+// [pkgspec.FileMetadata.FilePath]; the inner key is the RFC 6901 JSON
+// pointer of the node within the file (e.g. "/processors/0/set/field").
+// For a mapping entry the position is that of its value.
+//
+// Pointers line up with those produced elsewhere in the package: prefix
+// the path from [pkgspec.WalkProcessors] with "/processors" or
+// "/on_failure", or append an attribute name to [pkgspec.Field.JsonPointer].
+type Positions map[string]map[string]Position
+
+// Lookup returns the position of the node at pointer in file.
+func (p Positions) Lookup(file, pointer string) (Position, bool) {
+	pos, ok := p[file][pointer]
+	return pos, ok
+}
+
+// readPositions indexes the node positions of every fields file and ingest
+// pipeline in pkg. Files are keyed by their path joined to prefix.
+func readPositions(fsys fs.FS, pkg *Package, prefix string) (Positions, error) {
+	var files []string
+	for _, ff := range pkg.Fields {
+		files = append(files, ff.path)
+	}
+	for _, ds := range pkg.DataStreams {
+		for _, ff := range ds.Fields {
+			files = append(files, ff.path)
+		}
+	}
+	for _, td := range pkg.Transforms {
+		for _, ff := range td.Fields {
+			files = append(files, ff.path)
+		}
+	}
+	for _, pf := range pkg.AllPipelines() {
+		files = append(files, pf.path)
+	}
+
+	positions := make(Positions, len(files))
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", file, err)
+		}
+		var doc yaml.Node
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("decoding %s: %w", file, err)
+		}
+
+		index := map[string]Position{}
+		if len(doc.Content) > 0 {
+			indexNodePositions(doc.Content[0], "", index)
+		}
+		if prefix != "" {
+			file = path.Join(prefix, file)
+		}
+		positions[file] = index
+	}
+	return positions, nil
+}
+
+// indexNodePositions records the position of n under pointer and recurses
+// into its children.
+func indexNodePositions(n *yaml.Node, pointer string, index map[string]Position) {
+	index[pointer] = Position{Line: n.Line, Column: n.Column}
+
+	switch n.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			indexNodePositions(n.Content[i+1], pointer+"/"+escapePointerToken(n.Content[i].Value), index)
+		}
+	case yaml.SequenceNode:
+		for i, child := range n.Content {
+			indexNodePositions(child, pointer+"/"+strconv.Itoa(i), index)
+		}
+	}
+}
+
+// escapePointerToken escapes a JSON pointer reference token per RFC 6901.
+func escapePointerToken(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
@@ -15,10 +15,14 @@ import (
 	"errors"
 	"fmt"
 	"io/fs"
+	"iter"
+	"maps"
 	"os"
 	"path"
+	"slices"
 	"strings"
 
+	"github.com/andrewkroh/go-package-spec/internal/semver"
 	"github.com/andrewkroh/go-package-spec/pkgspec"
 )
 
@@ -47,8 +51,14 @@ type Package struct {
 	InputTestConfig *pkgspec.InputTestConfig // type:input only, nil unless WithTestConfigs used
 	InputTests      *InputPackageTests       // type:input only, nil unless WithTestConfigs used
 
+	DevConfigs map[string][]string // _dev/ file paths keyed by subdirectory (e.g. "benchmark"), nil unless WithDevConfigs used
+
 	Commit string // git HEAD commit ID, empty unless WithGitMetadata used
 
+	ChangedFiles []string // files changed since the base ref, sorted, nil unless WithChangedSince used
+
+	Positions Positions // node positions in fields and pipeline files, nil unless WithNodePositions used
+
 	path string
 }
 
@@ -70,6 +80,75 @@ func (p *Package) Manifest() *pkgspec.Manifest {
 	return nil
 }
 
+// ExcludedChecks returns the validation check codes listed under
+// errors.exclude_checks in validation.yml, or nil if the package has no
+// validation file or excludes no checks.
+func (p *Package) ExcludedChecks() []string {
+	if p.Validation == nil {
+		return nil
+	}
+	return slices.Clone(p.Validation.Errors.ExcludeChecks)
+}
+
+// SpecCompatible reports whether the package's format_version is no newer
+// than [pkgspec.SpecVersion], the package-spec version the pkgspec types were
+// generated from. A false result means the package may use attributes that
+// this library does not model, so they are silently dropped when reading.
+// An error is returned if the package has no manifest or either version is
+// not a valid semantic version.
+func (p *Package) SpecCompatible() (bool, error) {
+	m := p.Manifest()
+	if m == nil {
+		return false, errors.New("package has no manifest")
+	}
+	formatVersion, err := semver.Parse(m.FormatVersion)
+	if err != nil {
+		return false, fmt.Errorf("parsing format_version: %w", err)
+	}
+	specVersion, err := semver.Parse(pkgspec.SpecVersion)
+	if err != nil {
+		return false, fmt.Errorf("parsing spec version: %w", err)
+	}
+	return formatVersion.Compare(specVersion) <= 0, nil
+}
+
+// AllPipelines returns an iterator over every ingest pipeline in the package,
+// both package-level (elasticsearch/ingest_pipeline/) and per data stream,
+// in order of qualified name. The name is "<data_stream>/<file>" for data
+// stream pipelines and "_package/<file>" for package-level pipelines.
+func (p *Package) AllPipelines() iter.Seq2[string, *PipelineFile] {
+	all := make(map[string]*PipelineFile, len(p.Pipelines))
+	for file, pf := range p.Pipelines {
+		all["_package/"+file] = pf
+	}
+	for dsName, ds := range p.DataStreams {
+		for file, pf := range ds.Pipelines {
+			all[dsName+"/"+file] = pf
+		}
+	}
+
+	return func(yield func(string, *PipelineFile) bool) {
+		for _, name := range slices.Sorted(maps.Keys(all)) {
+			if !yield(name, all[name]) {
+				return
+			}
+		}
+	}
+}
+
+// IndexPrivileges returns the sorted, deduplicated Elasticsearch index
+// privileges requested by the package. The package-spec declares index
+// privileges per data stream (elasticsearch.privileges.indices in the data
+// stream manifest); the package manifest only declares cluster privileges.
+func (p *Package) IndexPrivileges() []string {
+	var privs []string
+	for _, ds := range p.DataStreams {
+		privs = append(privs, ds.Manifest.Elasticsearch.Privileges.Indices...)
+	}
+	slices.Sort(privs)
+	return slices.Compact(privs)
+}
+
 // IntegrationManifest returns the full integration manifest, or nil if the
 // package is not of type "integration".
 func (p *Package) IntegrationManifest() *pkgspec.IntegrationManifest {
@@ -95,16 +174,25 @@ func (p *Package) ContentManifest() *pkgspec.ContentManifest {
 type Option func(*config)
 
 type config struct {
-	fsys             fs.FS
-	knownFields      bool
-	gitMetadata      bool
-	agentTemplates   bool
-	imageMetadata    bool
-	testConfigs      bool
-	pathPrefix       string // prefix prepended to all FileMetadata file paths
-	repoRelativePath string // package path relative to the repo root (for CODEOWNERS lookup)
-	packagePath      string // original OS path, needed for git operations
-	codeownersPath   string // path to CODEOWNERS file for data stream ownership
+	fsys              fs.FS
+	knownFields       bool
+	gitMetadata       bool
+	agentTemplates    bool
+	imageMetadata     bool
+	testConfigs       bool
+	pathPrefix        string // prefix prepended to all FileMetadata file paths
+	repoRelativePath  string // package path relative to the repo root (for CODEOWNERS lookup)
+	packagePath       string // original OS path, needed for git operations
+	codeownersPath    string // path to CODEOWNERS file for data stream ownership
+	fieldResolver     func(ref string) (*pkgspec.Field, bool)
+	followSymlinks    bool
+	symlinkRoot       string
+	typeInference     bool
+	nodePositions     bool
+	devConfigs        bool
+	changedSince      string // git ref to diff against; only changed files are loaded
+	strictSampleEvent bool
+	concurrency       int // max files decoded in parallel; <= 1 is sequential
 }
 
 // WithFS provides a custom filesystem for reading package files. When set,
@@ -116,8 +204,9 @@ func WithFS(fsys fs.FS) Option {
 }
 
 // WithKnownFields enables strict YAML validation where only fields defined
-// in the model types are allowed. By default, unknown fields are silently
-// ignored for forward compatibility.
+// in the model types are allowed. Build dependencies other than ECS must be
+// a reference string or an object with only a reference. By default, unknown
+// fields are silently ignored for forward compatibility.
 func WithKnownFields() Option {
 	return func(c *config) {
 		c.knownFields = true
@@ -134,9 +223,10 @@ func WithAgentTemplates() Option {
 }
 
 // WithImageMetadata enables loading of image files from the img/ directory.
-// When set, the reader decodes image dimensions (width, height) and records
-// byte sizes for PNG, JPEG, and SVG files. SVG files only have byte size
-// recorded since they are vector images.
+// When set, the reader records the byte size and SHA-256 hash of every file
+// and decodes image dimensions (width, height) for PNG, JPEG, GIF, and WebP
+// files. SVG files and unrecognized formats only have byte size and hash
+// recorded.
 func WithImageMetadata() Option {
 	return func(c *config) {
 		c.imageMetadata = true
@@ -153,6 +243,17 @@ func WithTestConfigs() Option {
 	}
 }
 
+// WithDevConfigs enables discovery of developer asset files in the package's
+// _dev/ directory that have no typed model, such as benchmark/, deploy/, and
+// profile/ configurations. When set, Package.DevConfigs maps each _dev/
+// subdirectory name to the paths of the files it contains. The build/ and
+// test/ subdirectories are excluded because they are loaded into typed fields.
+func WithDevConfigs() Option {
+	return func(c *config) {
+		c.devConfigs = true
+	}
+}
+
 // WithGitMetadata enables git metadata enrichment. When set, the reader
 // populates Package.Commit with the HEAD commit ID and uses git blame to
 // populate Changelog.Date fields.
@@ -165,10 +266,12 @@ func WithGitMetadata() Option {
 // WithPathPrefix sets a prefix that is prepended to all [pkgspec.FileMetadata]
 // file paths after loading. This is useful when analyzing packages within a
 // larger repository, allowing file paths to be repo-relative (e.g.
-// "packages/nginx/manifest.yml") rather than package-relative.
+// "packages/nginx/manifest.yml") rather than package-relative. Backslash
+// separators in prefix are converted to forward slashes, so a prefix built
+// with filepath on Windows yields the same paths as on other platforms.
 func WithPathPrefix(prefix string) Option {
 	return func(c *config) {
-		c.pathPrefix = prefix
+		c.pathPrefix = toSlash(prefix)
 	}
 }
 
@@ -186,14 +289,127 @@ func WithCodeowners(path string) Option {
 // root. This is used to build CODEOWNERS lookup keys when the package lives
 // under a nested layout such as packages/<group>/<name>/, where the directory
 // basename alone is insufficient. The value should be a forward-slash path
-// like "packages/aws" or "packages/microsoft/defender_endpoint".
+// like "packages/aws" or "packages/microsoft/defender_endpoint"; backslash
+// separators are converted to forward slashes.
 //
 // If WithRepoRelativePath is not set, the value provided to WithPathPrefix is
 // used. If neither is set, the CODEOWNERS lookup falls back to the basename
 // of the package path, which matches historical behavior for the flat layout.
 func WithRepoRelativePath(p string) Option {
 	return func(c *config) {
-		c.repoRelativePath = p
+		c.repoRelativePath = toSlash(p)
+	}
+}
+
+// toSlash replaces each backslash in p with a forward slash. Unlike
+// filepath.ToSlash it does so on every OS, since all paths exposed by the
+// reader (FileMetadata, Doc.Path, ChangedFiles, ...) use forward slashes
+// regardless of where the package was read.
+func toSlash(p string) string {
+	return strings.ReplaceAll(p, `\`, "/")
+}
+
+// WithFieldResolver provides a callback to resolve fields that declare an
+// external source (e.g. fields reused from another package listed in the
+// build dependencies). The callback receives the fully-qualified dotted name
+// of each external field. When it returns true, attributes that are unset on
+// the local definition are filled in from the resolved field; local values
+// always take precedence. Fields the callback cannot resolve are left as-is.
+//
+// Fields with "external: ecs" are passed to the callback as well, so it may
+// return false for those to leave ECS enrichment to a later stage.
+func WithFieldResolver(fn func(ref string) (*pkgspec.Field, bool)) Option {
+	return func(c *config) {
+		c.fieldResolver = fn
+	}
+}
+
+// WithFollowSymlinks resolves symbolic links inside the package directory so
+// that files shared between data streams (e.g. a common fields file) are
+// loaded from their link target. Symlinked directories are traversed as well.
+//
+// For safety, every link target must resolve to a path inside the git
+// repository containing the package, or inside the package directory when it
+// is not in a repository; use WithSymlinkRoot to choose the root instead. A
+// link that escapes this root, or that points to a missing file, fails the
+// read when the reader opens it. Links in files and directories that the
+// reader never opens are ignored. This option has no effect when WithFS is
+// used.
+func WithFollowSymlinks() Option {
+	return func(c *config) {
+		c.followSymlinks = true
+	}
+}
+
+// WithSymlinkRoot sets the directory that symlink targets must stay within
+// when WithFollowSymlinks is used, in place of the enclosing git repository.
+func WithSymlinkRoot(dir string) Option {
+	return func(c *config) {
+		c.symlinkRoot = dir
+	}
+}
+
+// WithStrictSampleEvent requires sample event files (sample_event.json and
+// sample_event_<name>.json) to contain valid JSON. Read returns an error
+// naming the first invalid file. By default sample events are loaded as raw
+// bytes without validation.
+func WithStrictSampleEvent() Option {
+	return func(c *config) {
+		c.strictSampleEvent = true
+	}
+}
+
+// WithTypeInference allows reading packages whose manifest has no type
+// field. The type is inferred from the package layout: a data_stream/
+// directory means "integration" and a root fields/ directory means "input".
+// The inferred type is also set on the returned manifest. Without this
+// option, a missing type is an error.
+func WithTypeInference() Option {
+	return func(c *config) {
+		c.typeInference = true
+	}
+}
+
+// WithNodePositions records the line and column of every YAML node in
+// fields files and ingest pipelines in [Package.Positions]. This lets
+// callers locate a specific scalar, such as a single processor attribute,
+// rather than only the enclosing struct reported by FileMetadata.
+//
+// Each file is parsed a second time and every node gets a map entry holding
+// its JSON pointer, so memory use grows with the number of nodes and can
+// exceed that of the decoded package for large pipelines.
+func WithNodePositions() Option {
+	return func(c *config) {
+		c.nodePositions = true
+	}
+}
+
+// WithChangedSince limits loading to the files that git reports as changed
+// between baseRef and the working tree, for incremental updates. The
+// package manifest is always loaded so the package can be identified. A
+// data stream or transform is loaded in full when any of its files changed
+// and omitted otherwise; every other component is loaded only from changed
+// files, leaving unchanged components nil. The changed file paths are
+// recorded in Package.ChangedFiles.
+//
+// The path passed to Read must be an OS path inside a git repository.
+// Untracked files are not considered changed.
+func WithChangedSince(baseRef string) Option {
+	return func(c *config) {
+		c.changedSince = baseRef
+	}
+}
+
+// WithConcurrency decodes up to n field files of a fields directory in
+// parallel. YAML decoding is CPU-bound, so this shortens load time for data
+// streams with many large field files. Results are identical to sequential
+// reading. Values of n <= 1 read sequentially, which is the default.
+//
+// The filesystem and any WithFieldResolver function must be safe for
+// concurrent use.
+func WithConcurrency(n int) Option {
+	return func(c *config) {
+		c.concurrency = n
 	}
 }
 
@@ -208,22 +424,47 @@ func Read(pkgPath string, opts ...Option) (*Package, error) {
 	}
 
 	var root string
-	if cfg.fsys != nil {
+	switch {
+	case cfg.fsys != nil:
 		root = pkgPath
-	} else {
+	case cfg.followSymlinks:
+		fsys, err := newSymlinkFS(pkgPath, cfg.symlinkRoot)
+		if err != nil {
+			return nil, err
+		}
+		cfg.fsys = fsys
+		root = "."
+	default:
 		cfg.fsys = os.DirFS(pkgPath)
 		root = "."
 	}
 
+	// Hide unchanged files (optional, requires WithChangedSince).
+	var changedFiles []string
+	if cfg.changedSince != "" {
+		changed, err := gitChangedFiles(cfg.packagePath, cfg.changedSince)
+		if err != nil {
+			return nil, fmt.Errorf("listing changed files: %w", err)
+		}
+		cfg.fsys = newChangedFS(cfg.fsys, root, changed)
+		changedFiles = changed
+	}
+
 	// Detect package type from manifest.
 	manifestPath := path.Join(root, "manifest.yml")
 	pkgType, err := detectManifestType(cfg.fsys, manifestPath)
+	var inferred bool
+	if errors.Is(err, errNoManifestType) && cfg.typeInference {
+		pkgType, err = inferPackageType(cfg.fsys, root)
+		inferred = err == nil
+	}
 	if err != nil {
 		return nil, fmt.Errorf("detecting package type: %w", err)
 	}
 
 	pkg := &Package{
-		path: pkgPath,
+		ChangedFiles: changedFiles,
+		path:         pkgPath,
 	}
 
 	// Decode manifest into the correct type.
@@ -252,6 +493,9 @@ func Read(pkgPath string, opts ...Option) (*Package, error) {
 	default:
 		return nil, fmt.Errorf("unsupported package type: %q", pkgType)
 	}
+	if inferred {
+		pkg.Manifest().Type = pkgspec.ManifestType(pkgType)
+	}
 
 	// Read changelog.
 	changelogPath := path.Join(root, "changelog.yml")
@@ -286,14 +530,22 @@ func Read(pkgPath string, opts ...Option) (*Package, error) {
 
 	// Read images (optional, requires WithImageMetadata).
 	if cfg.imageMetadata {
-		imgDir := path.Join(root, "img")
-		images, err := readImages(cfg.fsys, imgDir)
+		images, err := readImages(cfg.fsys, root)
 		if err != nil {
 			return nil, fmt.Errorf("reading images: %w", err)
 		}
 		pkg.Images = images
 	}
 
+	// Read developer asset listings (optional, requires WithDevConfigs).
+	if cfg.devConfigs {
+		devConfigs, err := readDevConfigs(cfg.fsys, root)
+		if err != nil {
+			return nil, fmt.Errorf("reading _dev configs: %w", err)
+		}
+		pkg.DevConfigs = devConfigs
+	}
+
 	// Read documentation file metadata.
 	docs, err := readDocs(cfg.fsys, root)
 	if err != nil {
@@ -350,6 +602,11 @@ func Read(pkgPath string, opts ...Option) (*Package, error) {
 			return nil, fmt.Errorf("reading build manifest: %w", err)
 		}
 		if build != nil {
+			if cfg.knownFields {
+				if err := checkBuildDependencies(&build.Dependencies); err != nil {
+					return nil, fmt.Errorf("reading build manifest: decoding %s: %w", buildPath, err)
+				}
+			}
 			pkgspec.AnnotateFileMetadata(buildPath, build)
 			pkg.Build = build
 		}
@@ -389,14 +646,14 @@ func Read(pkgPath string, opts ...Option) (*Package, error) {
 
 		// Read sample event (optional).
 		sampleEventPath := path.Join(root, "sample_event.json")
-		sampleEvent, err := readOptionalFile(cfg.fsys, sampleEventPath)
+		sampleEvent, err := readSampleEvent(cfg.fsys, sampleEventPath, cfg.strictSampleEvent)
 		if err != nil {
 			return nil, fmt.Errorf("reading sample event: %w", err)
 		}
 		pkg.SampleEvent = sampleEvent
 
 		// Read named sample events: sample_event_<name>.json (optional).
-		namedSampleEvents, err := readNamedSampleEvents(cfg.fsys, root)
+		namedSampleEvents, err := readNamedSampleEvents(cfg.fsys, root, cfg.strictSampleEvent)
 		if err != nil {
 			return nil, fmt.Errorf("reading named sample events: %w", err)
 		}
@@ -465,12 +722,22 @@ func Read(pkgPath string, opts ...Option) (*Package, error) {
 		pkgKey := codeownersPackageKey(cfg)
 		for dsName, ds := range pkg.DataStreams {
 			dsPath := pkgKey + "/data_stream/" + dsName
-			if owner := cf.matchOwner(dsPath); owner != "" {
-				ds.Manifest.GithubCodeOwner = owner
+			if owners := cf.matchOwner(dsPath); len(owners) > 0 {
+				ds.Manifest.GithubCodeOwner = owners[0]
+				ds.Manifest.GithubCodeOwners = slices.Clone(owners)
 			}
 		}
 	}
 
+	// Index node positions, keyed by the prefixed file path.
+	if cfg.nodePositions {
+		positions, err := readPositions(cfg.fsys, pkg, cfg.pathPrefix)
+		if err != nil {
+			return nil, fmt.Errorf("reading node positions: %w", err)
+		}
+		pkg.Positions = positions
+	}
+
 	// Prefix all FileMetadata file paths.
 	if cfg.pathPrefix != "" {
 		pkgspec.PrefixFileMetadata(cfg.pathPrefix, pkg.manifest)
@@ -478,6 +745,14 @@ func Read(pkgPath string, opts ...Option) (*Package, error) {
 		for _, d := range pkg.Docs {
 			d.path = path.Join(cfg.pathPrefix, d.path)
 		}
+		for i, p := range pkg.ChangedFiles {
+			pkg.ChangedFiles[i] = path.Join(cfg.pathPrefix, p)
+		}
+		for _, paths := range pkg.DevConfigs {
+			for i, p := range paths {
+				paths[i] = path.Join(cfg.pathPrefix, p)
+			}
+		}
 	}
 
 	return pkg, nil
@@ -500,6 +775,30 @@ func codeownersPackageKey(cfg *config) string {
 	}
 }
 
+// checkBuildDependencies applies WithKnownFields to the build dependencies
+// other than ECS. They are collected in an inline map, so the YAML decoder
+// accepts any key there. Each one must be a bare reference string or an
+// object whose only key is a string "reference".
+func checkBuildDependencies(deps *pkgspec.BuildDependencies) error {
+	for _, name := range slices.Sorted(maps.Keys(deps.Extras)) {
+		switch v := deps.Extras[name].(type) {
+		case string:
+		case map[string]any:
+			for _, key := range slices.Sorted(maps.Keys(v)) {
+				if key != "reference" {
+					return fmt.Errorf("dependency %s: field %s not found", name, key)
+				}
+				if _, ok := v[key].(string); !ok {
+					return fmt.Errorf("dependency %s: reference must be a string", name)
+				}
+			}
+		default:
+			return fmt.Errorf("dependency %s must be a reference string or an object with a reference", name)
+		}
+	}
+	return nil
+}
+
 // manifestTypeDetector is used to extract only the "type" field from a manifest.
 type manifestTypeDetector struct {
 	Type string `yaml:"type"`
@@ -513,7 +812,27 @@ func detectManifestType(fsys fs.FS, manifestPath string) (string, error) {
 		return "", err
 	}
 	if detector.Type == "" {
-		return "", fmt.Errorf("manifest at %s has no type field", manifestPath)
+		return "", fmt.Errorf("manifest at %s: %w", manifestPath, errNoManifestType)
 	}
 	return detector.Type, nil
 }
+
+// errNoManifestType is returned by detectManifestType when the manifest has
+// no type field.
+var errNoManifestType = errors.New("no type field")
+
+// inferPackageType infers the package type from the directory layout under
+// root for manifests that do not declare one.
+func inferPackageType(fsys fs.FS, root string) (string, error) {
+	isDir := func(name string) bool {
+		info, err := fs.Stat(fsys, path.Join(root, name))
+		return err == nil && info.IsDir()
+	}
+	switch {
+	case isDir("data_stream"):
+		return string(pkgspec.ManifestTypeIntegration), nil
+	case isDir("fields"):
+		return string(pkgspec.ManifestTypeInput), nil
+	}
+	return "", fmt.Errorf("manifest has no type field and the type cannot be inferred from the package layout")
+}
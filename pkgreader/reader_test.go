@@ -1,8 +1,19 @@
 package pkgreader
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
+	"image"
+	"image/color/palette"
+	"image/gif"
+	"io/fs"
+	"maps"
 	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
 	"testing/fstest"
 
@@ -72,6 +83,9 @@ func TestReadIntegrationPackage(t *testing.T) {
 	if len(pkg.Validation.Errors.ExcludeChecks) != 1 {
 		t.Errorf("validation exclude_checks count = %d, want 1", len(pkg.Validation.Errors.ExcludeChecks))
 	}
+	if got := pkg.ExcludedChecks(); !slices.Equal(got, []string{"SVR00001"}) {
+		t.Errorf("ExcludedChecks() = %v, want [SVR00001]", got)
+	}
 
 	// Tags.
 	if len(pkg.Tags) != 1 {
@@ -383,6 +397,43 @@ func TestReadWithKnownFields(t *testing.T) {
 	}
 }
 
+func TestReadWithKnownFieldsBuildDependencies(t *testing.T) {
+	manifest := "name: test\ntitle: Test\nversion: 1.0.0\ntype: integration\nformat_version: 3.3.0\n"
+	tests := []struct {
+		name    string
+		build   string
+		wantErr string
+	}{
+		{"ecs only", "dependencies:\n  ecs:\n    reference: git@v8.11.0\n", ""},
+		{"package reference", "dependencies:\n  shared:\n    reference: git@v1.2.0\n", ""},
+		{"bare reference", "dependencies:\n  shared: git@v1.2.0\n", ""},
+		{"unknown dependency field", "dependencies:\n  shared:\n    refrence: git@v1.2.0\n", "dependency shared: field refrence not found"},
+		{"non-string reference", "dependencies:\n  shared:\n    reference: [a]\n", "dependency shared: reference must be a string"},
+		{"list", "dependencies:\n  shared: [a]\n", "dependency shared must be a reference string or an object with a reference"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fsys := fstest.MapFS{
+				"manifest.yml":         &fstest.MapFile{Data: []byte(manifest)},
+				"_dev/build/build.yml": &fstest.MapFile{Data: []byte(tc.build)},
+			}
+
+			// Lenient mode accepts any dependency.
+			if _, err := Read(".", WithFS(fsys)); err != nil {
+				t.Fatalf("lenient mode should not error: %v", err)
+			}
+
+			_, err := Read(".", WithFS(fsys), WithKnownFields())
+			switch {
+			case tc.wantErr == "" && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)):
+				t.Fatalf("error = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
 func TestReadOptionalFiles(t *testing.T) {
 	// Package with minimal files: no validation, no tags, no lifecycle.
 	fsys := fstest.MapFS{
@@ -402,6 +453,9 @@ func TestReadOptionalFiles(t *testing.T) {
 	if pkg.Validation != nil {
 		t.Error("Validation should be nil when file is absent")
 	}
+	if pkg.ExcludedChecks() != nil {
+		t.Error("ExcludedChecks() should be nil when validation file is absent")
+	}
 	if pkg.Tags != nil {
 		t.Error("Tags should be nil when file is absent")
 	}
@@ -568,6 +622,59 @@ func TestImageMetadata(t *testing.T) {
 	}
 }
 
+func TestImageMetadataGIFAndUnknownFormat(t *testing.T) {
+	var gifData bytes.Buffer
+	if err := gif.Encode(&gifData, image.NewPaletted(image.Rect(0, 0, 3, 2), palette.Plan9), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	fsys := fstest.MapFS{
+		"manifest.yml": &fstest.MapFile{
+			Data: []byte("name: test\ntitle: Test\nversion: 1.0.0\ntype: input\nformat_version: 3.3.0\n"),
+		},
+		"changelog.yml": &fstest.MapFile{
+			Data: []byte("- version: 1.0.0\n  changes:\n    - description: Init.\n      type: enhancement\n      link: https://example.com/1\n"),
+		},
+		"img/screenshot.gif": &fstest.MapFile{Data: gifData.Bytes()},
+		"img/icon.ico":       &fstest.MapFile{Data: []byte("not a decodable image")},
+		"img/README.md":      &fstest.MapFile{Data: []byte("# Images\n")},
+		"img/LICENSE":        &fstest.MapFile{Data: []byte("license\n")},
+	}
+
+	pkg, err := Read(".", WithFS(fsys), WithImageMetadata())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Files that are not images are skipped.
+	if got, want := slices.Sorted(maps.Keys(pkg.Images)), []string{"icon.ico", "screenshot.gif"}; !slices.Equal(got, want) {
+		t.Errorf("images = %q, want %q", got, want)
+	}
+
+	g, ok := pkg.Images["screenshot.gif"]
+	if !ok {
+		t.Fatal("image 'screenshot.gif' not found")
+	}
+	if g.Width != 3 || g.Height != 2 {
+		t.Errorf("screenshot.gif dimensions = %dx%d, want 3x2", g.Width, g.Height)
+	}
+	if g.ByteSize != int64(gifData.Len()) {
+		t.Errorf("screenshot.gif byte size = %d, want %d", g.ByteSize, gifData.Len())
+	}
+
+	// Unknown formats are recorded without dimensions.
+	ico, ok := pkg.Images["icon.ico"]
+	if !ok {
+		t.Fatal("image 'icon.ico' not found")
+	}
+	if ico.Width != 0 || ico.Height != 0 {
+		t.Errorf("icon.ico dimensions = %dx%d, want 0x0", ico.Width, ico.Height)
+	}
+	if ico.ByteSize == 0 || len(ico.SHA256) != 64 {
+		t.Errorf("icon.ico byte size = %d, sha256 = %q; want both recorded", ico.ByteSize, ico.SHA256)
+	}
+}
+
 func TestKibanaObjects(t *testing.T) {
 	pkg, err := Read("testdata/integration_pkg")
 	if err != nil {
@@ -602,6 +709,9 @@ func TestKibanaObjects(t *testing.T) {
 	if d.CoreMigrationVersion != "8.8.0" {
 		t.Errorf("coreMigrationVersion = %q, want 8.8.0", d.CoreMigrationVersion)
 	}
+	if d.TypeMigrationVersion != "8.9.0" {
+		t.Errorf("typeMigrationVersion = %q, want 8.9.0", d.TypeMigrationVersion)
+	}
 	if d.Managed == nil || *d.Managed != false {
 		t.Errorf("managed = %v, want false", d.Managed)
 	}
@@ -636,6 +746,27 @@ func TestKibanaObjects(t *testing.T) {
 	if d.Path() != "kibana/dashboard/overview.json" {
 		t.Errorf("path = %q, want kibana/dashboard/overview.json", d.Path())
 	}
+	if d.FilePath() != "kibana/dashboard/overview.json" {
+		t.Errorf("file path = %q, want kibana/dashboard/overview.json", d.FilePath())
+	}
+
+	// Raw holds the file content byte for byte.
+	raw, err := os.ReadFile("testdata/integration_pkg/kibana/dashboard/overview.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(d.Raw, raw) {
+		t.Errorf("raw = %q, want file content %q", d.Raw, raw)
+	}
+
+	// FileMetadata is prefixed like other package files.
+	pkg, err = Read("testdata/integration_pkg", WithPathPrefix("packages/test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := pkg.KibanaObjects["dashboard"][0].FilePath(); got != "packages/test/kibana/dashboard/overview.json" {
+		t.Errorf("prefixed file path = %q, want packages/test/kibana/dashboard/overview.json", got)
+	}
 }
 
 func TestKibanaObjectsNotLoadedForInput(t *testing.T) {
@@ -693,6 +824,19 @@ func TestTestConfigs(t *testing.T) {
 		if pc.EventPath == "" {
 			t.Error("pipeline test event path is empty")
 		}
+		wantExpectedPath := "data_stream/logs/_dev/test/pipeline/test-example.json-expected.json"
+		if pc.ExpectedPath != wantExpectedPath {
+			t.Errorf("pipeline test expected path = %q, want %s", pc.ExpectedPath, wantExpectedPath)
+		}
+		if pc.Expected == nil {
+			t.Fatal("pipeline test expected is nil")
+		}
+		if len(pc.Expected.Expected) != 2 {
+			t.Errorf("pipeline test expected event count = %d, want 2", len(pc.Expected.Expected))
+		}
+		if pc.Expected.FilePath() != wantExpectedPath {
+			t.Errorf("pipeline test expected file path = %q, want %s", pc.Expected.FilePath(), wantExpectedPath)
+		}
 		if pc.Config == nil {
 			t.Fatal("pipeline test config is nil")
 		}
@@ -844,3 +988,1429 @@ func TestPipelineTestCommonConfig(t *testing.T) {
 		t.Error("CommonConfig should be nil when test-common-config.yml is absent")
 	}
 }
+
+func TestFieldResolver(t *testing.T) {
+	fsys := fstest.MapFS{
+		"manifest.yml": &fstest.MapFile{
+			Data: []byte("name: test\ntitle: Test\nversion: 1.0.0\ntype: input\nformat_version: 3.3.0\n"),
+		},
+		"changelog.yml": &fstest.MapFile{
+			Data: []byte("- version: 1.0.0\n  changes:\n    - description: Init.\n      type: enhancement\n      link: https://example.com/1\n"),
+		},
+		"fields/fields.yml": &fstest.MapFile{
+			Data: []byte(`
+- name: base
+  type: group
+  fields:
+    - name: id
+      external: base_pkg
+    - name: label
+      external: base_pkg
+      description: Local description.
+    - name: missing
+      external: base_pkg
+- name: local
+  type: keyword
+`),
+		},
+	}
+
+	index := true
+	baseID := &pkgspec.Field{
+		Name:        "base.id",
+		Type:        pkgspec.FieldTypeKeyword,
+		Description: "Imported ID.",
+		Index:       &index,
+		Example:     map[string]any{"ids": []any{"a1"}},
+	}
+
+	var refs []string
+	resolver := func(ref string) (*pkgspec.Field, bool) {
+		refs = append(refs, ref)
+		switch ref {
+		case "base.id":
+			return baseID, true
+		case "base.label":
+			return &pkgspec.Field{Name: "base.label", Type: pkgspec.FieldTypeText, Description: "Imported label."}, true
+		}
+		return nil, false
+	}
+
+	pkg, err := Read(".", WithFS(fsys), WithFieldResolver(resolver))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"base.id", "base.label", "base.missing"}; !slices.Equal(refs, want) {
+		t.Errorf("resolver refs = %v, want %v", refs, want)
+	}
+
+	children := pkg.Fields["fields.yml"].Fields[0].Fields
+	if len(children) != 3 {
+		t.Fatalf("got %d base fields, want 3", len(children))
+	}
+
+	// Resolved attributes are merged into the local definition.
+	id := children[0]
+	if id.Name != "id" || id.Type != pkgspec.FieldTypeKeyword || id.Description != "Imported ID." {
+		t.Errorf("base.id = %+v, want keyword with imported description", id)
+	}
+	if id.External != "base_pkg" {
+		t.Errorf("base.id external = %q, want base_pkg", id.External)
+	}
+	if id.JsonPointer != "/0/fields/0" {
+		t.Errorf("base.id json pointer = %q, want /0/fields/0", id.JsonPointer)
+	}
+
+	// The merged attributes do not share storage with the resolved field.
+	index = false
+	baseID.Example.(map[string]any)["ids"].([]any)[0] = "changed"
+	if id.Index == nil || !*id.Index {
+		t.Errorf("base.id index = %v, want true after changing the resolved field", id.Index)
+	}
+	if got := id.Example.(map[string]any)["ids"].([]any)[0]; got != "a1" {
+		t.Errorf("base.id example id = %v, want a1 after changing the resolved field", got)
+	}
+
+	// Local attributes take precedence over resolved ones.
+	label := children[1]
+	if label.Type != pkgspec.FieldTypeText || label.Description != "Local description." {
+		t.Errorf("base.label = %+v, want text with local description", label)
+	}
+
+	// Unresolved references are left as-is.
+	missing := children[2]
+	if missing.Type != "" || missing.Description != "" {
+		t.Errorf("base.missing = %+v, want unchanged", missing)
+	}
+}
+
+func TestDataStreamDataset(t *testing.T) {
+	fsys := fstest.MapFS{
+		"manifest.yml": &fstest.MapFile{
+			Data: []byte("name: nginx\ntitle: Nginx\nversion: 1.0.0\ntype: integration\nformat_version: 3.3.0\n"),
+		},
+		"changelog.yml": &fstest.MapFile{
+			Data: []byte("- version: 1.0.0\n  changes:\n    - description: Init.\n      type: enhancement\n      link: https://example.com/1\n"),
+		},
+		"data_stream/access/manifest.yml": &fstest.MapFile{
+			Data: []byte("title: Access\ntype: logs\n"),
+		},
+		"data_stream/error/manifest.yml": &fstest.MapFile{
+			Data: []byte("title: Error\ntype: logs\ndataset: nginx_custom.error\n"),
+		},
+	}
+
+	pkg, err := Read(".", WithFS(fsys))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := pkg.DataStreams["access"].Dataset("nginx"), "nginx.access"; got != want {
+		t.Errorf("access dataset = %q, want %q", got, want)
+	}
+	if got, want := pkg.DataStreams["error"].Dataset("nginx"), "nginx_custom.error"; got != want {
+		t.Errorf("error dataset = %q, want %q", got, want)
+	}
+	if got, want := pkg.DataStreams["error"].IndexTemplateName("nginx"), "logs-nginx_custom.error"; got != want {
+		t.Errorf("error index template = %q, want %q", got, want)
+	}
+}
+
+func TestDataStreamStreamType(t *testing.T) {
+	fsys := fstest.MapFS{
+		"manifest.yml": &fstest.MapFile{
+			Data: []byte("name: nginx\ntitle: Nginx\nversion: 1.0.0\ntype: integration\nformat_version: 3.3.0\n"),
+		},
+		"changelog.yml": &fstest.MapFile{
+			Data: []byte("- version: 1.0.0\n  changes:\n    - description: Init.\n      type: enhancement\n      link: https://example.com/1\n"),
+		},
+		"data_stream/access/manifest.yml": &fstest.MapFile{
+			Data: []byte("title: Access\ntype: logs\n"),
+		},
+		"data_stream/stubstatus/manifest.yml": &fstest.MapFile{
+			Data: []byte("title: Stub Status\ntype: metrics\n"),
+		},
+		"data_stream/untyped/manifest.yml": &fstest.MapFile{
+			Data: []byte("title: Untyped\n"),
+		},
+	}
+
+	pkg, err := Read(".", WithFS(fsys))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, want := range map[string]string{"access": "logs", "stubstatus": "metrics", "untyped": ""} {
+		if got := pkg.DataStreams[name].StreamType(); got != want {
+			t.Errorf("%s StreamType() = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestDataStreamStreamTemplatePath(t *testing.T) {
+	fsys := fstest.MapFS{
+		"packages/nginx/manifest.yml": &fstest.MapFile{
+			Data: []byte("name: nginx\ntitle: Nginx\nversion: 1.0.0\ntype: integration\nformat_version: 3.3.0\n"),
+		},
+		"packages/nginx/data_stream/access/manifest.yml": &fstest.MapFile{
+			Data: []byte(`title: Access
+type: logs
+streams:
+  - input: logfile
+    title: Default template
+    description: Collect access logs.
+  - input: httpjson
+    title: Custom template
+    description: Collect access logs over HTTP.
+    template_path: httpjson.yml.hbs
+`),
+		},
+	}
+
+	pkg, err := Read("packages/nginx", WithFS(fsys))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds := pkg.DataStreams["access"]
+	for i, want := range []string{
+		"data_stream/access/agent/stream/stream.yml.hbs",
+		"data_stream/access/agent/stream/httpjson.yml.hbs",
+		"",
+	} {
+		if got := ds.StreamTemplatePath(i); got != want {
+			t.Errorf("StreamTemplatePath(%d) = %q, want %q", i, got, want)
+		}
+	}
+}
+
+func TestReadDataStream(t *testing.T) {
+	dsDir := filepath.Join("testdata", "integration_pkg", "data_stream", "logs")
+	ds, err := ReadDataStream(dsDir, WithTestConfigs())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The data stream matches the one loaded as part of the whole package.
+	pkg, err := Read(filepath.Join("testdata", "integration_pkg"), WithTestConfigs())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := pkg.DataStreams["logs"]
+
+	if ds.Path() != want.Path() {
+		t.Errorf("Path() = %q, want %q", ds.Path(), want.Path())
+	}
+	if ds.Manifest.Title != "Test Logs" || ds.StreamType() != "logs" {
+		t.Errorf("Manifest = %+v, want title Test Logs and type logs", ds.Manifest)
+	}
+	if got, want := ds.Manifest.FilePath(), want.Manifest.FilePath(); got != want {
+		t.Errorf("manifest file path = %q, want %q", got, want)
+	}
+	if got, want := slices.Sorted(maps.Keys(ds.Fields)), slices.Sorted(maps.Keys(want.Fields)); !slices.Equal(got, want) {
+		t.Errorf("Fields = %v, want %v", got, want)
+	}
+	if len(ds.Pipelines) != len(want.Pipelines) || ds.Tests == nil || ds.Lifecycle == nil {
+		t.Errorf("data stream components not loaded: pipelines %d, tests %v, lifecycle %v", len(ds.Pipelines), ds.Tests, ds.Lifecycle)
+	}
+	if got := ds.Dataset("integration_pkg"); got != "integration_pkg.logs" {
+		t.Errorf("Dataset() = %q, want integration_pkg.logs", got)
+	}
+
+	// With a custom filesystem and path prefix.
+	fsys := fstest.MapFS{
+		"pkgs/nginx/data_stream/access/manifest.yml":    {Data: []byte("title: Access\ntype: logs\n")},
+		"pkgs/nginx/data_stream/access/fields/base.yml": {Data: []byte("- name: message\n  type: text\n")},
+	}
+	ds, err = ReadDataStream("pkgs/nginx/data_stream/access", WithFS(fsys), WithPathPrefix("packages/nginx"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ds.Manifest.FilePath(), "packages/nginx/data_stream/access/manifest.yml"; got != want {
+		t.Errorf("manifest file path = %q, want %q", got, want)
+	}
+	if got, want := ds.Fields["base.yml"].Fields[0].FilePath(), "packages/nginx/data_stream/access/fields/base.yml"; got != want {
+		t.Errorf("field file path = %q, want %q", got, want)
+	}
+
+	if _, err := ReadDataStream("pkgs/nginx/data_stream/missing", WithFS(fsys)); err == nil {
+		t.Error("expected error for missing data stream")
+	}
+}
+
+func TestFollowSymlinks(t *testing.T) {
+	dir := t.TempDir()
+	pkgDir := filepath.Join(dir, "pkg")
+	files := map[string]string{
+		"manifest.yml":                   "name: test\ntitle: Test\nversion: 1.0.0\ntype: integration\nformat_version: 3.3.0\n",
+		"changelog.yml":                  "- version: 1.0.0\n  changes:\n    - description: Init.\n      type: enhancement\n      link: https://example.com/1\n",
+		"data_stream/logs/manifest.yml":  "title: Logs\ntype: logs\n",
+		"shared/fields/base-fields.yml":  "- name: message\n  type: match_only_text\n",
+		"../outside/fields/escape.yml":   "- name: escaped\n  type: keyword\n",
+		"data_stream/other/manifest.yml": "title: Other\ntype: logs\n",
+	}
+	for name, content := range files {
+		p := filepath.Join(pkgDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	link := filepath.Join(pkgDir, "data_stream", "logs", "fields", "base-fields.yml")
+	if err := os.MkdirAll(filepath.Dir(link), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join("..", "..", "..", "shared", "fields", "base-fields.yml"), link); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+	if err := os.Symlink(filepath.Join("..", "logs", "fields"), filepath.Join(pkgDir, "data_stream", "other", "fields")); err != nil {
+		t.Fatal(err)
+	}
+
+	// A broken link in a directory the reader never opens is ignored.
+	if err := os.Symlink("missing.yml", filepath.Join(pkgDir, "data_stream", "logs", "dangling")); err != nil {
+		t.Fatal(err)
+	}
+
+	// The package directory is the root so that the result does not depend
+	// on whether the temp directory is inside a git repository.
+	pkg, err := Read(pkgDir, WithFollowSymlinks(), WithSymlinkRoot(pkgDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, ds := range []string{"logs", "other"} {
+		ff := pkg.DataStreams[ds].Fields["base-fields.yml"]
+		if ff == nil || len(ff.Fields) != 1 || ff.Fields[0].Name != "message" {
+			t.Errorf("%s: base-fields.yml = %+v, want symlinked message field", ds, ff)
+		}
+	}
+
+	// Links that escape the package directory are rejected.
+	escape := filepath.Join(pkgDir, "data_stream", "logs", "fields", "escape.yml")
+	if err := os.Symlink(filepath.Join(dir, "outside", "fields", "escape.yml"), escape); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Read(pkgDir, WithFollowSymlinks(), WithSymlinkRoot(pkgDir)); err == nil || !strings.Contains(err.Error(), "outside") {
+		t.Errorf("expected error for symlink outside root, got %v", err)
+	}
+
+	// A wider root admits the same link.
+	pkg, err = Read(pkgDir, WithFollowSymlinks(), WithSymlinkRoot(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ff := pkg.DataStreams["logs"].Fields["escape.yml"]; ff == nil || len(ff.Fields) != 1 || ff.Fields[0].Name != "escaped" {
+		t.Errorf("escape.yml = %+v, want symlinked escaped field", ff)
+	}
+
+	// A broken link in a directory the reader opens fails the read.
+	if err := os.Remove(escape); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("missing.yml", escape); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Read(pkgDir, WithFollowSymlinks(), WithSymlinkRoot(pkgDir)); err == nil || !strings.Contains(err.Error(), "broken symlink") {
+		t.Errorf("expected error for broken symlink, got %v", err)
+	}
+}
+
+func TestRerouteGraph(t *testing.T) {
+	fsys := fstest.MapFS{
+		"manifest.yml": &fstest.MapFile{
+			Data: []byte("name: test\ntitle: Test\nversion: 1.0.0\ntype: integration\nformat_version: 3.3.0\n"),
+		},
+		"changelog.yml": &fstest.MapFile{
+			Data: []byte("- version: 1.0.0\n  changes:\n    - description: Init.\n      type: enhancement\n      link: https://example.com/1\n"),
+		},
+		"data_stream/router/manifest.yml": &fstest.MapFile{
+			Data: []byte("title: Router\ntype: logs\n"),
+		},
+		"data_stream/router/routing_rules.yml": &fstest.MapFile{
+			Data: []byte(`
+- source_dataset: test.router
+  rules:
+    - target_dataset: test.middle
+      if: ctx.kind == 'middle'
+      namespace: default
+`),
+		},
+		"data_stream/middle/manifest.yml": &fstest.MapFile{
+			Data: []byte("title: Middle\ntype: logs\n"),
+		},
+		"data_stream/middle/routing_rules.yml": &fstest.MapFile{
+			// source_dataset omitted: defaults to the data stream's dataset.
+			Data: []byte(`
+- rules:
+    - target_dataset: [test.leaf, "{{labels.dataset}}"]
+      if: ctx.kind == 'leaf'
+`),
+		},
+		"data_stream/leaf/manifest.yml": &fstest.MapFile{
+			Data: []byte("title: Leaf\ntype: logs\n"),
+		},
+	}
+
+	pkg, err := Read(".", WithFS(fsys))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := pkg.RerouteGraph()
+	if want := []string{"test.middle", "test.router"}; !slices.Equal(g.Sources(), want) {
+		t.Errorf("Sources() = %v, want %v", g.Sources(), want)
+	}
+	if want := []string{"test.middle"}; !slices.Equal(g.Targets("test.router"), want) {
+		t.Errorf("Targets(test.router) = %v, want %v", g.Targets("test.router"), want)
+	}
+	if want := []string{"test.leaf", "test.middle", "{{labels.dataset}}"}; !slices.Equal(g.Reachable("test.router"), want) {
+		t.Errorf("Reachable(test.router) = %v, want %v", g.Reachable("test.router"), want)
+	}
+	if got := g.Reachable("test.leaf"); len(got) != 0 {
+		t.Errorf("Reachable(test.leaf) = %v, want none", got)
+	}
+	if got := g.Cycles(); len(got) != 0 {
+		t.Errorf("Cycles() = %v, want none", got)
+	}
+
+	// Closing the loop leaf → router is reported as a cycle.
+	pkg.DataStreams["leaf"].RoutingRules = []pkgspec.RoutingRuleSet{{
+		Rules: []pkgspec.RoutingRule{{TargetDataset: pkgspec.StringOrStrings{"test.router"}}},
+	}}
+	g = pkg.RerouteGraph()
+	want := [][]string{{"test.leaf", "test.router", "test.middle"}}
+	if got := g.Cycles(); !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("Cycles() = %v, want %v", got, want)
+	}
+	if !slices.Contains(g.Reachable("test.router"), "test.router") {
+		t.Errorf("Reachable(test.router) = %v, want it to include itself", g.Reachable("test.router"))
+	}
+}
+
+func TestSampleEventPaths(t *testing.T) {
+	event := json.RawMessage(`{
+  "@timestamp": "2024-01-01T00:00:00Z",
+  "event": {"dataset": "nginx.access", "category": ["web"]},
+  "related": {"ip": ["10.0.0.1", "10.0.0.2"]},
+  "threat": {"enrichments": [{"indicator": {"ip": "1.2.3.4"}}, {"matched": {"atomic": "x"}}]},
+  "empty": {},
+  "host.name": "web-1"
+}`)
+
+	paths, err := SampleEventPaths(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"@timestamp",
+		"event.category",
+		"event.dataset",
+		"host.name",
+		"related.ip",
+		"threat.enrichments.indicator.ip",
+		"threat.enrichments.matched.atomic",
+	}
+	if !slices.Equal(paths, want) {
+		t.Errorf("SampleEventPaths() = %v, want %v", paths, want)
+	}
+
+	if _, err := SampleEventPaths(json.RawMessage(`{`)); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
+func TestAllPipelines(t *testing.T) {
+	pipeline := []byte("description: Test pipeline.\nprocessors:\n  - set:\n      field: event.kind\n      value: event\n")
+	fsys := fstest.MapFS{
+		"manifest.yml": &fstest.MapFile{
+			Data: []byte("name: test\ntitle: Test\nversion: 1.0.0\ntype: integration\nformat_version: 3.3.0\n"),
+		},
+		"changelog.yml": &fstest.MapFile{
+			Data: []byte("- version: 1.0.0\n  changes:\n    - description: Init.\n      type: enhancement\n      link: https://example.com/1\n"),
+		},
+		"elasticsearch/ingest_pipeline/shared.yml":                         &fstest.MapFile{Data: pipeline},
+		"data_stream/access/manifest.yml":                                  &fstest.MapFile{Data: []byte("title: Access\ntype: logs\n")},
+		"data_stream/access/elasticsearch/ingest_pipeline/default.yml":     &fstest.MapFile{Data: pipeline},
+		"data_stream/access/elasticsearch/ingest_pipeline/third-party.yml": &fstest.MapFile{Data: pipeline},
+		"data_stream/error/manifest.yml":                                   &fstest.MapFile{Data: []byte("title: Error\ntype: logs\n")},
+		"data_stream/error/elasticsearch/ingest_pipeline/default.yml":      &fstest.MapFile{Data: pipeline},
+	}
+
+	pkg, err := Read(".", WithFS(fsys))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for name, pf := range pkg.AllPipelines() {
+		names = append(names, name)
+		if len(pf.Pipeline.Processors) != 1 {
+			t.Errorf("%s: got %d processors, want 1", name, len(pf.Pipeline.Processors))
+		}
+	}
+
+	want := []string{
+		"_package/shared.yml",
+		"access/default.yml",
+		"access/third-party.yml",
+		"error/default.yml",
+	}
+	if !slices.Equal(names, want) {
+		t.Errorf("AllPipelines() names = %v, want %v", names, want)
+	}
+}
+
+func TestValidateChangelog(t *testing.T) {
+	fsys := fstest.MapFS{
+		"manifest.yml": &fstest.MapFile{
+			Data: []byte("name: test\ntitle: Test\nversion: 1.3.0\ntype: input\nformat_version: 3.3.0\n"),
+		},
+		"changelog.yml": &fstest.MapFile{
+			Data: []byte(`- version: 1.2.0
+  changes:
+    - description: Latest.
+      type: enhancement
+      link: https://example.com/3
+- version: 1.2.0-beta.1
+  changes:
+    - description: Beta.
+      type: enhancement
+      link: https://example.com/2
+- version: 1.10.0
+  changes:
+    - description: Out of order.
+      type: enhancement
+      link: https://example.com/1
+- version: v1.0
+  changes:
+    - description: Invalid.
+      type: enhancement
+      link: https://example.com/0
+`),
+		},
+	}
+
+	pkg, err := Read(".", WithFS(fsys))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"changelog.yml:1:3: latest changelog version 1.2.0 does not match manifest version 1.3.0",
+		"changelog.yml:11:3: changelog version 1.10.0 is not lower than the preceding version 1.2.0-beta.1",
+		"changelog.yml:16:3: changelog version v1.0 is not a valid semantic version",
+	}
+	issues := pkg.Validate()
+	var got []string
+	for _, issue := range issues {
+		got = append(got, issue.String())
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Validate() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// A consistent changelog has no issues.
+	fsys["manifest.yml"].Data = []byte("name: test\ntitle: Test\nversion: 1.0.0\ntype: input\nformat_version: 3.3.0\n")
+	fsys["changelog.yml"].Data = []byte("- version: 1.0.0\n  changes:\n    - description: Init.\n      type: enhancement\n      link: https://example.com/1\n")
+	pkg, err = Read(".", WithFS(fsys))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issues := pkg.Validate(); issues != nil {
+		t.Errorf("Validate() = %v, want nil", issues)
+	}
+}
+
+func TestTypeInference(t *testing.T) {
+	fsys := fstest.MapFS{
+		"manifest.yml": &fstest.MapFile{
+			Data: []byte("name: test\ntitle: Test\nversion: 1.0.0\nformat_version: 3.3.0\n"),
+		},
+		"changelog.yml": &fstest.MapFile{
+			Data: []byte("- version: 1.0.0\n  changes:\n    - description: Init.\n      type: enhancement\n      link: https://example.com/1\n"),
+		},
+		"data_stream/logs/manifest.yml": &fstest.MapFile{
+			Data: []byte("title: Logs\ntype: logs\n"),
+		},
+	}
+
+	// Strict by default.
+	if _, err := Read(".", WithFS(fsys)); err == nil {
+		t.Fatal("expected error for manifest without type")
+	}
+
+	pkg, err := Read(".", WithFS(fsys), WithTypeInference())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pkg.IntegrationManifest() == nil {
+		t.Fatal("expected package to be read as an integration")
+	}
+	if got := pkg.Manifest().Type; got != pkgspec.ManifestTypeIntegration {
+		t.Errorf("manifest type = %q, want integration", got)
+	}
+	if _, ok := pkg.DataStreams["logs"]; !ok {
+		t.Error("logs data stream not loaded")
+	}
+
+	// Without a recognizable layout the type still cannot be determined.
+	delete(fsys, "data_stream/logs/manifest.yml")
+	if _, err := Read(".", WithFS(fsys), WithTypeInference()); err == nil {
+		t.Error("expected error when type cannot be inferred")
+	}
+}
+
+func TestNodePositions(t *testing.T) {
+	fsys := fstest.MapFS{
+		"manifest.yml": &fstest.MapFile{
+			Data: []byte("name: test\ntitle: Test\nversion: 1.0.0\ntype: integration\nformat_version: 3.3.0\n"),
+		},
+		"changelog.yml": &fstest.MapFile{
+			Data: []byte("- version: 1.0.0\n  changes:\n    - description: Init.\n      type: enhancement\n      link: https://example.com/1\n"),
+		},
+		"data_stream/access/manifest.yml": &fstest.MapFile{Data: []byte("title: Access\ntype: logs\n")},
+		"data_stream/access/fields/fields.yml": &fstest.MapFile{
+			Data: []byte("- name: http\n  type: group\n  fields:\n    - name: status\n      type: long\n"),
+		},
+		"data_stream/access/elasticsearch/ingest_pipeline/default.yml": &fstest.MapFile{
+			Data: []byte("processors:\n  - set:\n      field: event.kind\n      value: event\n      on_failure:\n        - rename:\n            field: event.original\n"),
+		},
+	}
+
+	pkg, err := Read(".", WithFS(fsys))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pkg.Positions != nil {
+		t.Fatal("expected nil Positions without WithNodePositions")
+	}
+
+	pkg, err = Read(".", WithFS(fsys), WithNodePositions(), WithPathPrefix("packages/test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds := pkg.DataStreams["access"]
+	status := ds.Fields["fields.yml"].Fields[0].Fields[0]
+	pos, ok := pkg.Positions.Lookup(status.FilePath(), status.JsonPointer+"/type")
+	if want := (Position{Line: 5, Column: 13}); !ok || pos != want {
+		t.Errorf("status type position = %+v, %v; want %+v", pos, ok, want)
+	}
+
+	pf := ds.Pipelines["default.yml"]
+	var got []string
+	err = pkgspec.WalkProcessors(pf.Pipeline.Processors, func(p *pkgspec.Processor, ptr string) error {
+		pos, ok := pkg.Positions.Lookup(p.FilePath(), "/processors"+ptr+"/field")
+		if !ok {
+			t.Errorf("no position for %s field", ptr)
+		}
+		got = append(got, fmt.Sprintf("%s %d:%d", ptr, pos.Line, pos.Column))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"/0/set 3:14", "/0/set/on_failure/0/rename 7:20"}
+	if !slices.Equal(got, want) {
+		t.Errorf("processor field positions = %q, want %q", got, want)
+	}
+
+	// Files are keyed by their prefixed path.
+	if _, ok := pkg.Positions["packages/test/data_stream/access/elasticsearch/ingest_pipeline/default.yml"]; !ok {
+		t.Errorf("missing prefixed pipeline path in %v", slices.Collect(maps.Keys(pkg.Positions)))
+	}
+}
+
+func TestAllFieldsOrder(t *testing.T) {
+	fsys := fstest.MapFS{
+		"manifest.yml": &fstest.MapFile{
+			Data: []byte("name: test\ntitle: Test\nversion: 1.0.0\ntype: integration\nformat_version: 3.3.0\n"),
+		},
+		"changelog.yml": &fstest.MapFile{
+			Data: []byte("- version: 1.0.0\n  changes:\n    - description: Init.\n      type: enhancement\n      link: https://example.com/1\n"),
+		},
+		"data_stream/logs/manifest.yml":           &fstest.MapFile{Data: []byte("title: Logs\ntype: logs\n")},
+		"data_stream/logs/fields/fields.yml":      &fstest.MapFile{Data: []byte("- name: z.local\n  type: keyword\n- name: a.local\n  type: keyword\n")},
+		"data_stream/logs/fields/base-fields.yml": &fstest.MapFile{Data: []byte("- name: data_stream.type\n  type: constant_keyword\n")},
+		"data_stream/logs/fields/ecs.yml":         &fstest.MapFile{Data: []byte("- name: message\n  external: ecs\n")},
+		"data_stream/logs/fields/agent.yml":       &fstest.MapFile{Data: []byte("- name: agent.id\n  type: keyword\n")},
+	}
+
+	want := []string{"agent.id", "data_stream.type", "message", "z.local", "a.local"}
+	for range 5 {
+		pkg, err := Read(".", WithFS(fsys))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var names []string
+		for _, f := range pkg.DataStreams["logs"].AllFields() {
+			names = append(names, f.Name)
+		}
+		if !slices.Equal(names, want) {
+			t.Fatalf("AllFields() names = %v, want %v", names, want)
+		}
+	}
+}
+
+func TestKibanaSecurityRule(t *testing.T) {
+	ruleJSON := `{
+  "id": "test-rule-id-1",
+  "type": "security-rule",
+  "attributes": {
+    "name": "Okta Suspicious Login Attempt",
+    "description": "Detects suspicious login attempts via Okta SSO.",
+    "rule_id": "okta-suspicious-login-001",
+    "type": "eql",
+    "severity": "high",
+    "risk_score": 73,
+    "language": "eql",
+    "query": "authentication where event.dataset == \"okta.system\" and event.outcome == \"failure\"",
+    "index": ["logs-okta.system-*", "filebeat-*"],
+    "tags": ["Domain: Cloud", "Data Source: Okta"],
+    "threat": [
+      {
+        "framework": "MITRE ATT&CK",
+        "tactic": {"id": "TA0001", "name": "Initial Access", "reference": "https://attack.mitre.org/tactics/TA0001/"},
+        "technique": [
+          {
+            "id": "T1078",
+            "name": "Valid Accounts",
+            "reference": "https://attack.mitre.org/techniques/T1078/",
+            "subtechnique": [
+              {"id": "T1078.004", "name": "Cloud Accounts", "reference": "https://attack.mitre.org/techniques/T1078/004/"}
+            ]
+          }
+        ]
+      }
+    ],
+    "required_fields": [
+      {"name": "event.dataset", "type": "keyword", "ecs": true},
+      {"name": "okta.debug_context", "type": "flattened", "ecs": false}
+    ]
+  },
+  "references": []
+}`
+	dashboardJSON := `{"id": "overview", "type": "dashboard", "attributes": {"title": "Overview"}}`
+
+	fsys := fstest.MapFS{
+		"manifest.yml": &fstest.MapFile{
+			Data: []byte("name: test\ntitle: Test\nversion: 1.0.0\ntype: integration\nformat_version: 3.3.0\n"),
+		},
+		"changelog.yml": &fstest.MapFile{
+			Data: []byte("- version: 1.0.0\n  changes:\n    - description: Init.\n      type: enhancement\n      link: https://example.com/1\n"),
+		},
+		"kibana/security_rule/rule.json": &fstest.MapFile{Data: []byte(ruleJSON)},
+		"kibana/dashboard/overview.json": &fstest.MapFile{Data: []byte(dashboardJSON)},
+	}
+
+	pkg, err := Read(".", WithFS(fsys))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := pkg.KibanaObjects["dashboard"][0].SecurityRule(); ok {
+		t.Error("dashboard decoded as a security rule")
+	}
+
+	rule, ok := pkg.KibanaObjects["security_rule"][0].SecurityRule()
+	if !ok {
+		t.Fatal("security rule not decoded")
+	}
+
+	got := []string{rule.RuleID, rule.Name, rule.Description, rule.Type, rule.Language, rule.Severity}
+	want := []string{
+		"okta-suspicious-login-001",
+		"Okta Suspicious Login Attempt",
+		"Detects suspicious login attempts via Okta SSO.",
+		"eql", "eql", "high",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("rule attributes = %q, want %q", got, want)
+	}
+	if !strings.HasPrefix(rule.Query, "authentication where") {
+		t.Errorf("query = %q", rule.Query)
+	}
+	if rule.RiskScore == nil || *rule.RiskScore != 73 {
+		t.Errorf("risk_score = %v, want 73", rule.RiskScore)
+	}
+	if want := []string{"logs-okta.system-*", "filebeat-*"}; !slices.Equal(rule.Index, want) {
+		t.Errorf("index = %q, want %q", rule.Index, want)
+	}
+	if want := []string{"Domain: Cloud", "Data Source: Okta"}; !slices.Equal(rule.Tags, want) {
+		t.Errorf("tags = %q, want %q", rule.Tags, want)
+	}
+
+	if len(rule.Threat) != 1 {
+		t.Fatalf("threat count = %d, want 1", len(rule.Threat))
+	}
+	threat := rule.Threat[0]
+	if threat.Framework != "MITRE ATT&CK" || threat.Tactic.ID != "TA0001" {
+		t.Errorf("threat = %+v", threat)
+	}
+	if len(threat.Technique) != 1 || threat.Technique[0].ID != "T1078" ||
+		len(threat.Technique[0].Subtechnique) != 1 || threat.Technique[0].Subtechnique[0].ID != "T1078.004" {
+		t.Errorf("techniques = %+v", threat.Technique)
+	}
+
+	wantFields := []pkgspec.SecurityRuleRequiredField{
+		{Name: "event.dataset", Type: "keyword", ECS: true},
+		{Name: "okta.debug_context", Type: "flattened"},
+	}
+	if !slices.Equal(rule.RequiredFields, wantFields) {
+		t.Errorf("required_fields = %+v, want %+v", rule.RequiredFields, wantFields)
+	}
+}
+
+func TestKibanaExceptionList(t *testing.T) {
+	listJSON := `{
+  "id": "shared-exceptions",
+  "type": "exception-list-agnostic",
+  "attributes": {
+    "list_id": "okta-trusted-admins",
+    "list_type": "list",
+    "name": "Okta Trusted Admins",
+    "description": "Administrators excluded from Okta rules.",
+    "type": "detection",
+    "namespace_type": "agnostic",
+    "tags": ["Okta"],
+    "os_types": []
+  },
+  "references": []
+}`
+	itemJSON := `{
+  "id": "shared-exceptions-item-1",
+  "type": "exception-list-agnostic",
+  "attributes": {
+    "list_id": "okta-trusted-admins",
+    "item_id": "break-glass-account",
+    "name": "Break glass account",
+    "description": "Emergency access account.",
+    "type": "simple",
+    "namespace_type": "agnostic",
+    "entries": [
+      {"field": "user.name", "operator": "included", "type": "match", "value": "breakglass"}
+    ]
+  },
+  "references": []
+}`
+
+	fsys := fstest.MapFS{
+		"manifest.yml": &fstest.MapFile{
+			Data: []byte("name: test\ntitle: Test\nversion: 1.0.0\ntype: integration\nformat_version: 3.3.0\n"),
+		},
+		"kibana/exception_list/list.json":      &fstest.MapFile{Data: []byte(listJSON)},
+		"kibana/exception_list_item/item.json": &fstest.MapFile{Data: []byte(itemJSON)},
+		"kibana/dashboard/overview.json":       &fstest.MapFile{Data: []byte(`{"id": "overview", "type": "dashboard", "attributes": {"title": "Overview"}}`)},
+	}
+
+	pkg, err := Read(".", WithFS(fsys))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := pkg.KibanaObjects["dashboard"][0].ExceptionList(); ok {
+		t.Error("dashboard decoded as an exception list")
+	}
+
+	if n := len(pkg.KibanaObjects["exception_list"]); n != 1 {
+		t.Fatalf("exception_list count = %d, want 1", n)
+	}
+	list, ok := pkg.KibanaObjects["exception_list"][0].ExceptionList()
+	if !ok {
+		t.Fatal("exception list not decoded")
+	}
+	want := &pkgspec.ExceptionList{
+		ListID:        "okta-trusted-admins",
+		ListType:      pkgspec.ExceptionListTypeList,
+		Name:          "Okta Trusted Admins",
+		Description:   "Administrators excluded from Okta rules.",
+		Type:          "detection",
+		NamespaceType: "agnostic",
+		Tags:          []string{"Okta"},
+		OSTypes:       []string{},
+	}
+	if !reflect.DeepEqual(list, want) {
+		t.Errorf("ExceptionList() = %+v, want %+v", list, want)
+	}
+
+	// An item without list_type is classified by its item_id.
+	item, ok := pkg.KibanaObjects["exception_list_item"][0].ExceptionList()
+	if !ok {
+		t.Fatal("exception list item not decoded")
+	}
+	if item.ListType != pkgspec.ExceptionListTypeItem || item.ItemID != "break-glass-account" || item.ListID != "okta-trusted-admins" {
+		t.Errorf("item list_type, item_id, list_id = %q, %q, %q, want item, break-glass-account, okta-trusted-admins",
+			item.ListType, item.ItemID, item.ListID)
+	}
+	if len(item.Entries) != 1 {
+		t.Errorf("item entries = %v, want 1 entry", item.Entries)
+	}
+}
+
+func TestSpecCompatible(t *testing.T) {
+	tests := []struct {
+		formatVersion string
+		want          bool
+		wantErr       bool
+	}{
+		{formatVersion: "3.0.0", want: true},
+		{formatVersion: pkgspec.SpecVersion, want: true},
+		{formatVersion: "99.0.0", want: false},
+		{formatVersion: "3.x", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.formatVersion, func(t *testing.T) {
+			fsys := fstest.MapFS{
+				"manifest.yml": &fstest.MapFile{
+					Data: []byte("name: test\ntitle: Test\nversion: 1.0.0\ntype: input\nformat_version: " + tc.formatVersion + "\n"),
+				},
+			}
+
+			pkg, err := Read(".", WithFS(fsys))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := pkg.SpecCompatible()
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("SpecCompatible() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("SpecCompatible() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDevConfigs(t *testing.T) {
+	fsys := fstest.MapFS{
+		"manifest.yml": &fstest.MapFile{
+			Data: []byte("name: test\ntitle: Test\nversion: 1.0.0\ntype: integration\nformat_version: 3.3.0\n"),
+		},
+		"_dev/benchmark/rally/logs-benchmark.yml":         &fstest.MapFile{Data: []byte("description: Benchmark logs.\n")},
+		"_dev/benchmark/system/logs-benchmark.yml":        &fstest.MapFile{Data: []byte("description: Benchmark logs.\n")},
+		"_dev/deploy/docker/docker-compose.yml":           &fstest.MapFile{Data: []byte("services: {}\n")},
+		"_dev/profile/default.yml":                        &fstest.MapFile{Data: []byte("name: default\n")},
+		"_dev/build/build.yml":                            &fstest.MapFile{Data: []byte("dependencies: {}\n")},
+		"_dev/test/config.yml":                            &fstest.MapFile{Data: []byte("{}\n")},
+		"_dev/README.md":                                  &fstest.MapFile{Data: []byte("# Dev\n")},
+		"data_stream/logs/manifest.yml":                   &fstest.MapFile{Data: []byte("title: Logs\ntype: logs\n")},
+		"data_stream/logs/_dev/benchmark/pipeline/a.json": &fstest.MapFile{Data: []byte("{}\n")},
+	}
+
+	pkg, err := Read(".", WithFS(fsys))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pkg.DevConfigs != nil {
+		t.Fatalf("expected nil DevConfigs without WithDevConfigs, got %v", pkg.DevConfigs)
+	}
+
+	pkg, err = Read(".", WithFS(fsys), WithDevConfigs(), WithPathPrefix("packages/test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string][]string{
+		"benchmark": {
+			"packages/test/_dev/benchmark/rally/logs-benchmark.yml",
+			"packages/test/_dev/benchmark/system/logs-benchmark.yml",
+		},
+		"deploy":  {"packages/test/_dev/deploy/docker/docker-compose.yml"},
+		"profile": {"packages/test/_dev/profile/default.yml"},
+	}
+	if !maps.EqualFunc(pkg.DevConfigs, want, slices.Equal) {
+		t.Errorf("DevConfigs = %v, want %v", pkg.DevConfigs, want)
+	}
+}
+
+func TestPathPrefixForwardSlashes(t *testing.T) {
+	fsys := dirMapFS(t, "testdata/integration_pkg")
+
+	// A prefix built with filepath.Join on Windows.
+	pkg, err := Read(".", WithFS(fsys), WithPathPrefix(`packages\test`),
+		WithAgentTemplates(), WithTestConfigs(), WithDevConfigs(), WithNodePositions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	paths := fileMetadataPaths(reflect.ValueOf(pkg))
+	paths = append(paths, fileMetadataPaths(reflect.ValueOf(pkg.Manifest()))...)
+	for _, d := range pkg.Docs {
+		paths = append(paths, d.Path())
+	}
+	for _, devPaths := range pkg.DevConfigs {
+		paths = append(paths, devPaths...)
+	}
+	for p := range pkg.Positions {
+		paths = append(paths, p)
+	}
+	if len(paths) == 0 {
+		t.Fatal("no file paths found")
+	}
+	for _, p := range paths {
+		if strings.Contains(p, `\`) || !strings.HasPrefix(p, "packages/test/") {
+			t.Errorf("path %q is not a forward-slash path under packages/test/", p)
+		}
+	}
+}
+
+// dirMapFS loads the files below root into an in-memory FS.
+func dirMapFS(t *testing.T, root string) fstest.MapFS {
+	t.Helper()
+
+	fsys := fstest.MapFS{}
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		fsys[filepath.ToSlash(rel)] = &fstest.MapFile{Data: data}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fsys
+}
+
+// fileMetadataPaths returns the non-empty FilePath of every
+// pkgspec.FileMetadata reachable from v through exported fields.
+func fileMetadataPaths(v reflect.Value) []string {
+	if v.CanInterface() {
+		if m, ok := v.Interface().(pkgspec.FileMetadata); ok {
+			if m.FilePath() == "" {
+				return nil
+			}
+			return []string{m.FilePath()}
+		}
+	}
+
+	var paths []string
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			paths = fileMetadataPaths(v.Elem())
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				paths = append(paths, fileMetadataPaths(v.Field(i))...)
+			}
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			paths = append(paths, fileMetadataPaths(v.Index(i))...)
+		}
+	case reflect.Map:
+		for iter := v.MapRange(); iter.Next(); {
+			paths = append(paths, fileMetadataPaths(iter.Value())...)
+		}
+	}
+	return paths
+}
+
+func TestValidateChangelogLinks(t *testing.T) {
+	fsys := fstest.MapFS{
+		"manifest.yml": &fstest.MapFile{
+			Data: []byte("name: test\ntitle: Test\nversion: 1.1.0\ntype: input\nformat_version: 3.3.0\n"),
+		},
+		"changelog.yml": &fstest.MapFile{
+			Data: []byte(`- version: 1.1.0
+  changes:
+    - description: Fixed.
+      type: bugfix
+      link: N/A
+    - description: Relative.
+      type: enhancement
+      link: /elastic/integrations/pull/2
+- version: 1.0.0
+  changes:
+    - description: Init.
+      type: enhancement
+      link: https://github.com/elastic/integrations/pull/1
+`),
+		},
+	}
+
+	pkg, err := Read(".", WithFS(fsys))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		`changelog.yml:3:7: changelog entry link "N/A" is not an absolute URL`,
+		`changelog.yml:6:7: changelog entry link "/elastic/integrations/pull/2" is not an absolute URL`,
+	}
+	var got []string
+	for _, issue := range pkg.Validate() {
+		got = append(got, issue.String())
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Validate() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestStrictSampleEvent(t *testing.T) {
+	fsys := fstest.MapFS{
+		"manifest.yml":                       {Data: []byte("name: test\ntitle: Test\nversion: 1.0.0\ntype: integration\nformat_version: 3.3.0\n")},
+		"data_stream/logs/manifest.yml":      {Data: []byte("title: Logs\ntype: logs\n")},
+		"data_stream/logs/sample_event.json": {Data: []byte(`{"event": {"kind": "event"}`)},
+	}
+
+	// The default is lenient and loads the raw bytes.
+	pkg, err := Read(".", WithFS(fsys))
+	if err != nil {
+		t.Fatalf("lenient read: %v", err)
+	}
+	if len(pkg.DataStreams["logs"].SampleEvent) == 0 {
+		t.Error("sample event not loaded")
+	}
+
+	_, err = Read(".", WithFS(fsys), WithStrictSampleEvent())
+	if err == nil {
+		t.Fatal("expected error for malformed sample event")
+	}
+	if !strings.Contains(err.Error(), "data_stream/logs/sample_event.json is not valid JSON") {
+		t.Errorf("error = %v, want it to name the sample event file", err)
+	}
+
+	// Named sample events are validated too.
+	fsys["data_stream/logs/sample_event.json"] = &fstest.MapFile{Data: []byte(`{}`)}
+	fsys["data_stream/logs/sample_event_bad.json"] = &fstest.MapFile{Data: []byte(`not json`)}
+	_, err = Read(".", WithFS(fsys), WithStrictSampleEvent())
+	if err == nil || !strings.Contains(err.Error(), "data_stream/logs/sample_event_bad.json is not valid JSON") {
+		t.Errorf("error = %v, want it to name sample_event_bad.json", err)
+	}
+
+	delete(fsys, "data_stream/logs/sample_event_bad.json")
+	if _, err := Read(".", WithFS(fsys), WithStrictSampleEvent()); err != nil {
+		t.Errorf("strict read of valid sample event: %v", err)
+	}
+}
+
+func TestValidateFieldDescriptions(t *testing.T) {
+	fsys := fstest.MapFS{
+		"manifest.yml":                       {Data: []byte("name: test\ntitle: Test\nversion: 1.0.0\ntype: integration\nformat_version: 3.3.0\n")},
+		"data_stream/logs/manifest.yml":      {Data: []byte("title: Logs\ntype: logs\n")},
+		"data_stream/logs/sample_event.json": {Data: []byte(`{"message": "test"}`)},
+		"data_stream/logs/fields/ecs.yml": {Data: []byte(`- name: event.kind
+  external: ecs
+`)},
+		"data_stream/logs/fields/fields.yml": {Data: []byte(`- name: app
+  type: group
+  fields:
+    - name: documented
+      type: keyword
+      description: A documented field.
+    - name: undocumented
+      type: keyword
+`)},
+	}
+
+	pkg, err := Read(".", WithFS(fsys))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"data_stream/logs/fields/fields.yml:7:7: field app.undocumented has no description"}
+	var got []string
+	for _, issue := range pkg.Validate() {
+		got = append(got, issue.String())
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Validate() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestValidateStreamInputs(t *testing.T) {
+	fsys := fstest.MapFS{
+		"manifest.yml": {Data: []byte(`name: test
+title: Test
+version: 1.0.0
+type: integration
+format_version: 3.3.0
+policy_templates:
+  - name: logs
+    title: Logs
+    description: Collect logs.
+    inputs:
+      - type: logfile
+        title: Log files
+        description: Collect log files.
+`)},
+		"data_stream/logs/manifest.yml": {Data: []byte(`title: Logs
+type: logs
+streams:
+  - input: logfile
+    title: Log files
+    description: Collect log files.
+  - input: httpjson
+    title: API
+    description: Collect from the API.
+`)},
+		"data_stream/logs/sample_event.json": {Data: []byte(`{"message": "test"}`)},
+	}
+
+	pkg, err := Read(".", WithFS(fsys))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"data_stream/logs/manifest.yml:7:5: data stream logs stream input httpjson is not declared by any policy template"}
+	var got []string
+	for _, issue := range pkg.Validate() {
+		got = append(got, issue.String())
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Validate() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestValidateProcessorFields(t *testing.T) {
+	fsys := fstest.MapFS{
+		"manifest.yml":                       {Data: []byte("name: test\ntitle: Test\nversion: 1.0.0\ntype: integration\nformat_version: 3.3.0\n")},
+		"data_stream/logs/manifest.yml":      {Data: []byte("title: Logs\ntype: logs\n")},
+		"data_stream/logs/sample_event.json": {Data: []byte(`{"message": "test"}`)},
+		"data_stream/logs/fields/fields.yml": {Data: []byte(`- name: test
+  type: group
+  description: Test fields.
+  fields:
+    - name: status
+      type: keyword
+      description: Status.
+    - name: labels
+      type: object
+      description: Labels.
+- name: metrics.*.count
+  type: long
+  description: Counts.
+`)},
+		"data_stream/logs/elasticsearch/ingest_pipeline/default.yml": {Data: []byte(`processors:
+  - set:
+      field: test.status
+      value: ok
+  - rename:
+      field: message
+      target_field: test.original
+  - convert:
+      field: test.size
+      type: long
+  - set:
+      field: test.labels.env
+      value: prod
+  - set:
+      field: metrics.cpu.count
+      value: 1
+  - set:
+      field: _tmp.value
+      value: x
+  - set:
+      field: "{{{target}}}"
+      value: x
+on_failure:
+  - set:
+      field: error.message
+      value: failed
+`)},
+	}
+
+	pkg, err := Read(".", WithFS(fsys))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The check is opt-in.
+	if issues := pkg.Validate(); len(issues) != 0 {
+		t.Errorf("Validate() = %v, want no issues", issues)
+	}
+
+	want := []string{
+		"data_stream/logs/elasticsearch/ingest_pipeline/default.yml:5:5: data stream logs pipeline default.yml rename processor writes undeclared field test.original",
+		"data_stream/logs/elasticsearch/ingest_pipeline/default.yml:8:5: data stream logs pipeline default.yml convert processor writes undeclared field test.size",
+		"data_stream/logs/elasticsearch/ingest_pipeline/default.yml:24:5: data stream logs pipeline default.yml set processor writes undeclared field error.message",
+	}
+	var got []string
+	for _, issue := range pkg.Validate(CheckProcessorFields()) {
+		got = append(got, issue.String())
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Validate(CheckProcessorFields()) =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestValidateSampleEvents(t *testing.T) {
+	fsys := fstest.MapFS{
+		"manifest.yml": &fstest.MapFile{
+			Data: []byte("name: test\ntitle: Test\nversion: 1.0.0\ntype: integration\nformat_version: 3.3.0\n"),
+		},
+		"data_stream/access/manifest.yml":      &fstest.MapFile{Data: []byte("title: Access\ntype: logs\n")},
+		"data_stream/access/sample_event.json": &fstest.MapFile{Data: []byte(`{"message": "GET /"}`)},
+		"data_stream/error/manifest.yml":       &fstest.MapFile{Data: []byte("title: Error\ntype: logs\n")},
+	}
+
+	pkg, err := Read(".", WithFS(fsys))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"data_stream/error/manifest.yml:1:1: data stream error has no sample event (data_stream/error/sample_event.json)",
+	}
+	var got []string
+	for _, issue := range pkg.Validate() {
+		got = append(got, issue.String())
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Validate() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+// manyFieldFilesFS returns a package whose data stream has n field files,
+// each declaring a group of fieldsPerFile keyword fields.
+func manyFieldFilesFS(n, fieldsPerFile int) fstest.MapFS {
+	fsys := fstest.MapFS{
+		"manifest.yml":                  {Data: []byte("name: test\ntitle: Test\nversion: 1.0.0\ntype: integration\nformat_version: 3.3.0\n")},
+		"data_stream/logs/manifest.yml": {Data: []byte("title: Logs\ntype: logs\n")},
+	}
+	for i := range n {
+		var b strings.Builder
+		fmt.Fprintf(&b, "- name: group%d\n  type: group\n  fields:\n", i)
+		for j := range fieldsPerFile {
+			fmt.Fprintf(&b, "    - name: field%d\n      type: keyword\n      description: Field %d of file %d.\n", j, j, i)
+		}
+		fsys[fmt.Sprintf("data_stream/logs/fields/file%02d.yml", i)] = &fstest.MapFile{Data: []byte(b.String())}
+	}
+	return fsys
+}
+
+func TestWithConcurrency(t *testing.T) {
+	fsys := manyFieldFilesFS(20, 10)
+
+	sequential, err := Read(".", WithFS(fsys), WithConcurrency(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parallel, err := Read(".", WithFS(fsys), WithConcurrency(8))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := sequential.DataStreams["logs"].Fields
+	got := parallel.DataStreams["logs"].Fields
+	if len(want) != 20 {
+		t.Fatalf("sequential read found %d field files, want 20", len(want))
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Error("field files differ between concurrency 1 and 8")
+	}
+
+	// A decode error is reported for the first failing file regardless of
+	// scheduling.
+	fsys["data_stream/logs/fields/file03.yml"] = &fstest.MapFile{Data: []byte("- name: [\n")}
+	fsys["data_stream/logs/fields/file07.yml"] = &fstest.MapFile{Data: []byte("- name: [\n")}
+	_, err = Read(".", WithFS(fsys), WithConcurrency(8))
+	if err == nil || !strings.Contains(err.Error(), "file03.yml") {
+		t.Errorf("Read error = %v, want error for file03.yml", err)
+	}
+}
+
+func BenchmarkReadFieldsConcurrency(b *testing.B) {
+	fsys := manyFieldFilesFS(50, 200)
+	for _, n := range []int{1, 4} {
+		b.Run(fmt.Sprintf("concurrency=%d", n), func(b *testing.B) {
+			for b.Loop() {
+				if _, err := Read(".", WithFS(fsys), WithConcurrency(n)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestApproxSize(t *testing.T) {
+	small, err := Read(".", WithFS(manyFieldFilesFS(2, 10)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	large, err := Read(".", WithFS(manyFieldFilesFS(4, 10)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if small.ApproxSize() <= 0 || large.ApproxSize() <= small.ApproxSize() {
+		t.Errorf("ApproxSize() = %d for 20 fields and %d for 40 fields, want positive and growing",
+			small.ApproxSize(), large.ApproxSize())
+	}
+
+	// Optional content adds to the estimate.
+	base, err := Read("testdata/integration_pkg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	full, err := Read("testdata/integration_pkg", WithAgentTemplates(), WithImageMetadata(), WithNodePositions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if full.ApproxSize() <= base.ApproxSize() {
+		t.Errorf("ApproxSize() with optional content = %d, want more than %d", full.ApproxSize(), base.ApproxSize())
+	}
+}
+
+func TestContentHash(t *testing.T) {
+	fsys := dirMapFS(t, "testdata/integration_pkg")
+
+	hash := func(fsys fs.FS, opts ...Option) string {
+		t.Helper()
+		pkg, err := Read(".", append([]Option{WithFS(fsys)}, opts...)...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pkg.ContentHash()
+	}
+
+	want := hash(fsys)
+	if len(want) != 64 {
+		t.Fatalf("ContentHash() = %q, want a hex SHA-256 digest", want)
+	}
+	for i := 0; i < 3; i++ {
+		if got := hash(fsys); got != want {
+			t.Fatalf("ContentHash() of a second read = %s, want %s", got, want)
+		}
+	}
+	if got := hash(fsys, WithPathPrefix("packages/test"), WithNodePositions()); got != want {
+		t.Errorf("ContentHash() with WithPathPrefix = %s, want %s", got, want)
+	}
+
+	for _, name := range []string{
+		"manifest.yml",
+		"changelog.yml",
+		"data_stream/logs/fields/base-fields.yml",
+		"data_stream/logs/elasticsearch/ingest_pipeline/default.yml",
+	} {
+		modified := maps.Clone(fsys)
+		data := slices.Clone(fsys[name].Data)
+		data = bytes.Replace(data, []byte("description: "), []byte("description: Changed "), 1)
+		if bytes.Equal(data, fsys[name].Data) {
+			t.Fatalf("%s has no description to modify", name)
+		}
+		modified[name] = &fstest.MapFile{Data: data}
+
+		if got := hash(modified); got == want {
+			t.Errorf("ContentHash() unchanged after modifying %s", name)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package pkgreader
+
+import (
+	"maps"
+	"slices"
+)
+
+// RerouteGraph is a directed graph of datasets built from the routing rules
+// of a package's data streams. An edge from A to B means a document sent to
+// dataset A may be rerouted to dataset B. Targets that are field references
+// (e.g. "{{labels.dataset}}") are kept verbatim as nodes.
+type RerouteGraph struct {
+	edges map[string][]string // source dataset → sorted, deduplicated target datasets
+}
+
+// RerouteGraph builds the reroute graph for the package. A rule set without
+// a source_dataset applies to the dataset of the data stream that declares it,
+// as resolved by [DataStream.Dataset].
+func (p *Package) RerouteGraph() *RerouteGraph {
+	g := &RerouteGraph{edges: map[string][]string{}}
+
+	var pkgName string
+	if m := p.Manifest(); m != nil {
+		pkgName = m.Name
+	}
+
+	for _, ds := range p.DataStreams {
+		for _, rrs := range ds.RoutingRules {
+			source := rrs.SourceDataset
+			if source == "" {
+				source = ds.Dataset(pkgName)
+			}
+			for i := range rrs.Rules {
+				for _, t := range rrs.Rules[i].Targets() {
+					if t.Dataset == "" {
+						continue
+					}
+					g.edges[source] = append(g.edges[source], t.Dataset)
+				}
+			}
+		}
+	}
+
+	for source, targets := range g.edges {
+		slices.Sort(targets)
+		g.edges[source] = slices.Compact(targets)
+	}
+
+	return g
+}
+
+// Sources returns the datasets that have at least one reroute rule, sorted.
+func (g *RerouteGraph) Sources() []string {
+	return slices.Sorted(maps.Keys(g.edges))
+}
+
+// Targets returns the datasets that documents in dataset may be rerouted to
+// directly, sorted.
+func (g *RerouteGraph) Targets(dataset string) []string {
+	return slices.Clone(g.edges[dataset])
+}
+
+// Reachable returns every dataset a document sent to dataset can end up in
+// after any number of reroutes, sorted. The starting dataset itself is only
+// included when it can be reached again through a cycle.
+func (g *RerouteGraph) Reachable(dataset string) []string {
+	seen := map[string]bool{}
+	stack := slices.Clone(g.edges[dataset])
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if seen[n] {
+			continue
+		}
+		seen[n] = true
+		stack = append(stack, g.edges[n]...)
+	}
+	return slices.Sorted(maps.Keys(seen))
+}
+
+// Cycles returns the reroute loops in the graph. Each cycle is the list of
+// datasets along the loop, starting from the first one visited; a rule that
+// reroutes a dataset to itself is reported as a single-element cycle.
+func (g *RerouteGraph) Cycles() [][]string {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := map[string]int{}
+	var path []string
+	var cycles [][]string
+
+	var visit func(n string)
+	visit = func(n string) {
+		state[n] = visiting
+		path = append(path, n)
+		for _, t := range g.edges[n] {
+			switch state[t] {
+			case unvisited:
+				visit(t)
+			case visiting:
+				start := slices.Index(path, t)
+				cycles = append(cycles, slices.Clone(path[start:]))
+			}
+		}
+		path = path[:len(path)-1]
+		state[n] = done
+	}
+
+	for _, source := range g.Sources() {
+		if state[source] == unvisited {
+			visit(source)
+		}
+	}
+	return cycles
+}
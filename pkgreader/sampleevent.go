@@ -0,0 +1,50 @@
+package pkgreader
+
+import (
+	"encoding/json"
+	"slices"
+)
+
+// SampleEventPaths returns the sorted, deduplicated dotted paths of every
+// leaf value in a sample event document. Nested objects contribute their key
+// to the path, so {"event": {"dataset": "x"}} yields "event.dataset". Array
+// elements share the path of the array: an array of scalars is a single
+// leaf, and the keys of objects inside an array are joined to the array's
+// path. Empty objects and empty arrays produce no paths.
+func SampleEventPaths(event json.RawMessage) ([]string, error) {
+	var v any
+	if err := json.Unmarshal(event, &v); err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	collectLeafPaths(v, "", seen)
+
+	paths := make([]string, 0, len(seen))
+	for p := range seen {
+		paths = append(paths, p)
+	}
+	slices.Sort(paths)
+	return paths, nil
+}
+
+func collectLeafPaths(v any, prefix string, seen map[string]bool) {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, child := range v {
+			p := k
+			if prefix != "" {
+				p = prefix + "." + k
+			}
+			collectLeafPaths(child, p, seen)
+		}
+	case []any:
+		for _, elem := range v {
+			collectLeafPaths(elem, prefix, seen)
+		}
+	default:
+		if prefix != "" {
+			seen[prefix] = true
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package pkgreader
+
+import (
+	"unsafe"
+
+	"github.com/andrewkroh/go-package-spec/pkgspec"
+)
+
+// Per-item overheads used by ApproxSize for values whose size is not
+// dominated by their string content.
+const (
+	approxNodeBytes     = 64  // a map entry or slice element holding a dynamic value
+	approxPositionBytes = 128 // one Positions entry: JSON pointer key plus line/column
+)
+
+// ApproxSize estimates the number of bytes of memory retained by the loaded
+// package. It is meant for batch loaders that budget memory, for example to
+// flush a batch of packages to a database before loading more, and is not
+// exact. The estimate counts the dominant components: fields, ingest
+// pipeline processors, changelog entries, sample events, Kibana saved
+// objects, agent templates, image metadata, and node positions. Image and
+// doc file contents are not retained by the reader and are not counted.
+func (p *Package) ApproxSize() int64 {
+	var n int64
+	for i := range p.Changelog {
+		for _, e := range p.Changelog[i].Changes {
+			n += int64(unsafe.Sizeof(e)) + int64(len(e.Description)+len(e.Link))
+		}
+	}
+
+	for _, ds := range p.DataStreams {
+		n += fieldsFilesSize(ds.Fields)
+		n += pipelinesSize(ds.Pipelines)
+		n += int64(len(ds.SampleEvent))
+		for _, e := range ds.SampleEvents {
+			n += int64(len(e))
+		}
+		n += agentTemplatesSize(ds.AgentTemplates)
+	}
+	n += fieldsFilesSize(p.Fields)
+	n += pipelinesSize(p.Pipelines)
+	for _, td := range p.Transforms {
+		n += fieldsFilesSize(td.Fields)
+	}
+	n += int64(len(p.SampleEvent))
+	for _, e := range p.SampleEvents {
+		n += int64(len(e))
+	}
+
+	for _, objs := range p.KibanaObjects {
+		for _, o := range objs {
+			n += int64(unsafe.Sizeof(*o)) + int64(len(o.Attributes.Title)+len(o.Attributes.Description)+len(o.Raw))
+			n += valueSize(o.Attributes.Extras)
+		}
+	}
+	n += agentTemplatesSize(p.AgentTemplates)
+	for _, img := range p.Images {
+		n += int64(unsafe.Sizeof(*img)) + int64(len(img.SHA256)+len(img.path))
+	}
+	for _, ptrs := range p.Positions {
+		n += int64(len(ptrs)) * approxPositionBytes
+	}
+	return n
+}
+
+func fieldsFilesSize(files map[string]*FieldsFile) int64 {
+	var n int64
+	for _, ff := range files {
+		n += fieldsSize(ff.Fields)
+	}
+	return n
+}
+
+// fieldsSize estimates fields and their nested children as the struct size
+// plus the length of the name and description, which hold most of the text.
+func fieldsSize(fields []pkgspec.Field) int64 {
+	var n int64
+	for i := range fields {
+		f := &fields[i]
+		n += int64(unsafe.Sizeof(*f)) + int64(len(f.Name)+len(f.Description))
+		n += fieldsSize(f.Fields)
+	}
+	return n
+}
+
+func pipelinesSize(pipelines map[string]*PipelineFile) int64 {
+	var n int64
+	size := func(proc *pkgspec.Processor, _ string) error {
+		n += int64(unsafe.Sizeof(*proc)) + int64(len(proc.Type)) + valueSize(proc.Attributes)
+		return nil
+	}
+	for _, pf := range pipelines {
+		_ = pkgspec.WalkProcessors(pf.Pipeline.Processors, size)
+		_ = pkgspec.WalkProcessors(pf.Pipeline.OnFailure, size)
+	}
+	return n
+}
+
+func agentTemplatesSize(templates map[string]*AgentTemplate) int64 {
+	var n int64
+	for _, t := range templates {
+		n += int64(len(t.Content))
+	}
+	return n
+}
+
+// valueSize estimates a decoded YAML or JSON value as the length of its
+// strings plus a fixed overhead per map entry and slice element.
+func valueSize(v any) int64 {
+	switch v := v.(type) {
+	case string:
+		return int64(len(v))
+	case map[string]any:
+		var n int64
+		for k, e := range v {
+			n += approxNodeBytes + int64(len(k)) + valueSize(e)
+		}
+		return n
+	case []any:
+		var n int64
+		for _, e := range v {
+			n += approxNodeBytes + valueSize(e)
+		}
+		return n
+	}
+	return 0
+}
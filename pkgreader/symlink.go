@@ -0,0 +1,116 @@
+package pkgreader
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// symlinkFS is an [fs.FS] rooted at a package directory that resolves
+// symbolic links and rejects any whose target lies outside the boundary
+// directory. Directory entries for symlinks report the type of their target
+// so that symlinked files and directories are read like regular ones.
+type symlinkFS struct {
+	root     string // package directory
+	boundary string // resolved directory that link targets must stay within
+}
+
+// newSymlinkFS returns a symlinkFS for the package at dir. Link targets must
+// stay within boundary. An empty boundary means the git repository containing
+// dir, or dir itself when it is not inside a repository.
+func newSymlinkFS(dir, boundary string) (*symlinkFS, error) {
+	if boundary == "" {
+		var err error
+		if boundary, err = gitToplevel(dir); err != nil {
+			boundary = dir
+		}
+	}
+	boundary, err := filepath.Abs(boundary)
+	if err != nil {
+		return nil, err
+	}
+	boundary, err = filepath.EvalSymlinks(boundary)
+	if err != nil {
+		return nil, fmt.Errorf("resolving symlink boundary: %w", err)
+	}
+	return &symlinkFS{root: dir, boundary: boundary}, nil
+}
+
+// resolve returns the OS path for name with all symlinks evaluated. It fails
+// if the resolved path is outside the boundary. A dangling symlink is
+// reported as an error rather than as a missing file so it is not silently
+// treated as an absent optional file.
+func (s *symlinkFS) resolve(op, name string) (string, error) {
+	if !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	p := filepath.Join(s.root, filepath.FromSlash(name))
+	resolved, err := filepath.EvalSymlinks(p)
+	if err != nil {
+		if _, lerr := os.Lstat(p); lerr == nil {
+			return "", &fs.PathError{Op: op, Path: name, Err: errors.New("broken symlink")}
+		}
+		return "", &fs.PathError{Op: op, Path: name, Err: err}
+	}
+	resolved, err = filepath.Abs(resolved)
+	if err != nil {
+		return "", &fs.PathError{Op: op, Path: name, Err: err}
+	}
+	rel, err := filepath.Rel(s.boundary, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", &fs.PathError{Op: op, Path: name, Err: fmt.Errorf("symlink target %s is outside %s", resolved, s.boundary)}
+	}
+	return resolved, nil
+}
+
+// Open implements [fs.FS].
+func (s *symlinkFS) Open(name string) (fs.File, error) {
+	p, err := s.resolve("open", name)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(p)
+}
+
+// ReadDir implements [fs.ReadDirFS]. Symlinked entries are replaced with the
+// type of their target. A link that is broken or escapes the boundary keeps
+// its symlink entry so that listing a directory does not fail; opening it
+// reports the error.
+func (s *symlinkFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	p, err := s.resolve("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(p)
+	if err != nil {
+		return nil, err
+	}
+	for i, e := range entries {
+		if e.Type()&fs.ModeSymlink == 0 {
+			continue
+		}
+		target, err := s.resolve("readdir", path.Join(name, e.Name()))
+		if err != nil {
+			continue
+		}
+		info, err := os.Stat(target)
+		if err != nil {
+			continue
+		}
+		entries[i] = renamedDirEntry{DirEntry: fs.FileInfoToDirEntry(info), name: e.Name()}
+	}
+	return entries, nil
+}
+
+// renamedDirEntry reports the symlink's own name for an entry describing the
+// link target.
+type renamedDirEntry struct {
+	fs.DirEntry
+	name string
+}
+
+func (e renamedDirEntry) Name() string { return e.name }
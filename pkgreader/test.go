@@ -26,6 +26,10 @@ type PipelineTestCase struct {
 	EventPath    string                            // path to event file
 	ExpectedPath string                            // path to expected file, empty if absent
 	ConfigPath   string                            // path to per-case config, empty if absent
+
+	// Expected holds the decoded expected-results file ({"expected": [...]}),
+	// nil if absent.
+	Expected *pkgspec.PipelineTestExpected
 }
 
 // InputPackageTests holds test configs for input packages.
@@ -205,6 +209,15 @@ func readPipelineTests(fsys fs.FS, dir string, cfg *config) ([]*PipelineTestCase
 		expectedName := stem + "." + formatExtension(format) + "-expected.json"
 		if fileSet[expectedName] {
 			tc.ExpectedPath = path.Join(dir, expectedName)
+
+			// Expected documents are arbitrary pipeline output, so they
+			// are always decoded without known-field checks.
+			var e pkgspec.PipelineTestExpected
+			if err := decodeYAML(fsys, tc.ExpectedPath, &e, false); err != nil {
+				return nil, fmt.Errorf("reading %s: %w", expectedName, err)
+			}
+			pkgspec.AnnotateFileMetadata(tc.ExpectedPath, &e)
+			tc.Expected = &e
 		}
 
 		// Check for per-case config file.
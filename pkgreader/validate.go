@@ -0,0 +1,297 @@
+package pkgreader
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/andrewkroh/go-package-spec/internal/semver"
+	"github.com/andrewkroh/go-package-spec/pkgspec"
+)
+
+// ValidationIssue describes a problem found by [Package.Validate]. The
+// embedded FileMetadata locates the offending value in its source file.
+type ValidationIssue struct {
+	pkgspec.FileMetadata
+	Message string
+}
+
+// String formats the issue as "file:line:column: message".
+func (i ValidationIssue) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s", i.FilePath(), i.Line(), i.Column(), i.Message)
+}
+
+// ValidateOption enables optional checks in [Package.Validate].
+type ValidateOption func(*validateConfig)
+
+type validateConfig struct {
+	processorFields bool
+}
+
+// CheckProcessorFields enables a check that every field written by a set,
+// rename, or convert processor in a data stream's ingest pipelines is
+// declared in that data stream's fields. It is opt-in because pipelines may
+// legitimately write temporary fields that a later processor removes.
+func CheckProcessorFields() ValidateOption {
+	return func(c *validateConfig) {
+		c.processorFields = true
+	}
+}
+
+// Validate checks the package for consistency problems that the package
+// spec schemas cannot express. It returns nil when no issues are found.
+//
+// Checks:
+//   - The first changelog entry's version equals the manifest version.
+//   - Changelog versions are valid semantic versions listed in strictly
+//     descending order.
+//   - Each changelog entry's link is an absolute URL.
+//   - Every field that is not imported from ECS (external: ecs) has a
+//     description.
+//   - Every data stream stream input is declared as an input type by at
+//     least one policy template.
+//   - Every data stream has a sample_event.json. The package spec does not
+//     require one, but the documentation and field coverage tests rely on it.
+//   - With [CheckProcessorFields], every pipeline processor target field is
+//     declared in the data stream's fields.
+func (p *Package) Validate(opts ...ValidateOption) []ValidationIssue {
+	var cfg validateConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	issues := p.validateChangelog()
+	issues = append(issues, p.validateFieldDescriptions()...)
+	issues = append(issues, p.validateStreamInputs()...)
+	issues = append(issues, p.validateSampleEvents()...)
+	if cfg.processorFields {
+		issues = append(issues, p.validateProcessorFields()...)
+	}
+	return issues
+}
+
+func (p *Package) validateChangelog() []ValidationIssue {
+	if len(p.Changelog) == 0 {
+		return nil
+	}
+
+	var issues []ValidationIssue
+	issueAt := func(m pkgspec.FileMetadata, format string, args ...any) {
+		issues = append(issues, ValidationIssue{
+			FileMetadata: m,
+			Message:      fmt.Sprintf(format, args...),
+		})
+	}
+	issue := func(c *pkgspec.Changelog, format string, args ...any) {
+		issueAt(c.FileMetadata, format, args...)
+	}
+
+	first := &p.Changelog[0]
+	if m := p.Manifest(); m != nil && first.Version != m.Version {
+		issue(first, "latest changelog version %s does not match manifest version %s", first.Version, m.Version)
+	}
+
+	var prev *semver.Version
+	var prevVersion string
+	for i := range p.Changelog {
+		c := &p.Changelog[i]
+		if v, err := semver.Parse(c.Version); err != nil {
+			issue(c, "changelog version %s is not a valid semantic version", c.Version)
+		} else {
+			if prev != nil && v.Compare(*prev) >= 0 {
+				issue(c, "changelog version %s is not lower than the preceding version %s", c.Version, prevVersion)
+			}
+			prev, prevVersion = &v, c.Version
+		}
+
+		for j := range c.Changes {
+			e := &c.Changes[j]
+			if !isAbsoluteURL(e.Link) {
+				issueAt(e.FileMetadata, "changelog entry link %q is not an absolute URL", e.Link)
+			}
+		}
+	}
+
+	return issues
+}
+
+// isAbsoluteURL reports whether s parses as a URL with a scheme and host.
+func isAbsoluteURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && u.IsAbs() && u.Host != ""
+}
+
+// validateFieldDescriptions reports each flattened leaf field without a
+// description, except fields imported from ECS whose description comes from
+// the ECS definition. Fields files are checked in path order.
+func (p *Package) validateFieldDescriptions() []ValidationIssue {
+	files := map[string]*FieldsFile{}
+	for _, ds := range p.DataStreams {
+		for _, ff := range ds.Fields {
+			files[ff.Path()] = ff
+		}
+	}
+	for _, ff := range p.Fields {
+		files[ff.Path()] = ff
+	}
+	for _, td := range p.Transforms {
+		for _, ff := range td.Fields {
+			files[ff.Path()] = ff
+		}
+	}
+
+	var issues []ValidationIssue
+	for _, filePath := range slices.Sorted(maps.Keys(files)) {
+		for _, f := range pkgspec.FlattenFields(files[filePath].Fields, nil) {
+			if f.Description != "" || f.External == pkgspec.FieldExternalECS {
+				continue
+			}
+			issues = append(issues, ValidationIssue{
+				FileMetadata: f.FileMetadata,
+				Message:      fmt.Sprintf("field %s has no description", f.Name),
+			})
+		}
+	}
+	return issues
+}
+
+// validateStreamInputs reports each data stream stream whose input type is
+// not offered by any policy template input. Fleet only renders a stream when
+// its input is enabled through a policy template, so such streams can never
+// be configured. Data streams are checked in name order.
+func (p *Package) validateStreamInputs() []ValidationIssue {
+	m := p.IntegrationManifest()
+	if m == nil {
+		return nil
+	}
+
+	declared := map[string]bool{}
+	for _, pt := range m.PolicyTemplates {
+		for _, in := range pt.Inputs {
+			declared[in.Type] = true
+		}
+	}
+
+	var issues []ValidationIssue
+	for _, name := range slices.Sorted(maps.Keys(p.DataStreams)) {
+		ds := p.DataStreams[name]
+		for _, s := range ds.Manifest.Streams {
+			if s.Input == "" || declared[s.Input] {
+				continue
+			}
+			issues = append(issues, ValidationIssue{
+				FileMetadata: s.FileMetadata,
+				Message:      fmt.Sprintf("data stream %s stream input %s is not declared by any policy template", name, s.Input),
+			})
+		}
+	}
+	return issues
+}
+
+// validateSampleEvents reports each data stream without a sample_event.json
+// file. Data streams are checked in name order.
+func (p *Package) validateSampleEvents() []ValidationIssue {
+	var issues []ValidationIssue
+	for _, name := range slices.Sorted(maps.Keys(p.DataStreams)) {
+		ds := p.DataStreams[name]
+		if ds.SampleEvent != nil {
+			continue
+		}
+		issues = append(issues, ValidationIssue{
+			FileMetadata: ds.Manifest.FileMetadata,
+			Message:      fmt.Sprintf("data stream %s has no sample event (%s)", name, path.Join(ds.Path(), "sample_event.json")),
+		})
+	}
+	return issues
+}
+
+// validateProcessorFields reports each field written by a set, rename, or
+// convert processor that is not declared in the data stream's fields.
+// Metadata fields (prefixed with "_") and templated names ("{{...}}") are
+// skipped. A field counts as declared when a declared name or wildcard
+// pattern matches it, or when an ancestor is declared as an object,
+// flattened, or nested field that accepts arbitrary subfields. Data streams
+// and pipelines are checked in name order.
+func (p *Package) validateProcessorFields() []ValidationIssue {
+	var issues []ValidationIssue
+	for _, name := range slices.Sorted(maps.Keys(p.DataStreams)) {
+		ds := p.DataStreams[name]
+		declared := map[string]pkgspec.FieldType{}
+		for _, f := range pkgspec.FlattenFields(ds.AllFields(), nil) {
+			declared[f.Name] = f.Type
+		}
+
+		for _, file := range slices.Sorted(maps.Keys(ds.Pipelines)) {
+			pl := &ds.Pipelines[file].Pipeline
+			check := func(proc *pkgspec.Processor, _ string) error {
+				target := processorTargetField(proc)
+				if target == "" || strings.HasPrefix(target, "_") || strings.Contains(target, "{{") {
+					return nil
+				}
+				if !isDeclaredField(declared, target) {
+					issues = append(issues, ValidationIssue{
+						FileMetadata: proc.FileMetadata,
+						Message: fmt.Sprintf("data stream %s pipeline %s %s processor writes undeclared field %s",
+							name, file, proc.Type, target),
+					})
+				}
+				return nil
+			}
+			pkgspec.WalkProcessors(pl.Processors, check)
+			pkgspec.WalkProcessors(pl.OnFailure, check)
+		}
+	}
+	return issues
+}
+
+// processorTargetField returns the field written by a set, rename, or
+// convert processor, or "" for other processors. A convert processor
+// without target_field converts its field in place.
+func processorTargetField(proc *pkgspec.Processor) string {
+	attr := func(key string) string {
+		s, _ := proc.Attributes[key].(string)
+		return s
+	}
+	switch proc.Type {
+	case "set":
+		return attr("field")
+	case "rename":
+		return attr("target_field")
+	case "convert":
+		if t := attr("target_field"); t != "" {
+			return t
+		}
+		return attr("field")
+	}
+	return ""
+}
+
+// isDeclaredField reports whether name is covered by the declared flattened
+// fields, keyed by name with their types.
+func isDeclaredField(declared map[string]pkgspec.FieldType, name string) bool {
+	if _, ok := declared[name]; ok {
+		return true
+	}
+	for d := range declared {
+		if strings.Contains(d, "*") {
+			// Match segment-wise so that "*" does not cross a dot.
+			if ok, _ := path.Match(strings.ReplaceAll(d, ".", "/"), strings.ReplaceAll(name, ".", "/")); ok {
+				return true
+			}
+		}
+	}
+	for parent := name; ; {
+		i := strings.LastIndexByte(parent, '.')
+		if i < 0 {
+			return false
+		}
+		parent = parent[:i]
+		switch declared[parent] {
+		case pkgspec.FieldTypeObject, pkgspec.FieldTypeFlattened, pkgspec.FieldTypeNested:
+			return true
+		}
+	}
+}
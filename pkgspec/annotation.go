@@ -42,7 +42,7 @@ func (p filePrefix) walk(val reflect.Value) {
 	}
 
 	switch val.Kind() {
-	case reflect.Pointer:
+	case reflect.Pointer, reflect.Interface:
 		if !val.IsNil() {
 			p.walk(val.Elem())
 		}
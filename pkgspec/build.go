@@ -8,6 +8,9 @@ import yamlv3 "gopkg.in/yaml.v3"
 type BuildDependencies struct {
 	// ECS dependency
 	ECS BuildDependenciesECS `json:"ecs,omitempty" yaml:"ecs,omitempty"`
+	// Extras captures dependencies other than ECS (e.g. imported packages), keyed by dependency name.
+	// These are non-canonical and excluded from JSON serialization.
+	Extras map[string]any `json:"-" yaml:",inline"`
 }
 
 // BuildDependenciesECS ECS dependency
@@ -78,6 +78,9 @@ type DataStreamManifest struct {
 	// GithubCodeOwner is the GitHub team code owner from CODEOWNERS, populated when WithCodeowners is
 	// used.
 	GithubCodeOwner string `json:"github_code_owner,omitempty" yaml:"-"`
+	// GithubCodeOwners lists all GitHub code owners from the matching CODEOWNERS line, populated when
+	// WithCodeowners is used. GithubCodeOwner holds the first of these.
+	GithubCodeOwners []string `json:"github_code_owners,omitempty" yaml:"-"`
 }
 
 // UnmarshalYAML implements yaml.Unmarshaler for DataStreamManifest.
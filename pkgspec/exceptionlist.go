@@ -0,0 +1,41 @@
+package pkgspec
+
+// ExceptionList holds the attributes of a Kibana exception list or
+// exception list item (saved object type "exception-list" or
+// "exception-list-agnostic"). Both share one saved object type and are
+// distinguished by ListType. Exception lists are not described by the
+// package-spec schemas, so only the common attributes are modeled.
+type ExceptionList struct {
+	// ListID identifies the list. Items carry the list_id of the list they
+	// belong to.
+	ListID string `json:"list_id"`
+	// ItemID identifies an item within its list. Empty for lists.
+	ItemID string `json:"item_id,omitempty"`
+	// ListType is "list" for a list container or "item" for one of its
+	// entries.
+	ListType string `json:"list_type,omitempty"`
+	// Name is the display name.
+	Name string `json:"name,omitempty"`
+	// Description explains the purpose of the list or item.
+	Description string `json:"description,omitempty"`
+	// Type is the exception list type (e.g. detection, endpoint,
+	// rule_default) for lists, or the item type (e.g. simple) for items.
+	Type string `json:"type,omitempty"`
+	// NamespaceType is "single" for space-scoped or "agnostic" for
+	// lists shared across all Kibana spaces.
+	NamespaceType string `json:"namespace_type,omitempty"`
+	// Tags are free-form labels.
+	Tags []string `json:"tags,omitempty"`
+	// OSTypes restricts the exception to operating systems (e.g. windows).
+	OSTypes []string `json:"os_types,omitempty"`
+	// Entries are the conditions of an item. Their shape depends on the
+	// entry type (match, match_any, list, exists, nested, wildcard), so they
+	// are kept as decoded JSON.
+	Entries []any `json:"entries,omitempty"`
+}
+
+// Exception list types for ExceptionList.ListType.
+const (
+	ExceptionListTypeList = "list"
+	ExceptionListTypeItem = "item"
+)
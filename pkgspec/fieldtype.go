@@ -0,0 +1,90 @@
+package pkgspec
+
+import "slices"
+
+// knownFieldTypes lists the FieldType enum values in declaration order.
+var knownFieldTypes = []FieldType{
+	FieldTypeAggregateMetricDouble,
+	FieldTypeAlias,
+	FieldTypeHistogram,
+	FieldTypeConstantKeyword,
+	FieldTypeText,
+	FieldTypeMatchOnlyText,
+	FieldTypeKeyword,
+	FieldTypeLong,
+	FieldTypeInteger,
+	FieldTypeShort,
+	FieldTypeByte,
+	FieldTypeDouble,
+	FieldTypeFloat,
+	FieldTypeHalfFloat,
+	FieldTypeScaledFloat,
+	FieldTypeDate,
+	FieldTypeDateNanos,
+	FieldTypeBoolean,
+	FieldTypeBinary,
+	FieldTypeIntegerRange,
+	FieldTypeFloatRange,
+	FieldTypeLongRange,
+	FieldTypeDoubleRange,
+	FieldTypeDateRange,
+	FieldTypeIPRange,
+	FieldTypeGroup,
+	FieldTypeGeoPoint,
+	FieldTypeObject,
+	FieldTypeIP,
+	FieldTypeNested,
+	FieldTypeFlattened,
+	FieldTypeWildcard,
+	FieldTypeVersion,
+	FieldTypeUnsignedLong,
+	FieldTypeCountedKeyword,
+	FieldTypeSemanticText,
+	FieldTypeGeoShape,
+}
+
+// KnownFieldTypes returns every field type defined by the package-spec, in
+// the order the spec declares them. The returned slice is a copy and may be
+// modified by the caller.
+func KnownFieldTypes() []FieldType {
+	return slices.Clone(knownFieldTypes)
+}
+
+// IsNumeric reports whether t holds a single numeric value. Range types and
+// metric types such as histogram and aggregate_metric_double are not numeric.
+func (t FieldType) IsNumeric() bool {
+	switch t {
+	case FieldTypeLong, FieldTypeInteger, FieldTypeShort, FieldTypeByte,
+		FieldTypeDouble, FieldTypeFloat, FieldTypeHalfFloat, FieldTypeScaledFloat,
+		FieldTypeUnsignedLong:
+		return true
+	}
+	return false
+}
+
+// IsTextual reports whether t holds string content, either as exact values
+// (the keyword family) or as analyzed full text (the text family).
+func (t FieldType) IsTextual() bool {
+	switch t {
+	case FieldTypeKeyword, FieldTypeConstantKeyword, FieldTypeWildcard,
+		FieldTypeCountedKeyword, FieldTypeText, FieldTypeMatchOnlyText,
+		FieldTypeSemanticText:
+		return true
+	}
+	return false
+}
+
+// IsSupportedByECS reports whether t is one of the types used by field
+// definitions in the Elastic Common Schema. Fields of other types cannot be
+// declared as ECS fields.
+func (t FieldType) IsSupportedByECS() bool {
+	switch t {
+	case FieldTypeKeyword, FieldTypeConstantKeyword, FieldTypeWildcard,
+		FieldTypeText, FieldTypeMatchOnlyText,
+		FieldTypeLong, FieldTypeFloat, FieldTypeScaledFloat,
+		FieldTypeDate, FieldTypeBoolean, FieldTypeIP, FieldTypeGeoPoint,
+		FieldTypeObject, FieldTypeNested, FieldTypeFlattened:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,49 @@
+package pkgspec
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestKnownFieldTypes(t *testing.T) {
+	types := KnownFieldTypes()
+	for _, want := range []FieldType{FieldTypeKeyword, FieldTypeScaledFloat, FieldTypeGeoShape} {
+		if !slices.Contains(types, want) {
+			t.Errorf("KnownFieldTypes() missing %q", want)
+		}
+	}
+
+	// The result is a copy.
+	types[0] = "modified"
+	if KnownFieldTypes()[0] == "modified" {
+		t.Error("KnownFieldTypes() returned shared slice")
+	}
+}
+
+func TestFieldTypeClassification(t *testing.T) {
+	tests := []struct {
+		typ                      FieldType
+		numeric, textual, ecsUse bool
+	}{
+		{FieldTypeKeyword, false, true, true},
+		{FieldTypeMatchOnlyText, false, true, true},
+		{FieldTypeLong, true, false, true},
+		{FieldTypeScaledFloat, true, false, true},
+		{FieldTypeUnsignedLong, true, false, false},
+		{FieldTypeLongRange, false, false, false},
+		{FieldTypeHistogram, false, false, false},
+		{FieldTypeDate, false, false, true},
+		{FieldTypeGroup, false, false, false},
+	}
+	for _, tc := range tests {
+		if got := tc.typ.IsNumeric(); got != tc.numeric {
+			t.Errorf("%s.IsNumeric() = %v, want %v", tc.typ, got, tc.numeric)
+		}
+		if got := tc.typ.IsTextual(); got != tc.textual {
+			t.Errorf("%s.IsTextual() = %v, want %v", tc.typ, got, tc.textual)
+		}
+		if got := tc.typ.IsSupportedByECS(); got != tc.ecsUse {
+			t.Errorf("%s.IsSupportedByECS() = %v, want %v", tc.typ, got, tc.ecsUse)
+		}
+	}
+}
@@ -9,10 +9,19 @@ import (
 // reference. Callers populate this via the ecsLookup callback passed to
 // [FlattenFields].
 type ECSFieldDefinition struct {
-	DataType    string
-	Description string
-	Pattern     string
-	Array       bool
+	DataType      string
+	Description   string
+	Pattern       string
+	Array         bool
+	AllowedValues []ECSAllowedValue // permitted values, e.g. for event.category
+}
+
+// ECSAllowedValue is one of the values an ECS field accepts, as listed in
+// the allowed_values of its ECS definition.
+type ECSAllowedValue struct {
+	Name               string   `json:"name"`
+	Description        string   `json:"description,omitempty"`
+	ExpectedEventTypes []string `json:"expected_event_types,omitempty"`
 }
 
 // FlatField represents a field with its fully-qualified dotted name.
@@ -99,3 +108,63 @@ func flattenField(key []string, f Field) []FlatField {
 
 	return flat
 }
+
+// FieldDiff describes the differences between two sets of fields, compared
+// by their flattened names. Each slice is sorted by field name.
+type FieldDiff struct {
+	Added       []FlatField       // fields only present in the new set
+	Removed     []FlatField       // fields only present in the old set
+	TypeChanged []FieldTypeChange // fields present in both with different types
+}
+
+// FieldTypeChange records a field whose type differs between two field sets.
+type FieldTypeChange struct {
+	Name    string
+	OldType FieldType
+	NewType FieldType
+}
+
+// DiffFields compares two field sets after flattening them with
+// [FlattenFields]. Field order and nesting style do not matter: a field
+// declared as a dotted name and the same field declared inside a group are
+// considered equal. Only additions, removals, and type changes are reported.
+func DiffFields(oldFields, newFields []Field) FieldDiff {
+	oldFlat := uniqueFlatFields(FlattenFields(oldFields, nil))
+	newFlat := uniqueFlatFields(FlattenFields(newFields, nil))
+
+	oldByName := make(map[string]FlatField, len(oldFlat))
+	for _, f := range oldFlat {
+		oldByName[f.Name] = f
+	}
+	newNames := make(map[string]bool, len(newFlat))
+
+	var diff FieldDiff
+	for _, f := range newFlat {
+		newNames[f.Name] = true
+		o, ok := oldByName[f.Name]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, f)
+		case o.Type != f.Type:
+			diff.TypeChanged = append(diff.TypeChanged, FieldTypeChange{
+				Name:    f.Name,
+				OldType: o.Type,
+				NewType: f.Type,
+			})
+		}
+	}
+	for _, f := range oldFlat {
+		if !newNames[f.Name] {
+			diff.Removed = append(diff.Removed, f)
+		}
+	}
+	return diff
+}
+
+// uniqueFlatFields drops all but the first of any fields sharing a name from
+// a sorted slice of flattened fields.
+func uniqueFlatFields(flat []FlatField) []FlatField {
+	return slices.CompactFunc(flat, func(a, b FlatField) bool {
+		return a.Name == b.Name
+	})
+}
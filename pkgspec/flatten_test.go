@@ -124,7 +124,10 @@ func TestFlattenFields_ECSEnrichment(t *testing.T) {
 	lookup := func(name string) *ECSFieldDefinition {
 		defs := map[string]*ECSFieldDefinition{
 			"host.os.name": {DataType: "keyword", Description: "Operating system name."},
-			"event.kind":   {DataType: "keyword", Description: "The kind of the event."},
+			"event.kind": {DataType: "keyword", Description: "The kind of the event.", AllowedValues: []ECSAllowedValue{
+				{Name: "alert", Description: "An alert."},
+				{Name: "event", Description: "An event.", ExpectedEventTypes: []string{"info"}},
+			}},
 		}
 		return defs[name]
 	}
@@ -158,6 +161,15 @@ func TestFlattenFields_ECSEnrichment(t *testing.T) {
 	if flat[hostIdx].ECS.Description != "Operating system name." {
 		t.Errorf("got ECS Description %q, want %q", flat[hostIdx].ECS.Description, "Operating system name.")
 	}
+
+	// Allowed values are carried through.
+	kindIdx := slices.IndexFunc(flat, func(f FlatField) bool { return f.Name == "event.kind" })
+	if kindIdx < 0 || flat[kindIdx].ECS == nil {
+		t.Fatal("event.kind should have ECS data")
+	}
+	if got := flat[kindIdx].ECS.AllowedValues; len(got) != 2 || got[1].Name != "event" || !slices.Equal(got[1].ExpectedEventTypes, []string{"info"}) {
+		t.Errorf("got ECS AllowedValues %+v, want alert and event", got)
+	}
 }
 
 func TestFlattenFields_NilLookup(t *testing.T) {
@@ -287,3 +299,43 @@ func flatFieldNames(flat []FlatField) []string {
 	}
 	return names
 }
+
+func TestDiffFields(t *testing.T) {
+	oldFields := []Field{
+		{
+			Name: "source",
+			Type: FieldTypeGroup,
+			Fields: []Field{
+				{Name: "ip", Type: FieldTypeIP},
+				{Name: "port", Type: FieldTypeLong},
+			},
+		},
+		{Name: "message", Type: FieldTypeText},
+		{Name: "event.code", Type: FieldTypeLong},
+	}
+	// Reordered, with source.ip declared as a dotted name instead of inside
+	// the group.
+	newFields := []Field{
+		{Name: "event.code", Type: FieldTypeKeyword},
+		{Name: "source.ip", Type: FieldTypeIP},
+		{Name: "user.name", Type: FieldTypeKeyword},
+		{Name: "message", Type: FieldTypeText},
+	}
+
+	diff := DiffFields(oldFields, newFields)
+
+	if got, want := flatFieldNames(diff.Added), []string{"user.name"}; !slices.Equal(got, want) {
+		t.Errorf("Added = %v, want %v", got, want)
+	}
+	if got, want := flatFieldNames(diff.Removed), []string{"source.port"}; !slices.Equal(got, want) {
+		t.Errorf("Removed = %v, want %v", got, want)
+	}
+	wantChanged := []FieldTypeChange{{Name: "event.code", OldType: FieldTypeLong, NewType: FieldTypeKeyword}}
+	if !slices.Equal(diff.TypeChanged, wantChanged) {
+		t.Errorf("TypeChanged = %+v, want %+v", diff.TypeChanged, wantChanged)
+	}
+
+	if d := DiffFields(newFields, slices.Clone(newFields)); len(d.Added)+len(d.Removed)+len(d.TypeChanged) != 0 {
+		t.Errorf("DiffFields of identical sets = %+v, want empty", d)
+	}
+}
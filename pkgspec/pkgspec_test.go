@@ -1,6 +1,7 @@
 package pkgspec
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"testing"
@@ -159,3 +160,44 @@ description: A test field.
 		t.Errorf("path = %q, want test.yml", field.FilePath())
 	}
 }
+
+// TestAdditionalPropertiesRoundTrip verifies that keys unknown to the spec
+// survive a YAML decode followed by a YAML or JSON encode, so tools can
+// read, modify, and rewrite a file without losing them.
+func TestAdditionalPropertiesRoundTrip(t *testing.T) {
+	input := "if: ctx.event?.module == 'aws'\ntarget_dataset: aws.cloudtrail\nx-custom: keep me\n"
+
+	var rule RoutingRule
+	if err := yaml.Unmarshal([]byte(input), &rule); err != nil {
+		t.Fatal(err)
+	}
+	if rule.AdditionalProperties["x-custom"] != "keep me" {
+		t.Fatalf("AdditionalProperties = %v, want x-custom", rule.AdditionalProperties)
+	}
+
+	rule.If = "true"
+
+	out, err := yaml.Marshal(&rule)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var gotYAML map[string]any
+	if err := yaml.Unmarshal(out, &gotYAML); err != nil {
+		t.Fatal(err)
+	}
+	if gotYAML["x-custom"] != "keep me" || gotYAML["if"] != "true" {
+		t.Errorf("YAML round trip = %v, want x-custom and updated if", gotYAML)
+	}
+
+	out, err = json.Marshal(rule)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var gotJSON map[string]any
+	if err := json.Unmarshal(out, &gotJSON); err != nil {
+		t.Fatal(err)
+	}
+	if gotJSON["x-custom"] != "keep me" || gotJSON["if"] != "true" {
+		t.Errorf("JSON round trip = %s, want x-custom and updated if", out)
+	}
+}
@@ -2,6 +2,7 @@ package pkgspec
 
 import (
 	"encoding/json"
+	"strconv"
 
 	"gopkg.in/yaml.v3"
 )
@@ -65,3 +66,30 @@ func (p *Processor) MarshalJSON() ([]byte, error) {
 		p.Type: properties,
 	})
 }
+
+// WalkProcessors calls fn for each processor in ps, including nested
+// on_failure processors, in depth-first order. The path passed to fn is a
+// JSON pointer relative to ps (e.g. "/0/set" or "/0/set/on_failure/1/rename"),
+// so callers walking a pipeline typically prefix it with "/processors" or
+// "/on_failure". A processor is visited before its on_failure handlers, so
+// fn may mutate the processor (including its OnFailure list) in place.
+// Walking stops at the first error returned by fn.
+func WalkProcessors(ps []*Processor, fn func(p *Processor, path string) error) error {
+	return walkProcessors(ps, "", fn)
+}
+
+func walkProcessors(ps []*Processor, basePath string, fn func(p *Processor, path string) error) error {
+	for i, p := range ps {
+		if p == nil {
+			continue
+		}
+		path := basePath + "/" + strconv.Itoa(i) + "/" + p.Type
+		if err := fn(p, path); err != nil {
+			return err
+		}
+		if err := walkProcessors(p.OnFailure, path+"/on_failure", fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
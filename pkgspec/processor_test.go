@@ -0,0 +1,90 @@
+package pkgspec
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const walkPipelineYAML = `
+- set:
+    field: event.kind
+    value: event
+- rename:
+    field: message
+    target_field: event.original
+    on_failure:
+      - set:
+          field: error.message
+          value: rename failed
+- set:
+    field: event.category
+    value: [network]
+`
+
+func TestWalkProcessors(t *testing.T) {
+	var procs []*Processor
+	if err := yaml.Unmarshal([]byte(walkPipelineYAML), &procs); err != nil {
+		t.Fatal(err)
+	}
+
+	var paths []string
+	err := WalkProcessors(procs, func(p *Processor, path string) error {
+		paths = append(paths, path)
+		if p.Type == "set" {
+			p.Attributes["field"] = "renamed." + p.Attributes["field"].(string)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantPaths := []string{
+		"/0/set",
+		"/1/rename",
+		"/1/rename/on_failure/0/set",
+		"/2/set",
+	}
+	if !slices.Equal(paths, wantPaths) {
+		t.Errorf("paths = %v, want %v", paths, wantPaths)
+	}
+
+	// Mutations are visible through the original slice.
+	var targets []string
+	for _, p := range []*Processor{procs[0], procs[1].OnFailure[0], procs[2]} {
+		targets = append(targets, p.Attributes["field"].(string))
+	}
+	wantTargets := []string{"renamed.event.kind", "renamed.error.message", "renamed.event.category"}
+	if !slices.Equal(targets, wantTargets) {
+		t.Errorf("set targets = %v, want %v", targets, wantTargets)
+	}
+	if got := procs[1].Attributes["field"]; got != "message" {
+		t.Errorf("rename field = %v, want unchanged", got)
+	}
+}
+
+func TestWalkProcessorsStopsOnError(t *testing.T) {
+	var procs []*Processor
+	if err := yaml.Unmarshal([]byte(walkPipelineYAML), &procs); err != nil {
+		t.Fatal(err)
+	}
+
+	errStop := errors.New("stop")
+	var visited int
+	err := WalkProcessors(procs, func(p *Processor, path string) error {
+		visited++
+		if path == "/1/rename" {
+			return errStop
+		}
+		return nil
+	})
+	if !errors.Is(err, errStop) {
+		t.Fatalf("err = %v, want %v", err, errStop)
+	}
+	if visited != 2 {
+		t.Errorf("visited %d processors, want 2", visited)
+	}
+}
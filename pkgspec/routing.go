@@ -0,0 +1,32 @@
+package pkgspec
+
+// RoutingTarget is a single concrete reroute destination produced by
+// expanding a [RoutingRule].
+type RoutingTarget struct {
+	Dataset   string // target dataset; may be a field reference such as "{{labels.dataset}}"
+	Namespace string // target namespace, empty to keep the source namespace
+	If        string // condition under which the rule applies
+}
+
+// Targets returns the cartesian product of the rule's target datasets and
+// namespaces, in declaration order with datasets varying slowest. Each
+// target carries the rule's If condition. A rule without a namespace yields
+// one target per dataset with an empty Namespace.
+func (r *RoutingRule) Targets() []RoutingTarget {
+	datasets := r.TargetDataset
+	if len(datasets) == 0 {
+		datasets = StringOrStrings{""}
+	}
+	namespaces := r.Namespace
+	if len(namespaces) == 0 {
+		namespaces = StringOrStrings{""}
+	}
+
+	targets := make([]RoutingTarget, 0, len(datasets)*len(namespaces))
+	for _, ds := range datasets {
+		for _, ns := range namespaces {
+			targets = append(targets, RoutingTarget{Dataset: ds, Namespace: ns, If: r.If})
+		}
+	}
+	return targets
+}
@@ -0,0 +1,33 @@
+package pkgspec
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRoutingRuleTargets(t *testing.T) {
+	rule := RoutingRule{
+		If:            "ctx.event?.module == 'aws'",
+		TargetDataset: StringOrStrings{"aws.cloudtrail", "{{labels.dataset}}"},
+		Namespace:     StringOrStrings{"default", "{{labels.namespace}}"},
+	}
+
+	want := []RoutingTarget{
+		{Dataset: "aws.cloudtrail", Namespace: "default", If: rule.If},
+		{Dataset: "aws.cloudtrail", Namespace: "{{labels.namespace}}", If: rule.If},
+		{Dataset: "{{labels.dataset}}", Namespace: "default", If: rule.If},
+		{Dataset: "{{labels.dataset}}", Namespace: "{{labels.namespace}}", If: rule.If},
+	}
+	if got := rule.Targets(); !slices.Equal(got, want) {
+		t.Errorf("Targets() = %+v, want %+v", got, want)
+	}
+}
+
+func TestRoutingRuleTargetsWithoutNamespace(t *testing.T) {
+	rule := RoutingRule{If: "true", TargetDataset: StringOrStrings{"nginx.access"}}
+
+	want := []RoutingTarget{{Dataset: "nginx.access", If: "true"}}
+	if got := rule.Targets(); !slices.Equal(got, want) {
+		t.Errorf("Targets() = %+v, want %+v", got, want)
+	}
+}
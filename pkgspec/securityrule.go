@@ -0,0 +1,72 @@
+package pkgspec
+
+// SecurityRule holds the common attributes of a Kibana detection rule
+// (saved object type "security-rule"). Rules are not described by the
+// package-spec schemas, so only the attributes shared by most rule types
+// are modeled.
+type SecurityRule struct {
+	// RuleID is the stable identifier of the rule across versions.
+	RuleID string `json:"rule_id"`
+	// Name is the display name of the rule.
+	Name string `json:"name,omitempty"`
+	// Description explains what the rule detects.
+	Description string `json:"description,omitempty"`
+	// Type is the rule type (e.g. query, eql, esql, threshold,
+	// machine_learning, new_terms, threat_match).
+	Type string `json:"type,omitempty"`
+	// Language is the query language (e.g. kuery, lucene, eql, esql).
+	Language string `json:"language,omitempty"`
+	// Query is the detection query.
+	Query string `json:"query,omitempty"`
+	// Index lists the index patterns the query runs against.
+	Index []string `json:"index,omitempty"`
+	// Severity is the severity of alerts (low, medium, high, critical).
+	Severity string `json:"severity,omitempty"`
+	// RiskScore is the risk score of alerts, from 0 to 100.
+	RiskScore *float64 `json:"risk_score,omitempty"`
+	// Tags are free-form labels such as "Data Source: Okta".
+	Tags []string `json:"tags,omitempty"`
+	// Threat maps the rule to MITRE ATT&CK tactics and techniques.
+	Threat []SecurityRuleThreat `json:"threat,omitempty"`
+	// RequiredFields lists the fields the query depends on.
+	RequiredFields []SecurityRuleRequiredField `json:"required_fields,omitempty"`
+}
+
+// SecurityRuleThreat maps a rule to a single threat framework tactic and
+// the techniques within it.
+type SecurityRuleThreat struct {
+	// Framework is the threat framework (e.g. "MITRE ATT&CK").
+	Framework string `json:"framework,omitempty"`
+	// Tactic is the framework tactic.
+	Tactic SecurityRuleThreatEntry `json:"tactic"`
+	// Technique lists the techniques within the tactic.
+	Technique []SecurityRuleTechnique `json:"technique,omitempty"`
+}
+
+// SecurityRuleTechnique is a threat framework technique and its
+// subtechniques.
+type SecurityRuleTechnique struct {
+	SecurityRuleThreatEntry
+	// Subtechnique lists the subtechniques of the technique.
+	Subtechnique []SecurityRuleThreatEntry `json:"subtechnique,omitempty"`
+}
+
+// SecurityRuleThreatEntry identifies a tactic, technique, or subtechnique.
+type SecurityRuleThreatEntry struct {
+	// ID is the framework identifier (e.g. "TA0001" or "T1078.004").
+	ID string `json:"id"`
+	// Name is the display name.
+	Name string `json:"name,omitempty"`
+	// Reference is a URL describing the entry.
+	Reference string `json:"reference,omitempty"`
+}
+
+// SecurityRuleRequiredField is a field that a rule's query depends on.
+type SecurityRuleRequiredField struct {
+	// Name is the field name.
+	Name string `json:"name"`
+	// Type is the Elasticsearch field type.
+	Type string `json:"type,omitempty"`
+	// ECS is true if the field is defined by ECS.
+	ECS bool `json:"ecs,omitempty"`
+}
@@ -2,12 +2,21 @@ package pkgsql
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	_ "embed"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/andrewkroh/go-package-spec/pkgreader"
 	"github.com/andrewkroh/go-package-spec/pkgspec"
@@ -18,13 +27,32 @@ import (
 type Option func(*writeConfig)
 
 type writeConfig struct {
-	ecsLookup func(name string) *pkgspec.ECSFieldDefinition
-	docReader DocReader
+	ecsLookup   func(name string) *pkgspec.ECSFieldDefinition
+	docReader   DocReader
+	testReader  DocReader
+	varDedup    bool
+	varIDs      map[string]int64 // var definition hash → vars.id, reset per package
+	maxDocBytes int              // doc content limit in bytes, 0 for no limit
+	tokenizer   string           // FTS5 tokenizer, empty for defaultFTSTokenizer
+	onConflict  OnConflict       // join table conflict resolution, empty to fail
+	clock       func() time.Time // source of timestamp columns, nil for time.Now
+	tablePrefix string           // prepended to every table, view, and index name
+	kibanaRaw   bool             // store the original JSON of Kibana saved objects
+	withoutFTS  bool             // omit the FTS5 tables from the schema
+}
+
+// now returns the current time from the configured clock.
+func (c *writeConfig) now() time.Time {
+	if c.clock != nil {
+		return c.clock()
+	}
+	return time.Now()
 }
 
 // WithECSLookup provides a callback to resolve external ECS field definitions
 // during field flattening. When set, fields with "external: ecs" are enriched
-// with the ECS type, description, and pattern before insertion. Without this
+// with the ECS type, description, and pattern before insertion, and the ECS
+// allowed values are stored in the allowed_values column. Without this
 // option, external fields are inserted without enrichment.
 func WithECSLookup(fn func(name string) *pkgspec.ECSFieldDefinition) Option {
 	return func(c *writeConfig) {
@@ -44,6 +72,107 @@ func WithDocContent(reader DocReader) Option {
 	return func(c *writeConfig) { c.docReader = reader }
 }
 
+// WithTestContent enables loading pipeline test file content during SQL
+// writing. The provided DocReader is called with the package path and the
+// package-relative path of each pipeline test event and expected file, and the
+// content is stored in the event and expected columns of pipeline_tests.
+// OSDocReader may be used to read from the OS filesystem. Without this option,
+// only the file paths are recorded.
+func WithTestContent(reader DocReader) Option {
+	return func(c *writeConfig) { c.testReader = reader }
+}
+
+// WithKibanaRaw stores the original JSON file content of each Kibana saved
+// object, byte for byte, in the raw column of kibana_saved_objects so that
+// objects can be re-exported without re-serialization. Without this option,
+// the raw column is NULL.
+func WithKibanaRaw() Option {
+	return func(c *writeConfig) { c.kibanaRaw = true }
+}
+
+// WithVarDedup reuses a single vars row for identical var definitions within
+// a package. Definitions are compared by their full content, so only vars
+// that are identical in every attribute (name, type, default, title, ...)
+// share a row. Each parent is linked to a shared row through one join table
+// row, even when the parent declares the var more than once. The file_path,
+// file_line, and file_column of a shared row refer to the first occurrence.
+// Without this option, every var occurrence gets its own row.
+func WithVarDedup() Option {
+	return func(c *writeConfig) { c.varDedup = true }
+}
+
+// WithMaxDocBytes limits doc content loaded with WithDocContent to at most n
+// bytes. Longer content is cut at the last line break within the limit (or at
+// a UTF-8 character boundary if the first line alone is too long) and the
+// docs row is flagged with truncated = true. This bounds the size of the
+// docs table and its FTS index. A limit of zero or less disables truncation.
+func WithMaxDocBytes(n int) Option {
+	return func(c *writeConfig) { c.maxDocBytes = n }
+}
+
+// WithFTSTokenizer sets the FTS5 tokenizer used when WritePackages creates
+// the full-text search tables, for example "trigram" to match substrings
+// ("auth" finds "authentication") instead of the default "porter unicode61",
+// which stems whole words. The value is used verbatim as the tokenize
+// argument of each FTS5 table. It has no effect on tables that already exist.
+func WithFTSTokenizer(name string) Option {
+	return func(c *writeConfig) { c.tokenizer = name }
+}
+
+// WithoutFTS omits the FTS5 full-text search tables (and the views that
+// back them) when WritePackages creates the schema, and so skips rebuilding
+// their indexes. This speeds up loads for purely relational analysis that
+// does not need full-text search. Pass the same option to TableSchemas when
+// creating the schema manually; RebuildFTS skips tables that do not exist.
+func WithoutFTS() Option {
+	return func(c *writeConfig) { c.withoutFTS = true }
+}
+
+// WithTablePrefix prepends prefix to the name of every table, view, index,
+// and FTS5 table, so that the schema can share a database with other tables
+// without name clashes. For example, with the prefix "pkg_" packages are
+// written to pkg_packages. Foreign key references, views, and the FTS5
+// content tables refer to the prefixed names. The prefix must start with a
+// letter or underscore and contain only letters, digits, and underscores;
+// WritePackages, WritePackage, RebuildFTS, and Diff return an error for any
+// other prefix, and TableSchemas, TableJSONSchemas, and QuerySQL panic.
+//
+// Pass the same option to every function that accepts it when working with
+// a prefixed database: TableSchemas and RebuildFTS when using WritePackage,
+// and Diff, TableJSONSchemas, and QuerySQL when reading it.
+func WithTablePrefix(prefix string) Option {
+	return func(c *writeConfig) { c.tablePrefix = prefix }
+}
+
+// OnConflict is a SQLite conflict resolution algorithm used by
+// WithOnConflict.
+type OnConflict string
+
+// Conflict resolution algorithms for WithOnConflict.
+const (
+	// OnConflictIgnore keeps the existing row and skips the insert.
+	OnConflictIgnore OnConflict = "IGNORE"
+	// OnConflictReplace deletes the existing row and inserts a new one.
+	OnConflictReplace OnConflict = "REPLACE"
+)
+
+// WithOnConflict sets how inserts into the join tables (data_stream_fields,
+// package_fields, transform_fields, package_vars, policy_template_vars,
+// policy_template_input_vars, and stream_vars) resolve a violation of their
+// UNIQUE (parent, child) constraint, making repeated links idempotent.
+// Without this option a duplicate link fails the write. WithVarDedup does not
+// depend on it: a var declared twice on the same parent is linked once.
+func WithOnConflict(strategy OnConflict) Option {
+	return func(c *writeConfig) { c.onConflict = strategy }
+}
+
+// WithClock sets the function used to obtain the current time for timestamp
+// columns such as packages.imported_at. It defaults to time.Now. A fixed
+// clock makes the written database reproducible, which is useful in tests.
+func WithClock(now func() time.Time) Option {
+	return func(c *writeConfig) { c.clock = now }
+}
+
 // OSDocReader reads doc content from the OS filesystem by joining pkgPath
 // (the package directory) and docPath (the package-relative file path, e.g.
 // "docs/README.md") with filepath.Join.
@@ -51,22 +180,81 @@ func OSDocReader(pkgPath, docPath string) ([]byte, error) {
 	return os.ReadFile(filepath.Join(pkgPath, docPath))
 }
 
-// TableSchemas returns the CREATE TABLE statements (and FTS5 virtual table
-// statements) for all tables in dependency order. The statements include
+// TableSchemas returns the CREATE TABLE statements (followed by CREATE INDEX,
+// FTS5 virtual table, and CREATE VIEW statements) for all tables in dependency
+// order. The statements include
 // table and column comments inside the body, which are preserved in
 // sqlite_master when the tables are created. This makes the database file
 // self-documenting.
-func TableSchemas() []string {
-	return append(creates, ftsSchemas...)
+//
+// WithFTSTokenizer, WithoutFTS, and WithTablePrefix are applied to the
+// statements; other options are ignored. It panics if the prefix is invalid.
+func TableSchemas(opts ...Option) []string {
+	cfg := &writeConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	prefix := mustTablePrefix(cfg.tablePrefix)
+	tokenizer := cfg.tokenizer
+	if tokenizer == "" {
+		tokenizer = defaultFTSTokenizer
+	}
+
+	schemas := tableSchemas(tokenizer, !cfg.withoutFTS)
+	for i, ddl := range schemas {
+		schemas[i] = prefixTables(ddl, prefix)
+	}
+	return schemas
+}
+
+// tableSchemas returns the statements of TableSchemas with the FTS5 tables
+// using tokenizer, or without the FTS5 tables unless fts is set.
+func tableSchemas(tokenizer string, fts bool) []string {
+	if !fts {
+		return slices.Concat(creates, indexSchemas, viewSchemas)
+	}
+	return slices.Concat(creates, indexSchemas, ftsSchemas(tokenizer), viewSchemas)
+}
+
+//go:embed internal/db/query.sql
+var querySQL string
+
+// QuerySQL returns the generated sqlc query file used by the writer. Each
+// statement is preceded by a "-- name: <Name> :<kind>" annotation (e.g.
+// InsertPackages) and uses "?" placeholders, so callers managing their own
+// transactions or driver can prepare the same INSERT and DELETE statements.
+//
+// WithTablePrefix is applied to the statements; other options are ignored.
+// It panics if the prefix is invalid.
+func QuerySQL(opts ...Option) string {
+	cfg := &writeConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return prefixTables(querySQL, mustTablePrefix(cfg.tablePrefix))
 }
 
 // WritePackages creates tables (if not exist) and inserts each package
 // within its own transaction. If any package fails, the error includes
-// the package name. After all packages are inserted, it rebuilds the
-// FTS5 full-text search index.
+// the package name. Foreign key enforcement is enabled (see
+// [EnableForeignKeys]) so rows referencing a missing parent are rejected.
+// After all packages are inserted, it rebuilds the FTS5 full-text search
+// index.
 func WritePackages(ctx context.Context, db *sql.DB, pkgs []*pkgreader.Package, opts ...Option) error {
+	cfg := &writeConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if err := checkTablePrefix(cfg.tablePrefix); err != nil {
+		return err
+	}
+
+	if err := EnableForeignKeys(ctx, db); err != nil {
+		return err
+	}
+
 	// Create all tables (including FTS5 virtual tables).
-	for _, ddl := range TableSchemas() {
+	for _, ddl := range TableSchemas(opts...) {
 		if _, err := db.ExecContext(ctx, ddl); err != nil {
 			return fmt.Errorf("creating tables: %w", err)
 		}
@@ -83,7 +271,7 @@ func WritePackages(ctx context.Context, db *sql.DB, pkgs []*pkgreader.Package, o
 	}
 
 	// Rebuild FTS5 indexes after all inserts.
-	if err := RebuildFTS(ctx, db); err != nil {
+	if err := RebuildFTS(ctx, db, opts...); err != nil {
 		return fmt.Errorf("rebuilding FTS indexes: %w", err)
 	}
 
@@ -99,6 +287,9 @@ func WritePackage(ctx context.Context, db *sql.DB, pkg *pkgreader.Package, opts
 	for _, opt := range opts {
 		opt(cfg)
 	}
+	if err := checkTablePrefix(cfg.tablePrefix); err != nil {
+		return err
+	}
 
 	tx, err := db.BeginTx(ctx, nil)
 	if err != nil {
@@ -106,19 +297,23 @@ func WritePackage(ctx context.Context, db *sql.DB, pkg *pkgreader.Package, opts
 	}
 	defer tx.Rollback()
 
-	sc := newStmtCache(tx)
+	sc := newStmtCache(tx, cfg.onConflict, cfg.tablePrefix)
 	defer sc.close()
 
-	q := dbpkg.New(sc)
-
-	if err := writePackage(ctx, q, pkg, cfg); err != nil {
+	if err := writePackage(ctx, sc, pkg, cfg); err != nil {
 		return err
 	}
 
 	return tx.Commit()
 }
 
-func writePackage(ctx context.Context, q *dbpkg.Queries, pkg *pkgreader.Package, cfg *writeConfig) error {
+func writePackage(ctx context.Context, db dbpkg.DBTX, pkg *pkgreader.Package, cfg *writeConfig) error {
+	q := dbpkg.New(db)
+
+	if cfg.varDedup {
+		cfg.varIDs = map[string]int64{}
+	}
+
 	m := pkg.Manifest()
 	if m == nil {
 		return fmt.Errorf("package has no manifest")
@@ -144,6 +339,7 @@ func writePackage(ctx context.Context, q *dbpkg.Queries, pkg *pkgreader.Package,
 		conditionsAgentVersion         sql.NullString
 		conditionsKibanaVersion        sql.NullString
 		conditionsElasticSubscription  sql.NullString
+		conditionsElasticCapabilities  any
 		agentPrivilegesRoot            sql.NullBool
 		elasticsearchPrivilegesCluster any
 		policyTemplatesBehavior        sql.NullString
@@ -153,6 +349,7 @@ func writePackage(ctx context.Context, q *dbpkg.Queries, pkg *pkgreader.Package,
 		conditionsAgentVersion = toNullString(im.Conditions.Agent.Version)
 		conditionsKibanaVersion = toNullString(im.Conditions.Kibana.Version)
 		conditionsElasticSubscription = toNullString(string(im.Conditions.Elastic.Subscription))
+		conditionsElasticCapabilities = jsonNullString(im.Conditions.Elastic.Capabilities)
 		agentPrivilegesRoot = toNullBool(im.Agent.Privileges.Root)
 		elasticsearchPrivilegesCluster = jsonNullString(im.Elasticsearch.Privileges.Cluster)
 		policyTemplatesBehavior = toNullString(im.PolicyTemplatesBehavior)
@@ -161,10 +358,12 @@ func writePackage(ctx context.Context, q *dbpkg.Queries, pkg *pkgreader.Package,
 			conditionsAgentVersion = toNullString(inp.Conditions.Agent.Version)
 			conditionsKibanaVersion = toNullString(inp.Conditions.Kibana.Version)
 			conditionsElasticSubscription = toNullString(string(inp.Conditions.Elastic.Subscription))
+			conditionsElasticCapabilities = jsonNullString(inp.Conditions.Elastic.Capabilities)
 			agentPrivilegesRoot = toNullBool(inp.Agent.Privileges.Root)
 		} else if cm := pkg.ContentManifest(); cm != nil {
 			conditionsKibanaVersion = toNullString(cm.Conditions.Kibana.Version)
 			conditionsElasticSubscription = toNullString(string(cm.Conditions.Elastic.Subscription))
+			conditionsElasticCapabilities = jsonNullString(cm.Conditions.Elastic.Capabilities)
 		}
 	}
 
@@ -174,10 +373,14 @@ func writePackage(ctx context.Context, q *dbpkg.Queries, pkg *pkgreader.Package,
 		agentPrivilegesRoot,
 		toNullString(pkg.Commit),
 		conditionsAgentVersion,
+		conditionsElasticCapabilities,
 		conditionsElasticSubscription,
 		conditionsKibanaVersion,
+		0, // set by writeDeprecatedEntityCount once the deprecations are written
 		dirName,
 		elasticsearchPrivilegesCluster,
+		jsonNullString(pkg.IndexPrivileges()),
+		cfg.now().UTC().Format(time.RFC3339),
 		policyTemplatesBehavior,
 	))
 	if err != nil {
@@ -228,7 +431,7 @@ func writePackage(ctx context.Context, q *dbpkg.Queries, pkg *pkgreader.Package,
 			return fmt.Errorf("inserting changelog: %w", err)
 		}
 		for j := range cl.Changes {
-			_, err := q.InsertChangelogEntries(ctx, mapChangelogEntriesParams(&cl.Changes[j], clID))
+			_, err := q.InsertChangelogEntries(ctx, mapChangelogEntriesParams(&cl.Changes[j], clID, int64(j), cl.Version))
 			if err != nil {
 				return fmt.Errorf("inserting changelog entry: %w", err)
 			}
@@ -243,6 +446,17 @@ func writePackage(ctx context.Context, q *dbpkg.Queries, pkg *pkgreader.Package,
 		}
 	}
 
+	// Insert excluded validation checks.
+	for _, name := range pkg.ExcludedChecks() {
+		_, err := q.InsertValidationExcludedChecks(ctx, dbpkg.InsertValidationExcludedChecksParams{
+			PackagesID: pkgID,
+			Name:       name,
+		})
+		if err != nil {
+			return fmt.Errorf("inserting excluded validation check: %w", err)
+		}
+	}
+
 	// Insert images (if image metadata was loaded).
 	if err := writeImages(ctx, q, pkg, pkgID); err != nil {
 		return err
@@ -259,7 +473,7 @@ func writePackage(ctx context.Context, q *dbpkg.Queries, pkg *pkgreader.Package,
 			return err
 		}
 	case pkgspec.ManifestTypeContent:
-		if err := writeContent(ctx, q, pkg, pkgID, pathPrefix); err != nil {
+		if err := writeContent(ctx, q, pkg, pkgID, pathPrefix, cfg); err != nil {
 			return err
 		}
 	}
@@ -269,7 +483,11 @@ func writePackage(ctx context.Context, q *dbpkg.Queries, pkg *pkgreader.Package,
 		return err
 	}
 
-	return nil
+	// Insert aggregate metrics computed from the rows written above.
+	if err := writePackageMetrics(ctx, db, q, pkgID); err != nil {
+		return err
+	}
+	return writeDeprecatedEntityCount(ctx, db, pkgID)
 }
 
 func writeIntegration(ctx context.Context, q *dbpkg.Queries, pkg *pkgreader.Package, pkgID int64, pathPrefix string, cfg *writeConfig) error {
@@ -279,7 +497,7 @@ func writeIntegration(ctx context.Context, q *dbpkg.Queries, pkg *pkgreader.Pack
 	}
 
 	// Insert package-level vars.
-	if err := writeVars(ctx, q, im.Vars, func(varID int64) error {
+	if err := writeVars(ctx, q, im.Vars, cfg, func(varID int64) error {
 		_, err := q.InsertPackageVars(ctx, dbpkg.InsertPackageVarsParams{
 			PackageID: pkgID,
 			VarID:     varID,
@@ -303,10 +521,13 @@ func writeIntegration(ctx context.Context, q *dbpkg.Queries, pkg *pkgreader.Pack
 		pt := &im.PolicyTemplates[i]
 		ptID, err := q.InsertPolicyTemplates(ctx, mapPolicyTemplatesParams(
 			pt, pkgID,
-			sql.NullBool{},   // dynamic_signal_types
-			sql.NullString{}, // input
-			sql.NullString{}, // policy_template_type
-			sql.NullString{}, // template_path
+			jsonNullString(pt.DeploymentModes),
+			sql.NullBool{},        // dynamic_signal_types
+			sql.NullString{},      // input
+			int64(len(pt.Inputs)), // input_count
+			sql.NullString{},      // policy_template_type
+			sql.NullString{},      // template_path
+			int64(len(pt.Vars)),   // var_count
 		))
 		if err != nil {
 			return fmt.Errorf("inserting policy template: %w", err)
@@ -349,7 +570,7 @@ func writeIntegration(ctx context.Context, q *dbpkg.Queries, pkg *pkgreader.Pack
 		}
 
 		// Insert policy template vars.
-		if err := writeVars(ctx, q, pt.Vars, func(varID int64) error {
+		if err := writeVars(ctx, q, pt.Vars, cfg, func(varID int64) error {
 			_, err := q.InsertPolicyTemplateVars(ctx, dbpkg.InsertPolicyTemplateVarsParams{
 				PolicyTemplateID: ptID,
 				VarID:            varID,
@@ -392,7 +613,7 @@ func writeIntegration(ctx context.Context, q *dbpkg.Queries, pkg *pkgreader.Pack
 			}
 
 			// Insert input vars.
-			if err := writeVars(ctx, q, inp.Vars, func(varID int64) error {
+			if err := writeVars(ctx, q, inp.Vars, cfg, func(varID int64) error {
 				_, err := q.InsertPolicyTemplateInputVars(ctx, dbpkg.InsertPolicyTemplateInputVarsParams{
 					PolicyTemplateInputID: inpID,
 					VarID:                 varID,
@@ -415,7 +636,7 @@ func writeIntegration(ctx context.Context, q *dbpkg.Queries, pkg *pkgreader.Pack
 
 	// Insert data streams.
 	for dsName, ds := range pkg.DataStreams {
-		if err := writeDataStream(ctx, q, dsName, ds, pkgID, pathPrefix, cfg); err != nil {
+		if err := writeDataStream(ctx, q, pkg, dsName, ds, pkgID, pathPrefix, cfg); err != nil {
 			return fmt.Errorf("data stream %s: %w", dsName, err)
 		}
 	}
@@ -425,9 +646,11 @@ func writeIntegration(ctx context.Context, q *dbpkg.Queries, pkg *pkgreader.Pack
 		tID, err := q.InsertTransforms(ctx, mapTransformsParams(
 			&td.Transform,
 			pkgID,
+			toNullString(td.Transform.Dest.Index),
 			tName,
 			jsonNullString(transformManifestDestIndexTemplate(td.Manifest)),
 			toNullBool(transformManifestStart(td.Manifest)),
+			toNullString(td.Transform.RetentionPolicy.Time.MaxAge),
 		))
 		if err != nil {
 			return fmt.Errorf("inserting transform %s: %w", tName, err)
@@ -451,10 +674,13 @@ func writeIntegration(ctx context.Context, q *dbpkg.Queries, pkg *pkgreader.Pack
 		if err != nil {
 			return fmt.Errorf("inserting build manifest: %w", err)
 		}
+		if err := writePackageDependencies(ctx, q, &pkg.Build.Dependencies, pkgID); err != nil {
+			return err
+		}
 	}
 
 	// Insert Kibana saved objects.
-	if err := writeKibanaObjects(ctx, q, pkg, pkgID, pathPrefix); err != nil {
+	if err := writeKibanaObjects(ctx, q, pkg, pkgID, pathPrefix, cfg); err != nil {
 		return err
 	}
 
@@ -463,7 +689,8 @@ func writeIntegration(ctx context.Context, q *dbpkg.Queries, pkg *pkgreader.Pack
 		return err
 	}
 
-	return nil
+	// Insert processor counts by type.
+	return writeProcessorTypeCounts(ctx, q, pkg, pkgID)
 }
 
 func writeInput(ctx context.Context, q *dbpkg.Queries, pkg *pkgreader.Package, pkgID int64, pathPrefix string, cfg *writeConfig) error {
@@ -473,7 +700,7 @@ func writeInput(ctx context.Context, q *dbpkg.Queries, pkg *pkgreader.Package, p
 	}
 
 	// Insert package-level vars.
-	if err := writeVars(ctx, q, im.Vars, func(varID int64) error {
+	if err := writeVars(ctx, q, im.Vars, cfg, func(varID int64) error {
 		_, err := q.InsertPackageVars(ctx, dbpkg.InsertPackageVarsParams{
 			PackageID: pkgID,
 			VarID:     varID,
@@ -494,7 +721,7 @@ func writeInput(ctx context.Context, q *dbpkg.Queries, pkg *pkgreader.Package, p
 
 	// Insert policy templates.
 	for i := range im.PolicyTemplates {
-		if err := writeInputPolicyTemplate(ctx, q, &im.PolicyTemplates[i], pkgID, pathPrefix); err != nil {
+		if err := writeInputPolicyTemplate(ctx, q, &im.PolicyTemplates[i], pkgID, pathPrefix, cfg); err != nil {
 			return err
 		}
 	}
@@ -525,7 +752,7 @@ func writeInput(ctx context.Context, q *dbpkg.Queries, pkg *pkgreader.Package, p
 	return nil
 }
 
-func writeContent(ctx context.Context, q *dbpkg.Queries, pkg *pkgreader.Package, pkgID int64, pathPrefix string) error {
+func writeContent(ctx context.Context, q *dbpkg.Queries, pkg *pkgreader.Package, pkgID int64, pathPrefix string, cfg *writeConfig) error {
 	cm := pkg.ContentManifest()
 	if cm == nil {
 		return nil
@@ -542,15 +769,26 @@ func writeContent(ctx context.Context, q *dbpkg.Queries, pkg *pkgreader.Package,
 		}
 	}
 
+	// Insert discovery datasets.
+	for _, dd := range cm.Discovery.Datasets {
+		_, err := q.InsertDiscoveryDatasets(ctx, dbpkg.InsertDiscoveryDatasetsParams{
+			PackagesID: pkgID,
+			Name:       dd.Name,
+		})
+		if err != nil {
+			return fmt.Errorf("inserting discovery dataset: %w", err)
+		}
+	}
+
 	// Insert Kibana saved objects.
-	if err := writeKibanaObjects(ctx, q, pkg, pkgID, pathPrefix); err != nil {
+	if err := writeKibanaObjects(ctx, q, pkg, pkgID, pathPrefix, cfg); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func writeKibanaObjects(ctx context.Context, q *dbpkg.Queries, pkg *pkgreader.Package, pkgID int64, pathPrefix string) error {
+func writeKibanaObjects(ctx context.Context, q *dbpkg.Queries, pkg *pkgreader.Package, pkgID int64, pathPrefix string, cfg *writeConfig) error {
 	for assetType, objects := range pkg.KibanaObjects {
 		for _, obj := range objects {
 			// Security rules use "name" instead of "title" in attributes.
@@ -559,6 +797,12 @@ func writeKibanaObjects(ctx context.Context, q *dbpkg.Queries, pkg *pkgreader.Pa
 				title, _ = obj.Attributes.Extras["name"].(string)
 			}
 
+			// Stored as a string to keep the bytes exactly as read.
+			var raw any
+			if cfg.kibanaRaw && obj.Raw != nil {
+				raw = string(obj.Raw)
+			}
+
 			objID, err := q.InsertKibanaSavedObjects(ctx, dbpkg.InsertKibanaSavedObjectsParams{
 				PackagesID:           pkgID,
 				AssetType:            assetType,
@@ -571,6 +815,8 @@ func writeKibanaObjects(ctx context.Context, q *dbpkg.Queries, pkg *pkgreader.Pa
 				TypeMigrationVersion: toNullString(obj.TypeMigrationVersion),
 				Managed:              toNullBool(obj.Managed),
 				ReferenceCount:       int64(len(obj.References)),
+				PanelsCount:          dashboardPanelsCount(obj),
+				Raw:                  raw,
 			})
 			if err != nil {
 				return fmt.Errorf("inserting kibana saved object %s: %w", obj.ID, err)
@@ -588,9 +834,18 @@ func writeKibanaObjects(ctx context.Context, q *dbpkg.Queries, pkg *pkgreader.Pa
 				}
 			}
 
-			if assetType == "security_rule" && obj.Attributes.Extras != nil {
-				if err := writeSecurityRule(ctx, q, obj.Attributes.Extras, objID); err != nil {
-					return err
+			switch assetType {
+			case "security_rule":
+				if rule, ok := obj.SecurityRule(); ok {
+					if err := writeSecurityRule(ctx, q, rule, obj.Attributes.Extras, objID); err != nil {
+						return err
+					}
+				}
+			case "exception_list", "exception_list_item":
+				if list, ok := obj.ExceptionList(); ok {
+					if err := writeExceptionList(ctx, q, list, objID); err != nil {
+						return err
+					}
 				}
 			}
 		}
@@ -598,20 +853,46 @@ func writeKibanaObjects(ctx context.Context, q *dbpkg.Queries, pkg *pkgreader.Pa
 	return nil
 }
 
-func writeSecurityRule(ctx context.Context, q *dbpkg.Queries, extras map[string]any, ksoID int64) error {
-	ruleID, _ := extras["rule_id"].(string)
-	if ruleID == "" {
-		return nil
+// dashboardPanelsCount returns the number of panels in a dashboard's
+// panelsJSON attribute. Exported dashboards store panelsJSON as an encoded
+// string while elastic-package stores it decoded, so both forms are
+// accepted. It returns NULL for other object types or unparsable panels.
+func dashboardPanelsCount(obj *pkgreader.KibanaSavedObject) sql.NullInt64 {
+	if obj.Type != "dashboard" || obj.Attributes.Extras == nil {
+		return sql.NullInt64{}
+	}
+
+	var panels []any
+	switch v := obj.Attributes.Extras["panelsJSON"].(type) {
+	case []any:
+		panels = v
+	case string:
+		if err := json.Unmarshal([]byte(v), &panels); err != nil {
+			return sql.NullInt64{}
+		}
+	default:
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: int64(len(panels)), Valid: true}
+}
+
+// writeSecurityRule inserts a security rule and its child rows. The typed
+// rule supplies the common attributes; less common ones are read from the
+// raw attribute map.
+func writeSecurityRule(ctx context.Context, q *dbpkg.Queries, rule *pkgspec.SecurityRule, extras map[string]any, ksoID int64) error {
+	var riskScore sql.NullFloat64
+	if rule.RiskScore != nil {
+		riskScore = sql.NullFloat64{Float64: *rule.RiskScore, Valid: true}
 	}
 
 	srID, err := q.InsertSecurityRules(ctx, dbpkg.InsertSecurityRulesParams{
 		KibanaSavedObjectsID:       ksoID,
-		RuleID:                     ruleID,
-		Type:                       toNullString(extrasString(extras, "type")),
-		Severity:                   toNullString(extrasString(extras, "severity")),
-		RiskScore:                  extrasFloat64(extras, "risk_score"),
-		Language:                   toNullString(extrasString(extras, "language")),
-		Query:                      toNullString(extrasString(extras, "query")),
+		RuleID:                     rule.RuleID,
+		Type:                       toNullString(rule.Type),
+		Severity:                   toNullString(rule.Severity),
+		RiskScore:                  riskScore,
+		Language:                   toNullString(rule.Language),
+		Query:                      toNullString(rule.Query),
 		Enabled:                    extrasBool(extras, "enabled"),
 		Version:                    extrasInt64(extras, "version"),
 		License:                    toNullString(extrasString(extras, "license")),
@@ -621,6 +902,7 @@ func writeSecurityRule(ctx context.Context, q *dbpkg.Queries, extras map[string]
 		BuildingBlockType:          toNullString(extrasString(extras, "building_block_type")),
 		RuleNameOverride:           toNullString(extrasString(extras, "rule_name_override")),
 		TimestampOverride:          toNullString(extrasString(extras, "timestamp_override")),
+		TimelineID:                 toNullString(extrasString(extras, "timeline_id")),
 		Setup:                      toNullString(extrasString(extras, "setup")),
 		Note:                       toNullString(extrasString(extras, "note")),
 		Author:                     extrasJSON(extras, "author"),
@@ -643,37 +925,29 @@ func writeSecurityRule(ctx context.Context, q *dbpkg.Queries, extras map[string]
 	}
 
 	// Insert index patterns.
-	if patterns, ok := extras["index"].([]any); ok {
-		for _, p := range patterns {
-			if s, ok := p.(string); ok {
-				_, err := q.InsertSecurityRuleIndexPatterns(ctx, dbpkg.InsertSecurityRuleIndexPatternsParams{
-					SecurityRulesID: srID,
-					Pattern:         s,
-				})
-				if err != nil {
-					return fmt.Errorf("inserting security rule index pattern: %w", err)
-				}
-			}
+	for _, pattern := range rule.Index {
+		_, err := q.InsertSecurityRuleIndexPatterns(ctx, dbpkg.InsertSecurityRuleIndexPatternsParams{
+			SecurityRulesID: srID,
+			Pattern:         pattern,
+		})
+		if err != nil {
+			return fmt.Errorf("inserting security rule index pattern: %w", err)
 		}
 	}
 
 	// Insert tags.
-	if tags, ok := extras["tags"].([]any); ok {
-		for _, t := range tags {
-			if s, ok := t.(string); ok {
-				_, err := q.InsertSecurityRuleTags(ctx, dbpkg.InsertSecurityRuleTagsParams{
-					SecurityRulesID: srID,
-					Tag:             s,
-				})
-				if err != nil {
-					return fmt.Errorf("inserting security rule tag: %w", err)
-				}
-			}
+	for _, tag := range rule.Tags {
+		_, err := q.InsertSecurityRuleTags(ctx, dbpkg.InsertSecurityRuleTagsParams{
+			SecurityRulesID: srID,
+			Tag:             tag,
+		})
+		if err != nil {
+			return fmt.Errorf("inserting security rule tag: %w", err)
 		}
 	}
 
 	// Insert MITRE ATT&CK threat mappings.
-	if err := writeSecurityRuleThreats(ctx, q, extras, srID); err != nil {
+	if err := writeSecurityRuleThreats(ctx, q, rule.Threat, srID); err != nil {
 		return err
 	}
 
@@ -703,65 +977,59 @@ func writeSecurityRule(ctx context.Context, q *dbpkg.Queries, extras map[string]
 	}
 
 	// Insert required fields.
-	if fields, ok := extras["required_fields"].([]any); ok {
-		for _, item := range fields {
-			m, ok := item.(map[string]any)
-			if !ok {
-				continue
-			}
-			name, _ := m["name"].(string)
-			if name == "" {
-				continue
-			}
-			typ, _ := m["type"].(string)
-			ecs, _ := m["ecs"].(bool)
-			_, err := q.InsertSecurityRuleRequiredFields(ctx, dbpkg.InsertSecurityRuleRequiredFieldsParams{
-				SecurityRulesID: srID,
-				Name:            name,
-				Type:            toNullString(typ),
-				Ecs:             sql.NullBool{Bool: ecs, Valid: true},
-			})
-			if err != nil {
-				return fmt.Errorf("inserting security rule required field: %w", err)
-			}
+	for _, f := range rule.RequiredFields {
+		if f.Name == "" {
+			continue
+		}
+		_, err := q.InsertSecurityRuleRequiredFields(ctx, dbpkg.InsertSecurityRuleRequiredFieldsParams{
+			SecurityRulesID: srID,
+			Name:            f.Name,
+			Type:            toNullString(f.Type),
+			Ecs:             sql.NullBool{Bool: f.ECS, Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("inserting security rule required field: %w", err)
 		}
 	}
 
 	return nil
 }
 
+// writeExceptionList inserts the exception_lists row for an exception list
+// or exception list item saved object.
+func writeExceptionList(ctx context.Context, q *dbpkg.Queries, list *pkgspec.ExceptionList, ksoID int64) error {
+	_, err := q.InsertExceptionLists(ctx, dbpkg.InsertExceptionListsParams{
+		KibanaSavedObjectsID: ksoID,
+		ListID:               list.ListID,
+		ItemID:               toNullString(list.ItemID),
+		ListType:             list.ListType,
+		Type:                 toNullString(list.Type),
+		NamespaceType:        toNullString(list.NamespaceType),
+		Tags:                 jsonNullString(list.Tags),
+		OsTypes:              jsonNullString(list.OSTypes),
+		Entries:              jsonNullString(list.Entries),
+	})
+	if err != nil {
+		return fmt.Errorf("inserting exception list %s: %w", list.ListID, err)
+	}
+	return nil
+}
+
 // writeSecurityRuleThreats flattens the nested MITRE ATT&CK threat array.
 // Each tactic-technique pair becomes one row. A tactic with no techniques
 // produces one row with NULL technique columns.
-func writeSecurityRuleThreats(ctx context.Context, q *dbpkg.Queries, extras map[string]any, srID int64) error {
-	threats, ok := extras["threat"].([]any)
-	if !ok {
-		return nil
-	}
-
-	for _, item := range threats {
-		t, ok := item.(map[string]any)
-		if !ok {
-			continue
-		}
-
-		tactic, _ := t["tactic"].(map[string]any)
-		if tactic == nil {
-			continue
-		}
-		tacticID, _ := tactic["id"].(string)
-		tacticName, _ := tactic["name"].(string)
-		if tacticID == "" {
+func writeSecurityRuleThreats(ctx context.Context, q *dbpkg.Queries, threats []pkgspec.SecurityRuleThreat, srID int64) error {
+	for _, t := range threats {
+		if t.Tactic.ID == "" {
 			continue
 		}
 
-		techniques, _ := t["technique"].([]any)
-		if len(techniques) == 0 {
+		if len(t.Technique) == 0 {
 			// Tactic with no techniques: one row with NULL technique columns.
 			_, err := q.InsertSecurityRuleThreats(ctx, dbpkg.InsertSecurityRuleThreatsParams{
 				SecurityRulesID: srID,
-				TacticID:        tacticID,
-				TacticName:      tacticName,
+				TacticID:        t.Tactic.ID,
+				TacticName:      t.Tactic.Name,
 			})
 			if err != nil {
 				return fmt.Errorf("inserting security rule threat: %w", err)
@@ -769,17 +1037,10 @@ func writeSecurityRuleThreats(ctx context.Context, q *dbpkg.Queries, extras map[
 			continue
 		}
 
-		for _, techItem := range techniques {
-			tech, ok := techItem.(map[string]any)
-			if !ok {
-				continue
-			}
-			techID, _ := tech["id"].(string)
-			techName, _ := tech["name"].(string)
-
+		for _, tech := range t.Technique {
 			var subtechniques any
-			if subs, ok := tech["subtechnique"].([]any); ok && len(subs) > 0 {
-				data, err := json.Marshal(subs)
+			if len(tech.Subtechnique) > 0 {
+				data, err := json.Marshal(tech.Subtechnique)
 				if err == nil {
 					subtechniques = string(data)
 				}
@@ -787,10 +1048,10 @@ func writeSecurityRuleThreats(ctx context.Context, q *dbpkg.Queries, extras map[
 
 			_, err := q.InsertSecurityRuleThreats(ctx, dbpkg.InsertSecurityRuleThreatsParams{
 				SecurityRulesID: srID,
-				TacticID:        tacticID,
-				TacticName:      tacticName,
-				TechniqueID:     toNullString(techID),
-				TechniqueName:   toNullString(techName),
+				TacticID:        t.Tactic.ID,
+				TacticName:      t.Tactic.Name,
+				TechniqueID:     toNullString(tech.ID),
+				TechniqueName:   toNullString(tech.Name),
 				Subtechniques:   subtechniques,
 			})
 			if err != nil {
@@ -807,16 +1068,6 @@ func extrasString(m map[string]any, key string) string {
 	return v
 }
 
-// extrasFloat64 extracts a float64 value from a map as sql.NullFloat64.
-// JSON numbers unmarshal as float64.
-func extrasFloat64(m map[string]any, key string) sql.NullFloat64 {
-	v, ok := m[key].(float64)
-	if !ok {
-		return sql.NullFloat64{}
-	}
-	return sql.NullFloat64{Float64: v, Valid: true}
-}
-
 // extrasInt64 extracts a numeric value from a map as sql.NullInt64.
 // JSON numbers unmarshal as float64, so the conversion goes through float64.
 func extrasInt64(m map[string]any, key string) sql.NullInt64 {
@@ -854,7 +1105,7 @@ func extrasJSON(m map[string]any, key string) any {
 	return s
 }
 
-func writeInputPolicyTemplate(ctx context.Context, q *dbpkg.Queries, pt *pkgspec.InputPolicyTemplate, pkgID int64, pathPrefix string) error {
+func writeInputPolicyTemplate(ctx context.Context, q *dbpkg.Queries, pt *pkgspec.InputPolicyTemplate, pkgID int64, pathPrefix string, cfg *writeConfig) error {
 	// Resolve template_path to fully-qualified path for
 	// easy joins to agent_templates.file_path.
 	var resolvedTemplatePath sql.NullString
@@ -865,10 +1116,12 @@ func writeInputPolicyTemplate(ctx context.Context, q *dbpkg.Queries, pt *pkgspec
 	ptID, err := q.InsertPolicyTemplates(ctx, dbpkg.InsertPolicyTemplatesParams{
 		PackagesID:                                      pkgID,
 		ConfigurationLinks:                              jsonNullString(pt.ConfigurationLinks),
+		DeploymentModes:                                 jsonNullString(pt.DeploymentModes),
 		DeploymentModesAgentlessDivision:                toNullString(pt.DeploymentModes.Agentless.Division),
 		DeploymentModesAgentlessEnabled:                 toNullBool(pt.DeploymentModes.Agentless.Enabled),
 		DeploymentModesAgentlessIsDefault:               toNullBool(pt.DeploymentModes.Agentless.IsDefault),
 		DeploymentModesAgentlessOrganization:            toNullString(pt.DeploymentModes.Agentless.Organization),
+		DeploymentModesAgentlessRelease:                 toNullString(string(pt.DeploymentModes.Agentless.Release)),
 		DeploymentModesAgentlessResourcesRequestsCpu:    toNullString(pt.DeploymentModes.Agentless.Resources.Requests.CPU),
 		DeploymentModesAgentlessResourcesRequestsMemory: toNullString(pt.DeploymentModes.Agentless.Resources.Requests.Memory),
 		DeploymentModesAgentlessTeam:                    toNullString(pt.DeploymentModes.Agentless.Team),
@@ -881,6 +1134,7 @@ func writeInputPolicyTemplate(ctx context.Context, q *dbpkg.Queries, pt *pkgspec
 		PolicyTemplateType:                              toNullString(string(pt.Type)),
 		TemplatePath:                                    resolvedTemplatePath,
 		Title:                                           pt.Title,
+		VarCount:                                        int64(len(pt.Vars)),
 	})
 	if err != nil {
 		return fmt.Errorf("inserting input policy template: %w", err)
@@ -912,7 +1166,7 @@ func writeInputPolicyTemplate(ctx context.Context, q *dbpkg.Queries, pt *pkgspec
 	}
 
 	// Insert policy template vars.
-	if err := writeVars(ctx, q, pt.Vars, func(varID int64) error {
+	if err := writeVars(ctx, q, pt.Vars, cfg, func(varID int64) error {
 		_, err := q.InsertPolicyTemplateVars(ctx, dbpkg.InsertPolicyTemplateVarsParams{
 			PolicyTemplateID: ptID,
 			VarID:            varID,
@@ -934,8 +1188,11 @@ func writeInputPolicyTemplate(ctx context.Context, q *dbpkg.Queries, pt *pkgspec
 	return nil
 }
 
-func writeDataStream(ctx context.Context, q *dbpkg.Queries, dsName string, ds *pkgreader.DataStream, pkgID int64, pathPrefix string, cfg *writeConfig) error {
-	dsID, err := q.InsertDataStreams(ctx, mapDataStreamsParams(&ds.Manifest, pkgID, dsName))
+func writeDataStream(ctx context.Context, q *dbpkg.Queries, pkg *pkgreader.Package, dsName string, ds *pkgreader.DataStream, pkgID int64, pathPrefix string, cfg *writeConfig) error {
+	pkgName := pkg.Manifest().Name
+	dsID, err := q.InsertDataStreams(ctx, mapDataStreamsParams(&ds.Manifest, pkgID,
+		dsName, ds.Dataset(pkgName), toNullString(ds.IndexTemplateName(pkgName)),
+		int64(len(ds.Manifest.Streams))))
 	if err != nil {
 		return fmt.Errorf("inserting data stream: %w", err)
 	}
@@ -951,18 +1208,21 @@ func writeDataStream(ctx context.Context, q *dbpkg.Queries, dsName string, ds *p
 
 	// Insert sample event (unnamed).
 	if ds.SampleEvent != nil {
-		_, err := q.InsertSampleEvents(ctx, dbpkg.InsertSampleEventsParams{
+		seID, err := q.InsertSampleEvents(ctx, dbpkg.InsertSampleEventsParams{
 			DataStreamsID: dsID,
 			Event:         string(ds.SampleEvent),
 		})
 		if err != nil {
 			return fmt.Errorf("inserting sample event: %w", err)
 		}
+		if err := writeSampleEventFields(ctx, q, ds.SampleEvent, seID); err != nil {
+			return fmt.Errorf("inserting sample event fields: %w", err)
+		}
 	}
 
 	// Insert named sample events (sample_event_<name>.json).
 	for name, event := range ds.SampleEvents {
-		_, err := q.InsertSampleEvents(ctx, dbpkg.InsertSampleEventsParams{
+		seID, err := q.InsertSampleEvents(ctx, dbpkg.InsertSampleEventsParams{
 			DataStreamsID: dsID,
 			Name:          sql.NullString{String: name, Valid: true},
 			Event:         string(event),
@@ -970,6 +1230,9 @@ func writeDataStream(ctx context.Context, q *dbpkg.Queries, dsName string, ds *p
 		if err != nil {
 			return fmt.Errorf("inserting sample event %s: %w", name, err)
 		}
+		if err := writeSampleEventFields(ctx, q, event, seID); err != nil {
+			return fmt.Errorf("inserting sample event %s fields: %w", name, err)
+		}
 	}
 
 	// Insert streams.
@@ -978,18 +1241,14 @@ func writeDataStream(ctx context.Context, q *dbpkg.Queries, dsName string, ds *p
 		p := mapStreamsParams(stream, dsID)
 		// Resolve template_path to fully-qualified path for
 		// easy joins to agent_templates.file_path.
-		templatePath := stream.TemplatePath
-		if templatePath == "" {
-			templatePath = "stream.yml.hbs"
-		}
-		p.TemplatePath = toNullString(path.Join(pathPrefix, "data_stream", dsName, "agent", "stream", templatePath))
+		p.TemplatePath = toNullString(path.Join(pathPrefix, ds.StreamTemplatePath(i)))
 		streamID, err := q.InsertStreams(ctx, p)
 		if err != nil {
 			return fmt.Errorf("inserting stream: %w", err)
 		}
 
 		// Insert stream vars.
-		if err := writeVars(ctx, q, stream.Vars, func(varID int64) error {
+		if err := writeVars(ctx, q, stream.Vars, cfg, func(varID int64) error {
 			_, err := q.InsertStreamVars(ctx, dbpkg.InsertStreamVarsParams{
 				StreamID: streamID,
 				VarID:    varID,
@@ -1048,7 +1307,7 @@ func writeDataStream(ctx context.Context, q *dbpkg.Queries, dsName string, ds *p
 
 	// Insert test configs.
 	if ds.Tests != nil {
-		if err := writeDataStreamTests(ctx, q, ds.Tests, dsID); err != nil {
+		if err := writeDataStreamTests(ctx, q, pkg.Path(), ds.Tests, dsID, cfg); err != nil {
 			return fmt.Errorf("inserting tests: %w", err)
 		}
 	}
@@ -1061,26 +1320,51 @@ func writeDataStream(ctx context.Context, q *dbpkg.Queries, dsName string, ds *p
 	return nil
 }
 
+// writeSampleEventFields records the leaf field paths of a sample event.
+// Sample events are read without validation, so an event that is not valid
+// JSON records no paths rather than failing the write.
+func writeSampleEventFields(ctx context.Context, q *dbpkg.Queries, event []byte, seID int64) error {
+	paths, err := pkgreader.SampleEventPaths(event)
+	if err != nil {
+		return nil
+	}
+	for _, p := range paths {
+		_, err := q.InsertSampleEventFields(ctx, dbpkg.InsertSampleEventFieldsParams{
+			SampleEventsID: seID,
+			Path:           p,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func writeFields(ctx context.Context, q *dbpkg.Queries, fieldsMap map[string]*pkgreader.FieldsFile, cfg *writeConfig, link func(fieldID int64) error) error {
 	if fieldsMap == nil {
 		return nil
 	}
 
-	// Collect all fields from all files.
+	// Collect all fields from all files, in filename order so that rows
+	// are inserted in the same order on every run.
 	var allFields []pkgspec.Field
-	for _, ff := range fieldsMap {
-		allFields = append(allFields, ff.Fields...)
+	for _, name := range slices.Sorted(maps.Keys(fieldsMap)) {
+		allFields = append(allFields, fieldsMap[name].Fields...)
 	}
 
 	// Flatten fields.
 	flat := pkgspec.FlattenFields(allFields, cfg.ecsLookup)
 
 	for i := range flat {
-		fieldID, err := q.InsertFields(ctx, mapFieldsParams(&flat[i]))
+		var allowedValues any
+		if flat[i].ECS != nil {
+			allowedValues = jsonNullString(flat[i].ECS.AllowedValues)
+		}
+		fieldID, err := q.InsertFields(ctx, mapFieldsParams(&flat[i], allowedValues))
 		if err != nil {
 			return fmt.Errorf("inserting field %s: %w", flat[i].Name, err)
 		}
-		if err := link(fieldID); err != nil {
+		if err := linkErr(link(fieldID)); err != nil {
 			return fmt.Errorf("linking field %s: %w", flat[i].Name, err)
 		}
 	}
@@ -1155,18 +1439,22 @@ func writeImages(ctx context.Context, q *dbpkg.Queries, pkg *pkgreader.Package,
 func writeDocs(ctx context.Context, q *dbpkg.Queries, pkg *pkgreader.Package, pkgID int64, cfg *writeConfig) error {
 	for _, doc := range pkg.Docs {
 		var content sql.NullString
+		var truncated sql.NullBool
 		if cfg.docReader != nil {
 			data, err := cfg.docReader(pkg.Path(), doc.FSPath())
 			if err != nil {
 				return fmt.Errorf("reading doc %s: %w", doc.Path(), err)
 			}
-			content = sql.NullString{String: stripFieldTables(string(data)), Valid: true}
+			text, cut := truncateDoc(stripFieldTables(string(data)), cfg.maxDocBytes)
+			content = sql.NullString{String: text, Valid: true}
+			truncated = sql.NullBool{Bool: cut, Valid: true}
 		}
 		_, err := q.InsertDocs(ctx, dbpkg.InsertDocsParams{
 			PackagesID:  pkgID,
 			FilePath:    doc.Path(),
 			ContentType: string(doc.ContentType),
 			Content:     content,
+			Truncated:   truncated,
 		})
 		if err != nil {
 			return fmt.Errorf("inserting doc %s: %w", doc.Path(), err)
@@ -1175,6 +1463,22 @@ func writeDocs(ctx context.Context, q *dbpkg.Queries, pkg *pkgreader.Package, pk
 	return nil
 }
 
+// truncateDoc cuts s to at most n bytes, preferring the end of the last
+// complete line and otherwise a UTF-8 character boundary. It reports whether
+// s was shortened. A limit of zero or less leaves s unchanged.
+func truncateDoc(s string, n int) (string, bool) {
+	if n <= 0 || len(s) <= n {
+		return s, false
+	}
+	if i := strings.LastIndexByte(s[:n], '\n'); i >= 0 {
+		return s[:i+1], true
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n], true
+}
+
 // sectionParent holds the (mutually exclusive) owning-row ID for a sections
 // or var_groups row. Exactly one of the embedded NullInt64 values should be
 // Valid; the rest are NULL.
@@ -1233,13 +1537,39 @@ func writeVarGroups(ctx context.Context, q *dbpkg.Queries, groups []pkgspec.VarG
 	return nil
 }
 
-func writeVars(ctx context.Context, q *dbpkg.Queries, vars []pkgspec.Var, link func(varID int64) error) error {
+func writeVars(ctx context.Context, q *dbpkg.Queries, vars []pkgspec.Var, cfg *writeConfig, link func(varID int64) error) error {
+	// With WithVarDedup, a var declared twice on the same parent resolves
+	// to one vars row, which is linked only once.
+	linked := make(map[int64]bool)
 	for i := range vars {
+		var key string
+		if cfg.varIDs != nil {
+			k, err := varKey(&vars[i])
+			if err != nil {
+				return fmt.Errorf("hashing var %s: %w", vars[i].Name, err)
+			}
+			if varID, ok := cfg.varIDs[k]; ok {
+				if linked[varID] {
+					continue
+				}
+				linked[varID] = true
+				if err := linkErr(link(varID)); err != nil {
+					return fmt.Errorf("linking var %s: %w", vars[i].Name, err)
+				}
+				continue
+			}
+			key = k
+		}
+
 		varID, err := q.InsertVars(ctx, mapVarsParams(&vars[i]))
 		if err != nil {
 			return fmt.Errorf("inserting var %s: %w", vars[i].Name, err)
 		}
-		if err := link(varID); err != nil {
+		if key != "" {
+			cfg.varIDs[key] = varID
+		}
+		linked[varID] = true
+		if err := linkErr(link(varID)); err != nil {
 			return fmt.Errorf("linking var %s: %w", vars[i].Name, err)
 		}
 
@@ -1255,6 +1585,28 @@ func writeVars(ctx context.Context, q *dbpkg.Queries, vars []pkgspec.Var, link f
 	return nil
 }
 
+// linkErr returns the error of a join table insert, treating sql.ErrNoRows
+// as success. With OnConflictIgnore a duplicate link inserts no row, so its
+// RETURNING clause yields nothing.
+func linkErr(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	return err
+}
+
+// varKey returns a hash of the var definition used by WithVarDedup. The JSON
+// encoding covers every attribute, including deprecation, but not the source
+// file location.
+func varKey(v *pkgspec.Var) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
 // isDeprecated reports whether a Deprecated struct indicates active deprecation.
 func isDeprecated(d pkgspec.Deprecated) bool {
 	return d.Since != ""
@@ -1275,10 +1627,10 @@ func deprecationParams(d pkgspec.Deprecated) dbpkg.InsertDeprecationsParams {
 	}
 }
 
-func writeDataStreamTests(ctx context.Context, q *dbpkg.Queries, tests *pkgreader.DataStreamTests, dsID int64) error {
+func writeDataStreamTests(ctx context.Context, q *dbpkg.Queries, pkgPath string, tests *pkgreader.DataStreamTests, dsID int64, cfg *writeConfig) error {
 	// Insert pipeline tests.
 	for _, tc := range tests.Pipeline {
-		if err := writePipelineTest(ctx, q, tc, dsID); err != nil {
+		if err := writePipelineTest(ctx, q, pkgPath, tc, dsID, cfg); err != nil {
 			return fmt.Errorf("pipeline test %s: %w", tc.Name, err)
 		}
 	}
@@ -1317,7 +1669,7 @@ func writeDataStreamTests(ctx context.Context, q *dbpkg.Queries, tests *pkgreade
 	return nil
 }
 
-func writePipelineTest(ctx context.Context, q *dbpkg.Queries, tc *pkgreader.PipelineTestCase, dsID int64) error {
+func writePipelineTest(ctx context.Context, q *dbpkg.Queries, pkgPath string, tc *pkgreader.PipelineTestCase, dsID int64, cfg *writeConfig) error {
 	p := dbpkg.InsertPipelineTestsParams{
 		DataStreamsID: dsID,
 		Name:          tc.Name,
@@ -1327,6 +1679,23 @@ func writePipelineTest(ctx context.Context, q *dbpkg.Queries, tc *pkgreader.Pipe
 		ConfigPath:    toNullString(tc.ConfigPath),
 	}
 
+	// Load event and expected file content if requested.
+	if cfg.testReader != nil {
+		data, err := cfg.testReader(pkgPath, tc.EventPath)
+		if err != nil {
+			return fmt.Errorf("reading event %s: %w", tc.EventPath, err)
+		}
+		p.Event = sql.NullString{String: string(data), Valid: true}
+
+		if tc.ExpectedPath != "" {
+			data, err := cfg.testReader(pkgPath, tc.ExpectedPath)
+			if err != nil {
+				return fmt.Errorf("reading expected %s: %w", tc.ExpectedPath, err)
+			}
+			p.Expected = sql.NullString{String: string(data), Valid: true}
+		}
+	}
+
 	// Extract fields from the per-case config if present.
 	switch cfg := tc.Config.(type) {
 	case *pkgspec.PipelineTestJSONConfig:
@@ -1350,6 +1719,54 @@ func writePipelineTest(ctx context.Context, q *dbpkg.Queries, tc *pkgreader.Pipe
 	return err
 }
 
+// writePackageDependencies inserts one package_dependencies row for the ECS
+// dependency and for each additional dependency declared in build.yml.
+func writePackageDependencies(ctx context.Context, q *dbpkg.Queries, deps *pkgspec.BuildDependencies, pkgID int64) error {
+	if deps.ECS.Reference != "" || deps.ECS.ImportMappings != nil {
+		_, err := q.InsertPackageDependencies(ctx, dbpkg.InsertPackageDependenciesParams{
+			PackagesID:     pkgID,
+			Name:           "ecs",
+			Reference:      toNullString(deps.ECS.Reference),
+			Version:        toNullString(dependencyVersion(deps.ECS.Reference)),
+			ImportMappings: toNullBool(deps.ECS.ImportMappings),
+		})
+		if err != nil {
+			return fmt.Errorf("inserting package dependency ecs: %w", err)
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(deps.Extras)) {
+		// Dependencies are usually objects with a reference key, but accept
+		// a bare reference string too.
+		var ref string
+		switch v := deps.Extras[name].(type) {
+		case map[string]any:
+			ref = extrasString(v, "reference")
+		case string:
+			ref = v
+		}
+
+		_, err := q.InsertPackageDependencies(ctx, dbpkg.InsertPackageDependenciesParams{
+			PackagesID: pkgID,
+			Name:       name,
+			Reference:  toNullString(ref),
+			Version:    toNullString(dependencyVersion(ref)),
+		})
+		if err != nil {
+			return fmt.Errorf("inserting package dependency %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// dependencyVersion returns the version portion of a build dependency
+// reference by removing the "git@" and "v" prefixes (e.g. "git@v8.11.0"
+// becomes "8.11.0").
+func dependencyVersion(ref string) string {
+	ref = strings.TrimPrefix(ref, "git@")
+	return strings.TrimPrefix(ref, "v")
+}
+
 func writeInputTests(ctx context.Context, q *dbpkg.Queries, tests *pkgreader.InputPackageTests, pkgID int64) error {
 	// Insert system tests.
 	for caseName, cfg := range tests.System {
@@ -3,18 +3,25 @@ package pkgsql_test
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"io/fs"
+	"maps"
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"testing"
 	"testing/fstest"
+	"time"
+	"unicode/utf8"
 
 	_ "modernc.org/sqlite"
 
 	"github.com/andrewkroh/go-package-spec/pkgreader"
+	"github.com/andrewkroh/go-package-spec/pkgspec"
 	"github.com/andrewkroh/go-package-spec/pkgsql"
 )
 
@@ -28,23 +35,108 @@ func newTestDB(t *testing.T) *sql.DB {
 	return db
 }
 
+// testManifest is the manifest.yml that readTestPackage uses when the test
+// does not supply one. Tests that only need extra manifest keys append them.
+const testManifest = `
+name: test-package
+title: Test Package
+version: 1.0.0
+description: test
+format_version: 3.5.7
+type: integration
+owner:
+  github: elastic/integrations
+  type: elastic
+`
+
+// testChangelog is the changelog.yml that readTestPackage uses when the test
+// does not supply one. It matches the version in testManifest.
+const testChangelog = `
+- version: 1.0.0
+  changes:
+    - description: Initial release
+      type: enhancement
+      link: https://github.com/test/1
+`
+
+// readTestPackage reads a package from files, adding testManifest and
+// testChangelog unless files provides its own manifest.yml or
+// changelog.yml, so that tests only supply the files they are about.
+func readTestPackage(t *testing.T, files fstest.MapFS, opts ...pkgreader.Option) *pkgreader.Package {
+	t.Helper()
+
+	fsys := fstest.MapFS{
+		"manifest.yml":  {Data: []byte(testManifest)},
+		"changelog.yml": {Data: []byte(testChangelog)},
+	}
+	maps.Copy(fsys, files)
+
+	pkg, err := pkgreader.Read(".", append([]pkgreader.Option{pkgreader.WithFS(fsys)}, opts...)...)
+	if err != nil {
+		t.Fatalf("reading package: %v", err)
+	}
+	return pkg
+}
+
+// writeTestPackage writes the package read by readTestPackage into a new
+// in-memory database and returns the database.
+func writeTestPackage(t *testing.T, files fstest.MapFS, opts ...pkgsql.Option) *sql.DB {
+	t.Helper()
+
+	pkg := readTestPackage(t, files)
+	db := newTestDB(t)
+	if err := pkgsql.WritePackages(context.Background(), db, []*pkgreader.Package{pkg}, opts...); err != nil {
+		t.Fatalf("writing packages: %v", err)
+	}
+	return db
+}
+
 func TestTableSchemas(t *testing.T) {
 	schemas := pkgsql.TableSchemas()
 	if len(schemas) == 0 {
 		t.Fatal("expected at least one table schema")
 	}
 	for _, s := range schemas {
-		if !strings.HasPrefix(s, "CREATE TABLE IF NOT EXISTS") && !strings.HasPrefix(s, "CREATE VIRTUAL TABLE IF NOT EXISTS") && !strings.HasPrefix(s, "CREATE VIEW IF NOT EXISTS") {
-			t.Errorf("expected CREATE TABLE, CREATE VIRTUAL TABLE, or CREATE VIEW prefix, got: %s", s[:50])
+		if !strings.HasPrefix(s, "CREATE TABLE IF NOT EXISTS") && !strings.HasPrefix(s, "CREATE VIRTUAL TABLE IF NOT EXISTS") && !strings.HasPrefix(s, "CREATE VIEW IF NOT EXISTS") && !strings.HasPrefix(s, "CREATE INDEX IF NOT EXISTS") {
+			t.Errorf("expected CREATE TABLE, CREATE VIRTUAL TABLE, CREATE VIEW, or CREATE INDEX prefix, got: %s", s[:50])
+		}
+	}
+}
+
+func TestQuerySQL(t *testing.T) {
+	q := pkgsql.QuerySQL()
+	for _, want := range []string{
+		"-- name: InsertPackages :one\nINSERT INTO packages (",
+		"-- name: DeletePackages :exec\n",
+	} {
+		if !strings.Contains(q, want) {
+			t.Errorf("QuerySQL() missing %q", want)
+		}
+	}
+
+	// The statement can be prepared against a database created from
+	// TableSchemas.
+	db := newTestDB(t)
+	ctx := context.Background()
+	for _, ddl := range pkgsql.TableSchemas() {
+		if _, err := db.ExecContext(ctx, ddl); err != nil {
+			t.Fatalf("executing DDL: %v", err)
 		}
 	}
+	_, stmt, _ := strings.Cut(q, "-- name: InsertPackages :one\n")
+	stmt, _, _ = strings.Cut(stmt, ";")
+	prepared, err := db.PrepareContext(ctx, stmt)
+	if err != nil {
+		t.Fatalf("preparing InsertPackages: %v", err)
+	}
+	prepared.Close()
 }
 
 func TestTableSchemasContainComments(t *testing.T) {
 	schemas := pkgsql.TableSchemas()
 	for _, s := range schemas {
-		// FTS5 virtual tables and views don't have inline comments.
-		if strings.HasPrefix(s, "CREATE VIRTUAL TABLE") || strings.HasPrefix(s, "CREATE VIEW") {
+		// FTS5 virtual tables, views, and indexes don't have inline comments.
+		if strings.HasPrefix(s, "CREATE VIRTUAL TABLE") || strings.HasPrefix(s, "CREATE VIEW") || strings.HasPrefix(s, "CREATE INDEX") {
 			continue
 		}
 		if !strings.Contains(s, "-- ") {
@@ -651,6 +743,68 @@ policy_templates:
 	}
 }
 
+func TestWritePackageWithImagesPathPrefix(t *testing.T) {
+	fsys := fstest.MapFS{
+		"packages/img_test/manifest.yml": {Data: []byte(`
+name: img_test
+title: Image Test
+version: 1.0.0
+description: A package with images.
+format_version: 3.5.7
+type: integration
+owner:
+  github: elastic/integrations
+  type: elastic
+icons:
+  - src: /img/icon.png
+    title: Icon
+`)},
+		"packages/img_test/changelog.yml": {Data: []byte(`
+- version: 1.0.0
+  changes:
+    - description: Initial release
+      type: enhancement
+      link: https://github.com/test/1
+`)},
+		"packages/img_test/img/icon.png": {Data: png1x1},
+	}
+
+	pkg, err := pkgreader.Read("packages/img_test",
+		pkgreader.WithFS(fsys),
+		pkgreader.WithPathPrefix("packages/img_test"),
+		pkgreader.WithImageMetadata(),
+	)
+	if err != nil {
+		t.Fatalf("reading package: %v", err)
+	}
+
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	if err := pkgsql.WritePackages(ctx, db, []*pkgreader.Package{pkg}); err != nil {
+		t.Fatalf("writing packages: %v", err)
+	}
+
+	var src string
+	if err := db.QueryRowContext(ctx, "SELECT src FROM images").Scan(&src); err != nil {
+		t.Fatalf("querying images: %v", err)
+	}
+	if src != "/img/icon.png" {
+		t.Errorf("image src = %q, want /img/icon.png", src)
+	}
+
+	var joinCount int
+	err = db.QueryRowContext(ctx,
+		"SELECT count(*) FROM package_icons i JOIN images img ON i.src = img.src AND i.packages_id = img.packages_id").
+		Scan(&joinCount)
+	if err != nil {
+		t.Fatalf("querying icon-image join: %v", err)
+	}
+	if joinCount != 1 {
+		t.Errorf("expected 1 icon-image join, got %d", joinCount)
+	}
+}
+
 func TestWriteInputPackagePolicyTemplates(t *testing.T) {
 	fsys := fstest.MapFS{
 		"manifest.yml": {Data: []byte(`
@@ -836,6 +990,9 @@ discovery:
   fields:
     - name: event.kind
     - name: event.category
+  datasets:
+    - name: nginx.access
+    - name: nginx.error
 `)},
 		"changelog.yml": {Data: []byte(`
 - version: 1.0.0
@@ -902,6 +1059,33 @@ discovery:
 	if dfName != "event.category" {
 		t.Errorf("expected event.category, got %s", dfName)
 	}
+
+	// Verify discovery datasets.
+	rows, err := db.QueryContext(ctx, `
+		SELECT dd.name
+		FROM discovery_datasets dd
+		JOIN packages p ON p.id = dd.packages_id
+		WHERE p.name = 'test-content'
+		ORDER BY dd.name`)
+	if err != nil {
+		t.Fatalf("querying discovery datasets: %v", err)
+	}
+	defer rows.Close()
+
+	var datasets []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			t.Fatal(err)
+		}
+		datasets = append(datasets, name)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"nginx.access", "nginx.error"}; !slices.Equal(datasets, want) {
+		t.Errorf("discovery datasets = %v, want %v", datasets, want)
+	}
 }
 
 func TestWritePackageWithDocContent(t *testing.T) {
@@ -1464,75 +1648,3422 @@ data_stream:
 	}
 }
 
-func TestBuildFleetPackagesDB(t *testing.T) {
-	dir := os.Getenv("INTEGRATIONS_DIR")
-	if dir == "" {
-		t.Skip("INTEGRATIONS_DIR not set")
+func TestInputPackageTestConfigsNullable(t *testing.T) {
+	pkg := readTestPackage(t, fstest.MapFS{
+		"manifest.yml": {Data: []byte(`
+name: test-input
+title: Test Input
+version: 1.0.0
+description: test
+format_version: 3.5.7
+type: input
+owner:
+  github: elastic/integrations
+  type: elastic
+policy_templates:
+  - name: logs
+    type: logs
+    title: Logs
+    description: Collect logs.
+    input: logfile
+    template_path: input.yml.hbs
+`)},
+		"_dev/test/system/test-empty-config.yml": {Data: []byte("{}\n")},
+		"_dev/test/system/test-withvars-config.yml": {Data: []byte(`
+vars:
+  data_stream.dataset: custom_dataset
+data_stream:
+  vars:
+    data_stream.dataset: ds_override
+`)},
+		"_dev/test/policy/test-empty.yml": {Data: []byte("{}\n")},
+		"_dev/test/policy/test-withvars.yml": {Data: []byte(`
+input: logfile
+vars:
+  paths:
+    - /var/log/*.log
+data_stream:
+  vars:
+    tags: [forwarded]
+`)},
+	}, pkgreader.WithTestConfigs())
+
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	if err := pkgsql.WritePackages(ctx, db, []*pkgreader.Package{pkg}); err != nil {
+		t.Fatalf("writing package: %v", err)
 	}
 
-	packagesDir := filepath.Join(dir, "packages")
-	pkgPaths, err := pkgreader.ListPackages(packagesDir)
-	if err != nil {
-		t.Fatalf("listing packages: %v", err)
+	for _, table := range []string{"system_tests", "policy_tests"} {
+		// Input package tests link to the package, not a data stream.
+		var linked int
+		err := db.QueryRowContext(ctx,
+			"SELECT count(*) FROM "+table+" WHERE packages_id IS NOT NULL AND data_streams_id IS NULL").Scan(&linked)
+		if err != nil {
+			t.Fatalf("querying %s: %v", table, err)
+		}
+		if linked != 2 {
+			t.Errorf("%s: %d rows linked to the package, want 2", table, linked)
+		}
+
+		// The empty config has NULL vars and data_stream, as for data
+		// stream tests.
+		var vars, dataStream sql.NullString
+		err = db.QueryRowContext(ctx,
+			"SELECT vars, data_stream FROM "+table+" WHERE case_name = 'empty'").Scan(&vars, &dataStream)
+		if err != nil {
+			t.Fatalf("querying empty %s: %v", table, err)
+		}
+		if vars.Valid || dataStream.Valid {
+			t.Errorf("%s empty: vars = %v, data_stream = %v, want NULL", table, vars, dataStream)
+		}
+
+		err = db.QueryRowContext(ctx,
+			"SELECT vars, data_stream FROM "+table+" WHERE case_name = 'withvars'").Scan(&vars, &dataStream)
+		if err != nil {
+			t.Fatalf("querying withvars %s: %v", table, err)
+		}
+		if !vars.Valid || !dataStream.Valid {
+			t.Errorf("%s withvars: vars = %v, data_stream = %v, want non-NULL", table, vars, dataStream)
+		}
 	}
+}
 
-	dbPath := filepath.Join(".", "fleet-packages.sqlite")
-	os.Remove(dbPath)
+func TestWritePackageWithTestContent(t *testing.T) {
+	eventJSON := `{"events":[{"message":"hello"}]}`
+	expectedJSON := `{"expected":[{"message":"hello","event":{"original":"hello"}}]}`
+	rawLog := "line one\nline two\n"
 
-	db, err := sql.Open("sqlite", dbPath)
-	if err != nil {
-		t.Fatal(err)
+	fsys := fstest.MapFS{
+		"manifest.yml": {Data: []byte(`
+name: test-package
+title: Test
+version: 1.0.0
+description: test
+format_version: 3.5.7
+type: integration
+owner:
+  github: elastic/integrations
+  type: elastic
+policy_templates:
+  - name: default
+    title: Default
+    description: Default policy.
+    inputs:
+      - type: logfile
+        title: Log
+        description: Collect logs.
+`)},
+		"changelog.yml": {Data: []byte(`
+- version: 1.0.0
+  changes:
+    - description: Initial release
+      type: enhancement
+      link: https://github.com/test/1
+`)},
+		"data_stream/logs/manifest.yml": {Data: []byte(`
+title: Logs
+type: logs
+streams:
+  - input: logfile
+    title: Logs
+    description: Collect logs.
+`)},
+		"data_stream/logs/fields/base-fields.yml": {Data: []byte(`
+- name: "@timestamp"
+  type: date
+`)},
+		"data_stream/logs/_dev/test/pipeline/test-json.json":               {Data: []byte(eventJSON)},
+		"data_stream/logs/_dev/test/pipeline/test-json.json-expected.json": {Data: []byte(expectedJSON)},
+		"data_stream/logs/_dev/test/pipeline/test-raw.log":                 {Data: []byte(rawLog)},
 	}
-	defer db.Close()
 
-	// Enable WAL mode and other SQLite optimizations for bulk inserts.
-	for _, pragma := range []string{
-		"PRAGMA journal_mode=WAL",
-		"PRAGMA synchronous=NORMAL",
-		"PRAGMA cache_size=-64000",
-		"PRAGMA mmap_size=268435456",
-		"PRAGMA temp_store=MEMORY",
-	} {
-		if _, err := db.Exec(pragma); err != nil {
-			t.Fatalf("setting %s: %v", pragma, err)
-		}
+	pkg, err := pkgreader.Read(".", pkgreader.WithFS(fsys), pkgreader.WithTestConfigs())
+	if err != nil {
+		t.Fatalf("reading package: %v", err)
 	}
 
+	db := newTestDB(t)
 	ctx := context.Background()
 
-	// Create tables.
-	for _, ddl := range pkgsql.TableSchemas() {
-		if _, err := db.ExecContext(ctx, ddl); err != nil {
-			t.Fatalf("creating tables: %v", err)
-		}
+	testReader := func(_, testPath string) ([]byte, error) {
+		return fs.ReadFile(fsys, testPath)
 	}
 
-	codeownersPath := filepath.Join(dir, ".github", "CODEOWNERS")
-	opts := []pkgreader.Option{
-		pkgreader.WithKnownFields(),
-		pkgreader.WithGitMetadata(),
-		pkgreader.WithImageMetadata(),
-		pkgreader.WithTestConfigs(),
-		pkgreader.WithAgentTemplates(),
-		pkgreader.WithCodeowners(codeownersPath),
+	err = pkgsql.WritePackages(ctx, db, []*pkgreader.Package{pkg}, pkgsql.WithTestContent(testReader))
+	if err != nil {
+		t.Fatalf("writing packages: %v", err)
 	}
 
-	// Read packages in parallel, write to DB sequentially.
-	type result struct {
-		pkg  *pkgreader.Package
-		name string
-		err  error
+	// The JSON test case stores both the event and the expected output.
+	var event, expected sql.NullString
+	err = db.QueryRowContext(ctx,
+		"SELECT event, expected FROM pipeline_tests WHERE name = 'test-json'").
+		Scan(&event, &expected)
+	if err != nil {
+		t.Fatalf("querying json pipeline test: %v", err)
+	}
+	if event.String != eventJSON {
+		t.Errorf("event = %q, want %q", event.String, eventJSON)
+	}
+	if expected.String != expectedJSON {
+		t.Errorf("expected = %q, want %q", expected.String, expectedJSON)
 	}
 
-	// Use more workers than CPUs since package reading is I/O bound
-	// (git blame subprocess, file reads).
-	workers := 4 * runtime.NumCPU()
-	work := make(chan string, workers)
-	results := make(chan result, workers)
+	// The stored expected document is queryable as JSON.
+	var message string
+	err = db.QueryRowContext(ctx,
+		"SELECT json_extract(expected, '$.expected[0].message') FROM pipeline_tests WHERE name = 'test-json'").
+		Scan(&message)
+	if err != nil {
+		t.Fatalf("extracting expected message: %v", err)
+	}
+	if message != "hello" {
+		t.Errorf("expected message = %q, want hello", message)
+	}
 
-	var wg sync.WaitGroup
-	for range workers {
-		wg.Add(1)
+	// The raw test case has no expected file.
+	err = db.QueryRowContext(ctx,
+		"SELECT event, expected FROM pipeline_tests WHERE name = 'test-raw'").
+		Scan(&event, &expected)
+	if err != nil {
+		t.Fatalf("querying raw pipeline test: %v", err)
+	}
+	if event.String != rawLog {
+		t.Errorf("event = %q, want %q", event.String, rawLog)
+	}
+	if expected.Valid {
+		t.Errorf("expected NULL expected for raw test, got %q", expected.String)
+	}
+
+	// Without WithTestContent only paths are stored.
+	db2 := newTestDB(t)
+	if err := pkgsql.WritePackages(ctx, db2, []*pkgreader.Package{pkg}); err != nil {
+		t.Fatalf("writing packages: %v", err)
+	}
+	err = db2.QueryRowContext(ctx,
+		"SELECT event, expected FROM pipeline_tests WHERE name = 'test-json'").
+		Scan(&event, &expected)
+	if err != nil {
+		t.Fatalf("querying json pipeline test: %v", err)
+	}
+	if event.Valid || expected.Valid {
+		t.Errorf("expected NULL content without WithTestContent, got event=%v expected=%v", event, expected)
+	}
+}
+
+func TestWritePackageDependencies(t *testing.T) {
+	fsys := fstest.MapFS{
+		"manifest.yml": {Data: []byte(`
+name: test-package
+title: Test
+version: 1.0.0
+description: test
+format_version: 3.5.7
+type: integration
+owner:
+  github: elastic/integrations
+  type: elastic
+policy_templates:
+  - name: default
+    title: Default
+    description: Default policy.
+    inputs:
+      - type: logfile
+        title: Log
+        description: Collect logs.
+`)},
+		"changelog.yml": {Data: []byte(`
+- version: 1.0.0
+  changes:
+    - description: Initial release
+      type: enhancement
+      link: https://github.com/test/1
+`)},
+		"_dev/build/build.yml": {Data: []byte(`
+dependencies:
+  ecs:
+    reference: git@v8.11.0
+    import_mappings: true
+  base_pkg:
+    reference: git@v1.2.0
+`)},
+	}
+
+	pkg, err := pkgreader.Read(".", pkgreader.WithFS(fsys))
+	if err != nil {
+		t.Fatalf("reading package: %v", err)
+	}
+
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	if err := pkgsql.WritePackages(ctx, db, []*pkgreader.Package{pkg}); err != nil {
+		t.Fatalf("writing package: %v", err)
+	}
+
+	// Verify the ECS dependency row.
+	var reference, version string
+	var importMappings sql.NullBool
+	err = db.QueryRowContext(ctx,
+		"SELECT reference, version, import_mappings FROM package_dependencies WHERE name = 'ecs'").
+		Scan(&reference, &version, &importMappings)
+	if err != nil {
+		t.Fatalf("querying ecs dependency: %v", err)
+	}
+	if reference != "git@v8.11.0" {
+		t.Errorf("reference = %q, want git@v8.11.0", reference)
+	}
+	if version != "8.11.0" {
+		t.Errorf("version = %q, want 8.11.0", version)
+	}
+	if !importMappings.Valid || !importMappings.Bool {
+		t.Errorf("import_mappings = %v, want true", importMappings)
+	}
+
+	// Verify the imported package dependency row.
+	err = db.QueryRowContext(ctx,
+		"SELECT version FROM package_dependencies WHERE name = 'base_pkg'").
+		Scan(&version)
+	if err != nil {
+		t.Fatalf("querying base_pkg dependency: %v", err)
+	}
+	if version != "1.2.0" {
+		t.Errorf("version = %q, want 1.2.0", version)
+	}
+
+	// Find packages that depend on ECS 8.11.
+	var pkgName string
+	err = db.QueryRowContext(ctx, `
+		SELECT p.name
+		FROM package_dependencies d
+		JOIN packages p ON p.id = d.packages_id
+		WHERE d.name = 'ecs' AND d.version LIKE '8.11.%'`).Scan(&pkgName)
+	if err != nil {
+		t.Fatalf("querying packages by ecs version: %v", err)
+	}
+	if pkgName != "test-package" {
+		t.Errorf("package = %q, want test-package", pkgName)
+	}
+}
+
+func TestFieldTypeConflictsView(t *testing.T) {
+	fsys := fstest.MapFS{}
+	addPkg := func(name, userIDType string) {
+		fsys[name+"/manifest.yml"] = &fstest.MapFile{Data: []byte(`
+name: ` + name + `
+title: Test
+version: 1.0.0
+description: test
+format_version: 3.5.7
+type: integration
+owner:
+  github: elastic/integrations
+  type: elastic
+policy_templates:
+  - name: default
+    title: Default
+    description: Default policy.
+    inputs:
+      - type: logfile
+        title: Log
+        description: Collect logs.
+`)}
+		fsys[name+"/changelog.yml"] = &fstest.MapFile{Data: []byte(`
+- version: 1.0.0
+  changes:
+    - description: Initial release
+      type: enhancement
+      link: https://github.com/test/1
+`)}
+		fsys[name+"/data_stream/logs/manifest.yml"] = &fstest.MapFile{Data: []byte(`
+title: Logs
+type: logs
+streams:
+  - input: logfile
+    title: Logs
+    description: Collect logs.
+`)}
+		fsys[name+"/data_stream/logs/fields/fields.yml"] = &fstest.MapFile{Data: []byte(`
+- name: "@timestamp"
+  type: date
+- name: user
+  type: group
+  fields:
+    - name: id
+      type: ` + userIDType + `
+`)}
+	}
+	addPkg("pkg_a", "keyword")
+	addPkg("pkg_b", "long")
+
+	var pkgs []*pkgreader.Package
+	for _, name := range []string{"pkg_a", "pkg_b"} {
+		pkg, err := pkgreader.Read(name, pkgreader.WithFS(fsys))
+		if err != nil {
+			t.Fatalf("reading package %s: %v", name, err)
+		}
+		pkgs = append(pkgs, pkg)
+	}
+
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	if err := pkgsql.WritePackages(ctx, db, pkgs); err != nil {
+		t.Fatalf("writing packages: %v", err)
+	}
+
+	rows, err := db.QueryContext(ctx,
+		"SELECT name, type_count, package_count FROM field_type_conflicts")
+	if err != nil {
+		t.Fatalf("querying field_type_conflicts: %v", err)
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		var typeCount, packageCount int
+		if err := rows.Scan(&name, &typeCount, &packageCount); err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, name)
+		if typeCount != 2 {
+			t.Errorf("%s type_count = %d, want 2", name, typeCount)
+		}
+		if packageCount != 2 {
+			t.Errorf("%s package_count = %d, want 2", name, packageCount)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only user.id conflicts; @timestamp is a date in both packages.
+	if len(names) != 1 || names[0] != "user.id" {
+		t.Errorf("conflicting fields = %v, want [user.id]", names)
+	}
+}
+
+func TestPackageMetrics(t *testing.T) {
+	fsys := fstest.MapFS{
+		"manifest.yml": {Data: []byte(`
+name: metrics-test
+title: Metrics Test
+version: 1.0.0
+description: test
+format_version: 3.5.7
+type: integration
+owner:
+  github: elastic/integrations
+  type: elastic
+policy_templates:
+  - name: default
+    title: Default
+    description: Default policy.
+    inputs:
+      - type: logfile
+        title: Log
+        description: Collect logs.
+`)},
+		"changelog.yml": {Data: []byte(`
+- version: 1.0.0
+  changes:
+    - description: Initial release
+      type: enhancement
+      link: https://github.com/test/1
+`)},
+		"data_stream/access/manifest.yml": {Data: []byte(`
+title: Access
+type: logs
+`)},
+		"data_stream/access/fields/fields.yml": {Data: []byte(`
+- name: "@timestamp"
+  type: date
+- name: http
+  type: group
+  fields:
+    - name: method
+      type: keyword
+    - name: status
+      type: long
+`)},
+		"data_stream/access/elasticsearch/ingest_pipeline/default.yml": {Data: []byte(`
+description: Access pipeline.
+processors:
+  - set:
+      field: event.kind
+      value: event
+      on_failure:
+        - append:
+            field: error.message
+            value: failed
+  - rename:
+      field: message
+      target_field: event.original
+`)},
+		"data_stream/error/manifest.yml": {Data: []byte(`
+title: Error
+type: logs
+`)},
+		"data_stream/error/fields/fields.yml": {Data: []byte(`
+- name: "@timestamp"
+  type: date
+`)},
+		"kibana/dashboard/overview.json": {Data: []byte(`{"id": "overview", "type": "dashboard", "attributes": {"title": "Overview"}, "references": []}`)},
+	}
+
+	pkg, err := pkgreader.Read(".", pkgreader.WithFS(fsys))
+	if err != nil {
+		t.Fatalf("reading package: %v", err)
+	}
+
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	if err := pkgsql.WritePackages(ctx, db, []*pkgreader.Package{pkg}); err != nil {
+		t.Fatalf("writing packages: %v", err)
+	}
+
+	var dataStreams, fields, pipelines, processors, kibanaObjects int
+	err = db.QueryRowContext(ctx, `
+		SELECT data_stream_count, field_count, pipeline_count, processor_count, kibana_object_count
+		FROM package_metrics`).Scan(&dataStreams, &fields, &pipelines, &processors, &kibanaObjects)
+	if err != nil {
+		t.Fatalf("querying package_metrics: %v", err)
+	}
+
+	// Each metric must match the number of rows actually inserted.
+	for _, tc := range []struct {
+		name  string
+		got   int
+		query string
+	}{
+		{"data_stream_count", dataStreams, "SELECT count(*) FROM data_streams"},
+		{"field_count", fields, "SELECT count(*) FROM fields"},
+		{"pipeline_count", pipelines, "SELECT count(*) FROM ingest_pipelines"},
+		{"processor_count", processors, "SELECT count(*) FROM ingest_processors"},
+		{"kibana_object_count", kibanaObjects, "SELECT count(*) FROM kibana_saved_objects"},
+	} {
+		var want int
+		if err := db.QueryRowContext(ctx, tc.query).Scan(&want); err != nil {
+			t.Fatalf("%s: %v", tc.query, err)
+		}
+		if tc.got != want {
+			t.Errorf("%s = %d, want %d", tc.name, tc.got, want)
+		}
+	}
+
+	if dataStreams != 2 || fields != 4 || pipelines != 1 || processors != 3 || kibanaObjects != 1 {
+		t.Errorf("metrics = (%d, %d, %d, %d, %d), want (2, 4, 1, 3, 1)",
+			dataStreams, fields, pipelines, processors, kibanaObjects)
+	}
+}
+
+func TestDataStreamDataset(t *testing.T) {
+	fsys := fstest.MapFS{
+		"manifest.yml": {Data: []byte(`
+name: nginx
+title: Nginx
+version: 1.0.0
+description: test
+format_version: 3.5.7
+type: integration
+owner:
+  github: elastic/integrations
+  type: elastic
+`)},
+		"changelog.yml": {Data: []byte(`
+- version: 1.0.0
+  changes:
+    - description: Initial release
+      type: enhancement
+      link: https://github.com/test/1
+`)},
+		"data_stream/access/manifest.yml": {Data: []byte(`
+title: Access
+type: logs
+`)},
+		"data_stream/error/manifest.yml": {Data: []byte(`
+title: Error
+type: logs
+dataset: nginx_custom.error
+`)},
+	}
+
+	pkg, err := pkgreader.Read(".", pkgreader.WithFS(fsys))
+	if err != nil {
+		t.Fatalf("reading package: %v", err)
+	}
+
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	if err := pkgsql.WritePackages(ctx, db, []*pkgreader.Package{pkg}); err != nil {
+		t.Fatalf("writing packages: %v", err)
+	}
+
+	tests := []struct {
+		dirName          string
+		dataset          sql.NullString
+		effectiveDataset string
+	}{
+		{"access", sql.NullString{}, "nginx.access"},
+		{"error", sql.NullString{String: "nginx_custom.error", Valid: true}, "nginx_custom.error"},
+	}
+	for _, tc := range tests {
+		var dataset sql.NullString
+		var effectiveDataset string
+		err := db.QueryRowContext(ctx,
+			"SELECT dataset, effective_dataset FROM data_streams WHERE dir_name = ?", tc.dirName).Scan(&dataset, &effectiveDataset)
+		if err != nil {
+			t.Fatalf("querying %s dataset: %v", tc.dirName, err)
+		}
+		if dataset != tc.dataset {
+			t.Errorf("%s dataset = %v, want %v", tc.dirName, dataset, tc.dataset)
+		}
+		if effectiveDataset != tc.effectiveDataset {
+			t.Errorf("%s effective_dataset = %q, want %q", tc.dirName, effectiveDataset, tc.effectiveDataset)
+		}
+	}
+}
+
+func TestDataStreamCodeOwners(t *testing.T) {
+	codeowners := filepath.Join(t.TempDir(), "CODEOWNERS")
+	if err := os.WriteFile(codeowners, []byte(`
+* @elastic/integrations
+/packages/test-package @elastic/obs-team
+/packages/test-package/data_stream/access @elastic/security-team @elastic/obs-team
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	pkg := readTestPackage(t, fstest.MapFS{
+		"data_stream/access/manifest.yml": {Data: []byte("title: Access\ntype: logs\n")},
+		"data_stream/error/manifest.yml":  {Data: []byte("title: Error\ntype: logs\n")},
+	},
+		pkgreader.WithCodeowners(codeowners),
+		pkgreader.WithRepoRelativePath("packages/test-package"),
+	)
+	db := newTestDB(t)
+	ctx := context.Background()
+	if err := pkgsql.WritePackages(ctx, db, []*pkgreader.Package{pkg}); err != nil {
+		t.Fatalf("writing packages: %v", err)
+	}
+
+	tests := []struct {
+		dirName string
+		owner   string
+		owners  string
+	}{
+		{"access", "elastic/security-team", `["elastic/security-team","elastic/obs-team"]`},
+		{"error", "elastic/obs-team", `["elastic/obs-team"]`},
+	}
+	for _, tc := range tests {
+		var owner, owners sql.NullString
+		err := db.QueryRowContext(ctx,
+			"SELECT github_code_owner, github_code_owners FROM data_streams WHERE dir_name = ?", tc.dirName).Scan(&owner, &owners)
+		if err != nil {
+			t.Fatalf("querying %s code owners: %v", tc.dirName, err)
+		}
+		if owner.String != tc.owner {
+			t.Errorf("%s github_code_owner = %q, want %q", tc.dirName, owner.String, tc.owner)
+		}
+		if owners.String != tc.owners {
+			t.Errorf("%s github_code_owners = %q, want %q", tc.dirName, owners.String, tc.owners)
+		}
+
+		// Each element of the JSON column is queryable with json_each.
+		var n int
+		err = db.QueryRowContext(ctx, `
+			SELECT COUNT(*) FROM data_streams ds, json_each(ds.github_code_owners) o
+			WHERE ds.dir_name = ? AND o.value = ?`, tc.dirName, tc.owner).Scan(&n)
+		if err != nil {
+			t.Fatalf("querying %s code owners with json_each: %v", tc.dirName, err)
+		}
+		if n != 1 {
+			t.Errorf("%s: json_each found %d rows for %q, want 1", tc.dirName, n, tc.owner)
+		}
+	}
+}
+
+func TestValidationExcludedChecks(t *testing.T) {
+	fsys := fstest.MapFS{
+		"manifest.yml": {Data: []byte(`
+name: validation-test
+title: Validation Test
+version: 1.0.0
+description: test
+format_version: 3.5.7
+type: integration
+owner:
+  github: elastic/integrations
+  type: elastic
+`)},
+		"changelog.yml": {Data: []byte(`
+- version: 1.0.0
+  changes:
+    - description: Initial release
+      type: enhancement
+      link: https://github.com/test/1
+`)},
+		"validation.yml": {Data: []byte(`
+errors:
+  exclude_checks:
+    - SVR00002
+    - JSE00001
+`)},
+	}
+
+	pkg, err := pkgreader.Read(".", pkgreader.WithFS(fsys))
+	if err != nil {
+		t.Fatalf("reading package: %v", err)
+	}
+
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	if err := pkgsql.WritePackages(ctx, db, []*pkgreader.Package{pkg}); err != nil {
+		t.Fatalf("writing packages: %v", err)
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT vec.name
+		FROM validation_excluded_checks vec
+		JOIN packages p ON p.id = vec.packages_id
+		WHERE p.name = 'validation-test'
+		ORDER BY vec.id`)
+	if err != nil {
+		t.Fatalf("querying validation_excluded_checks: %v", err)
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, name)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 2 || names[0] != "SVR00002" || names[1] != "JSE00001" {
+		t.Errorf("excluded checks = %v, want [SVR00002 JSE00001]", names)
+	}
+}
+
+func TestWithVarDedup(t *testing.T) {
+	streamManifest := func(title string) []byte {
+		return []byte(`
+title: ` + title + `
+type: logs
+streams:
+  - input: logfile
+    title: ` + title + `
+    description: Collect logs.
+    vars:
+      - name: paths
+        type: text
+        title: Paths
+        multi: true
+        required: true
+        default:
+          - /var/log/*.log
+      - name: tags
+        type: text
+        title: Tags for ` + title + `
+        multi: true
+`)
+	}
+	fsys := fstest.MapFS{
+		"manifest.yml": {Data: []byte(`
+name: dedup-test
+title: Dedup Test
+version: 1.0.0
+description: test
+format_version: 3.5.7
+type: integration
+owner:
+  github: elastic/integrations
+  type: elastic
+`)},
+		"changelog.yml": {Data: []byte(`
+- version: 1.0.0
+  changes:
+    - description: Initial release
+      type: enhancement
+      link: https://github.com/test/1
+`)},
+		"data_stream/access/manifest.yml": {Data: streamManifest("Access")},
+		"data_stream/error/manifest.yml":  {Data: streamManifest("Error")},
+	}
+
+	pkg, err := pkgreader.Read(".", pkgreader.WithFS(fsys))
+	if err != nil {
+		t.Fatalf("reading package: %v", err)
+	}
+
+	count := func(t *testing.T, db *sql.DB, query string) int {
+		t.Helper()
+		var n int
+		if err := db.QueryRowContext(context.Background(), query).Scan(&n); err != nil {
+			t.Fatalf("querying %q: %v", query, err)
+		}
+		return n
+	}
+
+	for _, tc := range []struct {
+		name     string
+		opts     []pkgsql.Option
+		wantVars int
+	}{
+		{name: "default", wantVars: 4},
+		// The identical paths var is shared; the tags vars differ by title.
+		{name: "dedup", opts: []pkgsql.Option{pkgsql.WithVarDedup()}, wantVars: 3},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newTestDB(t)
+			if err := pkgsql.WritePackages(context.Background(), db, []*pkgreader.Package{pkg}, tc.opts...); err != nil {
+				t.Fatalf("writing packages: %v", err)
+			}
+
+			if got := count(t, db, "SELECT count(*) FROM vars"); got != tc.wantVars {
+				t.Errorf("got %d vars rows, want %d", got, tc.wantVars)
+			}
+			if got := count(t, db, "SELECT count(*) FROM vars WHERE name = 'paths'"); tc.wantVars == 3 && got != 1 {
+				t.Errorf("got %d paths vars rows, want 1", got)
+			}
+			// Every stream is still linked to both of its vars.
+			if got := count(t, db, "SELECT count(*) FROM stream_vars"); got != 4 {
+				t.Errorf("got %d stream_vars rows, want 4", got)
+			}
+		})
+	}
+}
+
+func TestWithTablePrefix(t *testing.T) {
+	fsys := fstest.MapFS{
+		"manifest.yml": {Data: []byte(`
+name: prefix_test
+title: Prefix Test
+version: 1.0.0
+description: test
+format_version: 3.5.7
+type: integration
+categories:
+  - security
+owner:
+  github: elastic/integrations
+  type: elastic
+policy_templates:
+  - name: logs
+    title: Logs
+    description: Collect logs.
+    data_streams:
+      - events
+    inputs:
+      - type: logfile
+        title: Log files
+        description: Collect log files.
+`)},
+		"changelog.yml": {Data: []byte(`
+- version: 1.0.0
+  changes:
+    - description: Initial release
+      type: enhancement
+      link: https://github.com/test/1
+`)},
+		"data_stream/events/manifest.yml": {Data: []byte(`
+title: Events
+type: logs
+streams:
+  - input: logfile
+    title: Log files
+    description: Collect log files.
+    vars:
+      - name: paths
+        type: text
+        title: Paths
+        multi: true
+`)},
+		"data_stream/events/fields/fields.yml": {Data: []byte(`
+- name: event.code
+  type: keyword
+  description: Event code.
+`)},
+		"docs/README.md": {Data: []byte("# Prefix Test\n\nCollects authentication events.\n")},
+	}
+	pkg, err := pkgreader.Read(".", pkgreader.WithFS(fsys))
+	if err != nil {
+		t.Fatalf("reading package: %v", err)
+	}
+
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	// A table owned by the embedding application with a clashing name.
+	if _, err := db.ExecContext(ctx, "CREATE TABLE packages (id INTEGER PRIMARY KEY, owner TEXT)"); err != nil {
+		t.Fatal(err)
+	}
+
+	err = pkgsql.WritePackages(ctx, db, []*pkgreader.Package{pkg},
+		pkgsql.WithTablePrefix("pkg_"),
+		pkgsql.WithDocContent(func(_, docPath string) ([]byte, error) { return fs.ReadFile(fsys, docPath) }))
+	if err != nil {
+		t.Fatalf("writing packages: %v", err)
+	}
+
+	// Every schema object other than the application's table is prefixed.
+	rows, err := db.QueryContext(ctx,
+		"SELECT name FROM sqlite_master WHERE name NOT LIKE 'sqlite_%' AND name NOT LIKE 'pkg\\_%' ESCAPE '\\'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var unprefixed []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			t.Fatal(err)
+		}
+		unprefixed = append(unprefixed, name)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(unprefixed, []string{"packages"}) {
+		t.Errorf("unprefixed schema objects = %v, want [packages]", unprefixed)
+	}
+
+	for query, want := range map[string]int{
+		"SELECT count(*) FROM packages":                                               0,
+		"SELECT count(*) FROM pkg_packages WHERE name = 'prefix_test'":                1,
+		"SELECT count(*) FROM pkg_data_streams":                                       1,
+		"SELECT count(*) FROM pkg_data_stream_fields":                                 1,
+		"SELECT count(*) FROM pkg_stream_vars":                                        1,
+		"SELECT count(*) FROM pkg_package_metrics WHERE data_stream_count = 1":        1,
+		"SELECT count(*) FROM pkg_input_stream_links":                                 1,
+		"SELECT count(*) FROM pkg_package_catalog WHERE categories = 'security'":      1,
+		"SELECT count(*) FROM pkg_docs_fts WHERE pkg_docs_fts MATCH 'authentication'": 1,
+	} {
+		var got int
+		if err := db.QueryRowContext(ctx, query).Scan(&got); err != nil {
+			t.Fatalf("%s: %v", query, err)
+		}
+		if got != want {
+			t.Errorf("%s = %d, want %d", query, got, want)
+		}
+	}
+
+	violations, err := pkgsql.CheckForeignKeys(ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(violations) != 0 {
+		t.Errorf("foreign key violations: %v", violations)
+	}
+
+	// The read helpers honor the prefix too. Without it, Diff would read the
+	// application's packages table, which has no name column.
+	diff, err := pkgsql.Diff(ctx, db, db, pkgsql.WithTablePrefix("pkg_"))
+	if err != nil {
+		t.Fatalf("diffing prefixed database: %v", err)
+	}
+	if len(diff.Added)+len(diff.Removed)+len(diff.Changed) != 0 {
+		t.Errorf("Diff of a database with itself = %+v, want no changes", diff)
+	}
+	schemas := pkgsql.TableJSONSchemas(pkgsql.WithTablePrefix("pkg_"))
+	if _, ok := schemas["pkg_packages"]; !ok {
+		t.Error("TableJSONSchemas has no pkg_packages schema")
+	}
+	if _, ok := schemas["packages"]; ok {
+		t.Error("TableJSONSchemas has an unprefixed packages schema")
+	}
+	if q := pkgsql.QuerySQL(pkgsql.WithTablePrefix("pkg_")); !strings.Contains(q, "INSERT INTO pkg_packages") {
+		t.Error("QuerySQL does not insert into pkg_packages")
+	}
+}
+
+func TestWithTablePrefixInvalid(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	opt := pkgsql.WithTablePrefix("pkg; DROP TABLE users; --")
+
+	if err := pkgsql.WritePackages(ctx, db, nil, opt); err == nil {
+		t.Error("WritePackages accepted an invalid prefix")
+	}
+	if err := pkgsql.RebuildFTS(ctx, db, opt); err == nil {
+		t.Error("RebuildFTS accepted an invalid prefix")
+	}
+	if _, err := pkgsql.Diff(ctx, db, db, opt); err == nil {
+		t.Error("Diff accepted an invalid prefix")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("TableSchemas did not panic on an invalid prefix")
+		}
+	}()
+	pkgsql.TableSchemas(opt)
+}
+
+func TestWithOnConflict(t *testing.T) {
+	// The api_key var is declared twice on the package. With WithVarDedup
+	// both declarations share one vars row, which the package links once
+	// with or without a conflict strategy.
+	fsys := fstest.MapFS{
+		"manifest.yml": {Data: []byte(`
+name: conflict-test
+title: Conflict Test
+version: 1.0.0
+description: test
+format_version: 3.5.7
+type: integration
+owner:
+  github: elastic/integrations
+  type: elastic
+vars:
+  - name: api_key
+    type: password
+    title: API Key
+  - name: api_key
+    type: password
+    title: API Key
+`)},
+		"changelog.yml": {Data: []byte(`
+- version: 1.0.0
+  changes:
+    - description: Initial release
+      type: enhancement
+      link: https://github.com/test/1
+`)},
+	}
+
+	pkg, err := pkgreader.Read(".", pkgreader.WithFS(fsys))
+	if err != nil {
+		t.Fatalf("reading package: %v", err)
+	}
+
+	for _, strategy := range []pkgsql.OnConflict{"", pkgsql.OnConflictIgnore, pkgsql.OnConflictReplace} {
+		name := string(strategy)
+		if name == "" {
+			name = "default"
+		}
+		t.Run(name, func(t *testing.T) {
+			db := newTestDB(t)
+			ctx := context.Background()
+			opts := []pkgsql.Option{pkgsql.WithVarDedup()}
+			if strategy != "" {
+				opts = append(opts, pkgsql.WithOnConflict(strategy))
+			}
+			if err := pkgsql.WritePackages(ctx, db, []*pkgreader.Package{pkg}, opts...); err != nil {
+				t.Fatalf("writing packages: %v", err)
+			}
+
+			var links, vars int
+			if err := db.QueryRowContext(ctx, "SELECT count(*) FROM package_vars").Scan(&links); err != nil {
+				t.Fatal(err)
+			}
+			if err := db.QueryRowContext(ctx, "SELECT count(*) FROM vars").Scan(&vars); err != nil {
+				t.Fatal(err)
+			}
+			if links != 1 || vars != 1 {
+				t.Errorf("got %d package_vars and %d vars rows, want 1 and 1", links, vars)
+			}
+		})
+	}
+}
+
+func TestConstraintColumns(t *testing.T) {
+	fsys := fstest.MapFS{
+		"data_stream/metrics/manifest.yml": {Data: []byte(`
+title: Metrics
+type: metrics
+streams:
+  - input: http/metrics
+    title: Metrics
+    description: Collect metrics.
+    vars:
+      - name: period
+        type: duration
+        title: Period
+        default: 30s
+        min_duration: 10s
+        max_duration: 1h
+`)},
+		"data_stream/metrics/fields/fields.yml": {Data: []byte(`
+- name: cpu.pct
+  type: scaled_float
+  scaling_factor: 1000
+- name: host.label
+  type: keyword
+  ignore_above: 256
+`)},
+	}
+
+	db := writeTestPackage(t, fsys)
+	ctx := context.Background()
+
+	var minDuration, maxDuration string
+	err := db.QueryRowContext(ctx, `SELECT min_duration, max_duration FROM vars WHERE name = 'period'`).Scan(&minDuration, &maxDuration)
+	if err != nil {
+		t.Fatalf("querying vars: %v", err)
+	}
+	if minDuration != "10s" || maxDuration != "1h" {
+		t.Errorf("duration bounds = [%s, %s], want [10s, 1h]", minDuration, maxDuration)
+	}
+
+	var scalingFactor int
+	err = db.QueryRowContext(ctx, `SELECT scaling_factor FROM fields WHERE name = 'cpu.pct'`).Scan(&scalingFactor)
+	if err != nil {
+		t.Fatalf("querying fields: %v", err)
+	}
+	if scalingFactor != 1000 {
+		t.Errorf("scaling_factor = %d, want 1000", scalingFactor)
+	}
+
+	var ignoreAbove int
+	err = db.QueryRowContext(ctx, `SELECT ignore_above FROM fields WHERE name = 'host.label'`).Scan(&ignoreAbove)
+	if err != nil {
+		t.Fatalf("querying fields: %v", err)
+	}
+	if ignoreAbove != 256 {
+		t.Errorf("ignore_above = %d, want 256", ignoreAbove)
+	}
+}
+
+func TestSampleEventFields(t *testing.T) {
+	fsys := fstest.MapFS{
+		"manifest.yml": {Data: []byte(`
+name: sample-test
+title: Sample Test
+version: 1.0.0
+description: test
+format_version: 3.5.7
+type: integration
+owner:
+  github: elastic/integrations
+  type: elastic
+`)},
+		"changelog.yml": {Data: []byte(`
+- version: 1.0.0
+  changes:
+    - description: Initial release
+      type: enhancement
+      link: https://github.com/test/1
+`)},
+		"data_stream/logs/manifest.yml": {Data: []byte(`
+title: Logs
+type: logs
+`)},
+		"data_stream/logs/fields/fields.yml": {Data: []byte(`
+- name: message
+  type: match_only_text
+- name: event.dataset
+  type: constant_keyword
+- name: log.level
+  type: keyword
+`)},
+		"data_stream/logs/sample_event.json": {Data: []byte(`{
+  "message": "hello",
+  "event": {"dataset": "sample_test.logs"},
+  "tags": ["a", "b"]
+}`)},
+	}
+
+	pkg, err := pkgreader.Read(".", pkgreader.WithFS(fsys))
+	if err != nil {
+		t.Fatalf("reading package: %v", err)
+	}
+
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	if err := pkgsql.WritePackages(ctx, db, []*pkgreader.Package{pkg}); err != nil {
+		t.Fatalf("writing packages: %v", err)
+	}
+
+	queryStrings := func(query string) []string {
+		t.Helper()
+		rows, err := db.QueryContext(ctx, query)
+		if err != nil {
+			t.Fatalf("querying %q: %v", query, err)
+		}
+		defer rows.Close()
+		var out []string
+		for rows.Next() {
+			var s string
+			if err := rows.Scan(&s); err != nil {
+				t.Fatal(err)
+			}
+			out = append(out, s)
+		}
+		if err := rows.Err(); err != nil {
+			t.Fatal(err)
+		}
+		return out
+	}
+
+	paths := queryStrings(`
+		SELECT sef.path
+		FROM sample_event_fields sef
+		JOIN sample_events se ON se.id = sef.sample_events_id
+		WHERE se.name IS NULL
+		ORDER BY sef.path`)
+	if want := []string{"event.dataset", "message", "tags"}; !slices.Equal(paths, want) {
+		t.Errorf("sample event paths = %v, want %v", paths, want)
+	}
+
+	// Documented fields missing from the sample event.
+	missing := queryStrings(`
+		SELECT f.name
+		FROM fields f
+		JOIN data_stream_fields dsf ON dsf.field_id = f.id
+		WHERE NOT EXISTS (
+			SELECT 1 FROM sample_event_fields sef
+			JOIN sample_events se ON se.id = sef.sample_events_id
+			WHERE se.data_streams_id = dsf.data_stream_id AND sef.path = f.name)
+		ORDER BY f.name`)
+	if want := []string{"log.level"}; !slices.Equal(missing, want) {
+		t.Errorf("fields missing from sample event = %v, want %v", missing, want)
+	}
+}
+
+func TestSampleEventFieldsMalformed(t *testing.T) {
+	fsys := fstest.MapFS{
+		"data_stream/logs/manifest.yml":          {Data: []byte("title: Logs\ntype: logs\n")},
+		"data_stream/logs/sample_event.json":     {Data: []byte(`{"message": "truncated`)},
+		"data_stream/logs/sample_event_one.json": {Data: []byte(`{"message": "ok"}`)},
+	}
+
+	db := writeTestPackage(t, fsys)
+	ctx := context.Background()
+
+	// The malformed event is stored as is, without field paths.
+	var event string
+	var paths int
+	err := db.QueryRowContext(ctx, `
+		SELECT se.event, (SELECT count(*) FROM sample_event_fields WHERE sample_events_id = se.id)
+		FROM sample_events se WHERE se.name IS NULL`).Scan(&event, &paths)
+	if err != nil {
+		t.Fatalf("querying malformed sample event: %v", err)
+	}
+	if event != `{"message": "truncated` || paths != 0 {
+		t.Errorf("malformed sample event = %q with %d paths, want original bytes with 0 paths", event, paths)
+	}
+
+	err = db.QueryRowContext(ctx, `
+		SELECT count(*) FROM sample_event_fields sef
+		JOIN sample_events se ON se.id = sef.sample_events_id
+		WHERE se.name = 'one'`).Scan(&paths)
+	if err != nil {
+		t.Fatalf("querying named sample event paths: %v", err)
+	}
+	if paths != 1 {
+		t.Errorf("named sample event has %d paths, want 1", paths)
+	}
+}
+
+func TestChangelogEntryOrdinals(t *testing.T) {
+	fsys := fstest.MapFS{
+		"manifest.yml": {Data: []byte(`
+name: changelog-test
+title: Changelog Test
+version: 1.1.0
+description: test
+format_version: 3.5.7
+type: integration
+owner:
+  github: elastic/integrations
+  type: elastic
+`)},
+		"changelog.yml": {Data: []byte(`
+- version: 1.1.0
+  changes:
+    - description: Add metrics.
+      type: enhancement
+      link: https://github.com/test/3
+    - description: Fix parsing.
+      type: bugfix
+      link: https://github.com/test/4
+    - description: Drop old field.
+      type: breaking-change
+      link: https://github.com/test/5
+- version: 1.0.0
+  changes:
+    - description: Initial release
+      type: enhancement
+      link: https://github.com/test/1
+`)},
+	}
+
+	pkg, err := pkgreader.Read(".", pkgreader.WithFS(fsys))
+	if err != nil {
+		t.Fatalf("reading package: %v", err)
+	}
+
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	if err := pkgsql.WritePackages(ctx, db, []*pkgreader.Package{pkg}); err != nil {
+		t.Fatalf("writing packages: %v", err)
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT version, ordinal, type
+		FROM changelog_entries
+		ORDER BY id`)
+	if err != nil {
+		t.Fatalf("querying changelog_entries: %v", err)
+	}
+	defer rows.Close()
+
+	var got []string
+	for rows.Next() {
+		var version, typ string
+		var ordinal int
+		if err := rows.Scan(&version, &ordinal, &typ); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, fmt.Sprintf("%s/%d/%s", version, ordinal, typ))
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"1.1.0/0/enhancement",
+		"1.1.0/1/bugfix",
+		"1.1.0/2/breaking-change",
+		"1.0.0/0/enhancement",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("changelog entries = %v, want %v", got, want)
+	}
+}
+
+func TestDeprecationSummaryView(t *testing.T) {
+	fsys := fstest.MapFS{
+		"manifest.yml": {Data: []byte(`
+name: deprecation-test
+title: Deprecation Test
+version: 2.0.0
+description: test
+format_version: 3.5.7
+type: integration
+owner:
+  github: elastic/integrations
+  type: elastic
+`)},
+		"changelog.yml": {Data: []byte(`
+- version: 2.0.0
+  changes:
+    - description: Deprecate ssl var.
+      type: enhancement
+      link: https://github.com/test/1
+`)},
+		"data_stream/logs/manifest.yml": {Data: []byte(`
+title: Logs
+type: logs
+streams:
+  - input: logfile
+    title: Logs
+    description: Collect logs.
+    vars:
+      - name: ssl
+        type: yaml
+        title: SSL
+        deprecated:
+          description: Use tls instead.
+          since: 2.0.0
+          replaced_by:
+            variable: tls
+      - name: tls
+        type: yaml
+        title: TLS
+`)},
+	}
+
+	pkg, err := pkgreader.Read(".", pkgreader.WithFS(fsys))
+	if err != nil {
+		t.Fatalf("reading package: %v", err)
+	}
+
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	if err := pkgsql.WritePackages(ctx, db, []*pkgreader.Package{pkg}); err != nil {
+		t.Fatalf("writing packages: %v", err)
+	}
+
+	var entityType, entityName, packageName, since, description, replacedByType, replacedBy string
+	err = db.QueryRowContext(ctx, `
+		SELECT entity_type, entity_name, package_name, since, description, replaced_by_type, replaced_by
+		FROM deprecation_summary`).Scan(&entityType, &entityName, &packageName, &since, &description, &replacedByType, &replacedBy)
+	if err != nil {
+		t.Fatalf("querying deprecation_summary: %v", err)
+	}
+
+	got := []string{entityType, entityName, packageName, since, description, replacedByType, replacedBy}
+	want := []string{"var", "ssl", "deprecation-test", "2.0.0", "Use tls instead.", "var", "tls"}
+	if !slices.Equal(got, want) {
+		t.Errorf("deprecation_summary row = %q, want %q", got, want)
+	}
+}
+
+func TestDeprecatedEntityCount(t *testing.T) {
+	fsys := fstest.MapFS{
+		"deprecated/manifest.yml": {Data: []byte(`
+name: deprecated
+title: Deprecated
+version: 2.0.0
+description: test
+format_version: 3.5.7
+type: integration
+owner:
+  github: elastic/integrations
+  type: elastic
+`)},
+		"deprecated/changelog.yml": {Data: []byte(`
+- version: 2.0.0
+  changes:
+    - description: Deprecate the old data stream.
+      type: enhancement
+      link: https://github.com/test/1
+`)},
+		"deprecated/data_stream/old/manifest.yml": {Data: []byte(`
+title: Old
+type: logs
+deprecated:
+  description: Use the new data stream.
+  since: 2.0.0
+  replaced_by:
+    data_stream: new
+streams:
+  - input: logfile
+    title: Old
+    description: Collect logs.
+`)},
+		"deprecated/data_stream/new/manifest.yml": {Data: []byte(`
+title: New
+type: logs
+streams:
+  - input: logfile
+    title: New
+    description: Collect logs.
+    vars:
+      - name: ssl
+        type: yaml
+        title: SSL
+        deprecated:
+          description: Use tls instead.
+          since: 2.0.0
+      - name: tls
+        type: yaml
+        title: TLS
+`)},
+		"current/manifest.yml": {Data: []byte(`
+name: current
+title: Current
+version: 1.0.0
+description: test
+format_version: 3.5.7
+type: integration
+owner:
+  github: elastic/integrations
+  type: elastic
+`)},
+		"current/changelog.yml": {Data: []byte(`
+- version: 1.0.0
+  changes:
+    - description: Initial release
+      type: enhancement
+      link: https://github.com/test/1
+`)},
+	}
+
+	var pkgs []*pkgreader.Package
+	for _, dir := range []string{"deprecated", "current"} {
+		pkg, err := pkgreader.Read(dir, pkgreader.WithFS(fsys))
+		if err != nil {
+			t.Fatalf("reading package %s: %v", dir, err)
+		}
+		pkgs = append(pkgs, pkg)
+	}
+
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	if err := pkgsql.WritePackages(ctx, db, pkgs); err != nil {
+		t.Fatalf("writing packages: %v", err)
+	}
+
+	for name, want := range map[string]int64{"deprecated": 2, "current": 0} {
+		var got int64
+		err := db.QueryRowContext(ctx, `
+			SELECT deprecated_entity_count FROM packages WHERE name = ?`, name).Scan(&got)
+		if err != nil {
+			t.Fatalf("querying packages for %s: %v", name, err)
+		}
+		if got != want {
+			t.Errorf("%s deprecated_entity_count = %d, want %d", name, got, want)
+		}
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT ds.entity_type || ':' || ds.entity_name
+		FROM deprecation_summary ds
+		JOIN packages p ON p.id = ds.packages_id
+		WHERE p.name = 'deprecated'
+		ORDER BY 1`)
+	if err != nil {
+		t.Fatalf("querying deprecation_summary: %v", err)
+	}
+	defer rows.Close()
+
+	var entities []string
+	for rows.Next() {
+		var entity string
+		if err := rows.Scan(&entity); err != nil {
+			t.Fatal(err)
+		}
+		entities = append(entities, entity)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"data_stream:old", "var:ssl"}; !slices.Equal(entities, want) {
+		t.Errorf("deprecated entities = %v, want %v", entities, want)
+	}
+}
+
+func TestInputStreamLinksView(t *testing.T) {
+	fsys := fstest.MapFS{
+		"manifest.yml": {Data: []byte(`
+name: links-test
+title: Links Test
+version: 1.0.0
+description: test
+format_version: 3.5.7
+type: integration
+owner:
+  github: elastic/integrations
+  type: elastic
+policy_templates:
+  - name: logs
+    title: Logs
+    description: Collect logs.
+    data_streams:
+      - access
+      - metrics
+    inputs:
+      - type: logfile
+        title: Collect logs
+        description: Collect log files.
+      - type: httpjson
+        title: Collect via API
+        description: Collect from the API.
+`)},
+		"changelog.yml": {Data: []byte(`
+- version: 1.0.0
+  changes:
+    - description: Initial release
+      type: enhancement
+      link: https://github.com/test/1
+`)},
+		"data_stream/access/manifest.yml": {Data: []byte(`
+title: Access
+type: logs
+streams:
+  - input: logfile
+    title: Access logs
+    description: Collect access logs.
+`)},
+		"data_stream/metrics/manifest.yml": {Data: []byte(`
+title: Metrics
+type: metrics
+streams:
+  - input: http/metrics
+    title: Metrics
+    description: Collect metrics.
+`)},
+		// Uses logfile too, but is not listed by the policy template.
+		"data_stream/audit/manifest.yml": {Data: []byte(`
+title: Audit
+type: logs
+streams:
+  - input: logfile
+    title: Audit logs
+    description: Collect audit logs.
+`)},
+	}
+
+	pkg, err := pkgreader.Read(".", pkgreader.WithFS(fsys))
+	if err != nil {
+		t.Fatalf("reading package: %v", err)
+	}
+
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	if err := pkgsql.WritePackages(ctx, db, []*pkgreader.Package{pkg}); err != nil {
+		t.Fatalf("writing packages: %v", err)
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT package_name, policy_template_name, input_type, data_stream_dir_name, dataset
+		FROM input_stream_links
+		ORDER BY input_type, data_stream_dir_name`)
+	if err != nil {
+		t.Fatalf("querying input_stream_links: %v", err)
+	}
+	defer rows.Close()
+
+	var got []string
+	for rows.Next() {
+		var pkgName, ptName, inputType, dirName, dataset string
+		if err := rows.Scan(&pkgName, &ptName, &inputType, &dirName, &dataset); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, strings.Join([]string{pkgName, ptName, inputType, dirName, dataset}, " "))
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"links-test logs logfile access links-test.access"}
+	if !slices.Equal(got, want) {
+		t.Errorf("input_stream_links = %q, want %q", got, want)
+	}
+}
+
+func TestVarsFTS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"manifest.yml": {Data: []byte(`
+name: proxy-test
+title: Proxy Test
+version: 1.0.0
+description: test
+format_version: 3.5.7
+type: integration
+owner:
+  github: elastic/integrations
+  type: elastic
+`)},
+		"changelog.yml": {Data: []byte(`
+- version: 1.0.0
+  changes:
+    - description: Initial release
+      type: enhancement
+      link: https://github.com/test/1
+`)},
+		"data_stream/events/manifest.yml": {Data: []byte(`
+title: Events
+type: logs
+streams:
+  - input: httpjson
+    title: Events
+    description: Collect events.
+    vars:
+      - name: proxy_url
+        type: text
+        title: Proxy
+        description: URL of the HTTP proxy used to reach the API.
+      - name: interval
+        type: text
+        title: Interval
+        description: How often to poll.
+`)},
+	}
+
+	pkg, err := pkgreader.Read(".", pkgreader.WithFS(fsys))
+	if err != nil {
+		t.Fatalf("reading package: %v", err)
+	}
+
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	if err := pkgsql.WritePackages(ctx, db, []*pkgreader.Package{pkg}); err != nil {
+		t.Fatalf("writing packages: %v", err)
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT p.name, v.name
+		FROM vars_fts
+		JOIN vars v ON v.id = vars_fts.rowid
+		JOIN stream_vars sv ON sv.var_id = v.id
+		JOIN streams s ON s.id = sv.stream_id
+		JOIN data_streams ds ON ds.id = s.data_streams_id
+		JOIN packages p ON p.id = ds.packages_id
+		WHERE vars_fts MATCH 'proxy URL'`)
+	if err != nil {
+		t.Fatalf("querying vars_fts: %v", err)
+	}
+	defer rows.Close()
+
+	var got []string
+	for rows.Next() {
+		var pkgName, varName string
+		if err := rows.Scan(&pkgName, &varName); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, pkgName+"/"+varName)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"proxy-test/proxy_url"}; !slices.Equal(got, want) {
+		t.Errorf("vars_fts matches = %v, want %v", got, want)
+	}
+}
+
+func TestPolicyTemplatesFTS(t *testing.T) {
+	db := writeTestPackage(t, fstest.MapFS{
+		"manifest.yml": {Data: []byte(testManifest + `
+policy_templates:
+  - name: audit
+    title: Audit
+    description: Collect audit events.
+    inputs:
+      - type: httpjson
+        title: Audit logs via API
+        description: Collect audit logs from the Okta System Log API.
+      - type: logfile
+        title: Audit logs from file
+        description: Collect audit logs from exported files.
+  - name: metrics
+    title: Metrics
+    description: Collect usage metrics.
+`)},
+	})
+	ctx := context.Background()
+
+	for query, want := range map[string][]string{
+		// Matches an input description only.
+		"Okta":                 {"test-package/audit"},
+		"inputs:exported":      {"test-package/audit"},
+		"description:usage":    {"test-package/metrics"},
+		"collect":              {"test-package/audit", "test-package/metrics"},
+		"description:exported": nil,
+	} {
+		rows, err := db.QueryContext(ctx, `
+			SELECT p.name, pt.name
+			FROM policy_templates_fts
+			JOIN policy_templates pt ON pt.id = policy_templates_fts.rowid
+			JOIN packages p ON p.id = pt.packages_id
+			WHERE policy_templates_fts MATCH ?
+			ORDER BY pt.name`, query)
+		if err != nil {
+			t.Fatalf("querying policy_templates_fts for %q: %v", query, err)
+		}
+
+		var got []string
+		for rows.Next() {
+			var pkgName, ptName string
+			if err := rows.Scan(&pkgName, &ptName); err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, pkgName+"/"+ptName)
+		}
+		if err := rows.Err(); err != nil {
+			t.Fatal(err)
+		}
+		rows.Close()
+		if !slices.Equal(got, want) {
+			t.Errorf("policy_templates_fts MATCH %q = %v, want %v", query, got, want)
+		}
+	}
+}
+
+func TestVarFlags(t *testing.T) {
+	fsys := fstest.MapFS{
+		"manifest.yml": {Data: []byte(`
+name: var-flags-test
+title: Var Flags Test
+version: 1.0.0
+description: test
+format_version: 3.5.7
+type: integration
+owner:
+  github: elastic/integrations
+  type: elastic
+vars:
+  - name: api_key
+    type: password
+    title: API Key
+    secret: true
+    required: true
+    show_user: true
+  - name: hosts
+    type: text
+    title: Hosts
+    multi: true
+    required: false
+    show_user: false
+  - name: debug
+    type: bool
+    title: Debug
+`)},
+		"changelog.yml": {Data: []byte(`
+- version: 1.0.0
+  changes:
+    - description: Initial release
+      type: enhancement
+      link: https://github.com/test/1
+`)},
+	}
+
+	pkg, err := pkgreader.Read(".", pkgreader.WithFS(fsys))
+	if err != nil {
+		t.Fatalf("reading package: %v", err)
+	}
+
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	if err := pkgsql.WritePackages(ctx, db, []*pkgreader.Package{pkg}); err != nil {
+		t.Fatalf("writing packages: %v", err)
+	}
+
+	// Unset flags are stored as NULL so they can be told apart from false.
+	flags := func(b sql.NullBool) string {
+		if !b.Valid {
+			return "null"
+		}
+		return strconv.FormatBool(b.Bool)
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT v.name, v.multi, v.secret, v.show_user, v.required
+		FROM vars v
+		JOIN package_vars pv ON pv.var_id = v.id
+		ORDER BY v.name`)
+	if err != nil {
+		t.Fatalf("querying vars: %v", err)
+	}
+	defer rows.Close()
+
+	var got []string
+	for rows.Next() {
+		var name string
+		var multi, secret, showUser, required sql.NullBool
+		if err := rows.Scan(&name, &multi, &secret, &showUser, &required); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, fmt.Sprintf("%s multi=%s secret=%s show_user=%s required=%s",
+			name, flags(multi), flags(secret), flags(showUser), flags(required)))
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"api_key multi=null secret=true show_user=true required=true",
+		"debug multi=null secret=null show_user=null required=null",
+		"hosts multi=true secret=null show_user=false required=false",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("var flags =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestImportedAt(t *testing.T) {
+	fsys := fstest.MapFS{
+		"manifest.yml": {Data: []byte(`
+name: imported-at-test
+title: Imported At Test
+version: 1.0.0
+description: test
+format_version: 3.5.7
+type: input
+owner:
+  github: elastic/integrations
+  type: elastic
+`)},
+		"changelog.yml": {Data: []byte(`
+- version: 1.0.0
+  changes:
+    - description: Initial release
+      type: enhancement
+      link: https://github.com/test/1
+`)},
+	}
+
+	pkg, err := pkgreader.Read(".", pkgreader.WithFS(fsys))
+	if err != nil {
+		t.Fatalf("reading package: %v", err)
+	}
+
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	before := time.Now().Truncate(time.Second)
+	if err := pkgsql.WritePackages(ctx, db, []*pkgreader.Package{pkg}); err != nil {
+		t.Fatalf("writing packages: %v", err)
+	}
+	after := time.Now()
+
+	var importedAt string
+	if err := db.QueryRowContext(ctx, "SELECT imported_at FROM packages").Scan(&importedAt); err != nil {
+		t.Fatalf("querying imported_at: %v", err)
+	}
+
+	ts, err := time.Parse(time.RFC3339, importedAt)
+	if err != nil {
+		t.Fatalf("imported_at %q is not RFC 3339: %v", importedAt, err)
+	}
+	if ts.Before(before) || ts.After(after) {
+		t.Errorf("imported_at = %v, want between %v and %v", ts, before, after)
+	}
+}
+
+func TestWithClock(t *testing.T) {
+	fixed := time.Date(2024, 5, 6, 7, 8, 9, 0, time.FixedZone("CEST", 2*60*60))
+	db := writeTestPackage(t, nil, pkgsql.WithClock(func() time.Time { return fixed }))
+	ctx := context.Background()
+
+	var importedAt string
+	if err := db.QueryRowContext(ctx, "SELECT imported_at FROM packages").Scan(&importedAt); err != nil {
+		t.Fatalf("querying imported_at: %v", err)
+	}
+	if want := "2024-05-06T05:08:09Z"; importedAt != want {
+		t.Errorf("imported_at = %q, want %q", importedAt, want)
+	}
+}
+
+func TestTableCounts(t *testing.T) {
+	fsys := fstest.MapFS{
+		"manifest.yml": {Data: []byte(`
+name: counts-test
+title: Counts Test
+version: 1.1.0
+description: test
+format_version: 3.5.7
+type: integration
+owner:
+  github: elastic/integrations
+  type: elastic
+`)},
+		"changelog.yml": {Data: []byte(`
+- version: 1.1.0
+  changes:
+    - description: Add fields.
+      type: enhancement
+      link: https://github.com/test/2
+- version: 1.0.0
+  changes:
+    - description: Initial release
+      type: enhancement
+      link: https://github.com/test/1
+`)},
+		"data_stream/logs/manifest.yml": {Data: []byte(`
+title: Logs
+type: logs
+`)},
+		"data_stream/logs/fields/fields.yml": {Data: []byte(`
+- name: message
+  type: text
+- name: event.kind
+  type: keyword
+`)},
+	}
+
+	pkg, err := pkgreader.Read(".", pkgreader.WithFS(fsys))
+	if err != nil {
+		t.Fatalf("reading package: %v", err)
+	}
+
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	if err := pkgsql.WritePackages(ctx, db, []*pkgreader.Package{pkg}); err != nil {
+		t.Fatalf("writing packages: %v", err)
+	}
+
+	counts, err := pkgsql.TableCounts(ctx, db)
+	if err != nil {
+		t.Fatalf("counting tables: %v", err)
+	}
+
+	for table, want := range map[string]int64{
+		"packages":           1,
+		"changelogs":         2,
+		"changelog_entries":  2,
+		"data_streams":       1,
+		"fields":             2,
+		"data_stream_fields": 2,
+		"vars":               0,
+	} {
+		if got, ok := counts[table]; !ok || got != want {
+			t.Errorf("counts[%q] = %d, %v; want %d", table, got, ok, want)
+		}
+	}
+
+	for table := range counts {
+		if strings.Contains(table, "_fts") || strings.HasPrefix(table, "sqlite_") {
+			t.Errorf("unexpected table %q in counts", table)
+		}
+	}
+}
+
+func TestColumnNullStats(t *testing.T) {
+	db := writeTestPackage(t, fstest.MapFS{
+		"data_stream/logs/manifest.yml": {Data: []byte(`
+title: Logs
+type: logs
+`)},
+		"data_stream/logs/fields/fields.yml": {Data: []byte(`
+- name: message
+  type: text
+  description: Log message.
+- name: event.kind
+  type: keyword
+- name: event.code
+  type: keyword
+  pattern: "^[0-9]+$"
+`)},
+	})
+	ctx := context.Background()
+
+	stats, err := pkgsql.ColumnNullStats(ctx, db, "fields")
+	if err != nil {
+		t.Fatalf("computing null stats: %v", err)
+	}
+	for col, want := range map[string]int64{
+		"id":          0,
+		"name":        0,
+		"type":        0,
+		"description": 2,
+		"pattern":     2,
+		"unit":        3,
+	} {
+		if got, ok := stats[col]; !ok || got != want {
+			t.Errorf("stats[%q] = %d, %v; want %d", col, got, ok, want)
+		}
+	}
+
+	if _, err := pkgsql.ColumnNullStats(ctx, db, "no_such_table"); err == nil {
+		t.Error("expected error for unknown table")
+	}
+}
+
+func TestAttackCoverageView(t *testing.T) {
+	manifest := `
+name: %s
+title: Attack Coverage Test
+version: 1.0.0
+description: test
+format_version: 3.5.7
+type: integration
+owner:
+  github: elastic/security-rules
+  type: elastic
+`
+	changelog := `
+- version: 1.0.0
+  changes:
+    - description: Initial release
+      type: enhancement
+      link: https://github.com/test/1
+`
+
+	// Two packages ship the same Okta rule.
+	var pkgs []*pkgreader.Package
+	for _, name := range []string{"okta_a", "okta_b"} {
+		fsys := fstest.MapFS{
+			name + "/manifest.yml":                   {Data: []byte(fmt.Sprintf(manifest, name))},
+			name + "/changelog.yml":                  {Data: []byte(changelog)},
+			name + "/kibana/security_rule/rule.json": {Data: []byte(oktaSecurityRuleJSON)},
+		}
+		pkg, err := pkgreader.Read(name, pkgreader.WithFS(fsys))
+		if err != nil {
+			t.Fatalf("reading package %s: %v", name, err)
+		}
+		pkgs = append(pkgs, pkg)
+	}
+
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	if err := pkgsql.WritePackages(ctx, db, pkgs); err != nil {
+		t.Fatalf("writing packages: %v", err)
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT tactic_id, tactic_name, COALESCE(technique_id, ''), rule_count, package_count
+		FROM attack_coverage
+		ORDER BY tactic_id, technique_id`)
+	if err != nil {
+		t.Fatalf("querying attack_coverage: %v", err)
+	}
+	defer rows.Close()
+
+	var got []string
+	for rows.Next() {
+		var tacticID, tacticName, techniqueID string
+		var ruleCount, packageCount int
+		if err := rows.Scan(&tacticID, &tacticName, &techniqueID, &ruleCount, &packageCount); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, fmt.Sprintf("%s/%s %s rules=%d packages=%d", tacticID, techniqueID, tacticName, ruleCount, packageCount))
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"TA0001/T1078 Initial Access rules=2 packages=2",
+		"TA0005/ Defense Evasion rules=2 packages=2",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("attack_coverage =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestIndexPatternProducersView(t *testing.T) {
+	manifest := `
+name: %s
+title: Index Pattern Test
+version: 1.0.0
+description: test
+format_version: 3.5.7
+type: integration
+owner:
+  github: elastic/integrations
+  type: elastic
+`
+	changelog := `
+- version: 1.0.0
+  changes:
+    - description: Initial release
+      type: enhancement
+      link: https://github.com/test/1
+`
+	dsManifest := `
+title: System
+type: logs
+`
+
+	// A pattern for one namespace matches whatever the namespace; a pattern
+	// without a namespace part matches by the default namespace.
+	namespacedRule := `{
+  "id": "test-rule-id-2",
+  "type": "security-rule",
+  "attributes": {
+    "name": "Namespaced",
+    "rule_id": "namespaced-001",
+    "index": ["logs-okta.system-prod", "logs-other*"]
+  }
+}`
+
+	// The okta package produces logs-okta.system-*; the rules package ships
+	// the rules that monitor it and the other package.
+	files := map[string]fstest.MapFS{
+		"okta": {
+			"okta/data_stream/system/manifest.yml": {Data: []byte(dsManifest)},
+		},
+		"other": {
+			"other/data_stream/system/manifest.yml": {Data: []byte(dsManifest)},
+		},
+		"rules": {
+			"rules/kibana/security_rule/rule.json":       {Data: []byte(oktaSecurityRuleJSON)},
+			"rules/kibana/security_rule/namespaced.json": {Data: []byte(namespacedRule)},
+		},
+	}
+	var pkgs []*pkgreader.Package
+	for _, name := range []string{"okta", "other", "rules"} {
+		fsys := files[name]
+		fsys[name+"/manifest.yml"] = &fstest.MapFile{Data: []byte(fmt.Sprintf(manifest, name))}
+		fsys[name+"/changelog.yml"] = &fstest.MapFile{Data: []byte(changelog)}
+		pkg, err := pkgreader.Read(name, pkgreader.WithFS(fsys))
+		if err != nil {
+			t.Fatalf("reading package %s: %v", name, err)
+		}
+		pkgs = append(pkgs, pkg)
+	}
+
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	if err := pkgsql.WritePackages(ctx, db, pkgs); err != nil {
+		t.Fatalf("writing packages: %v", err)
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT pattern, package_name, dataset, rule_count
+		FROM index_pattern_producers
+		ORDER BY pattern, package_name`)
+	if err != nil {
+		t.Fatalf("querying index_pattern_producers: %v", err)
+	}
+	defer rows.Close()
+
+	var got []string
+	for rows.Next() {
+		var pattern, pkgName, dataset string
+		var ruleCount int
+		if err := rows.Scan(&pattern, &pkgName, &dataset, &ruleCount); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, fmt.Sprintf("%s %s %s rules=%d", pattern, pkgName, dataset, ruleCount))
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	// filebeat-* is not produced by any loaded package.
+	want := []string{
+		"logs-okta.system-* okta okta.system rules=1",
+		"logs-okta.system-prod okta okta.system rules=1",
+		"logs-other* other other.system rules=1",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("index_pattern_producers =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestPackageCatalogView(t *testing.T) {
+	db := writeTestPackage(t, fstest.MapFS{
+		"manifest.yml": {Data: []byte(`
+name: catalog_test
+title: Catalog Test
+version: 1.1.0
+description: test
+format_version: 3.5.7
+type: integration
+categories:
+  - security
+  - network
+  - security
+owner:
+  github: elastic/security-service-integrations
+  type: elastic
+`)},
+		"changelog.yml": {Data: []byte(`
+- version: 1.1.0
+  changes:
+    - description: Add error data stream
+      type: enhancement
+      link: https://github.com/test/2
+- version: 1.0.0
+  changes:
+    - description: Initial release
+      type: enhancement
+      link: https://github.com/test/1
+`)},
+		"data_stream/access/manifest.yml": {Data: []byte("title: Access\ntype: logs\n")},
+		"data_stream/error/manifest.yml":  {Data: []byte("title: Error\ntype: logs\n")},
+	})
+	ctx := context.Background()
+
+	var name, owner, categories, latest string
+	var dataStreams int
+	err := db.QueryRowContext(ctx, `
+		SELECT name, owner_github, categories, data_stream_count, latest_version
+		FROM package_catalog`).Scan(&name, &owner, &categories, &dataStreams, &latest)
+	if err != nil {
+		t.Fatalf("querying package_catalog: %v", err)
+	}
+	got := fmt.Sprintf("%s %s categories=%s data_streams=%d latest=%s", name, owner, categories, dataStreams, latest)
+	want := "catalog_test elastic/security-service-integrations categories=network,security data_streams=2 latest=1.1.0"
+	if got != want {
+		t.Errorf("package_catalog = %q, want %q", got, want)
+	}
+}
+
+func TestPackageOwnerColumns(t *testing.T) {
+	manifest := `
+name: %[1]s
+title: Owner Test
+version: 1.0.0
+description: test
+format_version: 3.5.7
+type: %[1]s
+owner:
+  github: %[2]s
+  type: %[3]s
+`
+	changelog := `
+- version: 1.0.0
+  changes:
+    - description: Initial release
+      type: enhancement
+      link: https://github.com/test/1
+`
+
+	owners := []struct{ pkgType, github, ownerType string }{
+		{"integration", "elastic/integrations", "elastic"},
+		{"input", "elastic/integrations", "partner"},
+		{"content", "elastic/security-service-integrations", "community"},
+	}
+
+	var pkgs []*pkgreader.Package
+	for _, o := range owners {
+		fsys := fstest.MapFS{
+			o.pkgType + "/manifest.yml":  {Data: []byte(fmt.Sprintf(manifest, o.pkgType, o.github, o.ownerType))},
+			o.pkgType + "/changelog.yml": {Data: []byte(changelog)},
+		}
+		pkg, err := pkgreader.Read(o.pkgType, pkgreader.WithFS(fsys))
+		if err != nil {
+			t.Fatalf("reading %s package: %v", o.pkgType, err)
+		}
+		pkgs = append(pkgs, pkg)
+	}
+
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	if err := pkgsql.WritePackages(ctx, db, pkgs); err != nil {
+		t.Fatalf("writing packages: %v", err)
+	}
+
+	for _, o := range owners {
+		var github, ownerType string
+		err := db.QueryRowContext(ctx, "SELECT owner_github, owner_type FROM packages WHERE type = ?", o.pkgType).
+			Scan(&github, &ownerType)
+		if err != nil {
+			t.Fatalf("querying %s package owner: %v", o.pkgType, err)
+		}
+		if github != o.github || ownerType != o.ownerType {
+			t.Errorf("%s package owner = %s (%s), want %s (%s)", o.pkgType, github, ownerType, o.github, o.ownerType)
+		}
+	}
+
+	var names []string
+	rows, err := db.QueryContext(ctx, "SELECT name FROM packages WHERE owner_github = 'elastic/integrations' ORDER BY name")
+	if err != nil {
+		t.Fatalf("querying packages by owner: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, name)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"input", "integration"}; !slices.Equal(names, want) {
+		t.Errorf("packages owned by elastic/integrations = %q, want %q", names, want)
+	}
+}
+
+func TestTransformDestination(t *testing.T) {
+	fsys := fstest.MapFS{
+		"manifest.yml": {Data: []byte(`
+name: transform-test
+title: Transform Test
+version: 1.0.0
+description: test
+format_version: 3.5.7
+type: integration
+owner:
+  github: elastic/integrations
+  type: elastic
+`)},
+		"changelog.yml": {Data: []byte(`
+- version: 1.0.0
+  changes:
+    - description: Initial release
+      type: enhancement
+      link: https://github.com/test/1
+`)},
+		"elasticsearch/transform/latest_host/transform.yml": {Data: []byte(`
+source:
+  index:
+    - logs-test.*
+dest:
+  index: logs-transform_test.latest_host-1
+latest:
+  unique_key:
+    - host.id
+  sort: "@timestamp"
+retention_policy:
+  time:
+    field: "@timestamp"
+    max_age: 30d
+`)},
+		"elasticsearch/transform/latest_host/manifest.yml": {Data: []byte(`
+start: true
+`)},
+	}
+
+	pkg, err := pkgreader.Read(".", pkgreader.WithFS(fsys))
+	if err != nil {
+		t.Fatalf("reading package: %v", err)
+	}
+
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	if err := pkgsql.WritePackages(ctx, db, []*pkgreader.Package{pkg}); err != nil {
+		t.Fatalf("writing packages: %v", err)
+	}
+
+	var dirName, destIndex, retentionMaxAge string
+	err = db.QueryRowContext(ctx, "SELECT dir_name, dest_index, retention_max_age FROM transforms").
+		Scan(&dirName, &destIndex, &retentionMaxAge)
+	if err != nil {
+		t.Fatalf("querying transforms: %v", err)
+	}
+
+	got := []string{dirName, destIndex, retentionMaxAge}
+	want := []string{"latest_host", "logs-transform_test.latest_host-1", "30d"}
+	if !slices.Equal(got, want) {
+		t.Errorf("transform row = %q, want %q", got, want)
+	}
+}
+
+func TestWithMaxDocBytes(t *testing.T) {
+	large := strings.Repeat("Line of documentation about the package.\n", 1000)
+	fsys := fstest.MapFS{
+		"manifest.yml": {Data: []byte(`
+name: max-doc-test
+title: Max Doc Test
+version: 1.0.0
+description: test
+format_version: 3.5.7
+type: input
+owner:
+  github: elastic/integrations
+  type: elastic
+`)},
+		"changelog.yml": {Data: []byte(`
+- version: 1.0.0
+  changes:
+    - description: Initial release
+      type: enhancement
+      link: https://github.com/test/1
+`)},
+		"docs/README.md":      {Data: []byte(large)},
+		"docs/small.md":       {Data: []byte("# Small\n")},
+		"docs/single-line.md": {Data: []byte(strings.Repeat("é", 600))},
+	}
+
+	pkg, err := pkgreader.Read(".", pkgreader.WithFS(fsys))
+	if err != nil {
+		t.Fatalf("reading package: %v", err)
+	}
+
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	docReader := func(_, docPath string) ([]byte, error) {
+		return fs.ReadFile(fsys, docPath)
+	}
+	const limit = 1001
+	err = pkgsql.WritePackages(ctx, db, []*pkgreader.Package{pkg},
+		pkgsql.WithDocContent(docReader), pkgsql.WithMaxDocBytes(limit))
+	if err != nil {
+		t.Fatalf("writing packages: %v", err)
+	}
+
+	rows, err := db.QueryContext(ctx, "SELECT file_path, content, truncated FROM docs ORDER BY file_path")
+	if err != nil {
+		t.Fatalf("querying docs: %v", err)
+	}
+	defer rows.Close()
+
+	got := map[string]string{}
+	flags := map[string]bool{}
+	for rows.Next() {
+		var filePath, content string
+		var truncated bool
+		if err := rows.Scan(&filePath, &content, &truncated); err != nil {
+			t.Fatal(err)
+		}
+		got[filePath] = content
+		flags[filePath] = truncated
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Cut at the last complete line within the limit.
+	readme := got["docs/README.md"]
+	if len(readme) > limit || !strings.HasSuffix(readme, "package.\n") || !strings.HasPrefix(large, readme) {
+		t.Errorf("README.md content not cut at a line boundary (%d bytes): %q", len(readme), readme[max(0, len(readme)-50):])
+	}
+	if !flags["docs/README.md"] {
+		t.Error("README.md truncated = false, want true")
+	}
+
+	// A single long line is cut at a character boundary.
+	singleLine := got["docs/single-line.md"]
+	if len(singleLine) != limit-1 || !utf8.ValidString(singleLine) {
+		t.Errorf("single-line.md content is %d bytes (valid UTF-8: %v), want %d", len(singleLine), utf8.ValidString(singleLine), limit-1)
+	}
+	if !flags["docs/single-line.md"] {
+		t.Error("single-line.md truncated = false, want true")
+	}
+
+	if got["docs/small.md"] != "# Small\n" || flags["docs/small.md"] {
+		t.Errorf("small.md = %q (truncated %v), want unchanged", got["docs/small.md"], flags["docs/small.md"])
+	}
+}
+
+func TestProcessorTypeCounts(t *testing.T) {
+	fsys := fstest.MapFS{
+		"manifest.yml": {Data: []byte(`
+name: proc-counts
+title: Processor Counts
+version: 1.0.0
+description: test
+format_version: 3.5.7
+type: integration
+owner:
+  github: elastic/integrations
+  type: elastic
+`)},
+		"changelog.yml": {Data: []byte(`
+- version: 1.0.0
+  changes:
+    - description: Initial release
+      type: enhancement
+      link: https://github.com/test/1
+`)},
+		"data_stream/logs/manifest.yml": {Data: []byte(`
+title: Logs
+type: logs
+`)},
+		"data_stream/logs/elasticsearch/ingest_pipeline/default.yml": {Data: []byte(`
+description: Logs pipeline.
+processors:
+  - set:
+      field: event.kind
+      value: event
+      on_failure:
+        - set:
+            field: error.message
+            value: failed
+            on_failure:
+              - append:
+                  field: tags
+                  value: nested
+  - rename:
+      field: message
+      target_field: event.original
+on_failure:
+  - set:
+      field: event.kind
+      value: pipeline_error
+`)},
+		"elasticsearch/ingest_pipeline/shared.yml": {Data: []byte(`
+description: Shared pipeline.
+processors:
+  - rename:
+      field: a
+      target_field: b
+`)},
+	}
+
+	pkg, err := pkgreader.Read(".", pkgreader.WithFS(fsys))
+	if err != nil {
+		t.Fatalf("reading package: %v", err)
+	}
+
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	if err := pkgsql.WritePackages(ctx, db, []*pkgreader.Package{pkg}); err != nil {
+		t.Fatalf("writing packages: %v", err)
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT ptc.type, ptc.count
+		FROM processor_type_counts ptc
+		JOIN packages p ON p.id = ptc.packages_id
+		WHERE p.name = 'proc-counts'
+		ORDER BY ptc.type`)
+	if err != nil {
+		t.Fatalf("querying processor_type_counts: %v", err)
+	}
+	defer rows.Close()
+
+	var got []string
+	for rows.Next() {
+		var typ string
+		var n int
+		if err := rows.Scan(&typ, &n); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, typ+"="+strconv.Itoa(n))
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Nested on_failure handlers and package-level pipelines are counted.
+	want := []string{"append=1", "rename=2", "set=3"}
+	if !slices.Equal(got, want) {
+		t.Errorf("processor_type_counts = %v, want %v", got, want)
+	}
+}
+
+func TestKibanaDashboardPanelsCount(t *testing.T) {
+	fsys := fstest.MapFS{
+		"manifest.yml": {Data: []byte(`
+name: panels-test
+title: Panels Test
+version: 1.0.0
+description: test
+format_version: 3.5.7
+type: integration
+owner:
+  github: elastic/integrations
+  type: elastic
+`)},
+		"changelog.yml": {Data: []byte(`
+- version: 1.0.0
+  changes:
+    - description: Initial release
+      type: enhancement
+      link: https://github.com/test/1
+`)},
+		// elastic-package stores panelsJSON decoded.
+		"kibana/dashboard/decoded.json": {Data: []byte(`{
+  "id": "decoded",
+  "type": "dashboard",
+  "attributes": {
+    "title": "Decoded",
+    "description": "Overview of access logs.",
+    "panelsJSON": [{"panelIndex": "1"}, {"panelIndex": "2"}]
+  },
+  "references": []
+}`)},
+		// Kibana exports store panelsJSON as an encoded string.
+		"kibana/dashboard/encoded.json": {Data: []byte(`{
+  "id": "encoded",
+  "type": "dashboard",
+  "attributes": {
+    "title": "Encoded",
+    "panelsJSON": "[{\"panelIndex\":\"1\"},{\"panelIndex\":\"2\"}]"
+  },
+  "references": []
+}`)},
+		"kibana/visualization/vis.json": {Data: []byte(`{"id": "vis", "type": "visualization", "attributes": {"title": "Vis"}, "references": []}`)},
+	}
+
+	pkg, err := pkgreader.Read(".", pkgreader.WithFS(fsys))
+	if err != nil {
+		t.Fatalf("reading package: %v", err)
+	}
+
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	if err := pkgsql.WritePackages(ctx, db, []*pkgreader.Package{pkg}); err != nil {
+		t.Fatalf("writing packages: %v", err)
+	}
+
+	for id, want := range map[string]sql.NullInt64{
+		"decoded": {Int64: 2, Valid: true},
+		"encoded": {Int64: 2, Valid: true},
+		"vis":     {},
+	} {
+		var got sql.NullInt64
+		err := db.QueryRowContext(ctx,
+			"SELECT panels_count FROM kibana_saved_objects WHERE object_id = ?", id).Scan(&got)
+		if err != nil {
+			t.Fatalf("querying %s: %v", id, err)
+		}
+		if got != want {
+			t.Errorf("%s panels_count = %v, want %v", id, got, want)
+		}
+	}
+
+	var description string
+	err = db.QueryRowContext(ctx,
+		"SELECT description FROM kibana_saved_objects WHERE object_id = 'decoded'").Scan(&description)
+	if err != nil {
+		t.Fatalf("querying description: %v", err)
+	}
+	if description != "Overview of access logs." {
+		t.Errorf("description = %q, want Overview of access logs.", description)
+	}
+}
+
+func TestWithKibanaRaw(t *testing.T) {
+	// Indentation and key order must survive the round trip.
+	const dashboard = `{
+    "id": "raw-dash",
+    "type": "dashboard",
+    "attributes": {"title": "Raw", "panelsJSON": "[]"},
+    "references": []
+}
+`
+	pkg := readTestPackage(t, fstest.MapFS{
+		"kibana/dashboard/raw.json": {Data: []byte(dashboard)},
+	})
+
+	ctx := context.Background()
+	for _, tc := range []struct {
+		name string
+		opts []pkgsql.Option
+		want sql.NullString
+	}{
+		{name: "default"},
+		{name: "raw", opts: []pkgsql.Option{pkgsql.WithKibanaRaw()}, want: sql.NullString{String: dashboard, Valid: true}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newTestDB(t)
+			if err := pkgsql.WritePackages(ctx, db, []*pkgreader.Package{pkg}, tc.opts...); err != nil {
+				t.Fatalf("writing packages: %v", err)
+			}
+
+			var got sql.NullString
+			err := db.QueryRowContext(ctx,
+				"SELECT raw FROM kibana_saved_objects WHERE object_id = 'raw-dash'").Scan(&got)
+			if err != nil {
+				t.Fatalf("querying raw: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("raw = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFieldMappingFlags(t *testing.T) {
+	fsys := fstest.MapFS{
+		"manifest.yml": {Data: []byte(`
+name: mapping-flags
+title: Mapping Flags
+version: 1.0.0
+description: test
+format_version: 3.5.7
+type: integration
+owner:
+  github: elastic/integrations
+  type: elastic
+`)},
+		"changelog.yml": {Data: []byte(`
+- version: 1.0.0
+  changes:
+    - description: Initial release
+      type: enhancement
+      link: https://github.com/test/1
+`)},
+		"data_stream/logs/manifest.yml": {Data: []byte(`
+title: Logs
+type: logs
+`)},
+		"data_stream/logs/fields/fields.yml": {Data: []byte(`
+- name: stored_only
+  type: keyword
+  index: false
+  doc_values: false
+- name: raw
+  type: object
+  enabled: false
+- name: plain
+  type: keyword
+`)},
+	}
+
+	pkg, err := pkgreader.Read(".", pkgreader.WithFS(fsys))
+	if err != nil {
+		t.Fatalf("reading package: %v", err)
+	}
+
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	if err := pkgsql.WritePackages(ctx, db, []*pkgreader.Package{pkg}); err != nil {
+		t.Fatalf("writing packages: %v", err)
+	}
+
+	for name, want := range map[string][3]sql.NullBool{
+		"stored_only": {{Bool: false, Valid: true}, {Bool: false, Valid: true}, {}},
+		"raw":         {{}, {}, {Bool: false, Valid: true}},
+		"plain":       {{}, {}, {}},
+	} {
+		var got [3]sql.NullBool
+		err := db.QueryRowContext(ctx,
+			`SELECT "index", doc_values, enabled FROM fields WHERE name = ?`, name).
+			Scan(&got[0], &got[1], &got[2])
+		if err != nil {
+			t.Fatalf("querying %s: %v", name, err)
+		}
+		if got != want {
+			t.Errorf("%s (index, doc_values, enabled) = %v, want %v", name, got, want)
+		}
+	}
+
+	// Fields that are kept in _source but not indexed.
+	var notIndexed string
+	err = db.QueryRowContext(ctx,
+		`SELECT GROUP_CONCAT(name) FROM fields WHERE "index" = 0`).Scan(&notIndexed)
+	if err != nil {
+		t.Fatalf("querying unindexed fields: %v", err)
+	}
+	if notIndexed != "stored_only" {
+		t.Errorf("unindexed fields = %q, want stored_only", notIndexed)
+	}
+}
+
+func TestConditionsElasticCapabilities(t *testing.T) {
+	manifest := `
+name: %s
+title: Capabilities Test
+version: 1.0.0
+description: test
+format_version: 3.5.7
+type: integration
+owner:
+  github: elastic/integrations
+  type: elastic
+%s`
+	changelog := `
+- version: 1.0.0
+  changes:
+    - description: Initial release
+      type: enhancement
+      link: https://github.com/test/1
+`
+	conditions := `conditions:
+  elastic:
+    subscription: basic
+    capabilities:
+      - security
+      - observability
+`
+
+	var pkgs []*pkgreader.Package
+	for name, extra := range map[string]string{"with_caps": conditions, "without_caps": ""} {
+		fsys := fstest.MapFS{
+			name + "/manifest.yml":  {Data: []byte(fmt.Sprintf(manifest, name, extra))},
+			name + "/changelog.yml": {Data: []byte(changelog)},
+		}
+		pkg, err := pkgreader.Read(name, pkgreader.WithFS(fsys))
+		if err != nil {
+			t.Fatalf("reading package %s: %v", name, err)
+		}
+		pkgs = append(pkgs, pkg)
+	}
+
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	if err := pkgsql.WritePackages(ctx, db, pkgs); err != nil {
+		t.Fatalf("writing packages: %v", err)
+	}
+
+	for name, want := range map[string]sql.NullString{
+		"with_caps":    {String: `["security","observability"]`, Valid: true},
+		"without_caps": {},
+	} {
+		var got sql.NullString
+		err := db.QueryRowContext(ctx,
+			"SELECT conditions_elastic_capabilities FROM packages WHERE name = ?", name).Scan(&got)
+		if err != nil {
+			t.Fatalf("querying %s: %v", name, err)
+		}
+		if got != want {
+			t.Errorf("%s conditions_elastic_capabilities = %v, want %v", name, got, want)
+		}
+	}
+
+	// Packages requiring a capability can be filtered out of a deployment
+	// that lacks it.
+	var names []string
+	rows, err := db.QueryContext(ctx, `
+		SELECT name FROM packages
+		WHERE NOT EXISTS (
+		  SELECT 1 FROM json_each(conditions_elastic_capabilities) WHERE value = 'security'
+		)
+		ORDER BY name`)
+	if err != nil {
+		t.Fatalf("filtering by capability: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, name)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(names, []string{"without_caps"}) {
+		t.Errorf("packages without security capability = %v, want [without_caps]", names)
+	}
+}
+
+func TestWithFTSTokenizer(t *testing.T) {
+	fsys := fstest.MapFS{
+		"manifest.yml": {Data: []byte(`
+name: tokenizer-test
+title: Tokenizer Test
+version: 1.0.0
+description: test
+format_version: 3.5.7
+type: integration
+owner:
+  github: elastic/integrations
+  type: elastic
+`)},
+		"changelog.yml": {Data: []byte(`
+- version: 1.0.0
+  changes:
+    - description: Improved authentication error handling.
+      type: enhancement
+      link: https://github.com/test/1
+`)},
+	}
+
+	pkg, err := pkgreader.Read(".", pkgreader.WithFS(fsys))
+	if err != nil {
+		t.Fatalf("reading package: %v", err)
+	}
+
+	ctx := context.Background()
+	matches := func(t *testing.T, opts ...pkgsql.Option) int {
+		t.Helper()
+		db := newTestDB(t)
+		if err := pkgsql.WritePackages(ctx, db, []*pkgreader.Package{pkg}, opts...); err != nil {
+			t.Fatalf("writing packages: %v", err)
+		}
+		var n int
+		err := db.QueryRowContext(ctx,
+			`SELECT count(*) FROM changelog_entries_fts WHERE changelog_entries_fts MATCH 'auth'`).Scan(&n)
+		if err != nil {
+			t.Fatalf("querying changelog_entries_fts: %v", err)
+		}
+		return n
+	}
+
+	// The default tokenizer only matches whole (stemmed) tokens.
+	if n := matches(t); n != 0 {
+		t.Errorf("default tokenizer: 'auth' matched %d entries, want 0", n)
+	}
+
+	// The trigram tokenizer matches substrings within a token.
+	if n := matches(t, pkgsql.WithFTSTokenizer("trigram")); n != 1 {
+		t.Errorf("trigram tokenizer: 'auth' matched %d entries, want 1", n)
+	}
+}
+
+func TestWithoutFTS(t *testing.T) {
+	for _, ddl := range pkgsql.TableSchemas(pkgsql.WithoutFTS()) {
+		if strings.Contains(ddl, "_fts") {
+			t.Errorf("TableSchemas(WithoutFTS()) contains FTS statement: %.60s", ddl)
+		}
+	}
+
+	db := writeTestPackage(t, fstest.MapFS{
+		"docs/README.md": {Data: []byte("# Without FTS\n")},
+	}, pkgsql.WithoutFTS())
+	ctx := context.Background()
+
+	var ftsObjects int
+	err := db.QueryRowContext(ctx,
+		"SELECT count(*) FROM sqlite_master WHERE name LIKE '%\\_fts%' ESCAPE '\\'").Scan(&ftsObjects)
+	if err != nil {
+		t.Fatalf("querying sqlite_master: %v", err)
+	}
+	if ftsObjects != 0 {
+		t.Errorf("found %d FTS objects, want 0", ftsObjects)
+	}
+
+	var docs int
+	if err := db.QueryRowContext(ctx, "SELECT count(*) FROM docs").Scan(&docs); err != nil {
+		t.Fatalf("querying docs: %v", err)
+	}
+	if docs != 1 {
+		t.Errorf("docs rows = %d, want 1", docs)
+	}
+
+	// RebuildFTS is a no-op without the FTS tables.
+	if err := pkgsql.RebuildFTS(ctx, db); err != nil {
+		t.Errorf("RebuildFTS() without FTS tables: %v", err)
+	}
+}
+
+func TestDataStreamType(t *testing.T) {
+	fsys := fstest.MapFS{
+		"manifest.yml": {Data: []byte(`
+name: type_test
+title: Type Test
+version: 1.0.0
+description: test
+format_version: 3.5.7
+type: integration
+owner:
+  github: elastic/integrations
+  type: elastic
+`)},
+		"changelog.yml": {Data: []byte(`
+- version: 1.0.0
+  changes:
+    - description: Initial release
+      type: enhancement
+      link: https://github.com/test/1
+`)},
+		"data_stream/access/manifest.yml": {Data: []byte("title: Access\ntype: logs\n")},
+		"data_stream/status/manifest.yml": {Data: []byte("title: Status\ntype: metrics\n")},
+	}
+	pkg, err := pkgreader.Read(".", pkgreader.WithFS(fsys))
+	if err != nil {
+		t.Fatalf("reading package: %v", err)
+	}
+
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	if err := pkgsql.WritePackages(ctx, db, []*pkgreader.Package{pkg}); err != nil {
+		t.Fatalf("writing packages: %v", err)
+	}
+
+	var dataset string
+	err = db.QueryRowContext(ctx,
+		"SELECT effective_dataset FROM data_streams WHERE type = 'metrics'").Scan(&dataset)
+	if err != nil {
+		t.Fatalf("querying metrics data stream: %v", err)
+	}
+	if dataset != "type_test.status" {
+		t.Errorf("metrics data stream dataset = %q, want %q", dataset, "type_test.status")
+	}
+
+	// Filtering by type is served by an index.
+	var plan []string
+	rows, err := db.QueryContext(ctx, "EXPLAIN QUERY PLAN SELECT id FROM data_streams WHERE type = 'metrics'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id, parent, notUsed int
+		var detail string
+		if err := rows.Scan(&id, &parent, &notUsed, &detail); err != nil {
+			t.Fatal(err)
+		}
+		plan = append(plan, detail)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(strings.Join(plan, "\n"), "data_streams_type_idx") {
+		t.Errorf("query plan does not use data_streams_type_idx: %v", plan)
+	}
+}
+
+func TestPolicyTemplateMultipleAndDeploymentModes(t *testing.T) {
+	fsys := fstest.MapFS{
+		"manifest.yml": {Data: []byte(`
+name: modes_test
+title: Modes Test
+version: 1.0.0
+description: test
+format_version: 3.5.7
+type: integration
+owner:
+  github: elastic/integrations
+  type: elastic
+policy_templates:
+  - name: agentless
+    title: Agentless
+    description: Agentless collection.
+    multiple: false
+    deployment_modes:
+      default:
+        enabled: false
+      agentless:
+        enabled: true
+        organization: security
+        division: engineering
+        team: cloud-security
+  - name: plain
+    title: Plain
+    description: Plain collection.
+`)},
+		"changelog.yml": {Data: []byte(`
+- version: 1.0.0
+  changes:
+    - description: Initial release
+      type: enhancement
+      link: https://github.com/test/1
+`)},
+	}
+	pkg, err := pkgreader.Read(".", pkgreader.WithFS(fsys))
+	if err != nil {
+		t.Fatalf("reading package: %v", err)
+	}
+
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	if err := pkgsql.WritePackages(ctx, db, []*pkgreader.Package{pkg}); err != nil {
+		t.Fatalf("writing packages: %v", err)
+	}
+
+	var multiple sql.NullBool
+	var modes sql.NullString
+	var agentless sql.NullBool
+	err = db.QueryRowContext(ctx, `
+		SELECT multiple, deployment_modes, json_extract(deployment_modes, '$.agentless.enabled')
+		FROM policy_templates WHERE name = 'agentless'`).Scan(&multiple, &modes, &agentless)
+	if err != nil {
+		t.Fatalf("querying agentless policy template: %v", err)
+	}
+	if multiple != (sql.NullBool{Bool: false, Valid: true}) {
+		t.Errorf("multiple = %v, want false", multiple)
+	}
+	if !modes.Valid || !strings.Contains(modes.String, `"team":"cloud-security"`) {
+		t.Errorf("deployment_modes = %v, want agentless settings", modes)
+	}
+	if !agentless.Valid || !agentless.Bool {
+		t.Errorf("deployment_modes agentless.enabled = %v, want true", agentless)
+	}
+
+	err = db.QueryRowContext(ctx,
+		"SELECT multiple, deployment_modes FROM policy_templates WHERE name = 'plain'").Scan(&multiple, &modes)
+	if err != nil {
+		t.Fatalf("querying plain policy template: %v", err)
+	}
+	if multiple.Valid || modes.Valid {
+		t.Errorf("plain multiple = %v, deployment_modes = %v, want NULL", multiple, modes)
+	}
+}
+
+func TestPolicyTemplateCounts(t *testing.T) {
+	db := writeTestPackage(t, fstest.MapFS{
+		"manifest.yml": {Data: []byte(testManifest + `
+policy_templates:
+  - name: collect
+    title: Collect
+    description: Collect logs.
+    vars:
+      - name: proxy_url
+        type: text
+        title: Proxy URL
+        multi: false
+        required: false
+        show_user: false
+    inputs:
+      - type: logfile
+        title: Log files
+        description: Collect from files.
+      - type: httpjson
+        title: API
+        description: Collect from the API.
+`)},
+		"data_stream/log/manifest.yml": {Data: []byte(`
+title: Log
+type: logs
+streams:
+  - input: logfile
+    title: Log files
+    description: Collect from files.
+  - input: httpjson
+    title: API
+    description: Collect from the API.
+`)},
+	})
+	ctx := context.Background()
+
+	var inputCount, varCount, inputRows, varRows int
+	err := db.QueryRowContext(ctx, `
+		SELECT pt.input_count, pt.var_count,
+			(SELECT COUNT(*) FROM policy_template_inputs WHERE policy_templates_id = pt.id),
+			(SELECT COUNT(*) FROM policy_template_vars WHERE policy_template_id = pt.id)
+		FROM policy_templates pt WHERE pt.name = 'collect'`).Scan(&inputCount, &varCount, &inputRows, &varRows)
+	if err != nil {
+		t.Fatalf("querying policy template counts: %v", err)
+	}
+	if inputCount != 2 || inputCount != inputRows {
+		t.Errorf("input_count = %d, want 2 (policy_template_inputs rows = %d)", inputCount, inputRows)
+	}
+	if varCount != 1 || varCount != varRows {
+		t.Errorf("var_count = %d, want 1 (policy_template_vars rows = %d)", varCount, varRows)
+	}
+
+	var streamCount, streamRows int
+	err = db.QueryRowContext(ctx, `
+		SELECT ds.stream_count,
+			(SELECT COUNT(*) FROM streams WHERE data_streams_id = ds.id)
+		FROM data_streams ds WHERE ds.dir_name = 'log'`).Scan(&streamCount, &streamRows)
+	if err != nil {
+		t.Fatalf("querying data stream counts: %v", err)
+	}
+	if streamCount != 2 || streamCount != streamRows {
+		t.Errorf("stream_count = %d, want 2 (streams rows = %d)", streamCount, streamRows)
+	}
+}
+
+func TestDataStreamIndexTemplateName(t *testing.T) {
+	db := writeTestPackage(t, fstest.MapFS{
+		"data_stream/access/manifest.yml": {Data: []byte("title: Access\ntype: logs\n")},
+		"data_stream/error/manifest.yml":  {Data: []byte("title: Error\ntype: logs\ndataset: nginx_custom.error\n")},
+	})
+	ctx := context.Background()
+
+	for dirName, want := range map[string]string{
+		"access": "logs-test-package.access",
+		"error":  "logs-nginx_custom.error",
+	} {
+		var got sql.NullString
+		err := db.QueryRowContext(ctx,
+			"SELECT index_template_name FROM data_streams WHERE dir_name = ?", dirName).Scan(&got)
+		if err != nil {
+			t.Fatalf("querying %s: %v", dirName, err)
+		}
+		if got.String != want {
+			t.Errorf("%s index_template_name = %v, want %q", dirName, got, want)
+		}
+	}
+}
+
+func TestDataStreamHiddenAndSourceMode(t *testing.T) {
+	db := writeTestPackage(t, fstest.MapFS{
+		"data_stream/access/manifest.yml": {Data: []byte(`
+title: Access
+type: logs
+hidden: true
+elasticsearch:
+  source_mode: synthetic
+`)},
+		"data_stream/error/manifest.yml": {Data: []byte("title: Error\ntype: logs\n")},
+	})
+	ctx := context.Background()
+
+	var dirNames []string
+	rows, err := db.QueryContext(ctx,
+		"SELECT dir_name FROM data_streams WHERE elasticsearch_source_mode = 'synthetic' AND hidden")
+	if err != nil {
+		t.Fatalf("querying data_streams: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			t.Fatal(err)
+		}
+		dirNames = append(dirNames, name)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(dirNames, []string{"access"}) {
+		t.Errorf("hidden synthetic-source data streams = %v, want [access]", dirNames)
+	}
+
+	var hidden sql.NullBool
+	var sourceMode sql.NullString
+	err = db.QueryRowContext(ctx,
+		"SELECT hidden, elasticsearch_source_mode FROM data_streams WHERE dir_name = 'error'").Scan(&hidden, &sourceMode)
+	if err != nil {
+		t.Fatalf("querying error data stream: %v", err)
+	}
+	if hidden.Valid || sourceMode.Valid {
+		t.Errorf("error data stream hidden = %v, source_mode = %v, want NULL", hidden, sourceMode)
+	}
+}
+
+func TestFieldNormalizeAndPattern(t *testing.T) {
+	db := writeTestPackage(t, fstest.MapFS{
+		"data_stream/access/manifest.yml": {Data: []byte("title: Access\ntype: logs\n")},
+		"data_stream/access/fields/fields.yml": {Data: []byte(`
+- name: nginx.access.remote_ip_list
+  type: keyword
+  description: Remote IP addresses.
+  normalize:
+    - array
+- name: nginx.access.request_id
+  type: keyword
+  description: Request ID.
+  pattern: '^[0-9a-f]{16}$'
+`)},
+	})
+	ctx := context.Background()
+
+	for name, want := range map[string][2]sql.NullString{
+		"nginx.access.remote_ip_list": {{String: `["array"]`, Valid: true}, {}},
+		"nginx.access.request_id":     {{}, {String: "^[0-9a-f]{16}$", Valid: true}},
+	} {
+		var normalize, pattern sql.NullString
+		err := db.QueryRowContext(ctx,
+			"SELECT normalize, pattern FROM fields WHERE name = ?", name).Scan(&normalize, &pattern)
+		if err != nil {
+			t.Fatalf("querying %s: %v", name, err)
+		}
+		if normalize != want[0] || pattern != want[1] {
+			t.Errorf("%s normalize = %v, pattern = %v, want %v, %v", name, normalize, pattern, want[0], want[1])
+		}
+	}
+}
+
+func TestWithECSLookup(t *testing.T) {
+	pkg := readTestPackage(t, fstest.MapFS{
+		"data_stream/access/manifest.yml": {Data: []byte("title: Access\ntype: logs\n")},
+		"data_stream/access/fields/ecs.yml": {Data: []byte(`
+- name: event.category
+  external: ecs
+- name: source.ip
+  external: ecs
+`)},
+	})
+
+	lookup := func(name string) *pkgspec.ECSFieldDefinition {
+		switch name {
+		case "event.category":
+			return &pkgspec.ECSFieldDefinition{
+				DataType:    "keyword",
+				Description: "Event category.",
+				Array:       true,
+				AllowedValues: []pkgspec.ECSAllowedValue{
+					{Name: "authentication", Description: "Login events.", ExpectedEventTypes: []string{"start", "end"}},
+					{Name: "network", Description: "Network events."},
+				},
+			}
+		case "source.ip":
+			return &pkgspec.ECSFieldDefinition{DataType: "ip", Description: "IP address of the source."}
+		}
+		return nil
+	}
+
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	if err := pkgsql.WritePackages(ctx, db, []*pkgreader.Package{pkg}, pkgsql.WithECSLookup(lookup)); err != nil {
+		t.Fatalf("writing packages: %v", err)
+	}
+
+	for name, want := range map[string]sql.NullString{
+		"event.category": {String: `[{"name":"authentication","description":"Login events.","expected_event_types":["start","end"]},{"name":"network","description":"Network events."}]`, Valid: true},
+		"source.ip":      {},
+	} {
+		var got sql.NullString
+		err := db.QueryRowContext(ctx,
+			"SELECT allowed_values FROM fields WHERE name = ?", name).Scan(&got)
+		if err != nil {
+			t.Fatalf("querying %s: %v", name, err)
+		}
+		if got != want {
+			t.Errorf("%s allowed_values = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestElasticsearchPrivilegesIndex(t *testing.T) {
+	db := writeTestPackage(t, fstest.MapFS{
+		"manifest.yml": {Data: []byte(testManifest + `
+elasticsearch:
+  privileges:
+    cluster:
+      - monitor
+`)},
+		"data_stream/alerts/manifest.yml": {Data: []byte(`
+title: Alerts
+type: logs
+elasticsearch:
+  privileges:
+    indices:
+      - read
+      - auto_configure
+`)},
+		"data_stream/events/manifest.yml": {Data: []byte(`
+title: Events
+type: logs
+elasticsearch:
+  privileges:
+    indices:
+      - read
+`)},
+	})
+	ctx := context.Background()
+
+	var cluster, index sql.NullString
+	err := db.QueryRowContext(ctx,
+		"SELECT elasticsearch_privileges_cluster, elasticsearch_privileges_index FROM packages").Scan(&cluster, &index)
+	if err != nil {
+		t.Fatalf("querying packages: %v", err)
+	}
+	if cluster.String != `["monitor"]` {
+		t.Errorf("elasticsearch_privileges_cluster = %v, want [\"monitor\"]", cluster)
+	}
+	if index.String != `["auto_configure","read"]` {
+		t.Errorf("elasticsearch_privileges_index = %v, want [\"auto_configure\",\"read\"]", index)
+	}
+}
+
+func TestBuildFleetPackagesDB(t *testing.T) {
+	dir := os.Getenv("INTEGRATIONS_DIR")
+	if dir == "" {
+		t.Skip("INTEGRATIONS_DIR not set")
+	}
+
+	packagesDir := filepath.Join(dir, "packages")
+	pkgPaths, err := pkgreader.ListPackages(packagesDir)
+	if err != nil {
+		t.Fatalf("listing packages: %v", err)
+	}
+
+	dbPath := filepath.Join(".", "fleet-packages.sqlite")
+	os.Remove(dbPath)
+
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// Enable WAL mode and other SQLite optimizations for bulk inserts.
+	for _, pragma := range []string{
+		"PRAGMA journal_mode=WAL",
+		"PRAGMA synchronous=NORMAL",
+		"PRAGMA cache_size=-64000",
+		"PRAGMA mmap_size=268435456",
+		"PRAGMA temp_store=MEMORY",
+	} {
+		if _, err := db.Exec(pragma); err != nil {
+			t.Fatalf("setting %s: %v", pragma, err)
+		}
+	}
+
+	ctx := context.Background()
+
+	// Create tables.
+	for _, ddl := range pkgsql.TableSchemas() {
+		if _, err := db.ExecContext(ctx, ddl); err != nil {
+			t.Fatalf("creating tables: %v", err)
+		}
+	}
+
+	codeownersPath := filepath.Join(dir, ".github", "CODEOWNERS")
+	opts := []pkgreader.Option{
+		pkgreader.WithKnownFields(),
+		pkgreader.WithGitMetadata(),
+		pkgreader.WithImageMetadata(),
+		pkgreader.WithTestConfigs(),
+		pkgreader.WithAgentTemplates(),
+		pkgreader.WithCodeowners(codeownersPath),
+	}
+
+	// Read packages in parallel, write to DB sequentially.
+	type result struct {
+		pkg  *pkgreader.Package
+		name string
+		err  error
+	}
+
+	// Use more workers than CPUs since package reading is I/O bound
+	// (git blame subprocess, file reads).
+	workers := 4 * runtime.NumCPU()
+	work := make(chan string, workers)
+	results := make(chan result, workers)
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
 		go func() {
 			defer wg.Done()
 			for pkgPath := range work {
@@ -1594,18 +5125,21 @@ func TestBuildFleetPackagesDB(t *testing.T) {
 	}
 
 	// Verify github_code_owner is populated for a data stream with CODEOWNERS entry.
-	var githubCodeOwner sql.NullString
+	var githubCodeOwner, githubCodeOwners sql.NullString
 	err = db.QueryRowContext(ctx, `
-		SELECT ds.github_code_owner
+		SELECT ds.github_code_owner, ds.github_code_owners
 		FROM data_streams ds
 		JOIN packages p ON p.id = ds.packages_id
-		WHERE p.name = 'aws' AND ds.dir_name = 'cloudtrail'`).Scan(&githubCodeOwner)
+		WHERE p.name = 'aws' AND ds.dir_name = 'cloudtrail'`).Scan(&githubCodeOwner, &githubCodeOwners)
 	if err != nil {
 		t.Fatalf("querying github_code_owner: %v", err)
 	}
 	if !githubCodeOwner.Valid || githubCodeOwner.String == "" {
 		t.Error("expected non-NULL github_code_owner for aws/cloudtrail with WithCodeowners")
 	}
+	if !githubCodeOwners.Valid || !strings.Contains(githubCodeOwners.String, githubCodeOwner.String) {
+		t.Errorf("expected github_code_owners to include %q, got %q", githubCodeOwner.String, githubCodeOwners.String)
+	}
 }
 
 func TestWritePackageWithAgentTemplates(t *testing.T) {
@@ -1889,8 +5423,9 @@ policy_templates:
 	}
 }
 
-func TestWritePackageWithSecurityRules(t *testing.T) {
-	ruleJSON := `{
+// oktaSecurityRuleJSON is an EQL detection rule mapped to two MITRE ATT&CK
+// tactics, one with a technique and subtechnique and one without techniques.
+const oktaSecurityRuleJSON = `{
   "id": "test-rule-id-1",
   "type": "security-rule",
   "attributes": {
@@ -1909,6 +5444,9 @@ func TestWritePackageWithSecurityRules(t *testing.T) {
     "from": "now-9m",
     "max_signals": 100,
     "timestamp_override": "event.ingested",
+    "building_block_type": "default",
+    "rule_name_override": "okta.target.display_name",
+    "timeline_id": "4d4c0b59-ea83-483f-b8c1-8c360ee53c5c",
     "setup": "## Setup\nRequires Okta integration.",
     "note": "## Triage\nCheck the source IP address.",
     "author": ["Elastic"],
@@ -1963,6 +5501,7 @@ func TestWritePackageWithSecurityRules(t *testing.T) {
   "references": []
 }`
 
+func TestWritePackageWithSecurityRules(t *testing.T) {
 	fsys := fstest.MapFS{
 		"manifest.yml": {Data: []byte(`
 name: security-rule-test
@@ -1990,7 +5529,7 @@ policy_templates:
       type: enhancement
       link: https://github.com/test/1
 `)},
-		"kibana/security_rule/rule.json": {Data: []byte(ruleJSON)},
+		"kibana/security_rule/rule.json": {Data: []byte(oktaSecurityRuleJSON)},
 	}
 
 	pkg, err := pkgreader.Read(".", pkgreader.WithFS(fsys))
@@ -2068,6 +5607,23 @@ policy_templates:
 		t.Errorf("expected note to contain 'source IP', got %s", note)
 	}
 
+	// Verify building block and investigation fields.
+	var buildingBlockType, ruleNameOverride, timelineID string
+	err = db.QueryRowContext(ctx, "SELECT building_block_type, rule_name_override, timeline_id FROM security_rules").
+		Scan(&buildingBlockType, &ruleNameOverride, &timelineID)
+	if err != nil {
+		t.Fatalf("querying building_block_type: %v", err)
+	}
+	if buildingBlockType != "default" {
+		t.Errorf("expected building_block_type=default, got %s", buildingBlockType)
+	}
+	if ruleNameOverride != "okta.target.display_name" {
+		t.Errorf("expected rule_name_override=okta.target.display_name, got %s", ruleNameOverride)
+	}
+	if timelineID != "4d4c0b59-ea83-483f-b8c1-8c360ee53c5c" {
+		t.Errorf("expected timeline_id=4d4c0b59-ea83-483f-b8c1-8c360ee53c5c, got %s", timelineID)
+	}
+
 	// Verify security_rule_index_patterns has 2 rows.
 	var patternCount int
 	err = db.QueryRowContext(ctx, "SELECT count(*) FROM security_rule_index_patterns").Scan(&patternCount)
@@ -2205,6 +5761,143 @@ policy_templates:
 	}
 }
 
+func TestWritePackageWithMalformedSecurityRule(t *testing.T) {
+	// A string risk_score and a non-string tag do not match the expected
+	// types. The rule is still written with the remaining attributes.
+	fsys := fstest.MapFS{
+		"kibana/security_rule/rule.json": {Data: []byte(`{
+  "id": "malformed-rule",
+  "type": "security-rule",
+  "attributes": {
+    "name": "Malformed Rule",
+    "rule_id": "malformed-001",
+    "type": "query",
+    "severity": "low",
+    "risk_score": "21",
+    "index": ["logs-okta.system-*"],
+    "tags": ["Data Source: Okta", 5],
+    "threat": [{"framework": "MITRE ATT&CK", "tactic": {"id": "TA0001", "name": "Initial Access"}}]
+  }
+}`)},
+	}
+
+	db := writeTestPackage(t, fsys)
+	ctx := context.Background()
+
+	var severity string
+	var riskScore sql.NullFloat64
+	err := db.QueryRowContext(ctx,
+		"SELECT severity, risk_score FROM security_rules WHERE rule_id = 'malformed-001'").Scan(&severity, &riskScore)
+	if err != nil {
+		t.Fatalf("querying security_rules: %v", err)
+	}
+	if severity != "low" || riskScore.Valid {
+		t.Errorf("severity = %q, risk_score = %v, want low and NULL", severity, riskScore)
+	}
+
+	for table, want := range map[string]int{
+		"security_rule_index_patterns": 1,
+		"security_rule_tags":           1,
+		"security_rule_threats":        1,
+	} {
+		var n int
+		if err := db.QueryRowContext(ctx, "SELECT count(*) FROM "+table).Scan(&n); err != nil {
+			t.Fatalf("counting %s: %v", table, err)
+		}
+		if n != want {
+			t.Errorf("%s has %d rows, want %d", table, n, want)
+		}
+	}
+}
+
+func TestWritePackageWithExceptionLists(t *testing.T) {
+	fsys := fstest.MapFS{
+		"manifest.yml": {Data: []byte(`
+name: exception-list-test
+title: Exception List Test
+version: 1.0.0
+description: A package with exception lists.
+format_version: 3.5.7
+type: integration
+owner:
+  github: elastic/security-rules
+  type: elastic
+`)},
+		"changelog.yml": {Data: []byte(`
+- version: 1.0.0
+  changes:
+    - description: Initial release
+      type: enhancement
+      link: https://github.com/test/1
+`)},
+		"kibana/exception_list/list.json": {Data: []byte(`{
+  "id": "trusted-admins",
+  "type": "exception-list",
+  "attributes": {
+    "list_id": "trusted-admins",
+    "list_type": "list",
+    "name": "Trusted Admins",
+    "description": "Administrators excluded from detections.",
+    "type": "detection",
+    "namespace_type": "single",
+    "tags": ["Okta"]
+  }
+}`)},
+		"kibana/exception_list_item/item.json": {Data: []byte(`{
+  "id": "trusted-admins-item",
+  "type": "exception-list",
+  "attributes": {
+    "list_id": "trusted-admins",
+    "item_id": "break-glass",
+    "list_type": "item",
+    "name": "Break glass",
+    "description": "Emergency access account.",
+    "type": "simple",
+    "namespace_type": "single",
+    "os_types": ["windows"],
+    "entries": [{"field": "user.name", "operator": "included", "type": "match", "value": "breakglass"}]
+  }
+}`)},
+	}
+
+	pkg, err := pkgreader.Read(".", pkgreader.WithFS(fsys))
+	if err != nil {
+		t.Fatalf("reading package: %v", err)
+	}
+
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	if err := pkgsql.WritePackages(ctx, db, []*pkgreader.Package{pkg}); err != nil {
+		t.Fatalf("writing packages: %v", err)
+	}
+
+	// Items join to their list on list_id.
+	var listTitle, itemTitle, listType, itemType, namespaceType string
+	var tags, osTypes, entries sql.NullString
+	err = db.QueryRowContext(ctx, `
+		SELECT lkso.title, ikso.title, l.type, i.type, i.namespace_type, l.tags, i.os_types, i.entries
+		FROM exception_lists i
+		JOIN kibana_saved_objects ikso ON ikso.id = i.kibana_saved_objects_id
+		JOIN exception_lists l ON l.list_id = i.list_id AND l.list_type = 'list'
+		JOIN kibana_saved_objects lkso ON lkso.id = l.kibana_saved_objects_id
+		WHERE i.list_type = 'item' AND i.item_id = 'break-glass'`).
+		Scan(&listTitle, &itemTitle, &listType, &itemType, &namespaceType, &tags, &osTypes, &entries)
+	if err != nil {
+		t.Fatalf("querying exception_lists: %v", err)
+	}
+
+	got := []string{listTitle, itemTitle, listType, itemType, namespaceType, tags.String, osTypes.String, entries.String}
+	want := []string{
+		"Trusted Admins", "Break glass", "detection", "simple", "single",
+		`["Okta"]`, `["windows"]`,
+		`[{"field":"user.name","operator":"included","type":"match","value":"breakglass"}]`,
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("exception list row = %q, want %q", got, want)
+	}
+}
+
 func TestSecurityRulesFTS(t *testing.T) {
 	ruleJSON := `{
   "id": "fts-test-rule-1",
@@ -0,0 +1,152 @@
+package pkgsql_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/andrewkroh/go-package-spec/pkgreader"
+	"github.com/andrewkroh/go-package-spec/pkgsql"
+)
+
+// syntheticPackage reads an integration package named name with dataStreams
+// data streams, each declaring fieldsPerStream keyword fields and a
+// two-processor ingest pipeline.
+func syntheticPackage(b *testing.B, name string, dataStreams, fieldsPerStream int) *pkgreader.Package {
+	b.Helper()
+
+	fsys := fstest.MapFS{
+		name + "/manifest.yml": {Data: []byte(fmt.Sprintf(`
+name: %s
+title: Benchmark
+version: 1.0.0
+description: Synthetic package for benchmarks.
+format_version: 3.5.7
+type: integration
+owner:
+  github: elastic/integrations
+  type: elastic
+`, name))},
+		name + "/changelog.yml": {Data: []byte(`
+- version: 1.0.0
+  changes:
+    - description: Initial release
+      type: enhancement
+      link: https://github.com/test/1
+`)},
+	}
+	for i := range dataStreams {
+		dir := fmt.Sprintf("%s/data_stream/ds%03d", name, i)
+		fsys[dir+"/manifest.yml"] = &fstest.MapFile{Data: []byte(fmt.Sprintf(`
+title: Data stream %d
+type: logs
+streams:
+  - input: logfile
+    title: Logs
+    description: Collect logs.
+`, i))}
+		fsys[dir+"/elasticsearch/ingest_pipeline/default.yml"] = &fstest.MapFile{Data: []byte(`
+processors:
+  - set:
+      field: event.kind
+      value: event
+  - rename:
+      field: message
+      target_field: event.original
+`)}
+
+		var fields strings.Builder
+		for j := range fieldsPerStream {
+			fmt.Fprintf(&fields, "- name: ds%03d.field%04d\n  type: keyword\n  description: Field %d.\n", i, j, j)
+		}
+		fsys[dir+"/fields/fields.yml"] = &fstest.MapFile{Data: []byte(fields.String())}
+	}
+
+	pkg, err := pkgreader.Read(name, pkgreader.WithFS(fsys))
+	if err != nil {
+		b.Fatalf("reading package %s: %v", name, err)
+	}
+	return pkg
+}
+
+// newBenchDB returns an in-memory database with the schema created.
+func newBenchDB(b *testing.B) *sql.DB {
+	b.Helper()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() { db.Close() })
+
+	for _, ddl := range pkgsql.TableSchemas() {
+		if _, err := db.Exec(ddl); err != nil {
+			b.Fatalf("creating tables: %v", err)
+		}
+	}
+	return db
+}
+
+// benchmarkSizes are the package shapes measured by the write benchmarks.
+var benchmarkSizes = []struct{ dataStreams, fields int }{
+	{1, 100},
+	{10, 100},
+	{10, 1000},
+}
+
+// BenchmarkWritePackage measures writing one package with WritePackage into
+// a database whose tables already exist. Schema creation is not timed.
+func BenchmarkWritePackage(b *testing.B) {
+	ctx := context.Background()
+	for _, size := range benchmarkSizes {
+		b.Run(fmt.Sprintf("data_streams=%d/fields=%d", size.dataStreams, size.fields), func(b *testing.B) {
+			pkg := syntheticPackage(b, "bench", size.dataStreams, size.fields)
+			for b.Loop() {
+				b.StopTimer()
+				db := newBenchDB(b)
+				b.StartTimer()
+
+				if err := pkgsql.WritePackage(ctx, db, pkg); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(size.dataStreams*size.fields*b.N)/b.Elapsed().Seconds(), "fields/s")
+		})
+	}
+}
+
+// BenchmarkWritePackages measures writing a batch of packages with
+// WritePackages, including schema creation and the FTS rebuild, which is
+// the end-to-end cost of building a catalog database.
+func BenchmarkWritePackages(b *testing.B) {
+	ctx := context.Background()
+	const batch = 10
+	for _, size := range benchmarkSizes {
+		b.Run(fmt.Sprintf("packages=%d/data_streams=%d/fields=%d", batch, size.dataStreams, size.fields), func(b *testing.B) {
+			pkgs := make([]*pkgreader.Package, batch)
+			for i := range pkgs {
+				pkgs[i] = syntheticPackage(b, fmt.Sprintf("bench%02d", i), size.dataStreams, size.fields)
+			}
+			for b.Loop() {
+				b.StopTimer()
+				db, err := sql.Open("sqlite", ":memory:")
+				if err != nil {
+					b.Fatal(err)
+				}
+				b.StartTimer()
+
+				if err := pkgsql.WritePackages(ctx, db, pkgs); err != nil {
+					b.Fatal(err)
+				}
+
+				b.StopTimer()
+				db.Close()
+				b.StartTimer()
+			}
+			b.ReportMetric(float64(batch*size.dataStreams*size.fields*b.N)/b.Elapsed().Seconds(), "fields/s")
+		})
+	}
+}
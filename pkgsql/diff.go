@@ -0,0 +1,108 @@
+package pkgsql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/andrewkroh/go-package-spec/internal/semver"
+)
+
+// DBDiff describes how the packages of one database differ from another.
+// Each slice is sorted by package name.
+type DBDiff struct {
+	Added   []PackageVersion       // packages only in the new database
+	Removed []PackageVersion       // packages only in the old database
+	Changed []PackageVersionChange // packages in both with different versions
+}
+
+// PackageVersion identifies a package by name and version.
+type PackageVersion struct {
+	Name    string
+	Version string
+}
+
+// PackageVersionChange records a package whose version differs between two
+// databases.
+type PackageVersionChange struct {
+	Name       string
+	OldVersion string
+	NewVersion string
+}
+
+// Diff compares the packages table of two databases written by
+// WritePackages, treating a as the old database and b as the new one.
+// Packages are matched by name. It reports packages added in b, removed
+// from a, and packages present in both whose version changed.
+//
+// A database may hold several versions of a package, one packages row per
+// version. Diff compares only the latest version of each package, as
+// ordered by semantic version precedence, so older versions kept alongside
+// it do not affect the result.
+//
+// WithTablePrefix selects the packages table of databases written with the
+// same option; other options are ignored.
+func Diff(ctx context.Context, a, b *sql.DB, opts ...Option) (*DBDiff, error) {
+	cfg := &writeConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if err := checkTablePrefix(cfg.tablePrefix); err != nil {
+		return nil, err
+	}
+
+	oldVersions, err := packageVersions(ctx, a, cfg.tablePrefix)
+	if err != nil {
+		return nil, fmt.Errorf("reading old packages: %w", err)
+	}
+	newVersions, err := packageVersions(ctx, b, cfg.tablePrefix)
+	if err != nil {
+		return nil, fmt.Errorf("reading new packages: %w", err)
+	}
+
+	diff := &DBDiff{}
+	for _, name := range slices.Sorted(maps.Keys(newVersions)) {
+		newVersion := newVersions[name]
+		oldVersion, ok := oldVersions[name]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, PackageVersion{Name: name, Version: newVersion})
+		case oldVersion != newVersion:
+			diff.Changed = append(diff.Changed, PackageVersionChange{
+				Name:       name,
+				OldVersion: oldVersion,
+				NewVersion: newVersion,
+			})
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(oldVersions)) {
+		if _, ok := newVersions[name]; !ok {
+			diff.Removed = append(diff.Removed, PackageVersion{Name: name, Version: oldVersions[name]})
+		}
+	}
+	return diff, nil
+}
+
+// packageVersions returns the latest version of each package in db keyed
+// by name, reading the packages table with the given prefix.
+func packageVersions(ctx context.Context, db *sql.DB, prefix string) (map[string]string, error) {
+	rows, err := db.QueryContext(ctx, prefixTables("SELECT name, version FROM packages", prefix))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := map[string]string{}
+	for rows.Next() {
+		var name, version string
+		if err := rows.Scan(&name, &version); err != nil {
+			return nil, err
+		}
+		if cur, ok := versions[name]; !ok || semver.Compare(version, cur) > 0 {
+			versions[name] = version
+		}
+	}
+	return versions, rows.Err()
+}
@@ -0,0 +1,103 @@
+package pkgsql_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/andrewkroh/go-package-spec/pkgreader"
+	"github.com/andrewkroh/go-package-spec/pkgsql"
+)
+
+// writeCatalog writes one minimal integration package per name and version
+// into a new database. Each package is read from a <name>-<version>
+// directory, so a database can hold several versions of a package.
+func writeCatalog(t *testing.T, packages ...string) *sql.DB {
+	t.Helper()
+
+	var pkgs []*pkgreader.Package
+	for i := 0; i < len(packages); i += 2 {
+		name, version := packages[i], packages[i+1]
+		dir := name + "-" + version
+		fsys := fstest.MapFS{
+			dir + "/manifest.yml": {Data: []byte(fmt.Sprintf(`
+name: %s
+title: Test
+version: %s
+description: test
+format_version: 3.5.7
+type: integration
+owner:
+  github: elastic/integrations
+  type: elastic
+`, name, version))},
+			dir + "/changelog.yml": {Data: []byte(fmt.Sprintf(`
+- version: %s
+  changes:
+    - description: Release
+      type: enhancement
+      link: https://github.com/test/1
+`, version))},
+		}
+		pkg, err := pkgreader.Read(dir, pkgreader.WithFS(fsys))
+		if err != nil {
+			t.Fatalf("reading package %s: %v", dir, err)
+		}
+		pkgs = append(pkgs, pkg)
+	}
+
+	db := newTestDB(t)
+	if err := pkgsql.WritePackages(context.Background(), db, pkgs); err != nil {
+		t.Fatalf("writing packages: %v", err)
+	}
+	return db
+}
+
+func TestDiff(t *testing.T) {
+	old := writeCatalog(t, "aws", "2.0.0", "nginx", "1.0.0", "okta", "3.1.0")
+	cur := writeCatalog(t, "aws", "2.1.0", "nginx", "1.0.0", "zeek", "0.5.0")
+
+	diff, err := pkgsql.Diff(context.Background(), old, cur)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &pkgsql.DBDiff{
+		Added:   []pkgsql.PackageVersion{{Name: "zeek", Version: "0.5.0"}},
+		Removed: []pkgsql.PackageVersion{{Name: "okta", Version: "3.1.0"}},
+		Changed: []pkgsql.PackageVersionChange{{Name: "aws", OldVersion: "2.0.0", NewVersion: "2.1.0"}},
+	}
+	if !reflect.DeepEqual(diff, want) {
+		t.Errorf("Diff() = %+v, want %+v", diff, want)
+	}
+
+	diff, err = pkgsql.Diff(context.Background(), cur, cur)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(diff, &pkgsql.DBDiff{}) {
+		t.Errorf("Diff() of identical databases = %+v, want empty", diff)
+	}
+}
+
+func TestDiffMultipleVersions(t *testing.T) {
+	// Each database holds several versions of aws. Only the latest one is
+	// compared, regardless of the order the rows were written.
+	old := writeCatalog(t, "aws", "2.0.0", "aws", "1.9.0", "nginx", "1.0.0")
+	cur := writeCatalog(t, "aws", "2.10.0", "aws", "2.9.0", "nginx", "1.0.0-beta", "nginx", "1.0.0")
+
+	diff, err := pkgsql.Diff(context.Background(), old, cur)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &pkgsql.DBDiff{
+		Changed: []pkgsql.PackageVersionChange{{Name: "aws", OldVersion: "2.0.0", NewVersion: "2.10.0"}},
+	}
+	if !reflect.DeepEqual(diff, want) {
+		t.Errorf("Diff() = %+v, want %+v", diff, want)
+	}
+}
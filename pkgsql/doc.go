@@ -16,6 +16,6 @@
 // SQLite driver. The consumer must import a driver (e.g. modernc.org/sqlite)
 // and pass a *sql.DB.
 //
-//go:generate go run ../cmd/gensql -tables ../cmd/gensql/tables.yml -output . -package pkgsql
+//go:generate go run ../cmd/gensql -tables ../cmd/gensql/tables.yml -output . -package pkgsql -time-check
 //go:generate go run github.com/sqlc-dev/sqlc/cmd/sqlc@v1.29.0 generate -f internal/db/sqlc.yaml
 package pkgsql
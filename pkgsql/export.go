@@ -0,0 +1,133 @@
+package pkgsql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// exportLine is one line of [ExportJSONL] output.
+type exportLine struct {
+	Table string         `json:"table"`
+	Row   map[string]any `json:"row"`
+}
+
+// ExportJSONL writes every row of every user table in db to w as
+// newline-delimited JSON. Each line has the form
+//
+//	{"table":"packages","row":{...}}
+//
+// Tables are exported in name order. FTS5 virtual tables and their shadow
+// tables are skipped. Values of JSON columns are embedded as parsed JSON
+// rather than as strings.
+func ExportJSONL(ctx context.Context, db *sql.DB, w io.Writer) error {
+	tables, err := userTables(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(w)
+	for _, table := range tables {
+		if err := exportTable(ctx, db, enc, table); err != nil {
+			return fmt.Errorf("exporting table %s: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// userTables returns the names of all user tables in name order, excluding
+// virtual tables and the shadow tables SQLite creates for them.
+func userTables(ctx context.Context, db *sql.DB) ([]string, error) {
+	rows, err := db.QueryContext(ctx,
+		"SELECT name, COALESCE(sql, '') FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name")
+	if err != nil {
+		return nil, fmt.Errorf("listing tables: %w", err)
+	}
+	defer rows.Close()
+
+	var names, virtual []string
+	for rows.Next() {
+		var name, ddl string
+		if err := rows.Scan(&name, &ddl); err != nil {
+			return nil, fmt.Errorf("listing tables: %w", err)
+		}
+		if strings.HasPrefix(ddl, "CREATE VIRTUAL TABLE") {
+			virtual = append(virtual, name)
+			continue
+		}
+		names = append(names, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("listing tables: %w", err)
+	}
+
+	// Drop shadow tables such as docs_fts_data and docs_fts_config.
+	tables := names[:0]
+	for _, name := range names {
+		shadow := false
+		for _, v := range virtual {
+			if strings.HasPrefix(name, v+"_") {
+				shadow = true
+				break
+			}
+		}
+		if !shadow {
+			tables = append(tables, name)
+		}
+	}
+	return tables, nil
+}
+
+// jsonColumns returns the set of columns in table declared with type JSON.
+func jsonColumns(ctx context.Context, db *sql.DB, table string) (map[string]bool, error) {
+	rows, err := db.QueryContext(ctx, "SELECT name, type FROM pragma_table_info(?)", table)
+	if err != nil {
+		return nil, fmt.Errorf("reading column types: %w", err)
+	}
+	defer rows.Close()
+
+	cols := map[string]bool{}
+	for rows.Next() {
+		var name, typ string
+		if err := rows.Scan(&name, &typ); err != nil {
+			return nil, fmt.Errorf("reading column types: %w", err)
+		}
+		if strings.EqualFold(typ, "JSON") {
+			cols[name] = true
+		}
+	}
+	return cols, rows.Err()
+}
+
+func exportTable(ctx context.Context, db *sql.DB, enc *json.Encoder, table string) error {
+	jsonCols, err := jsonColumns(ctx, db, table)
+	if err != nil {
+		return err
+	}
+
+	rs, err := QueryRows(ctx, db, `SELECT * FROM "`+table+`"`)
+	if err != nil {
+		return err
+	}
+
+	for row := range rs.All() {
+		for col, v := range row {
+			// Some drivers return TEXT as []byte, which encoding/json
+			// would otherwise emit as base64.
+			if b, ok := v.([]byte); ok {
+				v = string(b)
+				row[col] = v
+			}
+			if s, ok := v.(string); ok && jsonCols[col] && json.Valid([]byte(s)) {
+				row[col] = json.RawMessage(s)
+			}
+		}
+		if err := enc.Encode(exportLine{Table: table, Row: row}); err != nil {
+			return err
+		}
+	}
+	return rs.Err()
+}
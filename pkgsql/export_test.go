@@ -0,0 +1,109 @@
+package pkgsql_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/andrewkroh/go-package-spec/pkgreader"
+	"github.com/andrewkroh/go-package-spec/pkgsql"
+)
+
+func TestExportJSONL(t *testing.T) {
+	fsys := fstest.MapFS{
+		"manifest.yml": {Data: []byte(`
+name: export-test
+title: Export Test
+version: 1.2.3
+description: test
+format_version: 3.5.7
+type: integration
+owner:
+  github: elastic/integrations
+  type: elastic
+elasticsearch:
+  privileges:
+    cluster:
+      - monitor
+`)},
+		"changelog.yml": {Data: []byte(`
+- version: 1.2.3
+  changes:
+    - description: Initial release
+      type: enhancement
+      link: https://github.com/test/1
+`)},
+		"docs/README.md": {Data: []byte("# Export Test\n")},
+	}
+
+	pkg, err := pkgreader.Read(".", pkgreader.WithFS(fsys))
+	if err != nil {
+		t.Fatalf("reading package: %v", err)
+	}
+
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	if err := pkgsql.WritePackages(ctx, db, []*pkgreader.Package{pkg}); err != nil {
+		t.Fatalf("writing packages: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := pkgsql.ExportJSONL(ctx, db, &buf); err != nil {
+		t.Fatalf("exporting: %v", err)
+	}
+
+	type line struct {
+		Table string                     `json:"table"`
+		Row   map[string]json.RawMessage `json:"row"`
+	}
+
+	var pkgRow map[string]json.RawMessage
+	tables := map[string]int{}
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var l line
+		if err := json.Unmarshal(scanner.Bytes(), &l); err != nil {
+			t.Fatalf("invalid JSONL line %q: %v", scanner.Text(), err)
+		}
+		tables[l.Table]++
+		if l.Table == "packages" {
+			pkgRow = l.Row
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	for table := range tables {
+		if strings.Contains(table, "_fts") {
+			t.Errorf("unexpected FTS table %q in export", table)
+		}
+	}
+	if tables["docs"] != 1 {
+		t.Errorf("docs rows = %d, want 1", tables["docs"])
+	}
+
+	if pkgRow == nil {
+		t.Fatal("no packages row in export")
+	}
+	if got := string(pkgRow["name"]); got != `"export-test"` {
+		t.Errorf("packages.name = %s, want \"export-test\"", got)
+	}
+	if got := string(pkgRow["version"]); got != `"1.2.3"` {
+		t.Errorf("packages.version = %s, want \"1.2.3\"", got)
+	}
+
+	// JSON columns are embedded as JSON, not as quoted strings.
+	var cluster []string
+	if err := json.Unmarshal(pkgRow["elasticsearch_privileges_cluster"], &cluster); err != nil {
+		t.Fatalf("elasticsearch_privileges_cluster is not embedded JSON: %s", pkgRow["elasticsearch_privileges_cluster"])
+	}
+	if len(cluster) != 1 || cluster[0] != "monitor" {
+		t.Errorf("elasticsearch_privileges_cluster = %v, want [monitor]", cluster)
+	}
+}
@@ -0,0 +1,58 @@
+package pkgsql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// FKViolation describes a row whose foreign key references a missing parent
+// row, as reported by SQLite's PRAGMA foreign_key_check.
+type FKViolation struct {
+	Table  string // table containing the orphaned row
+	RowID  int64  // rowid of the orphaned row
+	Parent string // table the foreign key refers to
+	FKID   int64  // index of the foreign key constraint within Table
+}
+
+// EnableForeignKeys turns on foreign key enforcement. SQLite leaves foreign
+// keys unenforced unless enabled, so inserts that reference a missing parent
+// row would otherwise succeed silently.
+//
+// The setting applies per connection and cannot be changed inside a
+// transaction. For a pooled *sql.DB it only covers the connection it runs on;
+// limit the pool to one connection (db.SetMaxOpenConns(1)) or enable foreign
+// keys in the driver DSN to cover every connection.
+func EnableForeignKeys(ctx context.Context, db *sql.DB) error {
+	if _, err := db.ExecContext(ctx, "PRAGMA foreign_keys = ON"); err != nil {
+		return fmt.Errorf("enabling foreign keys: %w", err)
+	}
+	return nil
+}
+
+// CheckForeignKeys runs PRAGMA foreign_key_check over the whole database and
+// returns every row that references a missing parent. It returns nil when
+// all foreign keys are satisfied. This works regardless of whether
+// enforcement is enabled, so it can validate a database after loading.
+func CheckForeignKeys(ctx context.Context, db *sql.DB) ([]FKViolation, error) {
+	rows, err := db.QueryContext(ctx, "PRAGMA foreign_key_check")
+	if err != nil {
+		return nil, fmt.Errorf("checking foreign keys: %w", err)
+	}
+	defer rows.Close()
+
+	var violations []FKViolation
+	for rows.Next() {
+		var v FKViolation
+		var rowID sql.NullInt64
+		if err := rows.Scan(&v.Table, &rowID, &v.Parent, &v.FKID); err != nil {
+			return nil, fmt.Errorf("scanning foreign key violation: %w", err)
+		}
+		v.RowID = rowID.Int64
+		violations = append(violations, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("checking foreign keys: %w", err)
+	}
+	return violations, nil
+}
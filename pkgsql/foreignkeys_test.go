@@ -0,0 +1,55 @@
+package pkgsql_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/andrewkroh/go-package-spec/pkgsql"
+)
+
+func TestCheckForeignKeys(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	db.SetMaxOpenConns(1)
+
+	for _, ddl := range pkgsql.TableSchemas() {
+		if _, err := db.ExecContext(ctx, ddl); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	violations, err := pkgsql.CheckForeignKeys(ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(violations) != 0 {
+		t.Fatalf("empty database has violations: %+v", violations)
+	}
+
+	// Foreign keys are not enforced by default, so an orphan can be inserted.
+	res, err := db.ExecContext(ctx, `INSERT INTO changelogs (packages_id, version) VALUES (999, '1.0.0')`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rowID, err := res.LastInsertId()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	violations, err = pkgsql.CheckForeignKeys(ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := pkgsql.FKViolation{Table: "changelogs", RowID: rowID, Parent: "packages"}
+	if len(violations) != 1 || violations[0] != want {
+		t.Fatalf("violations = %+v, want [%+v]", violations, want)
+	}
+
+	// Once enabled, inserting another orphan fails.
+	if err := pkgsql.EnableForeignKeys(ctx, db); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.ExecContext(ctx, `INSERT INTO changelogs (packages_id, version) VALUES (998, '1.0.0')`); err == nil {
+		t.Fatal("expected foreign key error inserting orphan with enforcement enabled")
+	}
+}
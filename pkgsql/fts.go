@@ -3,8 +3,15 @@ package pkgsql
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"strings"
 )
 
+// defaultFTSTokenizer is the FTS5 tokenizer used unless WithFTSTokenizer
+// selects another. It stems English words, so "failed" matches "fail", but
+// only matches whole tokens.
+const defaultFTSTokenizer = "porter unicode61"
+
 // docsFTS is the FTS5 virtual table for full-text search over doc content.
 // Uses external content mode: reads content from the docs table, stores
 // only the inverted index. After bulk inserts, rebuild with:
@@ -14,7 +21,7 @@ const docsFTS = `CREATE VIRTUAL TABLE IF NOT EXISTS docs_fts USING fts5(
   content,
   content=docs,
   content_rowid=id,
-  tokenize='porter unicode61'
+  tokenize=%s
 )`
 
 // changelogEntriesFTS is the FTS5 virtual table for full-text search over
@@ -25,7 +32,20 @@ const changelogEntriesFTS = `CREATE VIRTUAL TABLE IF NOT EXISTS changelog_entrie
   description,
   content=changelog_entries,
   content_rowid=id,
-  tokenize='porter unicode61'
+  tokenize=%s
+)`
+
+// varsFTS is the FTS5 virtual table for full-text search over var names,
+// titles, and descriptions. Uses external content mode against the vars
+// table. Join back to a package through the var link tables (package_vars,
+// policy_template_vars, policy_template_input_vars, stream_vars).
+const varsFTS = `CREATE VIRTUAL TABLE IF NOT EXISTS vars_fts USING fts5(
+  name,
+  title,
+  description,
+  content=vars,
+  content_rowid=id,
+  tokenize=%s
 )`
 
 // securityRulesFTSView is a view joining security_rules with
@@ -55,22 +75,90 @@ const securityRulesFTS = `CREATE VIRTUAL TABLE IF NOT EXISTS security_rules_fts
   note,
   content=security_rules_fts_content,
   content_rowid=id,
-  tokenize='porter unicode61'
+  tokenize=%s
+)`
+
+// policyTemplatesFTSView is a view providing the content source for FTS5
+// indexing of policy templates. The descriptions of a template's inputs are
+// concatenated into one column so that a template is found by the
+// capabilities of its inputs (e.g. "Collect logs via the API").
+const policyTemplatesFTSView = `CREATE VIEW IF NOT EXISTS policy_templates_fts_content AS
+SELECT
+  pt.id AS id,
+  pt.name AS name,
+  pt.title AS title,
+  pt.description AS description,
+  COALESCE((
+    SELECT GROUP_CONCAT(pti.title || ' ' || pti.description, ' ')
+    FROM policy_template_inputs pti
+    WHERE pti.policy_templates_id = pt.id
+  ), '') AS inputs
+FROM policy_templates pt`
+
+// policyTemplatesFTS is the FTS5 virtual table for full-text search over
+// policy templates. Uses external content mode backed by the
+// policy_templates_fts_content view. Indexes the template name, title, and
+// description, and the titles and descriptions of its inputs. Join back to
+// the package through policy_templates.packages_id.
+const policyTemplatesFTS = `CREATE VIRTUAL TABLE IF NOT EXISTS policy_templates_fts USING fts5(
+  name,
+  title,
+  description,
+  inputs,
+  content=policy_templates_fts_content,
+  content_rowid=id,
+  tokenize=%s
 )`
 
-var ftsSchemas = []string{docsFTS, changelogEntriesFTS, securityRulesFTSView, securityRulesFTS}
+// ftsSchemas returns the FTS5 virtual table statements and the views backing
+// the security rules and policy templates indexes, with each virtual table
+// using tokenizer.
+func ftsSchemas(tokenizer string) []string {
+	tokenize := "'" + strings.ReplaceAll(tokenizer, "'", "''") + "'"
+	return []string{
+		fmt.Sprintf(docsFTS, tokenize),
+		fmt.Sprintf(changelogEntriesFTS, tokenize),
+		fmt.Sprintf(varsFTS, tokenize),
+		securityRulesFTSView,
+		fmt.Sprintf(securityRulesFTS, tokenize),
+		policyTemplatesFTSView,
+		fmt.Sprintf(policyTemplatesFTS, tokenize),
+	}
+}
+
+// ftsTables lists the FTS5 virtual tables created by ftsSchemas.
+var ftsTables = []string{"docs_fts", "changelog_entries_fts", "vars_fts", "security_rules_fts", "policy_templates_fts"}
 
 // RebuildFTS rebuilds all FTS5 full-text search indexes (docs, changelog
-// entries, and security rules). WritePackages calls this automatically after
-// all packages are inserted. Callers using WritePackage directly must call
-// this after all inserts are complete.
-func RebuildFTS(ctx context.Context, db *sql.DB) error {
-	for _, stmt := range []string{
-		"INSERT INTO docs_fts(docs_fts) VALUES('rebuild')",
-		"INSERT INTO changelog_entries_fts(changelog_entries_fts) VALUES('rebuild')",
-		"INSERT INTO security_rules_fts(security_rules_fts) VALUES('rebuild')",
-	} {
-		if _, err := db.ExecContext(ctx, stmt); err != nil {
+// entries, vars, security rules, and policy templates). WritePackages calls
+// this automatically after all packages are inserted. Callers using
+// WritePackage directly must call this after all inserts are complete,
+// passing the same WithTablePrefix option if one was used. Other options
+// are ignored. FTS5 tables that do not exist, for example because the
+// schema was created with WithoutFTS, are skipped.
+func RebuildFTS(ctx context.Context, db *sql.DB, opts ...Option) error {
+	cfg := &writeConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if err := checkTablePrefix(cfg.tablePrefix); err != nil {
+		return err
+	}
+
+	for _, table := range ftsTables {
+		table = cfg.tablePrefix + table
+
+		var n int
+		err := db.QueryRowContext(ctx,
+			"SELECT count(*) FROM sqlite_master WHERE type = 'table' AND name = ?", table).Scan(&n)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			continue
+		}
+
+		if _, err := db.ExecContext(ctx, "INSERT INTO "+table+"("+table+") VALUES('rebuild')"); err != nil {
 			return err
 		}
 	}
@@ -0,0 +1,13 @@
+package pkgsql
+
+// dataStreamsTypeIndex supports filtering and grouping data streams by their
+// type (logs, metrics, traces, synthetics, profiles).
+//
+// Example:
+//
+//	SELECT type, COUNT(*) FROM data_streams GROUP BY type
+const dataStreamsTypeIndex = `CREATE INDEX IF NOT EXISTS data_streams_type_idx ON data_streams (type)`
+
+// indexSchemas contains the CREATE INDEX statements for columns that are
+// commonly used as query filters.
+var indexSchemas = []string{dataStreamsTypeIndex}
@@ -99,8 +99,9 @@ func timeNullString(t *time.Time) sql.NullString {
 }
 
 // mapFieldsParams converts a FlatField to db.InsertFieldsParams.
-func mapFieldsParams(v *pkgspec.FlatField) db.InsertFieldsParams {
+func mapFieldsParams(v *pkgspec.FlatField, allowedValues any) db.InsertFieldsParams {
 	return db.InsertFieldsParams{
+		AllowedValues:         allowedValues,
 		Analyzer:              toNullString(v.Analyzer),
 		CopyTo:                toNullString(v.CopyTo),
 		DateFormat:            toNullString(v.DateFormat),
@@ -146,20 +147,24 @@ func mapFieldsParams(v *pkgspec.FlatField) db.InsertFieldsParams {
 }
 
 // mapPackagesParams converts a Manifest to db.InsertPackagesParams.
-func mapPackagesParams(v *pkgspec.Manifest, agentPrivilegesRoot sql.NullBool, commitId sql.NullString, conditionsAgentVersion sql.NullString, conditionsElasticSubscription sql.NullString, conditionsKibanaVersion sql.NullString, dirName string, elasticsearchPrivilegesCluster any, policyTemplatesBehavior sql.NullString) db.InsertPackagesParams {
+func mapPackagesParams(v *pkgspec.Manifest, agentPrivilegesRoot sql.NullBool, commitId sql.NullString, conditionsAgentVersion sql.NullString, conditionsElasticCapabilities any, conditionsElasticSubscription sql.NullString, conditionsKibanaVersion sql.NullString, deprecatedEntityCount int64, dirName string, elasticsearchPrivilegesCluster any, elasticsearchPrivilegesIndex any, importedAt string, policyTemplatesBehavior sql.NullString) db.InsertPackagesParams {
 	return db.InsertPackagesParams{
 		AgentPrivilegesRoot:            agentPrivilegesRoot,
 		CommitID:                       commitId,
 		ConditionsAgentVersion:         conditionsAgentVersion,
+		ConditionsElasticCapabilities:  conditionsElasticCapabilities,
 		ConditionsElasticSubscription:  conditionsElasticSubscription,
 		ConditionsKibanaVersion:        conditionsKibanaVersion,
+		DeprecatedEntityCount:          deprecatedEntityCount,
 		Description:                    v.Description,
 		DirName:                        dirName,
 		ElasticsearchPrivilegesCluster: elasticsearchPrivilegesCluster,
+		ElasticsearchPrivilegesIndex:   elasticsearchPrivilegesIndex,
 		FileColumn:                     toNullInt64(v.Column()),
 		FileLine:                       toNullInt64(v.Line()),
 		FilePath:                       toNullString(v.FilePath()),
 		FormatVersion:                  v.FormatVersion,
+		ImportedAt:                     importedAt,
 		Name:                           v.Name,
 		OwnerGithub:                    v.Owner.Github,
 		OwnerType:                      string(v.Owner.Type),
@@ -196,7 +201,7 @@ func mapChangelogsParams(v *pkgspec.Changelog, parentID int64) db.InsertChangelo
 }
 
 // mapChangelogEntriesParams converts a ChangelogEntry to db.InsertChangelogEntriesParams.
-func mapChangelogEntriesParams(v *pkgspec.ChangelogEntry, parentID int64) db.InsertChangelogEntriesParams {
+func mapChangelogEntriesParams(v *pkgspec.ChangelogEntry, parentID int64, ordinal int64, version string) db.InsertChangelogEntriesParams {
 	return db.InsertChangelogEntriesParams{
 		ChangelogsID: parentID,
 		Description:  v.Description,
@@ -204,16 +209,19 @@ func mapChangelogEntriesParams(v *pkgspec.ChangelogEntry, parentID int64) db.Ins
 		FileLine:     toNullInt64(v.Line()),
 		FilePath:     toNullString(v.FilePath()),
 		Link:         v.Link,
+		Ordinal:      ordinal,
 		Type:         string(v.Type),
+		Version:      version,
 	}
 }
 
 // mapDataStreamsParams converts a DataStreamManifest to db.InsertDataStreamsParams.
-func mapDataStreamsParams(v *pkgspec.DataStreamManifest, parentID int64, dirName string) db.InsertDataStreamsParams {
+func mapDataStreamsParams(v *pkgspec.DataStreamManifest, parentID int64, dirName string, effectiveDataset string, indexTemplateName sql.NullString, streamCount int64) db.InsertDataStreamsParams {
 	return db.InsertDataStreamsParams{
 		Dataset:                       toNullString(v.Dataset),
 		DatasetIsPrefix:               toNullBool(v.DatasetIsPrefix),
 		DirName:                       dirName,
+		EffectiveDataset:              effectiveDataset,
 		ElasticsearchDynamicDataset:   toNullBool(v.Elasticsearch.DynamicDataset),
 		ElasticsearchDynamicNamespace: toNullBool(v.Elasticsearch.DynamicNamespace),
 		ElasticsearchIndexMode:        toNullString(string(v.Elasticsearch.IndexMode)),
@@ -224,11 +232,14 @@ func mapDataStreamsParams(v *pkgspec.DataStreamManifest, parentID int64, dirName
 		FileLine:                      toNullInt64(v.Line()),
 		FilePath:                      toNullString(v.FilePath()),
 		GithubCodeOwner:               toNullString(v.GithubCodeOwner),
+		GithubCodeOwners:              jsonNullString(v.GithubCodeOwners),
 		Hidden:                        toNullBool(v.Hidden),
 		IlmPolicy:                     toNullString(v.ILMPolicy),
+		IndexTemplateName:             indexTemplateName,
 		PackagesID:                    parentID,
 		ProviderPermissions:           jsonNullString(v.ProviderPermissions),
 		Release:                       toNullString(string(v.Release)),
+		StreamCount:                   streamCount,
 		Title:                         v.Title,
 		Type:                          toNullString(string(v.Type)),
 	}
@@ -270,10 +281,11 @@ func mapPackageScreenshotsParams(v *pkgspec.Screenshot, parentID int64) db.Inser
 }
 
 // mapPolicyTemplatesParams converts a PolicyTemplate to db.InsertPolicyTemplatesParams.
-func mapPolicyTemplatesParams(v *pkgspec.PolicyTemplate, parentID int64, dynamicSignalTypes sql.NullBool, input sql.NullString, policyTemplateType sql.NullString, templatePath sql.NullString) db.InsertPolicyTemplatesParams {
+func mapPolicyTemplatesParams(v *pkgspec.PolicyTemplate, parentID int64, deploymentModes any, dynamicSignalTypes sql.NullBool, input sql.NullString, inputCount int64, policyTemplateType sql.NullString, templatePath sql.NullString, varCount int64) db.InsertPolicyTemplatesParams {
 	return db.InsertPolicyTemplatesParams{
 		ConfigurationLinks:                              jsonNullString(v.ConfigurationLinks),
 		DataStreams:                                     jsonNullString(v.DataStreams),
+		DeploymentModes:                                 deploymentModes,
 		DeploymentModesAgentlessDivision:                toNullString(v.DeploymentModes.Agentless.Division),
 		DeploymentModesAgentlessEnabled:                 toNullBool(v.DeploymentModes.Agentless.Enabled),
 		DeploymentModesAgentlessIsDefault:               toNullBool(v.DeploymentModes.Agentless.IsDefault),
@@ -290,6 +302,7 @@ func mapPolicyTemplatesParams(v *pkgspec.PolicyTemplate, parentID int64, dynamic
 		FilePath:                                        toNullString(v.FilePath()),
 		FipsCompatible:                                  toNullBool(v.FipsCompatible),
 		Input:                                           input,
+		InputCount:                                      inputCount,
 		Multiple:                                        toNullBool(v.Multiple),
 		Name:                                            v.Name,
 		PackagesID:                                      parentID,
@@ -297,6 +310,7 @@ func mapPolicyTemplatesParams(v *pkgspec.PolicyTemplate, parentID int64, dynamic
 		ProviderPermissions:                             jsonNullString(v.ProviderPermissions),
 		TemplatePath:                                    templatePath,
 		Title:                                           v.Title,
+		VarCount:                                        varCount,
 	}
 }
 
@@ -469,10 +483,11 @@ func mapTagsParams(v *pkgspec.Tag, parentID int64) db.InsertTagsParams {
 }
 
 // mapTransformsParams converts a Transform to db.InsertTransformsParams.
-func mapTransformsParams(v *pkgspec.Transform, parentID int64, dirName string, manifestDestinationIndexTemplate any, manifestStart sql.NullBool) db.InsertTransformsParams {
+func mapTransformsParams(v *pkgspec.Transform, parentID int64, destIndex sql.NullString, dirName string, manifestDestinationIndexTemplate any, manifestStart sql.NullBool, retentionMaxAge sql.NullString) db.InsertTransformsParams {
 	return db.InsertTransformsParams{
 		Description:                      toNullString(v.Description),
 		Dest:                             jsonNullString(v.Dest),
+		DestIndex:                        destIndex,
 		DirName:                          dirName,
 		FileColumn:                       toNullInt64(v.Column()),
 		FileLine:                         toNullInt64(v.Line()),
@@ -484,6 +499,7 @@ func mapTransformsParams(v *pkgspec.Transform, parentID int64, dirName string, m
 		Meta:                             jsonNullString(v.Meta),
 		PackagesID:                       parentID,
 		Pivot:                            jsonNullString(v.Pivot),
+		RetentionMaxAge:                  retentionMaxAge,
 		RetentionPolicy:                  jsonNullString(v.RetentionPolicy),
 		Settings:                         jsonNullString(v.Settings),
 		Source:                           jsonNullString(v.Source),
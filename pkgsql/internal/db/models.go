@@ -39,6 +39,8 @@ type Changelog struct {
 type ChangelogEntry struct {
 	ID           int64
 	ChangelogsID int64
+	Ordinal      int64
+	Version      string
 	FilePath     sql.NullString
 	FileLine     sql.NullInt64
 	FileColumn   sql.NullInt64
@@ -51,6 +53,9 @@ type DataStream struct {
 	ID                            int64
 	PackagesID                    int64
 	DirName                       string
+	EffectiveDataset              string
+	IndexTemplateName             sql.NullString
+	StreamCount                   int64
 	FilePath                      sql.NullString
 	FileLine                      sql.NullInt64
 	FileColumn                    sql.NullInt64
@@ -69,6 +74,7 @@ type DataStream struct {
 	Title                         string
 	Type                          sql.NullString
 	GithubCodeOwner               sql.NullString
+	GithubCodeOwners              interface{}
 }
 
 type DataStreamField struct {
@@ -93,6 +99,12 @@ type Deprecation struct {
 	VarsID                   sql.NullInt64
 }
 
+type DiscoveryDataset struct {
+	ID         int64
+	Name       string
+	PackagesID int64
+}
+
 type DiscoveryField struct {
 	ID         int64
 	Name       string
@@ -105,10 +117,25 @@ type Doc struct {
 	ContentType string
 	FilePath    string
 	PackagesID  int64
+	Truncated   sql.NullBool
+}
+
+type ExceptionList struct {
+	ID                   int64
+	Entries              interface{}
+	ItemID               sql.NullString
+	KibanaSavedObjectsID int64
+	ListID               string
+	ListType             string
+	NamespaceType        sql.NullString
+	OsTypes              interface{}
+	Tags                 interface{}
+	Type                 sql.NullString
 }
 
 type Field struct {
 	ID                    int64
+	AllowedValues         interface{}
 	FilePath              sql.NullString
 	FileLine              sql.NullInt64
 	FileColumn            sql.NullInt64
@@ -202,6 +229,8 @@ type KibanaSavedObject struct {
 	ObjectID             string
 	ObjectType           sql.NullString
 	PackagesID           int64
+	PanelsCount          sql.NullInt64
+	Raw                  interface{}
 	ReferenceCount       int64
 	Title                sql.NullString
 	TypeMigrationVersion sql.NullString
@@ -212,10 +241,14 @@ type Package struct {
 	AgentPrivilegesRoot            sql.NullBool
 	CommitID                       sql.NullString
 	ConditionsAgentVersion         sql.NullString
+	ConditionsElasticCapabilities  interface{}
 	ConditionsElasticSubscription  sql.NullString
 	ConditionsKibanaVersion        sql.NullString
+	DeprecatedEntityCount          int64
 	DirName                        string
 	ElasticsearchPrivilegesCluster interface{}
+	ElasticsearchPrivilegesIndex   interface{}
+	ImportedAt                     string
 	PolicyTemplatesBehavior        sql.NullString
 	FilePath                       sql.NullString
 	FileLine                       sql.NullInt64
@@ -237,6 +270,15 @@ type PackageCategory struct {
 	PackageID int64
 }
 
+type PackageDependency struct {
+	ID             int64
+	ImportMappings sql.NullBool
+	Name           string
+	PackagesID     int64
+	Reference      sql.NullString
+	Version        sql.NullString
+}
+
 type PackageField struct {
 	ID        int64
 	FieldID   int64
@@ -253,6 +295,16 @@ type PackageIcon struct {
 	Type       sql.NullString
 }
 
+type PackageMetric struct {
+	ID                int64
+	DataStreamCount   int64
+	FieldCount        int64
+	KibanaObjectCount int64
+	PackagesID        int64
+	PipelineCount     int64
+	ProcessorCount    int64
+}
+
 type PackageScreenshot struct {
 	ID         int64
 	PackagesID int64
@@ -273,7 +325,9 @@ type PipelineTest struct {
 	ConfigPath           sql.NullString
 	DataStreamsID        int64
 	DynamicFields        interface{}
+	Event                sql.NullString
 	EventPath            string
+	Expected             interface{}
 	ExpectedPath         sql.NullString
 	Fields               interface{}
 	Format               string
@@ -288,10 +342,13 @@ type PipelineTest struct {
 type PolicyTemplate struct {
 	ID                                              int64
 	PackagesID                                      int64
+	DeploymentModes                                 interface{}
 	DynamicSignalTypes                              sql.NullBool
 	Input                                           sql.NullString
+	InputCount                                      int64
 	PolicyTemplateType                              sql.NullString
 	TemplatePath                                    sql.NullString
+	VarCount                                        int64
 	FilePath                                        sql.NullString
 	FileLine                                        sql.NullInt64
 	FileColumn                                      sql.NullInt64
@@ -388,6 +445,13 @@ type PolicyTest struct {
 	Vars            interface{}
 }
 
+type ProcessorTypeCount struct {
+	ID         int64
+	Count      int64
+	PackagesID int64
+	Type       string
+}
+
 type RoutingRule struct {
 	ID            int64
 	DataStreamsID int64
@@ -406,6 +470,12 @@ type SampleEvent struct {
 	Name          sql.NullString
 }
 
+type SampleEventField struct {
+	ID             int64
+	Path           string
+	SampleEventsID int64
+}
+
 type Section struct {
 	ID                     int64
 	PackagesID             sql.NullInt64
@@ -448,6 +518,7 @@ type SecurityRule struct {
 	ThreatMapping              interface{}
 	ThreatQuery                sql.NullString
 	Threshold                  interface{}
+	TimelineID                 sql.NullString
 	TimestampOverride          sql.NullString
 	Type                       sql.NullString
 	Version                    sql.NullInt64
@@ -577,9 +648,11 @@ type Tag struct {
 type Transform struct {
 	ID                               int64
 	PackagesID                       int64
+	DestIndex                        sql.NullString
 	DirName                          string
 	ManifestDestinationIndexTemplate interface{}
 	ManifestStart                    sql.NullBool
+	RetentionMaxAge                  sql.NullString
 	FilePath                         sql.NullString
 	FileLine                         sql.NullInt64
 	FileColumn                       sql.NullInt64
@@ -601,6 +674,12 @@ type TransformField struct {
 	TransformID int64
 }
 
+type ValidationExcludedCheck struct {
+	ID         int64
+	Name       string
+	PackagesID int64
+}
+
 type Var struct {
 	ID                    int64
 	FilePath              sql.NullString
@@ -1,5 +1,6 @@
 -- name: InsertFields :one
 INSERT INTO fields (
+  allowed_values,
   file_path,
   file_line,
   file_column,
@@ -82,18 +83,26 @@ INSERT INTO fields (
   ?,
   ?,
   ?,
+  ?,
   ?
 ) RETURNING id;
 
+-- name: DeleteFields :exec
+DELETE FROM fields WHERE id = ?;
+
 -- name: InsertPackages :one
 INSERT INTO packages (
   agent_privileges_root,
   commit_id,
   conditions_agent_version,
+  conditions_elastic_capabilities,
   conditions_elastic_subscription,
   conditions_kibana_version,
+  deprecated_entity_count,
   dir_name,
   elasticsearch_privileges_cluster,
+  elasticsearch_privileges_index,
+  imported_at,
   policy_templates_behavior,
   file_path,
   file_line,
@@ -127,9 +136,16 @@ INSERT INTO packages (
   ?,
   ?,
   ?,
+  ?,
+  ?,
+  ?,
+  ?,
   ?
 ) RETURNING id;
 
+-- name: DeletePackages :exec
+DELETE FROM packages WHERE id = ?;
+
 -- name: InsertBuildManifests :one
 INSERT INTO build_manifests (
   packages_id,
@@ -147,6 +163,12 @@ INSERT INTO build_manifests (
   ?
 ) RETURNING id;
 
+-- name: DeleteBuildManifests :exec
+DELETE FROM build_manifests WHERE id = ?;
+
+-- name: DeleteBuildManifestsByParent :exec
+DELETE FROM build_manifests WHERE packages_id = ?;
+
 -- name: InsertChangelogs :one
 INSERT INTO changelogs (
   packages_id,
@@ -164,9 +186,17 @@ INSERT INTO changelogs (
   ?
 ) RETURNING id;
 
+-- name: DeleteChangelogs :exec
+DELETE FROM changelogs WHERE id = ?;
+
+-- name: DeleteChangelogsByParent :exec
+DELETE FROM changelogs WHERE packages_id = ?;
+
 -- name: InsertChangelogEntries :one
 INSERT INTO changelog_entries (
   changelogs_id,
+  ordinal,
+  version,
   file_path,
   file_line,
   file_column,
@@ -180,13 +210,24 @@ INSERT INTO changelog_entries (
   ?,
   ?,
   ?,
+  ?,
+  ?,
   ?
 ) RETURNING id;
 
+-- name: DeleteChangelogEntries :exec
+DELETE FROM changelog_entries WHERE id = ?;
+
+-- name: DeleteChangelogEntriesByParent :exec
+DELETE FROM changelog_entries WHERE changelogs_id = ?;
+
 -- name: InsertDataStreams :one
 INSERT INTO data_streams (
   packages_id,
   dir_name,
+  effective_dataset,
+  index_template_name,
+  stream_count,
   file_path,
   file_line,
   file_column,
@@ -204,7 +245,8 @@ INSERT INTO data_streams (
   "release",
   title,
   type,
-  github_code_owner
+  github_code_owner,
+  github_code_owners
 ) VALUES (
   ?,
   ?,
@@ -225,9 +267,19 @@ INSERT INTO data_streams (
   ?,
   ?,
   ?,
+  ?,
+  ?,
+  ?,
+  ?,
   ?
 ) RETURNING id;
 
+-- name: DeleteDataStreams :exec
+DELETE FROM data_streams WHERE id = ?;
+
+-- name: DeleteDataStreamsByParent :exec
+DELETE FROM data_streams WHERE packages_id = ?;
+
 -- name: InsertAgentTemplates :one
 INSERT INTO agent_templates (
   content,
@@ -241,6 +293,9 @@ INSERT INTO agent_templates (
   ?
 ) RETURNING id;
 
+-- name: DeleteAgentTemplates :exec
+DELETE FROM agent_templates WHERE id = ?;
+
 -- name: InsertDataStreamFields :one
 INSERT INTO data_stream_fields (
   data_stream_id,
@@ -250,6 +305,21 @@ INSERT INTO data_stream_fields (
   ?
 ) RETURNING id;
 
+-- name: DeleteDataStreamFields :exec
+DELETE FROM data_stream_fields WHERE id = ?;
+
+-- name: InsertDiscoveryDatasets :one
+INSERT INTO discovery_datasets (
+  name,
+  packages_id
+) VALUES (
+  ?,
+  ?
+) RETURNING id;
+
+-- name: DeleteDiscoveryDatasets :exec
+DELETE FROM discovery_datasets WHERE id = ?;
+
 -- name: InsertDiscoveryFields :one
 INSERT INTO discovery_fields (
   name,
@@ -259,19 +329,27 @@ INSERT INTO discovery_fields (
   ?
 ) RETURNING id;
 
+-- name: DeleteDiscoveryFields :exec
+DELETE FROM discovery_fields WHERE id = ?;
+
 -- name: InsertDocs :one
 INSERT INTO docs (
   content,
   content_type,
   file_path,
-  packages_id
+  packages_id,
+  truncated
 ) VALUES (
   ?,
   ?,
   ?,
+  ?,
   ?
 ) RETURNING id;
 
+-- name: DeleteDocs :exec
+DELETE FROM docs WHERE id = ?;
+
 -- name: InsertImages :one
 INSERT INTO images (
   byte_size,
@@ -289,6 +367,9 @@ INSERT INTO images (
   ?
 ) RETURNING id;
 
+-- name: DeleteImages :exec
+DELETE FROM images WHERE id = ?;
+
 -- name: InsertIngestPipelines :one
 INSERT INTO ingest_pipelines (
   data_streams_id,
@@ -306,6 +387,12 @@ INSERT INTO ingest_pipelines (
   ?
 ) RETURNING id;
 
+-- name: DeleteIngestPipelines :exec
+DELETE FROM ingest_pipelines WHERE id = ?;
+
+-- name: DeleteIngestPipelinesByParent :exec
+DELETE FROM ingest_pipelines WHERE data_streams_id = ?;
+
 -- name: InsertIngestProcessors :one
 INSERT INTO ingest_processors (
   ingest_pipelines_id,
@@ -327,6 +414,12 @@ INSERT INTO ingest_processors (
   ?
 ) RETURNING id;
 
+-- name: DeleteIngestProcessors :exec
+DELETE FROM ingest_processors WHERE id = ?;
+
+-- name: DeleteIngestProcessorsByParent :exec
+DELETE FROM ingest_processors WHERE ingest_pipelines_id = ?;
+
 -- name: InsertKibanaSavedObjects :one
 INSERT INTO kibana_saved_objects (
   asset_type,
@@ -337,6 +430,8 @@ INSERT INTO kibana_saved_objects (
   object_id,
   object_type,
   packages_id,
+  panels_count,
+  raw,
   reference_count,
   title,
   type_migration_version
@@ -351,9 +446,40 @@ INSERT INTO kibana_saved_objects (
   ?,
   ?,
   ?,
+  ?,
+  ?,
   ?
 ) RETURNING id;
 
+-- name: DeleteKibanaSavedObjects :exec
+DELETE FROM kibana_saved_objects WHERE id = ?;
+
+-- name: InsertExceptionLists :one
+INSERT INTO exception_lists (
+  entries,
+  item_id,
+  kibana_saved_objects_id,
+  list_id,
+  list_type,
+  namespace_type,
+  os_types,
+  tags,
+  type
+) VALUES (
+  ?,
+  ?,
+  ?,
+  ?,
+  ?,
+  ?,
+  ?,
+  ?,
+  ?
+) RETURNING id;
+
+-- name: DeleteExceptionLists :exec
+DELETE FROM exception_lists WHERE id = ?;
+
 -- name: InsertKibanaReferences :one
 INSERT INTO kibana_references (
   kibana_saved_objects_id,
@@ -367,6 +493,9 @@ INSERT INTO kibana_references (
   ?
 ) RETURNING id;
 
+-- name: DeleteKibanaReferences :exec
+DELETE FROM kibana_references WHERE id = ?;
+
 -- name: InsertPackageCategories :one
 INSERT INTO package_categories (
   category,
@@ -376,6 +505,27 @@ INSERT INTO package_categories (
   ?
 ) RETURNING id;
 
+-- name: DeletePackageCategories :exec
+DELETE FROM package_categories WHERE id = ?;
+
+-- name: InsertPackageDependencies :one
+INSERT INTO package_dependencies (
+  import_mappings,
+  name,
+  packages_id,
+  reference,
+  version
+) VALUES (
+  ?,
+  ?,
+  ?,
+  ?,
+  ?
+) RETURNING id;
+
+-- name: DeletePackageDependencies :exec
+DELETE FROM package_dependencies WHERE id = ?;
+
 -- name: InsertPackageFields :one
 INSERT INTO package_fields (
   field_id,
@@ -385,6 +535,9 @@ INSERT INTO package_fields (
   ?
 ) RETURNING id;
 
+-- name: DeletePackageFields :exec
+DELETE FROM package_fields WHERE id = ?;
+
 -- name: InsertPackageIcons :one
 INSERT INTO package_icons (
   packages_id,
@@ -402,6 +555,32 @@ INSERT INTO package_icons (
   ?
 ) RETURNING id;
 
+-- name: DeletePackageIcons :exec
+DELETE FROM package_icons WHERE id = ?;
+
+-- name: DeletePackageIconsByParent :exec
+DELETE FROM package_icons WHERE packages_id = ?;
+
+-- name: InsertPackageMetrics :one
+INSERT INTO package_metrics (
+  data_stream_count,
+  field_count,
+  kibana_object_count,
+  packages_id,
+  pipeline_count,
+  processor_count
+) VALUES (
+  ?,
+  ?,
+  ?,
+  ?,
+  ?,
+  ?
+) RETURNING id;
+
+-- name: DeletePackageMetrics :exec
+DELETE FROM package_metrics WHERE id = ?;
+
 -- name: InsertPackageScreenshots :one
 INSERT INTO package_screenshots (
   packages_id,
@@ -417,12 +596,20 @@ INSERT INTO package_screenshots (
   ?
 ) RETURNING id;
 
+-- name: DeletePackageScreenshots :exec
+DELETE FROM package_screenshots WHERE id = ?;
+
+-- name: DeletePackageScreenshotsByParent :exec
+DELETE FROM package_screenshots WHERE packages_id = ?;
+
 -- name: InsertPipelineTests :one
 INSERT INTO pipeline_tests (
   config_path,
   data_streams_id,
   dynamic_fields,
+  event,
   event_path,
+  expected,
   expected_path,
   fields,
   format,
@@ -445,16 +632,24 @@ INSERT INTO pipeline_tests (
   ?,
   ?,
   ?,
+  ?,
+  ?,
   ?
 ) RETURNING id;
 
+-- name: DeletePipelineTests :exec
+DELETE FROM pipeline_tests WHERE id = ?;
+
 -- name: InsertPolicyTemplates :one
 INSERT INTO policy_templates (
   packages_id,
+  deployment_modes,
   dynamic_signal_types,
   input,
+  input_count,
   policy_template_type,
   template_path,
+  var_count,
   file_path,
   file_line,
   file_column,
@@ -500,9 +695,18 @@ INSERT INTO policy_templates (
   ?,
   ?,
   ?,
+  ?,
+  ?,
+  ?,
   ?
 ) RETURNING id;
 
+-- name: DeletePolicyTemplates :exec
+DELETE FROM policy_templates WHERE id = ?;
+
+-- name: DeletePolicyTemplatesByParent :exec
+DELETE FROM policy_templates WHERE packages_id = ?;
+
 -- name: InsertPolicyTemplateCategories :one
 INSERT INTO policy_template_categories (
   category,
@@ -512,6 +716,9 @@ INSERT INTO policy_template_categories (
   ?
 ) RETURNING id;
 
+-- name: DeletePolicyTemplateCategories :exec
+DELETE FROM policy_template_categories WHERE id = ?;
+
 -- name: InsertPolicyTemplateIcons :one
 INSERT INTO policy_template_icons (
   policy_templates_id,
@@ -529,6 +736,12 @@ INSERT INTO policy_template_icons (
   ?
 ) RETURNING id;
 
+-- name: DeletePolicyTemplateIcons :exec
+DELETE FROM policy_template_icons WHERE id = ?;
+
+-- name: DeletePolicyTemplateIconsByParent :exec
+DELETE FROM policy_template_icons WHERE policy_templates_id = ?;
+
 -- name: InsertPolicyTemplateInputs :one
 INSERT INTO policy_template_inputs (
   policy_templates_id,
@@ -566,6 +779,12 @@ INSERT INTO policy_template_inputs (
   ?
 ) RETURNING id;
 
+-- name: DeletePolicyTemplateInputs :exec
+DELETE FROM policy_template_inputs WHERE id = ?;
+
+-- name: DeletePolicyTemplateInputsByParent :exec
+DELETE FROM policy_template_inputs WHERE policy_templates_id = ?;
+
 -- name: InsertPolicyTemplateScreenshots :one
 INSERT INTO policy_template_screenshots (
   policy_templates_id,
@@ -581,6 +800,12 @@ INSERT INTO policy_template_screenshots (
   ?
 ) RETURNING id;
 
+-- name: DeletePolicyTemplateScreenshots :exec
+DELETE FROM policy_template_screenshots WHERE id = ?;
+
+-- name: DeletePolicyTemplateScreenshotsByParent :exec
+DELETE FROM policy_template_screenshots WHERE policy_templates_id = ?;
+
 -- name: InsertPolicyTests :one
 INSERT INTO policy_tests (
   case_name,
@@ -612,6 +837,23 @@ INSERT INTO policy_tests (
   ?
 ) RETURNING id;
 
+-- name: DeletePolicyTests :exec
+DELETE FROM policy_tests WHERE id = ?;
+
+-- name: InsertProcessorTypeCounts :one
+INSERT INTO processor_type_counts (
+  count,
+  packages_id,
+  type
+) VALUES (
+  ?,
+  ?,
+  ?
+) RETURNING id;
+
+-- name: DeleteProcessorTypeCounts :exec
+DELETE FROM processor_type_counts WHERE id = ?;
+
 -- name: InsertRoutingRules :one
 INSERT INTO routing_rules (
   data_streams_id,
@@ -631,6 +873,12 @@ INSERT INTO routing_rules (
   ?
 ) RETURNING id;
 
+-- name: DeleteRoutingRules :exec
+DELETE FROM routing_rules WHERE id = ?;
+
+-- name: DeleteRoutingRulesByParent :exec
+DELETE FROM routing_rules WHERE data_streams_id = ?;
+
 -- name: InsertSampleEvents :one
 INSERT INTO sample_events (
   data_streams_id,
@@ -642,6 +890,21 @@ INSERT INTO sample_events (
   ?
 ) RETURNING id;
 
+-- name: DeleteSampleEvents :exec
+DELETE FROM sample_events WHERE id = ?;
+
+-- name: InsertSampleEventFields :one
+INSERT INTO sample_event_fields (
+  path,
+  sample_events_id
+) VALUES (
+  ?,
+  ?
+) RETURNING id;
+
+-- name: DeleteSampleEventFields :exec
+DELETE FROM sample_event_fields WHERE id = ?;
+
 -- name: InsertSecurityRules :one
 INSERT INTO security_rules (
   anomaly_threshold,
@@ -673,6 +936,7 @@ INSERT INTO security_rules (
   threat_mapping,
   threat_query,
   threshold,
+  timeline_id,
   timestamp_override,
   type,
   version
@@ -708,9 +972,13 @@ INSERT INTO security_rules (
   ?,
   ?,
   ?,
+  ?,
   ?
 ) RETURNING id;
 
+-- name: DeleteSecurityRules :exec
+DELETE FROM security_rules WHERE id = ?;
+
 -- name: InsertSecurityRuleIndexPatterns :one
 INSERT INTO security_rule_index_patterns (
   pattern,
@@ -720,6 +988,9 @@ INSERT INTO security_rule_index_patterns (
   ?
 ) RETURNING id;
 
+-- name: DeleteSecurityRuleIndexPatterns :exec
+DELETE FROM security_rule_index_patterns WHERE id = ?;
+
 -- name: InsertSecurityRuleRelatedIntegrations :one
 INSERT INTO security_rule_related_integrations (
   integration,
@@ -733,6 +1004,9 @@ INSERT INTO security_rule_related_integrations (
   ?
 ) RETURNING id;
 
+-- name: DeleteSecurityRuleRelatedIntegrations :exec
+DELETE FROM security_rule_related_integrations WHERE id = ?;
+
 -- name: InsertSecurityRuleRequiredFields :one
 INSERT INTO security_rule_required_fields (
   ecs,
@@ -746,6 +1020,9 @@ INSERT INTO security_rule_required_fields (
   ?
 ) RETURNING id;
 
+-- name: DeleteSecurityRuleRequiredFields :exec
+DELETE FROM security_rule_required_fields WHERE id = ?;
+
 -- name: InsertSecurityRuleTags :one
 INSERT INTO security_rule_tags (
   security_rules_id,
@@ -755,6 +1032,9 @@ INSERT INTO security_rule_tags (
   ?
 ) RETURNING id;
 
+-- name: DeleteSecurityRuleTags :exec
+DELETE FROM security_rule_tags WHERE id = ?;
+
 -- name: InsertSecurityRuleThreats :one
 INSERT INTO security_rule_threats (
   security_rules_id,
@@ -772,6 +1052,9 @@ INSERT INTO security_rule_threats (
   ?
 ) RETURNING id;
 
+-- name: DeleteSecurityRuleThreats :exec
+DELETE FROM security_rule_threats WHERE id = ?;
+
 -- name: InsertStaticTests :one
 INSERT INTO static_tests (
   case_name,
@@ -793,6 +1076,9 @@ INSERT INTO static_tests (
   ?
 ) RETURNING id;
 
+-- name: DeleteStaticTests :exec
+DELETE FROM static_tests WHERE id = ?;
+
 -- name: InsertStreams :one
 INSERT INTO streams (
   data_streams_id,
@@ -824,6 +1110,12 @@ INSERT INTO streams (
   ?
 ) RETURNING id;
 
+-- name: DeleteStreams :exec
+DELETE FROM streams WHERE id = ?;
+
+-- name: DeleteStreamsByParent :exec
+DELETE FROM streams WHERE data_streams_id = ?;
+
 -- name: InsertSections :one
 INSERT INTO sections (
   packages_id,
@@ -843,6 +1135,9 @@ INSERT INTO sections (
   ?
 ) RETURNING id;
 
+-- name: DeleteSections :exec
+DELETE FROM sections WHERE id = ?;
+
 -- name: InsertSystemTests :one
 INSERT INTO system_tests (
   case_name,
@@ -898,6 +1193,9 @@ INSERT INTO system_tests (
   ?
 ) RETURNING id;
 
+-- name: DeleteSystemTests :exec
+DELETE FROM system_tests WHERE id = ?;
+
 -- name: InsertSystemTestSamples :one
 INSERT INTO system_test_samples (
   system_tests_id,
@@ -911,6 +1209,12 @@ INSERT INTO system_test_samples (
   ?
 ) RETURNING id;
 
+-- name: DeleteSystemTestSamples :exec
+DELETE FROM system_test_samples WHERE id = ?;
+
+-- name: DeleteSystemTestSamplesByParent :exec
+DELETE FROM system_test_samples WHERE system_tests_id = ?;
+
 -- name: InsertTags :one
 INSERT INTO tags (
   packages_id,
@@ -930,12 +1234,20 @@ INSERT INTO tags (
   ?
 ) RETURNING id;
 
+-- name: DeleteTags :exec
+DELETE FROM tags WHERE id = ?;
+
+-- name: DeleteTagsByParent :exec
+DELETE FROM tags WHERE packages_id = ?;
+
 -- name: InsertTransforms :one
 INSERT INTO transforms (
   packages_id,
+  dest_index,
   dir_name,
   manifest_destination_index_template,
   manifest_start,
+  retention_max_age,
   file_path,
   file_line,
   file_column,
@@ -966,9 +1278,17 @@ INSERT INTO transforms (
   ?,
   ?,
   ?,
+  ?,
+  ?,
   ?
 ) RETURNING id;
 
+-- name: DeleteTransforms :exec
+DELETE FROM transforms WHERE id = ?;
+
+-- name: DeleteTransformsByParent :exec
+DELETE FROM transforms WHERE packages_id = ?;
+
 -- name: InsertTransformFields :one
 INSERT INTO transform_fields (
   field_id,
@@ -978,6 +1298,21 @@ INSERT INTO transform_fields (
   ?
 ) RETURNING id;
 
+-- name: DeleteTransformFields :exec
+DELETE FROM transform_fields WHERE id = ?;
+
+-- name: InsertValidationExcludedChecks :one
+INSERT INTO validation_excluded_checks (
+  name,
+  packages_id
+) VALUES (
+  ?,
+  ?
+) RETURNING id;
+
+-- name: DeleteValidationExcludedChecks :exec
+DELETE FROM validation_excluded_checks WHERE id = ?;
+
 -- name: InsertVarGroups :one
 INSERT INTO var_groups (
   packages_id,
@@ -1003,6 +1338,9 @@ INSERT INTO var_groups (
   ?
 ) RETURNING id;
 
+-- name: DeleteVarGroups :exec
+DELETE FROM var_groups WHERE id = ?;
+
 -- name: InsertVarGroupOptions :one
 INSERT INTO var_group_options (
   var_groups_id,
@@ -1022,6 +1360,12 @@ INSERT INTO var_group_options (
   ?
 ) RETURNING id;
 
+-- name: DeleteVarGroupOptions :exec
+DELETE FROM var_group_options WHERE id = ?;
+
+-- name: DeleteVarGroupOptionsByParent :exec
+DELETE FROM var_group_options WHERE var_groups_id = ?;
+
 -- name: InsertVars :one
 INSERT INTO vars (
   file_path,
@@ -1065,6 +1409,9 @@ INSERT INTO vars (
   ?
 ) RETURNING id;
 
+-- name: DeleteVars :exec
+DELETE FROM vars WHERE id = ?;
+
 -- name: InsertDeprecations :one
 INSERT INTO deprecations (
   data_streams_id,
@@ -1094,6 +1441,9 @@ INSERT INTO deprecations (
   ?
 ) RETURNING id;
 
+-- name: DeleteDeprecations :exec
+DELETE FROM deprecations WHERE id = ?;
+
 -- name: InsertPackageVars :one
 INSERT INTO package_vars (
   package_id,
@@ -1103,6 +1453,9 @@ INSERT INTO package_vars (
   ?
 ) RETURNING id;
 
+-- name: DeletePackageVars :exec
+DELETE FROM package_vars WHERE id = ?;
+
 -- name: InsertPolicyTemplateInputVars :one
 INSERT INTO policy_template_input_vars (
   policy_template_input_id,
@@ -1112,6 +1465,9 @@ INSERT INTO policy_template_input_vars (
   ?
 ) RETURNING id;
 
+-- name: DeletePolicyTemplateInputVars :exec
+DELETE FROM policy_template_input_vars WHERE id = ?;
+
 -- name: InsertPolicyTemplateVars :one
 INSERT INTO policy_template_vars (
   policy_template_id,
@@ -1121,6 +1477,9 @@ INSERT INTO policy_template_vars (
   ?
 ) RETURNING id;
 
+-- name: DeletePolicyTemplateVars :exec
+DELETE FROM policy_template_vars WHERE id = ?;
+
 -- name: InsertStreamVars :one
 INSERT INTO stream_vars (
   stream_id,
@@ -1129,3 +1488,6 @@ INSERT INTO stream_vars (
   ?,
   ?
 ) RETURNING id;
+
+-- name: DeleteStreamVars :exec
+DELETE FROM stream_vars WHERE id = ?;
@@ -10,6 +10,681 @@ import (
 	"database/sql"
 )
 
+const deleteAgentTemplates = `-- name: DeleteAgentTemplates :exec
+DELETE FROM agent_templates WHERE id = ?
+`
+
+func (q *Queries) DeleteAgentTemplates(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteAgentTemplates, id)
+	return err
+}
+
+const deleteBuildManifests = `-- name: DeleteBuildManifests :exec
+DELETE FROM build_manifests WHERE id = ?
+`
+
+func (q *Queries) DeleteBuildManifests(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteBuildManifests, id)
+	return err
+}
+
+const deleteBuildManifestsByParent = `-- name: DeleteBuildManifestsByParent :exec
+DELETE FROM build_manifests WHERE packages_id = ?
+`
+
+func (q *Queries) DeleteBuildManifestsByParent(ctx context.Context, packagesID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteBuildManifestsByParent, packagesID)
+	return err
+}
+
+const deleteChangelogEntries = `-- name: DeleteChangelogEntries :exec
+DELETE FROM changelog_entries WHERE id = ?
+`
+
+func (q *Queries) DeleteChangelogEntries(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteChangelogEntries, id)
+	return err
+}
+
+const deleteChangelogEntriesByParent = `-- name: DeleteChangelogEntriesByParent :exec
+DELETE FROM changelog_entries WHERE changelogs_id = ?
+`
+
+func (q *Queries) DeleteChangelogEntriesByParent(ctx context.Context, changelogsID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteChangelogEntriesByParent, changelogsID)
+	return err
+}
+
+const deleteChangelogs = `-- name: DeleteChangelogs :exec
+DELETE FROM changelogs WHERE id = ?
+`
+
+func (q *Queries) DeleteChangelogs(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteChangelogs, id)
+	return err
+}
+
+const deleteChangelogsByParent = `-- name: DeleteChangelogsByParent :exec
+DELETE FROM changelogs WHERE packages_id = ?
+`
+
+func (q *Queries) DeleteChangelogsByParent(ctx context.Context, packagesID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteChangelogsByParent, packagesID)
+	return err
+}
+
+const deleteDataStreamFields = `-- name: DeleteDataStreamFields :exec
+DELETE FROM data_stream_fields WHERE id = ?
+`
+
+func (q *Queries) DeleteDataStreamFields(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteDataStreamFields, id)
+	return err
+}
+
+const deleteDataStreams = `-- name: DeleteDataStreams :exec
+DELETE FROM data_streams WHERE id = ?
+`
+
+func (q *Queries) DeleteDataStreams(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteDataStreams, id)
+	return err
+}
+
+const deleteDataStreamsByParent = `-- name: DeleteDataStreamsByParent :exec
+DELETE FROM data_streams WHERE packages_id = ?
+`
+
+func (q *Queries) DeleteDataStreamsByParent(ctx context.Context, packagesID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteDataStreamsByParent, packagesID)
+	return err
+}
+
+const deleteDeprecations = `-- name: DeleteDeprecations :exec
+DELETE FROM deprecations WHERE id = ?
+`
+
+func (q *Queries) DeleteDeprecations(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteDeprecations, id)
+	return err
+}
+
+const deleteDiscoveryDatasets = `-- name: DeleteDiscoveryDatasets :exec
+DELETE FROM discovery_datasets WHERE id = ?
+`
+
+func (q *Queries) DeleteDiscoveryDatasets(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteDiscoveryDatasets, id)
+	return err
+}
+
+const deleteDiscoveryFields = `-- name: DeleteDiscoveryFields :exec
+DELETE FROM discovery_fields WHERE id = ?
+`
+
+func (q *Queries) DeleteDiscoveryFields(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteDiscoveryFields, id)
+	return err
+}
+
+const deleteDocs = `-- name: DeleteDocs :exec
+DELETE FROM docs WHERE id = ?
+`
+
+func (q *Queries) DeleteDocs(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteDocs, id)
+	return err
+}
+
+const deleteExceptionLists = `-- name: DeleteExceptionLists :exec
+DELETE FROM exception_lists WHERE id = ?
+`
+
+func (q *Queries) DeleteExceptionLists(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteExceptionLists, id)
+	return err
+}
+
+const deleteFields = `-- name: DeleteFields :exec
+DELETE FROM fields WHERE id = ?
+`
+
+func (q *Queries) DeleteFields(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteFields, id)
+	return err
+}
+
+const deleteImages = `-- name: DeleteImages :exec
+DELETE FROM images WHERE id = ?
+`
+
+func (q *Queries) DeleteImages(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteImages, id)
+	return err
+}
+
+const deleteIngestPipelines = `-- name: DeleteIngestPipelines :exec
+DELETE FROM ingest_pipelines WHERE id = ?
+`
+
+func (q *Queries) DeleteIngestPipelines(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteIngestPipelines, id)
+	return err
+}
+
+const deleteIngestPipelinesByParent = `-- name: DeleteIngestPipelinesByParent :exec
+DELETE FROM ingest_pipelines WHERE data_streams_id = ?
+`
+
+func (q *Queries) DeleteIngestPipelinesByParent(ctx context.Context, dataStreamsID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteIngestPipelinesByParent, dataStreamsID)
+	return err
+}
+
+const deleteIngestProcessors = `-- name: DeleteIngestProcessors :exec
+DELETE FROM ingest_processors WHERE id = ?
+`
+
+func (q *Queries) DeleteIngestProcessors(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteIngestProcessors, id)
+	return err
+}
+
+const deleteIngestProcessorsByParent = `-- name: DeleteIngestProcessorsByParent :exec
+DELETE FROM ingest_processors WHERE ingest_pipelines_id = ?
+`
+
+func (q *Queries) DeleteIngestProcessorsByParent(ctx context.Context, ingestPipelinesID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteIngestProcessorsByParent, ingestPipelinesID)
+	return err
+}
+
+const deleteKibanaReferences = `-- name: DeleteKibanaReferences :exec
+DELETE FROM kibana_references WHERE id = ?
+`
+
+func (q *Queries) DeleteKibanaReferences(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteKibanaReferences, id)
+	return err
+}
+
+const deleteKibanaSavedObjects = `-- name: DeleteKibanaSavedObjects :exec
+DELETE FROM kibana_saved_objects WHERE id = ?
+`
+
+func (q *Queries) DeleteKibanaSavedObjects(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteKibanaSavedObjects, id)
+	return err
+}
+
+const deletePackageCategories = `-- name: DeletePackageCategories :exec
+DELETE FROM package_categories WHERE id = ?
+`
+
+func (q *Queries) DeletePackageCategories(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deletePackageCategories, id)
+	return err
+}
+
+const deletePackageDependencies = `-- name: DeletePackageDependencies :exec
+DELETE FROM package_dependencies WHERE id = ?
+`
+
+func (q *Queries) DeletePackageDependencies(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deletePackageDependencies, id)
+	return err
+}
+
+const deletePackageFields = `-- name: DeletePackageFields :exec
+DELETE FROM package_fields WHERE id = ?
+`
+
+func (q *Queries) DeletePackageFields(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deletePackageFields, id)
+	return err
+}
+
+const deletePackageIcons = `-- name: DeletePackageIcons :exec
+DELETE FROM package_icons WHERE id = ?
+`
+
+func (q *Queries) DeletePackageIcons(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deletePackageIcons, id)
+	return err
+}
+
+const deletePackageIconsByParent = `-- name: DeletePackageIconsByParent :exec
+DELETE FROM package_icons WHERE packages_id = ?
+`
+
+func (q *Queries) DeletePackageIconsByParent(ctx context.Context, packagesID int64) error {
+	_, err := q.db.ExecContext(ctx, deletePackageIconsByParent, packagesID)
+	return err
+}
+
+const deletePackageMetrics = `-- name: DeletePackageMetrics :exec
+DELETE FROM package_metrics WHERE id = ?
+`
+
+func (q *Queries) DeletePackageMetrics(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deletePackageMetrics, id)
+	return err
+}
+
+const deletePackageScreenshots = `-- name: DeletePackageScreenshots :exec
+DELETE FROM package_screenshots WHERE id = ?
+`
+
+func (q *Queries) DeletePackageScreenshots(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deletePackageScreenshots, id)
+	return err
+}
+
+const deletePackageScreenshotsByParent = `-- name: DeletePackageScreenshotsByParent :exec
+DELETE FROM package_screenshots WHERE packages_id = ?
+`
+
+func (q *Queries) DeletePackageScreenshotsByParent(ctx context.Context, packagesID int64) error {
+	_, err := q.db.ExecContext(ctx, deletePackageScreenshotsByParent, packagesID)
+	return err
+}
+
+const deletePackageVars = `-- name: DeletePackageVars :exec
+DELETE FROM package_vars WHERE id = ?
+`
+
+func (q *Queries) DeletePackageVars(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deletePackageVars, id)
+	return err
+}
+
+const deletePackages = `-- name: DeletePackages :exec
+DELETE FROM packages WHERE id = ?
+`
+
+func (q *Queries) DeletePackages(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deletePackages, id)
+	return err
+}
+
+const deletePipelineTests = `-- name: DeletePipelineTests :exec
+DELETE FROM pipeline_tests WHERE id = ?
+`
+
+func (q *Queries) DeletePipelineTests(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deletePipelineTests, id)
+	return err
+}
+
+const deletePolicyTemplateCategories = `-- name: DeletePolicyTemplateCategories :exec
+DELETE FROM policy_template_categories WHERE id = ?
+`
+
+func (q *Queries) DeletePolicyTemplateCategories(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deletePolicyTemplateCategories, id)
+	return err
+}
+
+const deletePolicyTemplateIcons = `-- name: DeletePolicyTemplateIcons :exec
+DELETE FROM policy_template_icons WHERE id = ?
+`
+
+func (q *Queries) DeletePolicyTemplateIcons(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deletePolicyTemplateIcons, id)
+	return err
+}
+
+const deletePolicyTemplateIconsByParent = `-- name: DeletePolicyTemplateIconsByParent :exec
+DELETE FROM policy_template_icons WHERE policy_templates_id = ?
+`
+
+func (q *Queries) DeletePolicyTemplateIconsByParent(ctx context.Context, policyTemplatesID int64) error {
+	_, err := q.db.ExecContext(ctx, deletePolicyTemplateIconsByParent, policyTemplatesID)
+	return err
+}
+
+const deletePolicyTemplateInputVars = `-- name: DeletePolicyTemplateInputVars :exec
+DELETE FROM policy_template_input_vars WHERE id = ?
+`
+
+func (q *Queries) DeletePolicyTemplateInputVars(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deletePolicyTemplateInputVars, id)
+	return err
+}
+
+const deletePolicyTemplateInputs = `-- name: DeletePolicyTemplateInputs :exec
+DELETE FROM policy_template_inputs WHERE id = ?
+`
+
+func (q *Queries) DeletePolicyTemplateInputs(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deletePolicyTemplateInputs, id)
+	return err
+}
+
+const deletePolicyTemplateInputsByParent = `-- name: DeletePolicyTemplateInputsByParent :exec
+DELETE FROM policy_template_inputs WHERE policy_templates_id = ?
+`
+
+func (q *Queries) DeletePolicyTemplateInputsByParent(ctx context.Context, policyTemplatesID int64) error {
+	_, err := q.db.ExecContext(ctx, deletePolicyTemplateInputsByParent, policyTemplatesID)
+	return err
+}
+
+const deletePolicyTemplateScreenshots = `-- name: DeletePolicyTemplateScreenshots :exec
+DELETE FROM policy_template_screenshots WHERE id = ?
+`
+
+func (q *Queries) DeletePolicyTemplateScreenshots(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deletePolicyTemplateScreenshots, id)
+	return err
+}
+
+const deletePolicyTemplateScreenshotsByParent = `-- name: DeletePolicyTemplateScreenshotsByParent :exec
+DELETE FROM policy_template_screenshots WHERE policy_templates_id = ?
+`
+
+func (q *Queries) DeletePolicyTemplateScreenshotsByParent(ctx context.Context, policyTemplatesID int64) error {
+	_, err := q.db.ExecContext(ctx, deletePolicyTemplateScreenshotsByParent, policyTemplatesID)
+	return err
+}
+
+const deletePolicyTemplateVars = `-- name: DeletePolicyTemplateVars :exec
+DELETE FROM policy_template_vars WHERE id = ?
+`
+
+func (q *Queries) DeletePolicyTemplateVars(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deletePolicyTemplateVars, id)
+	return err
+}
+
+const deletePolicyTemplates = `-- name: DeletePolicyTemplates :exec
+DELETE FROM policy_templates WHERE id = ?
+`
+
+func (q *Queries) DeletePolicyTemplates(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deletePolicyTemplates, id)
+	return err
+}
+
+const deletePolicyTemplatesByParent = `-- name: DeletePolicyTemplatesByParent :exec
+DELETE FROM policy_templates WHERE packages_id = ?
+`
+
+func (q *Queries) DeletePolicyTemplatesByParent(ctx context.Context, packagesID int64) error {
+	_, err := q.db.ExecContext(ctx, deletePolicyTemplatesByParent, packagesID)
+	return err
+}
+
+const deletePolicyTests = `-- name: DeletePolicyTests :exec
+DELETE FROM policy_tests WHERE id = ?
+`
+
+func (q *Queries) DeletePolicyTests(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deletePolicyTests, id)
+	return err
+}
+
+const deleteProcessorTypeCounts = `-- name: DeleteProcessorTypeCounts :exec
+DELETE FROM processor_type_counts WHERE id = ?
+`
+
+func (q *Queries) DeleteProcessorTypeCounts(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteProcessorTypeCounts, id)
+	return err
+}
+
+const deleteRoutingRules = `-- name: DeleteRoutingRules :exec
+DELETE FROM routing_rules WHERE id = ?
+`
+
+func (q *Queries) DeleteRoutingRules(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteRoutingRules, id)
+	return err
+}
+
+const deleteRoutingRulesByParent = `-- name: DeleteRoutingRulesByParent :exec
+DELETE FROM routing_rules WHERE data_streams_id = ?
+`
+
+func (q *Queries) DeleteRoutingRulesByParent(ctx context.Context, dataStreamsID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteRoutingRulesByParent, dataStreamsID)
+	return err
+}
+
+const deleteSampleEventFields = `-- name: DeleteSampleEventFields :exec
+DELETE FROM sample_event_fields WHERE id = ?
+`
+
+func (q *Queries) DeleteSampleEventFields(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteSampleEventFields, id)
+	return err
+}
+
+const deleteSampleEvents = `-- name: DeleteSampleEvents :exec
+DELETE FROM sample_events WHERE id = ?
+`
+
+func (q *Queries) DeleteSampleEvents(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteSampleEvents, id)
+	return err
+}
+
+const deleteSections = `-- name: DeleteSections :exec
+DELETE FROM sections WHERE id = ?
+`
+
+func (q *Queries) DeleteSections(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteSections, id)
+	return err
+}
+
+const deleteSecurityRuleIndexPatterns = `-- name: DeleteSecurityRuleIndexPatterns :exec
+DELETE FROM security_rule_index_patterns WHERE id = ?
+`
+
+func (q *Queries) DeleteSecurityRuleIndexPatterns(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteSecurityRuleIndexPatterns, id)
+	return err
+}
+
+const deleteSecurityRuleRelatedIntegrations = `-- name: DeleteSecurityRuleRelatedIntegrations :exec
+DELETE FROM security_rule_related_integrations WHERE id = ?
+`
+
+func (q *Queries) DeleteSecurityRuleRelatedIntegrations(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteSecurityRuleRelatedIntegrations, id)
+	return err
+}
+
+const deleteSecurityRuleRequiredFields = `-- name: DeleteSecurityRuleRequiredFields :exec
+DELETE FROM security_rule_required_fields WHERE id = ?
+`
+
+func (q *Queries) DeleteSecurityRuleRequiredFields(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteSecurityRuleRequiredFields, id)
+	return err
+}
+
+const deleteSecurityRuleTags = `-- name: DeleteSecurityRuleTags :exec
+DELETE FROM security_rule_tags WHERE id = ?
+`
+
+func (q *Queries) DeleteSecurityRuleTags(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteSecurityRuleTags, id)
+	return err
+}
+
+const deleteSecurityRuleThreats = `-- name: DeleteSecurityRuleThreats :exec
+DELETE FROM security_rule_threats WHERE id = ?
+`
+
+func (q *Queries) DeleteSecurityRuleThreats(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteSecurityRuleThreats, id)
+	return err
+}
+
+const deleteSecurityRules = `-- name: DeleteSecurityRules :exec
+DELETE FROM security_rules WHERE id = ?
+`
+
+func (q *Queries) DeleteSecurityRules(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteSecurityRules, id)
+	return err
+}
+
+const deleteStaticTests = `-- name: DeleteStaticTests :exec
+DELETE FROM static_tests WHERE id = ?
+`
+
+func (q *Queries) DeleteStaticTests(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteStaticTests, id)
+	return err
+}
+
+const deleteStreamVars = `-- name: DeleteStreamVars :exec
+DELETE FROM stream_vars WHERE id = ?
+`
+
+func (q *Queries) DeleteStreamVars(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteStreamVars, id)
+	return err
+}
+
+const deleteStreams = `-- name: DeleteStreams :exec
+DELETE FROM streams WHERE id = ?
+`
+
+func (q *Queries) DeleteStreams(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteStreams, id)
+	return err
+}
+
+const deleteStreamsByParent = `-- name: DeleteStreamsByParent :exec
+DELETE FROM streams WHERE data_streams_id = ?
+`
+
+func (q *Queries) DeleteStreamsByParent(ctx context.Context, dataStreamsID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteStreamsByParent, dataStreamsID)
+	return err
+}
+
+const deleteSystemTestSamples = `-- name: DeleteSystemTestSamples :exec
+DELETE FROM system_test_samples WHERE id = ?
+`
+
+func (q *Queries) DeleteSystemTestSamples(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteSystemTestSamples, id)
+	return err
+}
+
+const deleteSystemTestSamplesByParent = `-- name: DeleteSystemTestSamplesByParent :exec
+DELETE FROM system_test_samples WHERE system_tests_id = ?
+`
+
+func (q *Queries) DeleteSystemTestSamplesByParent(ctx context.Context, systemTestsID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteSystemTestSamplesByParent, systemTestsID)
+	return err
+}
+
+const deleteSystemTests = `-- name: DeleteSystemTests :exec
+DELETE FROM system_tests WHERE id = ?
+`
+
+func (q *Queries) DeleteSystemTests(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteSystemTests, id)
+	return err
+}
+
+const deleteTags = `-- name: DeleteTags :exec
+DELETE FROM tags WHERE id = ?
+`
+
+func (q *Queries) DeleteTags(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteTags, id)
+	return err
+}
+
+const deleteTagsByParent = `-- name: DeleteTagsByParent :exec
+DELETE FROM tags WHERE packages_id = ?
+`
+
+func (q *Queries) DeleteTagsByParent(ctx context.Context, packagesID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteTagsByParent, packagesID)
+	return err
+}
+
+const deleteTransformFields = `-- name: DeleteTransformFields :exec
+DELETE FROM transform_fields WHERE id = ?
+`
+
+func (q *Queries) DeleteTransformFields(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteTransformFields, id)
+	return err
+}
+
+const deleteTransforms = `-- name: DeleteTransforms :exec
+DELETE FROM transforms WHERE id = ?
+`
+
+func (q *Queries) DeleteTransforms(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteTransforms, id)
+	return err
+}
+
+const deleteTransformsByParent = `-- name: DeleteTransformsByParent :exec
+DELETE FROM transforms WHERE packages_id = ?
+`
+
+func (q *Queries) DeleteTransformsByParent(ctx context.Context, packagesID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteTransformsByParent, packagesID)
+	return err
+}
+
+const deleteValidationExcludedChecks = `-- name: DeleteValidationExcludedChecks :exec
+DELETE FROM validation_excluded_checks WHERE id = ?
+`
+
+func (q *Queries) DeleteValidationExcludedChecks(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteValidationExcludedChecks, id)
+	return err
+}
+
+const deleteVarGroupOptions = `-- name: DeleteVarGroupOptions :exec
+DELETE FROM var_group_options WHERE id = ?
+`
+
+func (q *Queries) DeleteVarGroupOptions(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteVarGroupOptions, id)
+	return err
+}
+
+const deleteVarGroupOptionsByParent = `-- name: DeleteVarGroupOptionsByParent :exec
+DELETE FROM var_group_options WHERE var_groups_id = ?
+`
+
+func (q *Queries) DeleteVarGroupOptionsByParent(ctx context.Context, varGroupsID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteVarGroupOptionsByParent, varGroupsID)
+	return err
+}
+
+const deleteVarGroups = `-- name: DeleteVarGroups :exec
+DELETE FROM var_groups WHERE id = ?
+`
+
+func (q *Queries) DeleteVarGroups(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteVarGroups, id)
+	return err
+}
+
+const deleteVars = `-- name: DeleteVars :exec
+DELETE FROM vars WHERE id = ?
+`
+
+func (q *Queries) DeleteVars(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteVars, id)
+	return err
+}
+
 const insertAgentTemplates = `-- name: InsertAgentTemplates :one
 INSERT INTO agent_templates (
   content,
@@ -87,6 +762,8 @@ func (q *Queries) InsertBuildManifests(ctx context.Context, arg InsertBuildManif
 const insertChangelogEntries = `-- name: InsertChangelogEntries :one
 INSERT INTO changelog_entries (
   changelogs_id,
+  ordinal,
+  version,
   file_path,
   file_line,
   file_column,
@@ -100,12 +777,16 @@ INSERT INTO changelog_entries (
   ?,
   ?,
   ?,
+  ?,
+  ?,
   ?
 ) RETURNING id
 `
 
 type InsertChangelogEntriesParams struct {
 	ChangelogsID int64
+	Ordinal      int64
+	Version      string
 	FilePath     sql.NullString
 	FileLine     sql.NullInt64
 	FileColumn   sql.NullInt64
@@ -117,6 +798,8 @@ type InsertChangelogEntriesParams struct {
 func (q *Queries) InsertChangelogEntries(ctx context.Context, arg InsertChangelogEntriesParams) (int64, error) {
 	row := q.db.QueryRowContext(ctx, insertChangelogEntries,
 		arg.ChangelogsID,
+		arg.Ordinal,
+		arg.Version,
 		arg.FilePath,
 		arg.FileLine,
 		arg.FileColumn,
@@ -196,6 +879,9 @@ const insertDataStreams = `-- name: InsertDataStreams :one
 INSERT INTO data_streams (
   packages_id,
   dir_name,
+  effective_dataset,
+  index_template_name,
+  stream_count,
   file_path,
   file_line,
   file_column,
@@ -213,7 +899,8 @@ INSERT INTO data_streams (
   "release",
   title,
   type,
-  github_code_owner
+  github_code_owner,
+  github_code_owners
 ) VALUES (
   ?,
   ?,
@@ -234,6 +921,10 @@ INSERT INTO data_streams (
   ?,
   ?,
   ?,
+  ?,
+  ?,
+  ?,
+  ?,
   ?
 ) RETURNING id
 `
@@ -241,6 +932,9 @@ INSERT INTO data_streams (
 type InsertDataStreamsParams struct {
 	PackagesID                    int64
 	DirName                       string
+	EffectiveDataset              string
+	IndexTemplateName             sql.NullString
+	StreamCount                   int64
 	FilePath                      sql.NullString
 	FileLine                      sql.NullInt64
 	FileColumn                    sql.NullInt64
@@ -259,12 +953,16 @@ type InsertDataStreamsParams struct {
 	Title                         string
 	Type                          sql.NullString
 	GithubCodeOwner               sql.NullString
+	GithubCodeOwners              interface{}
 }
 
 func (q *Queries) InsertDataStreams(ctx context.Context, arg InsertDataStreamsParams) (int64, error) {
 	row := q.db.QueryRowContext(ctx, insertDataStreams,
 		arg.PackagesID,
 		arg.DirName,
+		arg.EffectiveDataset,
+		arg.IndexTemplateName,
+		arg.StreamCount,
 		arg.FilePath,
 		arg.FileLine,
 		arg.FileColumn,
@@ -283,6 +981,7 @@ func (q *Queries) InsertDataStreams(ctx context.Context, arg InsertDataStreamsPa
 		arg.Title,
 		arg.Type,
 		arg.GithubCodeOwner,
+		arg.GithubCodeOwners,
 	)
 	var id int64
 	err := row.Scan(&id)
@@ -354,6 +1053,28 @@ func (q *Queries) InsertDeprecations(ctx context.Context, arg InsertDeprecations
 	return id, err
 }
 
+const insertDiscoveryDatasets = `-- name: InsertDiscoveryDatasets :one
+INSERT INTO discovery_datasets (
+  name,
+  packages_id
+) VALUES (
+  ?,
+  ?
+) RETURNING id
+`
+
+type InsertDiscoveryDatasetsParams struct {
+	Name       string
+	PackagesID int64
+}
+
+func (q *Queries) InsertDiscoveryDatasets(ctx context.Context, arg InsertDiscoveryDatasetsParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, insertDiscoveryDatasets, arg.Name, arg.PackagesID)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
 const insertDiscoveryFields = `-- name: InsertDiscoveryFields :one
 INSERT INTO discovery_fields (
   name,
@@ -381,11 +1102,13 @@ INSERT INTO docs (
   content,
   content_type,
   file_path,
-  packages_id
+  packages_id,
+  truncated
 ) VALUES (
   ?,
   ?,
   ?,
+  ?,
   ?
 ) RETURNING id
 `
@@ -395,6 +1118,7 @@ type InsertDocsParams struct {
 	ContentType string
 	FilePath    string
 	PackagesID  int64
+	Truncated   sql.NullBool
 }
 
 func (q *Queries) InsertDocs(ctx context.Context, arg InsertDocsParams) (int64, error) {
@@ -403,6 +1127,60 @@ func (q *Queries) InsertDocs(ctx context.Context, arg InsertDocsParams) (int64,
 		arg.ContentType,
 		arg.FilePath,
 		arg.PackagesID,
+		arg.Truncated,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const insertExceptionLists = `-- name: InsertExceptionLists :one
+INSERT INTO exception_lists (
+  entries,
+  item_id,
+  kibana_saved_objects_id,
+  list_id,
+  list_type,
+  namespace_type,
+  os_types,
+  tags,
+  type
+) VALUES (
+  ?,
+  ?,
+  ?,
+  ?,
+  ?,
+  ?,
+  ?,
+  ?,
+  ?
+) RETURNING id
+`
+
+type InsertExceptionListsParams struct {
+	Entries              interface{}
+	ItemID               sql.NullString
+	KibanaSavedObjectsID int64
+	ListID               string
+	ListType             string
+	NamespaceType        sql.NullString
+	OsTypes              interface{}
+	Tags                 interface{}
+	Type                 sql.NullString
+}
+
+func (q *Queries) InsertExceptionLists(ctx context.Context, arg InsertExceptionListsParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, insertExceptionLists,
+		arg.Entries,
+		arg.ItemID,
+		arg.KibanaSavedObjectsID,
+		arg.ListID,
+		arg.ListType,
+		arg.NamespaceType,
+		arg.OsTypes,
+		arg.Tags,
+		arg.Type,
 	)
 	var id int64
 	err := row.Scan(&id)
@@ -411,6 +1189,7 @@ func (q *Queries) InsertDocs(ctx context.Context, arg InsertDocsParams) (int64,
 
 const insertFields = `-- name: InsertFields :one
 INSERT INTO fields (
+  allowed_values,
   file_path,
   file_line,
   file_column,
@@ -493,11 +1272,13 @@ INSERT INTO fields (
   ?,
   ?,
   ?,
+  ?,
   ?
 ) RETURNING id
 `
 
 type InsertFieldsParams struct {
+	AllowedValues         interface{}
 	FilePath              sql.NullString
 	FileLine              sql.NullInt64
 	FileColumn            sql.NullInt64
@@ -543,6 +1324,7 @@ type InsertFieldsParams struct {
 
 func (q *Queries) InsertFields(ctx context.Context, arg InsertFieldsParams) (int64, error) {
 	row := q.db.QueryRowContext(ctx, insertFields,
+		arg.AllowedValues,
 		arg.FilePath,
 		arg.FileLine,
 		arg.FileColumn,
@@ -764,6 +1546,8 @@ INSERT INTO kibana_saved_objects (
   object_id,
   object_type,
   packages_id,
+  panels_count,
+  raw,
   reference_count,
   title,
   type_migration_version
@@ -778,6 +1562,8 @@ INSERT INTO kibana_saved_objects (
   ?,
   ?,
   ?,
+  ?,
+  ?,
   ?
 ) RETURNING id
 `
@@ -791,6 +1577,8 @@ type InsertKibanaSavedObjectsParams struct {
 	ObjectID             string
 	ObjectType           sql.NullString
 	PackagesID           int64
+	PanelsCount          sql.NullInt64
+	Raw                  interface{}
 	ReferenceCount       int64
 	Title                sql.NullString
 	TypeMigrationVersion sql.NullString
@@ -806,6 +1594,8 @@ func (q *Queries) InsertKibanaSavedObjects(ctx context.Context, arg InsertKibana
 		arg.ObjectID,
 		arg.ObjectType,
 		arg.PackagesID,
+		arg.PanelsCount,
+		arg.Raw,
 		arg.ReferenceCount,
 		arg.Title,
 		arg.TypeMigrationVersion,
@@ -837,6 +1627,43 @@ func (q *Queries) InsertPackageCategories(ctx context.Context, arg InsertPackage
 	return id, err
 }
 
+const insertPackageDependencies = `-- name: InsertPackageDependencies :one
+INSERT INTO package_dependencies (
+  import_mappings,
+  name,
+  packages_id,
+  reference,
+  version
+) VALUES (
+  ?,
+  ?,
+  ?,
+  ?,
+  ?
+) RETURNING id
+`
+
+type InsertPackageDependenciesParams struct {
+	ImportMappings sql.NullBool
+	Name           string
+	PackagesID     int64
+	Reference      sql.NullString
+	Version        sql.NullString
+}
+
+func (q *Queries) InsertPackageDependencies(ctx context.Context, arg InsertPackageDependenciesParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, insertPackageDependencies,
+		arg.ImportMappings,
+		arg.Name,
+		arg.PackagesID,
+		arg.Reference,
+		arg.Version,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
 const insertPackageFields = `-- name: InsertPackageFields :one
 INSERT INTO package_fields (
   field_id,
@@ -900,6 +1727,47 @@ func (q *Queries) InsertPackageIcons(ctx context.Context, arg InsertPackageIcons
 	return id, err
 }
 
+const insertPackageMetrics = `-- name: InsertPackageMetrics :one
+INSERT INTO package_metrics (
+  data_stream_count,
+  field_count,
+  kibana_object_count,
+  packages_id,
+  pipeline_count,
+  processor_count
+) VALUES (
+  ?,
+  ?,
+  ?,
+  ?,
+  ?,
+  ?
+) RETURNING id
+`
+
+type InsertPackageMetricsParams struct {
+	DataStreamCount   int64
+	FieldCount        int64
+	KibanaObjectCount int64
+	PackagesID        int64
+	PipelineCount     int64
+	ProcessorCount    int64
+}
+
+func (q *Queries) InsertPackageMetrics(ctx context.Context, arg InsertPackageMetricsParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, insertPackageMetrics,
+		arg.DataStreamCount,
+		arg.FieldCount,
+		arg.KibanaObjectCount,
+		arg.PackagesID,
+		arg.PipelineCount,
+		arg.ProcessorCount,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
 const insertPackageScreenshots = `-- name: InsertPackageScreenshots :one
 INSERT INTO package_screenshots (
   packages_id,
@@ -964,10 +1832,14 @@ INSERT INTO packages (
   agent_privileges_root,
   commit_id,
   conditions_agent_version,
+  conditions_elastic_capabilities,
   conditions_elastic_subscription,
   conditions_kibana_version,
+  deprecated_entity_count,
   dir_name,
   elasticsearch_privileges_cluster,
+  elasticsearch_privileges_index,
+  imported_at,
   policy_templates_behavior,
   file_path,
   file_line,
@@ -1001,6 +1873,10 @@ INSERT INTO packages (
   ?,
   ?,
   ?,
+  ?,
+  ?,
+  ?,
+  ?,
   ?
 ) RETURNING id
 `
@@ -1009,10 +1885,14 @@ type InsertPackagesParams struct {
 	AgentPrivilegesRoot            sql.NullBool
 	CommitID                       sql.NullString
 	ConditionsAgentVersion         sql.NullString
+	ConditionsElasticCapabilities  interface{}
 	ConditionsElasticSubscription  sql.NullString
 	ConditionsKibanaVersion        sql.NullString
+	DeprecatedEntityCount          int64
 	DirName                        string
 	ElasticsearchPrivilegesCluster interface{}
+	ElasticsearchPrivilegesIndex   interface{}
+	ImportedAt                     string
 	PolicyTemplatesBehavior        sql.NullString
 	FilePath                       sql.NullString
 	FileLine                       sql.NullInt64
@@ -1033,10 +1913,14 @@ func (q *Queries) InsertPackages(ctx context.Context, arg InsertPackagesParams)
 		arg.AgentPrivilegesRoot,
 		arg.CommitID,
 		arg.ConditionsAgentVersion,
+		arg.ConditionsElasticCapabilities,
 		arg.ConditionsElasticSubscription,
 		arg.ConditionsKibanaVersion,
+		arg.DeprecatedEntityCount,
 		arg.DirName,
 		arg.ElasticsearchPrivilegesCluster,
+		arg.ElasticsearchPrivilegesIndex,
+		arg.ImportedAt,
 		arg.PolicyTemplatesBehavior,
 		arg.FilePath,
 		arg.FileLine,
@@ -1061,7 +1945,9 @@ INSERT INTO pipeline_tests (
   config_path,
   data_streams_id,
   dynamic_fields,
+  event,
   event_path,
+  expected,
   expected_path,
   fields,
   format,
@@ -1084,6 +1970,8 @@ INSERT INTO pipeline_tests (
   ?,
   ?,
   ?,
+  ?,
+  ?,
   ?
 ) RETURNING id
 `
@@ -1092,7 +1980,9 @@ type InsertPipelineTestsParams struct {
 	ConfigPath           sql.NullString
 	DataStreamsID        int64
 	DynamicFields        interface{}
+	Event                sql.NullString
 	EventPath            string
+	Expected             interface{}
 	ExpectedPath         sql.NullString
 	Fields               interface{}
 	Format               string
@@ -1109,7 +1999,9 @@ func (q *Queries) InsertPipelineTests(ctx context.Context, arg InsertPipelineTes
 		arg.ConfigPath,
 		arg.DataStreamsID,
 		arg.DynamicFields,
+		arg.Event,
 		arg.EventPath,
+		arg.Expected,
 		arg.ExpectedPath,
 		arg.Fields,
 		arg.Format,
@@ -1353,10 +2245,13 @@ func (q *Queries) InsertPolicyTemplateVars(ctx context.Context, arg InsertPolicy
 const insertPolicyTemplates = `-- name: InsertPolicyTemplates :one
 INSERT INTO policy_templates (
   packages_id,
+  deployment_modes,
   dynamic_signal_types,
   input,
+  input_count,
   policy_template_type,
   template_path,
+  var_count,
   file_path,
   file_line,
   file_column,
@@ -1402,16 +2297,22 @@ INSERT INTO policy_templates (
   ?,
   ?,
   ?,
+  ?,
+  ?,
+  ?,
   ?
 ) RETURNING id
 `
 
 type InsertPolicyTemplatesParams struct {
 	PackagesID                                      int64
+	DeploymentModes                                 interface{}
 	DynamicSignalTypes                              sql.NullBool
 	Input                                           sql.NullString
+	InputCount                                      int64
 	PolicyTemplateType                              sql.NullString
 	TemplatePath                                    sql.NullString
+	VarCount                                        int64
 	FilePath                                        sql.NullString
 	FileLine                                        sql.NullInt64
 	FileColumn                                      sql.NullInt64
@@ -1437,10 +2338,13 @@ type InsertPolicyTemplatesParams struct {
 func (q *Queries) InsertPolicyTemplates(ctx context.Context, arg InsertPolicyTemplatesParams) (int64, error) {
 	row := q.db.QueryRowContext(ctx, insertPolicyTemplates,
 		arg.PackagesID,
+		arg.DeploymentModes,
 		arg.DynamicSignalTypes,
 		arg.Input,
+		arg.InputCount,
 		arg.PolicyTemplateType,
 		arg.TemplatePath,
+		arg.VarCount,
 		arg.FilePath,
 		arg.FileLine,
 		arg.FileColumn,
@@ -1536,6 +2440,31 @@ func (q *Queries) InsertPolicyTests(ctx context.Context, arg InsertPolicyTestsPa
 	return id, err
 }
 
+const insertProcessorTypeCounts = `-- name: InsertProcessorTypeCounts :one
+INSERT INTO processor_type_counts (
+  count,
+  packages_id,
+  type
+) VALUES (
+  ?,
+  ?,
+  ?
+) RETURNING id
+`
+
+type InsertProcessorTypeCountsParams struct {
+	Count      int64
+	PackagesID int64
+	Type       string
+}
+
+func (q *Queries) InsertProcessorTypeCounts(ctx context.Context, arg InsertProcessorTypeCountsParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, insertProcessorTypeCounts, arg.Count, arg.PackagesID, arg.Type)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
 const insertRoutingRules = `-- name: InsertRoutingRules :one
 INSERT INTO routing_rules (
   data_streams_id,
@@ -1581,6 +2510,28 @@ func (q *Queries) InsertRoutingRules(ctx context.Context, arg InsertRoutingRules
 	return id, err
 }
 
+const insertSampleEventFields = `-- name: InsertSampleEventFields :one
+INSERT INTO sample_event_fields (
+  path,
+  sample_events_id
+) VALUES (
+  ?,
+  ?
+) RETURNING id
+`
+
+type InsertSampleEventFieldsParams struct {
+	Path           string
+	SampleEventsID int64
+}
+
+func (q *Queries) InsertSampleEventFields(ctx context.Context, arg InsertSampleEventFieldsParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, insertSampleEventFields, arg.Path, arg.SampleEventsID)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
 const insertSampleEvents = `-- name: InsertSampleEvents :one
 INSERT INTO sample_events (
   data_streams_id,
@@ -1833,6 +2784,7 @@ INSERT INTO security_rules (
   threat_mapping,
   threat_query,
   threshold,
+  timeline_id,
   timestamp_override,
   type,
   version
@@ -1868,6 +2820,7 @@ INSERT INTO security_rules (
   ?,
   ?,
   ?,
+  ?,
   ?
 ) RETURNING id
 `
@@ -1902,6 +2855,7 @@ type InsertSecurityRulesParams struct {
 	ThreatMapping              interface{}
 	ThreatQuery                sql.NullString
 	Threshold                  interface{}
+	TimelineID                 sql.NullString
 	TimestampOverride          sql.NullString
 	Type                       sql.NullString
 	Version                    sql.NullInt64
@@ -1938,6 +2892,7 @@ func (q *Queries) InsertSecurityRules(ctx context.Context, arg InsertSecurityRul
 		arg.ThreatMapping,
 		arg.ThreatQuery,
 		arg.Threshold,
+		arg.TimelineID,
 		arg.TimestampOverride,
 		arg.Type,
 		arg.Version,
@@ -2307,9 +3262,11 @@ func (q *Queries) InsertTransformFields(ctx context.Context, arg InsertTransform
 const insertTransforms = `-- name: InsertTransforms :one
 INSERT INTO transforms (
   packages_id,
+  dest_index,
   dir_name,
   manifest_destination_index_template,
   manifest_start,
+  retention_max_age,
   file_path,
   file_line,
   file_column,
@@ -2340,15 +3297,19 @@ INSERT INTO transforms (
   ?,
   ?,
   ?,
+  ?,
+  ?,
   ?
 ) RETURNING id
 `
 
 type InsertTransformsParams struct {
 	PackagesID                       int64
+	DestIndex                        sql.NullString
 	DirName                          string
 	ManifestDestinationIndexTemplate interface{}
 	ManifestStart                    sql.NullBool
+	RetentionMaxAge                  sql.NullString
 	FilePath                         sql.NullString
 	FileLine                         sql.NullInt64
 	FileColumn                       sql.NullInt64
@@ -2367,9 +3328,11 @@ type InsertTransformsParams struct {
 func (q *Queries) InsertTransforms(ctx context.Context, arg InsertTransformsParams) (int64, error) {
 	row := q.db.QueryRowContext(ctx, insertTransforms,
 		arg.PackagesID,
+		arg.DestIndex,
 		arg.DirName,
 		arg.ManifestDestinationIndexTemplate,
 		arg.ManifestStart,
+		arg.RetentionMaxAge,
 		arg.FilePath,
 		arg.FileLine,
 		arg.FileColumn,
@@ -2389,6 +3352,28 @@ func (q *Queries) InsertTransforms(ctx context.Context, arg InsertTransformsPara
 	return id, err
 }
 
+const insertValidationExcludedChecks = `-- name: InsertValidationExcludedChecks :one
+INSERT INTO validation_excluded_checks (
+  name,
+  packages_id
+) VALUES (
+  ?,
+  ?
+) RETURNING id
+`
+
+type InsertValidationExcludedChecksParams struct {
+	Name       string
+	PackagesID int64
+}
+
+func (q *Queries) InsertValidationExcludedChecks(ctx context.Context, arg InsertValidationExcludedChecksParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, insertValidationExcludedChecks, arg.Name, arg.PackagesID)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
 const insertVarGroupOptions = `-- name: InsertVarGroupOptions :one
 INSERT INTO var_group_options (
   var_groups_id,
@@ -1,6 +1,7 @@
 CREATE TABLE IF NOT EXISTS fields (
   -- Elasticsearch field definitions, flattened from nested YAML into dotted-path names.
   id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier
+  allowed_values JSON, -- allowed values from the ECS definition (JSON array of objects with name, description, and expected_event_types), populated when WithECSLookup is used
   file_path TEXT, -- source file path
   file_line INTEGER, -- source file line number
   file_column INTEGER, -- source file column number
@@ -50,10 +51,14 @@ CREATE TABLE IF NOT EXISTS packages (
   agent_privileges_root BOOLEAN, -- whether collection requires root privileges in the agent
   commit_id TEXT, -- git HEAD commit ID (populated when WithGitMetadata is used)
   conditions_agent_version TEXT, -- required Elastic Agent version range
+  conditions_elastic_capabilities JSON, -- stack capabilities required by the package (JSON array, e.g. ["security"])
   conditions_elastic_subscription TEXT, -- required Elastic subscription level
   conditions_kibana_version TEXT, -- required Kibana version range
+  deprecated_entity_count INTEGER NOT NULL, -- number of deprecated entities (the package, policy templates, inputs, data streams, and vars)
   dir_name TEXT NOT NULL UNIQUE, -- directory name of the package
   elasticsearch_privileges_cluster JSON, -- Elasticsearch cluster privilege requirements (JSON array)
+  elasticsearch_privileges_index JSON, -- sorted, distinct Elasticsearch index privileges requested by the package's data streams (elasticsearch.privileges.indices); NULL when none are requested
+  imported_at TEXT NOT NULL, -- UTC time the package row was written, in RFC 3339 format
   policy_templates_behavior TEXT, -- behavior when multiple policy templates are defined (all, combined_policy, individual_policies)
   file_path TEXT, -- source file path
   file_line INTEGER, -- source file line number
@@ -88,13 +93,15 @@ CREATE TABLE IF NOT EXISTS changelogs (
   file_line INTEGER, -- source file line number
   file_column INTEGER, -- source file column number
   version TEXT NOT NULL, -- Package version.
-  date TEXT -- Date is the approximate release date, populated via git blame when WithGitMetadata is used.
+  date TEXT CHECK (date IS NULL OR date GLOB '[0-9][0-9][0-9][0-9]-[0-9][0-9]-[0-9][0-9]*') -- Date is the approximate release date, populated via git blame when WithGitMetadata is used.
 );
 
 CREATE TABLE IF NOT EXISTS changelog_entries (
   -- Individual changelog entries within a changelog version.
   id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier
   changelogs_id INTEGER NOT NULL REFERENCES changelogs(id), -- foreign key to changelogs
+  ordinal INTEGER NOT NULL, -- order of the entry within its version's changes (0-based)
+  version TEXT NOT NULL, -- package version of the parent changelog (denormalized from changelogs.version)
   file_path TEXT, -- source file path
   file_line INTEGER, -- source file line number
   file_column INTEGER, -- source file column number
@@ -108,6 +115,9 @@ CREATE TABLE IF NOT EXISTS data_streams (
   id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier
   packages_id INTEGER NOT NULL REFERENCES packages(id), -- foreign key to packages
   dir_name TEXT NOT NULL, -- directory name of the data stream
+  effective_dataset TEXT NOT NULL, -- dataset the data stream writes to: the manifest dataset when set, otherwise <package>.<data stream>
+  index_template_name TEXT, -- index template name installed by Fleet, <type>-<dataset> (e.g. logs-nginx.access); NULL when the data stream has no type
+  stream_count INTEGER NOT NULL, -- number of streams rows
   file_path TEXT, -- source file path
   file_line INTEGER, -- source file line number
   file_column INTEGER, -- source file column number
@@ -125,7 +135,8 @@ CREATE TABLE IF NOT EXISTS data_streams (
   "release" TEXT, -- Stability of data stream.
   title TEXT NOT NULL, -- Title of data stream. It should include the source of the data that is being collected, and the kind of data collected such as logs or metrics. Words should be uppercased.
   type TEXT, -- Type of data stream
-  github_code_owner TEXT -- GithubCodeOwner is the GitHub team code owner from CODEOWNERS, populated when WithCodeowners is used.
+  github_code_owner TEXT, -- GithubCodeOwner is the GitHub team code owner from CODEOWNERS, populated when WithCodeowners is used.
+  github_code_owners JSON -- GithubCodeOwners lists all GitHub code owners from the matching CODEOWNERS line, populated when WithCodeowners is used. GithubCodeOwner holds the first of these.
 );
 
 CREATE TABLE IF NOT EXISTS agent_templates (
@@ -141,7 +152,15 @@ CREATE TABLE IF NOT EXISTS data_stream_fields (
   -- Join table linking fields to data streams.
   id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier
   data_stream_id INTEGER NOT NULL REFERENCES data_streams(id), -- foreign key to data_streams
-  field_id INTEGER NOT NULL REFERENCES fields(id) -- foreign key to fields
+  field_id INTEGER NOT NULL REFERENCES fields(id), -- foreign key to fields
+  UNIQUE (data_stream_id, field_id)
+);
+
+CREATE TABLE IF NOT EXISTS discovery_datasets (
+  -- Datasets a content package can be used with, matched against the data_stream.dataset of existing indices.
+  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier
+  name TEXT NOT NULL, -- name of the dataset
+  packages_id INTEGER NOT NULL REFERENCES packages(id) -- foreign key to packages
 );
 
 CREATE TABLE IF NOT EXISTS discovery_fields (
@@ -155,20 +174,21 @@ CREATE TABLE IF NOT EXISTS docs (
   -- Documentation files within packages. Content is optionally populated when WithDocContent is used.
   id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier
   content TEXT, -- markdown content (NULL unless WithDocContent was used)
-  content_type TEXT NOT NULL, -- classification: readme, doc, or knowledge_base
+  content_type TEXT NOT NULL, -- classification: readme, doc, knowledge_base, or template (_dev/build/docs source)
   file_path TEXT NOT NULL, -- file path relative to the package root (e.g. docs/README.md)
-  packages_id INTEGER NOT NULL REFERENCES packages(id) -- foreign key to packages
+  packages_id INTEGER NOT NULL REFERENCES packages(id), -- foreign key to packages
+  truncated BOOLEAN -- whether content was cut to the WithMaxDocBytes limit (NULL when content is NULL)
 );
 
 CREATE TABLE IF NOT EXISTS images (
   -- Image files within packages (img/ directory). Join with icon/screenshot tables on src to correlate declared metadata with actual image properties.
   id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier
   byte_size INTEGER NOT NULL, -- file size in bytes
-  height INTEGER, -- image height in pixels (NULL for SVG)
+  height INTEGER, -- image height in pixels (NULL for SVG and unrecognized formats)
   packages_id INTEGER NOT NULL REFERENCES packages(id), -- foreign key to packages
   sha256 TEXT NOT NULL, -- hex-encoded SHA-256 hash of file contents
   src TEXT NOT NULL, -- image path with leading slash to match icon/screenshot src (e.g. /img/icon.png)
-  width INTEGER -- image width in pixels (NULL for SVG)
+  width INTEGER -- image width in pixels (NULL for SVG and unrecognized formats)
 );
 
 CREATE TABLE IF NOT EXISTS ingest_pipelines (
@@ -206,11 +226,27 @@ CREATE TABLE IF NOT EXISTS kibana_saved_objects (
   object_id TEXT NOT NULL, -- unique identifier of the saved object
   object_type TEXT, -- object type from JSON (e.g. dashboard, visualization, search)
   packages_id INTEGER NOT NULL REFERENCES packages(id), -- foreign key to packages
+  panels_count INTEGER, -- number of panels in attributes.panelsJSON (dashboards only)
+  raw JSON, -- original JSON file content, byte for byte (NULL unless WithKibanaRaw is used)
   reference_count INTEGER NOT NULL, -- number of references to other saved objects
   title TEXT, -- human-readable title from attributes
   type_migration_version TEXT -- type-specific migration version
 );
 
+CREATE TABLE IF NOT EXISTS exception_lists (
+  -- Exception lists and exception list items extracted from Kibana saved objects of type exception-list (kibana/exception_list and kibana/exception_list_item). Has a 1:1 relationship with kibana_saved_objects. Name and description are on the parent table. Items join to their list on list_id.
+  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier
+  entries JSON, -- item conditions as declared (JSON array, NULL for lists)
+  item_id TEXT, -- item identifier within the list (NULL for lists)
+  kibana_saved_objects_id INTEGER NOT NULL REFERENCES kibana_saved_objects(id), -- foreign key to kibana_saved_objects
+  list_id TEXT NOT NULL, -- list identifier (attributes.list_id); for items, the list the item belongs to
+  list_type TEXT NOT NULL, -- list or item
+  namespace_type TEXT, -- single (space-scoped) or agnostic (shared across spaces)
+  os_types JSON, -- operating systems the exception applies to (JSON array)
+  tags JSON, -- tags (JSON array)
+  type TEXT -- list type (detection, endpoint, rule_default, ...) or item type (simple)
+);
+
 CREATE TABLE IF NOT EXISTS kibana_references (
   -- References between Kibana saved objects. Each row is one reference from a saved object to another, enabling dependency graph queries.
   id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier
@@ -227,11 +263,22 @@ CREATE TABLE IF NOT EXISTS package_categories (
   package_id INTEGER NOT NULL REFERENCES packages(id) -- foreign key to packages
 );
 
+CREATE TABLE IF NOT EXISTS package_dependencies (
+  -- Build dependencies declared in _dev/build/build.yml. Each row is one dependency (ecs or an imported package) of a package.
+  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier
+  import_mappings BOOLEAN, -- whether common dynamic templates and properties are imported (ecs only)
+  name TEXT NOT NULL, -- dependency name (e.g. ecs)
+  packages_id INTEGER NOT NULL REFERENCES packages(id), -- foreign key to packages
+  reference TEXT, -- dependency source reference as written in build.yml (e.g. git@v8.11.0)
+  version TEXT -- version parsed from the reference with any git@ and v prefix removed (e.g. 8.11.0)
+);
+
 CREATE TABLE IF NOT EXISTS package_fields (
   -- Join table linking fields to packages (for input packages).
   id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier
   field_id INTEGER NOT NULL REFERENCES fields(id), -- foreign key to fields
-  package_id INTEGER NOT NULL REFERENCES packages(id) -- foreign key to packages
+  package_id INTEGER NOT NULL REFERENCES packages(id), -- foreign key to packages
+  UNIQUE (package_id, field_id)
 );
 
 CREATE TABLE IF NOT EXISTS package_icons (
@@ -245,6 +292,17 @@ CREATE TABLE IF NOT EXISTS package_icons (
   type TEXT -- MIME type of the icon image file.
 );
 
+CREATE TABLE IF NOT EXISTS package_metrics (
+  -- Aggregate entity counts per package, computed from the inserted rows after each package is written. Avoids COUNT queries over large tables at analysis time.
+  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier
+  data_stream_count INTEGER NOT NULL, -- number of data streams
+  field_count INTEGER NOT NULL, -- number of flattened fields across data streams, package fields, and transforms
+  kibana_object_count INTEGER NOT NULL, -- number of Kibana saved objects
+  packages_id INTEGER NOT NULL REFERENCES packages(id), -- foreign key to packages
+  pipeline_count INTEGER NOT NULL, -- number of ingest pipelines
+  processor_count INTEGER NOT NULL -- number of ingest processors, including nested on_failure handlers
+);
+
 CREATE TABLE IF NOT EXISTS package_screenshots (
   -- Screenshot definitions for a package.
   id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier
@@ -261,7 +319,9 @@ CREATE TABLE IF NOT EXISTS pipeline_tests (
   config_path TEXT, -- path to per-case config file
   data_streams_id INTEGER NOT NULL REFERENCES data_streams(id), -- foreign key to data_streams
   dynamic_fields JSON, -- dynamic fields with regex patterns (from per-case config)
+  event TEXT, -- raw contents of the event file (populated only when written with WithTestContent)
   event_path TEXT NOT NULL, -- path to event file
+  expected JSON, -- contents of the expected output file (populated only when written with WithTestContent)
   expected_path TEXT, -- path to expected output file
   fields JSON, -- field definitions (from per-case config)
   format TEXT NOT NULL, -- event file format (json or raw)
@@ -277,10 +337,13 @@ CREATE TABLE IF NOT EXISTS policy_templates (
   -- Policy templates offered by integration and input packages. Defines how a package is configured in Fleet.
   id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier
   packages_id INTEGER NOT NULL REFERENCES packages(id), -- foreign key to packages
+  deployment_modes JSON, -- deployment_modes object as declared in the manifest (default and agentless settings). NULL when not specified.
   dynamic_signal_types BOOLEAN, -- whether transforms and index templates are created based on pipeline config (input packages only)
   input TEXT, -- input type for input packages (e.g. cel, httpjson)
+  input_count INTEGER NOT NULL, -- number of policy_template_inputs rows (always 0 for input packages)
   policy_template_type TEXT, -- data stream type for input packages (logs, metrics, synthetics, traces)
   template_path TEXT, -- Resolved file path to the agent template relative to the package root (e.g. agent/input/input.yml.hbs). Only set for input packages. Joinable directly to agent_templates.file_path.
+  var_count INTEGER NOT NULL, -- number of vars declared on the policy template itself, not on its inputs (exceeds its policy_template_vars rows when WithVarDedup links a repeated var once)
   file_path TEXT, -- source file path
   file_line INTEGER, -- source file line number
   file_column INTEGER, -- source file column number
@@ -370,6 +433,14 @@ CREATE TABLE IF NOT EXISTS policy_tests (
   vars JSON -- Variables used to configure settings defined in the package manifest.
 );
 
+CREATE TABLE IF NOT EXISTS processor_type_counts (
+  -- Ingest processor counts by type per integration package, covering data stream and package-level pipelines including nested on_failure handlers.
+  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier
+  count INTEGER NOT NULL, -- number of processors of this type
+  packages_id INTEGER NOT NULL REFERENCES packages(id), -- foreign key to packages
+  type TEXT NOT NULL -- processor type (e.g. set, grok, rename)
+);
+
 CREATE TABLE IF NOT EXISTS routing_rules (
   -- Routing rules for rerouting documents from a source dataset (technical preview).
   id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier
@@ -390,6 +461,13 @@ CREATE TABLE IF NOT EXISTS sample_events (
   name TEXT -- sample event name (NULL for sample_event.json; suffix from sample_event_<name>.json otherwise)
 );
 
+CREATE TABLE IF NOT EXISTS sample_event_fields (
+  -- Leaf field paths found in a sample event, one row per dotted path. Sample events that are not valid JSON have no rows. Join with fields on name to check that documented fields appear in the sample.
+  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier
+  path TEXT NOT NULL, -- dotted path of a leaf value in the sample event (e.g. event.dataset); array elements share their parent path
+  sample_events_id INTEGER NOT NULL REFERENCES sample_events(id) -- foreign key to sample_events
+);
+
 CREATE TABLE IF NOT EXISTS security_rules (
   -- Security detection rule attributes extracted from Kibana saved objects of type security_rule. Has a 1:1 relationship with kibana_saved_objects. Title and description are on the parent table.
   id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier
@@ -422,6 +500,7 @@ CREATE TABLE IF NOT EXISTS security_rules (
   threat_mapping JSON, -- threat indicator field mappings for threat_match rules (JSON array)
   threat_query TEXT, -- threat indicator query for threat_match rules
   threshold JSON, -- threshold configuration for threshold rules (JSON object)
+  timeline_id TEXT, -- ID of the Timeline template used when investigating alerts from the rule
   timestamp_override TEXT, -- field name used to override @timestamp for rule execution
   type TEXT, -- rule type: eql, query, new_terms, esql, machine_learning, threshold, threat_match
   version INTEGER -- rule version number
@@ -568,9 +647,11 @@ CREATE TABLE IF NOT EXISTS transforms (
   -- Elasticsearch transform configurations within integration packages.
   id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier
   packages_id INTEGER NOT NULL REFERENCES packages(id), -- foreign key to packages
+  dest_index TEXT, -- destination index the transform writes to (dest.index)
   dir_name TEXT NOT NULL, -- directory name of the transform
   manifest_destination_index_template JSON, -- Elasticsearch index template for the transform destination (JSON)
   manifest_start BOOLEAN, -- whether to start the transform upon installation
+  retention_max_age TEXT, -- maximum age of documents kept in the destination index (retention_policy.time.max_age)
   file_path TEXT, -- source file path
   file_line INTEGER, -- source file line number
   file_column INTEGER, -- source file column number
@@ -590,7 +671,15 @@ CREATE TABLE IF NOT EXISTS transform_fields (
   -- Join table linking fields to transforms.
   id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier
   field_id INTEGER NOT NULL REFERENCES fields(id), -- foreign key to fields
-  transform_id INTEGER NOT NULL REFERENCES transforms(id) -- foreign key to transforms
+  transform_id INTEGER NOT NULL REFERENCES transforms(id), -- foreign key to transforms
+  UNIQUE (transform_id, field_id)
+);
+
+CREATE TABLE IF NOT EXISTS validation_excluded_checks (
+  -- Validation checks excluded by a package in validation.yml (errors.exclude_checks). Enables auditing which packages skip which spec checks.
+  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier
+  name TEXT NOT NULL, -- excluded validation check code (e.g. SVR00002)
+  packages_id INTEGER NOT NULL REFERENCES packages(id) -- foreign key to packages
 );
 
 CREATE TABLE IF NOT EXISTS var_groups (
@@ -665,26 +754,30 @@ CREATE TABLE IF NOT EXISTS package_vars (
   -- Join table linking vars to packages.
   id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier
   package_id INTEGER NOT NULL REFERENCES packages(id), -- foreign key to packages
-  var_id INTEGER NOT NULL REFERENCES vars(id) -- foreign key to vars
+  var_id INTEGER NOT NULL REFERENCES vars(id), -- foreign key to vars
+  UNIQUE (package_id, var_id)
 );
 
 CREATE TABLE IF NOT EXISTS policy_template_input_vars (
   -- Join table linking vars to policy template inputs.
   id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier
   policy_template_input_id INTEGER NOT NULL REFERENCES policy_template_inputs(id), -- foreign key to policy_template_inputs
-  var_id INTEGER NOT NULL REFERENCES vars(id) -- foreign key to vars
+  var_id INTEGER NOT NULL REFERENCES vars(id), -- foreign key to vars
+  UNIQUE (policy_template_input_id, var_id)
 );
 
 CREATE TABLE IF NOT EXISTS policy_template_vars (
   -- Join table linking vars to policy templates.
   id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier
   policy_template_id INTEGER NOT NULL REFERENCES policy_templates(id), -- foreign key to policy_templates
-  var_id INTEGER NOT NULL REFERENCES vars(id) -- foreign key to vars
+  var_id INTEGER NOT NULL REFERENCES vars(id), -- foreign key to vars
+  UNIQUE (policy_template_id, var_id)
 );
 
 CREATE TABLE IF NOT EXISTS stream_vars (
   -- Join table linking vars to streams.
   id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier
   stream_id INTEGER NOT NULL REFERENCES streams(id), -- foreign key to streams
-  var_id INTEGER NOT NULL REFERENCES vars(id) -- foreign key to vars
+  var_id INTEGER NOT NULL REFERENCES vars(id), -- foreign key to vars
+  UNIQUE (stream_id, var_id)
 );
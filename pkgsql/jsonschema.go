@@ -0,0 +1,85 @@
+package pkgsql
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// jsonSchema is a JSON Schema document describing one table row.
+type jsonSchema struct {
+	Schema               string                        `json:"$schema"`
+	Title                string                        `json:"title"`
+	Description          string                        `json:"description,omitempty"`
+	Type                 string                        `json:"type"`
+	Properties           map[string]jsonSchemaProperty `json:"properties"`
+	Required             []string                      `json:"required,omitempty"`
+	AdditionalProperties bool                          `json:"additionalProperties"`
+}
+
+// jsonSchemaProperty describes one column. Type is omitted for JSON columns,
+// which may hold any JSON value.
+type jsonSchemaProperty struct {
+	Type        []string `json:"type,omitempty"`
+	Description string   `json:"description,omitempty"`
+}
+
+// jsonSchemaTypes maps SQL column types to JSON Schema types.
+var jsonSchemaTypes = map[string]string{
+	"TEXT":    "string",
+	"INTEGER": "integer",
+	"REAL":    "number",
+	"BOOLEAN": "boolean",
+}
+
+// TableJSONSchemas returns a JSON Schema (draft 2020-12) document for each
+// table created by TableSchemas, keyed by table name. A schema describes one
+// row as an object whose properties are the table's columns, using the
+// column comments as descriptions. NOT NULL columns are listed as required
+// and other columns also accept null. JSON columns have no type constraint.
+// FTS5 tables and views are not included.
+//
+// WithTablePrefix is applied to the table names used as keys and titles;
+// other options are ignored. It panics if the prefix is invalid.
+//
+// The schemas let LLM agents and other tools reason about the database
+// structure without parsing SQL.
+func TableJSONSchemas(opts ...Option) map[string]json.RawMessage {
+	cfg := &writeConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	prefix := mustTablePrefix(cfg.tablePrefix)
+
+	schemas := make(map[string]json.RawMessage, len(tableInfos))
+	for _, t := range tableInfos {
+		name := prefix + t.name
+		s := jsonSchema{
+			Schema:      "https://json-schema.org/draft/2020-12/schema",
+			Title:       name,
+			Description: t.comment,
+			Type:        "object",
+			Properties:  make(map[string]jsonSchemaProperty, len(t.columns)),
+		}
+		for _, c := range t.columns {
+			p := jsonSchemaProperty{Description: c.comment}
+			if typ, ok := jsonSchemaTypes[c.sqlType]; ok {
+				p.Type = []string{typ}
+				if !c.notNull {
+					p.Type = append(p.Type, "null")
+				}
+			}
+			s.Properties[c.name] = p
+			if c.notNull {
+				s.Required = append(s.Required, c.name)
+			}
+		}
+
+		data, err := json.Marshal(s)
+		if err != nil {
+			// The schema contains only strings, slices, and maps.
+			panic(fmt.Sprintf("marshaling JSON schema for %s: %v", name, err))
+		}
+		schemas[name] = data
+	}
+	return schemas
+}
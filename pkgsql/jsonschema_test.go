@@ -0,0 +1,72 @@
+package pkgsql_test
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/andrewkroh/go-package-spec/pkgsql"
+)
+
+func TestTableJSONSchemas(t *testing.T) {
+	schemas := pkgsql.TableJSONSchemas()
+
+	// Every regular table has a schema.
+	var tables int
+	for _, ddl := range pkgsql.TableSchemas() {
+		if strings.HasPrefix(ddl, "CREATE TABLE ") {
+			tables++
+		}
+	}
+	if len(schemas) != tables {
+		t.Errorf("got %d schemas, want %d (one per table)", len(schemas), tables)
+	}
+
+	raw, ok := schemas["fields"]
+	if !ok {
+		t.Fatal("no schema for fields")
+	}
+
+	type property struct {
+		Type        []string `json:"type"`
+		Description string   `json:"description"`
+	}
+	var schema struct {
+		Schema      string              `json:"$schema"`
+		Title       string              `json:"title"`
+		Description string              `json:"description"`
+		Type        string              `json:"type"`
+		Properties  map[string]property `json:"properties"`
+		Required    []string            `json:"required"`
+	}
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		t.Fatalf("decoding fields schema: %v", err)
+	}
+
+	if schema.Title != "fields" || schema.Type != "object" || schema.Description == "" {
+		t.Errorf("schema header = %q %q %q, want fields object with description", schema.Title, schema.Type, schema.Description)
+	}
+
+	name, ok := schema.Properties["name"]
+	if !ok {
+		t.Fatal("fields schema has no name property")
+	}
+	if !slices.Equal(name.Type, []string{"string"}) {
+		t.Errorf("name type = %v, want [string]", name.Type)
+	}
+	if want := "Name of field. Names containing dots are automatically split into sub-fields."; !strings.HasPrefix(name.Description, want) {
+		t.Errorf("name description = %q, want prefix %q", name.Description, want)
+	}
+	if !slices.Contains(schema.Required, "name") || slices.Contains(schema.Required, "description") {
+		t.Errorf("required = %v, want name but not description", schema.Required)
+	}
+
+	// Nullable columns accept null, and JSON columns accept any value.
+	if got := schema.Properties["description"].Type; !slices.Equal(got, []string{"string", "null"}) {
+		t.Errorf("description type = %v, want [string null]", got)
+	}
+	if got := schema.Properties["example"].Type; got != nil {
+		t.Errorf("example type = %v, want none for a JSON column", got)
+	}
+}
@@ -0,0 +1,165 @@
+package pkgsql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/andrewkroh/go-package-spec/pkgreader"
+	"github.com/andrewkroh/go-package-spec/pkgspec"
+	dbpkg "github.com/andrewkroh/go-package-spec/pkgsql/internal/db"
+)
+
+// packageMetricsQuery counts the rows inserted for a single package. Each
+// field row is linked to exactly one owner (data stream, input package, or
+// transform), so the three link tables can be summed.
+const packageMetricsQuery = `SELECT
+  (SELECT COUNT(*) FROM data_streams WHERE packages_id = ?1),
+  (SELECT COUNT(*) FROM data_stream_fields dsf
+     JOIN data_streams ds ON ds.id = dsf.data_stream_id
+     WHERE ds.packages_id = ?1)
+  + (SELECT COUNT(*) FROM package_fields WHERE package_id = ?1)
+  + (SELECT COUNT(*) FROM transform_fields tf
+     JOIN transforms t ON t.id = tf.transform_id
+     WHERE t.packages_id = ?1),
+  (SELECT COUNT(*) FROM ingest_pipelines ip
+     JOIN data_streams ds ON ds.id = ip.data_streams_id
+     WHERE ds.packages_id = ?1),
+  (SELECT COUNT(*) FROM ingest_processors proc
+     JOIN ingest_pipelines ip ON ip.id = proc.ingest_pipelines_id
+     JOIN data_streams ds ON ds.id = ip.data_streams_id
+     WHERE ds.packages_id = ?1),
+  (SELECT COUNT(*) FROM kibana_saved_objects WHERE packages_id = ?1)`
+
+// writePackageMetrics computes aggregate counts from the rows already
+// inserted for the package and stores them in package_metrics.
+func writePackageMetrics(ctx context.Context, db dbpkg.DBTX, q *dbpkg.Queries, pkgID int64) error {
+	p := dbpkg.InsertPackageMetricsParams{PackagesID: pkgID}
+	err := db.QueryRowContext(ctx, packageMetricsQuery, pkgID).Scan(
+		&p.DataStreamCount,
+		&p.FieldCount,
+		&p.PipelineCount,
+		&p.ProcessorCount,
+		&p.KibanaObjectCount,
+	)
+	if err != nil {
+		return fmt.Errorf("counting package metrics: %w", err)
+	}
+
+	if _, err := q.InsertPackageMetrics(ctx, p); err != nil {
+		return fmt.Errorf("inserting package metrics: %w", err)
+	}
+	return nil
+}
+
+// deprecatedEntityCountQuery sets packages.deprecated_entity_count from the
+// deprecations attributed to the package by the deprecation_summary view.
+const deprecatedEntityCountQuery = `UPDATE packages
+SET deprecated_entity_count = (SELECT COUNT(*) FROM deprecation_summary WHERE packages_id = ?1)
+WHERE id = ?1`
+
+// writeDeprecatedEntityCount counts the deprecations written for the package
+// and stores the count on its packages row, which is inserted before them.
+func writeDeprecatedEntityCount(ctx context.Context, db dbpkg.DBTX, pkgID int64) error {
+	if _, err := db.ExecContext(ctx, deprecatedEntityCountQuery, pkgID); err != nil {
+		return fmt.Errorf("counting deprecated entities: %w", err)
+	}
+	return nil
+}
+
+// writeProcessorTypeCounts counts the ingest processors of every pipeline in
+// the package by type, including package-level pipelines and nested
+// on_failure handlers, and stores one processor_type_counts row per type.
+func writeProcessorTypeCounts(ctx context.Context, q *dbpkg.Queries, pkg *pkgreader.Package, pkgID int64) error {
+	counts := map[string]int64{}
+	count := func(p *pkgspec.Processor, _ string) error {
+		counts[p.Type]++
+		return nil
+	}
+	for _, pf := range pkg.AllPipelines() {
+		_ = pkgspec.WalkProcessors(pf.Pipeline.Processors, count)
+		_ = pkgspec.WalkProcessors(pf.Pipeline.OnFailure, count)
+	}
+
+	for _, typ := range slices.Sorted(maps.Keys(counts)) {
+		_, err := q.InsertProcessorTypeCounts(ctx, dbpkg.InsertProcessorTypeCountsParams{
+			PackagesID: pkgID,
+			Type:       typ,
+			Count:      counts[typ],
+		})
+		if err != nil {
+			return fmt.Errorf("inserting processor type count %s: %w", typ, err)
+		}
+	}
+	return nil
+}
+
+// TableCounts returns the number of rows in every user table of db, keyed by
+// table name. FTS5 virtual tables and their shadow tables are skipped, as in
+// [ExportJSONL].
+func TableCounts(ctx context.Context, db *sql.DB) (map[string]int64, error) {
+	tables, err := userTables(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(tables))
+	for _, table := range tables {
+		var n int64
+		if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM "`+table+`"`).Scan(&n); err != nil {
+			return nil, fmt.Errorf("counting rows in %s: %w", table, err)
+		}
+		counts[table] = n
+	}
+	return counts, nil
+}
+
+// ColumnNullStats returns the number of NULL values in each column of table,
+// keyed by column name. It is intended for data-quality reporting, such as
+// finding optional spec fields that packages rarely populate. Columns are
+// read from the table's schema, and all counts are computed in a single scan.
+func ColumnNullStats(ctx context.Context, db *sql.DB, table string) (map[string]int64, error) {
+	rows, err := db.QueryContext(ctx, "SELECT name FROM pragma_table_info(?)", table)
+	if err != nil {
+		return nil, fmt.Errorf("reading columns of %s: %w", table, err)
+	}
+	var columns []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("reading columns of %s: %w", table, err)
+		}
+		columns = append(columns, name)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading columns of %s: %w", table, err)
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("table %s not found", table)
+	}
+
+	exprs := make([]string, len(columns))
+	for i, col := range columns {
+		exprs[i] = `COUNT(*) FILTER (WHERE "` + col + `" IS NULL)`
+	}
+	counts := make([]int64, len(columns))
+	dest := make([]any, len(columns))
+	for i := range counts {
+		dest[i] = &counts[i]
+	}
+	query := "SELECT " + strings.Join(exprs, ", ") + ` FROM "` + table + `"`
+	if err := db.QueryRowContext(ctx, query).Scan(dest...); err != nil {
+		return nil, fmt.Errorf("counting NULLs in %s: %w", table, err)
+	}
+
+	stats := make(map[string]int64, len(columns))
+	for i, col := range columns {
+		stats[col] = counts[i]
+	}
+	return stats, nil
+}
@@ -0,0 +1,68 @@
+package pkgsql
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// tablePrefixPattern matches the prefixes accepted by WithTablePrefix. The
+// prefix is concatenated into SQL text, so it is restricted to characters
+// that keep the prefixed names plain identifiers.
+var tablePrefixPattern = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// checkTablePrefix returns an error if prefix is not accepted by
+// WithTablePrefix.
+func checkTablePrefix(prefix string) error {
+	if !tablePrefixPattern.MatchString(prefix) {
+		return fmt.Errorf("invalid table prefix %q: must start with a letter or underscore and contain only letters, digits, and underscores", prefix)
+	}
+	return nil
+}
+
+// mustTablePrefix is like checkTablePrefix but panics on an invalid prefix.
+// It is used by functions that cannot return an error.
+func mustTablePrefix(prefix string) string {
+	if err := checkTablePrefix(prefix); err != nil {
+		panic("pkgsql: " + err.Error())
+	}
+	return prefix
+}
+
+// schemaObjectPattern matches the name of a table, view, index, or FTS5
+// table declared by a statement of tableSchemas.
+var schemaObjectPattern = regexp.MustCompile(`\bIF NOT EXISTS (\w+)`)
+
+// schemaObjects is the set of names declared by tableSchemas. Only these
+// names are rewritten by prefixTables, so CTE names, table-valued functions
+// such as json_each, and sqlite_master are left alone.
+var schemaObjects = func() map[string]bool {
+	names := map[string]bool{}
+	for _, ddl := range tableSchemas(defaultFTSTokenizer, true) {
+		for _, m := range schemaObjectPattern.FindAllStringSubmatch(ddl, -1) {
+			names[m[1]] = true
+		}
+	}
+	return names
+}()
+
+// tableRefPattern matches an identifier in a position that names a table,
+// view, or index rather than a column: after IF NOT EXISTS, REFERENCES,
+// INTO, UPDATE, FROM, JOIN, or ON, and as an FTS5 content= option. Column
+// names that coincide with table names (e.g. policy_templates.data_streams)
+// never appear in these positions.
+var tableRefPattern = regexp.MustCompile(`\b(IF NOT EXISTS\s+|REFERENCES\s+|INTO\s+|UPDATE\s+|FROM\s+|JOIN\s+|ON\s+|content=)(\w+)`)
+
+// prefixTables prepends prefix to every reference to a schema object in
+// query. The query is returned unchanged when prefix is empty.
+func prefixTables(query, prefix string) string {
+	if prefix == "" {
+		return query
+	}
+	return tableRefPattern.ReplaceAllStringFunc(query, func(m string) string {
+		sub := tableRefPattern.FindStringSubmatch(m)
+		if !schemaObjects[sub[2]] {
+			return m
+		}
+		return sub[1] + prefix + sub[2]
+	})
+}
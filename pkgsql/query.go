@@ -0,0 +1,86 @@
+package pkgsql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"iter"
+)
+
+// RowStream streams the result of a query one row at a time. Rows are
+// scanned lazily as the iterator returned by [RowStream.All] is consumed,
+// so arbitrarily large results can be written to JSONL or CSV without
+// being held in memory.
+type RowStream struct {
+	rows    *sql.Rows
+	columns []string
+	err     error
+}
+
+// QueryRows executes query and returns a [RowStream] over its results.
+// The caller must either consume [RowStream.All] or call [RowStream.Close]
+// to release the underlying rows.
+func QueryRows(ctx context.Context, db *sql.DB, query string, args ...any) (*RowStream, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("executing query: %w", err)
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		rows.Close()
+		return nil, fmt.Errorf("reading columns: %w", err)
+	}
+
+	return &RowStream{rows: rows, columns: columns}, nil
+}
+
+// Columns returns the result column names in query order.
+func (s *RowStream) Columns() []string {
+	return s.columns
+}
+
+// All returns an iterator that yields each row as a map keyed by column
+// name. The underlying rows are closed when the iterator is exhausted or
+// the loop exits early. Check [RowStream.Err] after iterating. The
+// iterator can only be consumed once.
+func (s *RowStream) All() iter.Seq[map[string]any] {
+	return func(yield func(map[string]any) bool) {
+		defer s.rows.Close()
+
+		values := make([]any, len(s.columns))
+		dest := make([]any, len(s.columns))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+
+		for s.rows.Next() {
+			if err := s.rows.Scan(dest...); err != nil {
+				s.err = fmt.Errorf("scanning row: %w", err)
+				return
+			}
+
+			row := make(map[string]any, len(s.columns))
+			for i, col := range s.columns {
+				row[col] = values[i]
+			}
+			if !yield(row) {
+				return
+			}
+		}
+		if err := s.rows.Err(); err != nil {
+			s.err = fmt.Errorf("iterating rows: %w", err)
+		}
+	}
+}
+
+// Err returns the first error encountered while iterating, if any.
+func (s *RowStream) Err() error {
+	return s.err
+}
+
+// Close releases the underlying rows. It is safe to call after the
+// iterator has completed.
+func (s *RowStream) Close() error {
+	return s.rows.Close()
+}
@@ -0,0 +1,66 @@
+package pkgsql_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/andrewkroh/go-package-spec/pkgsql"
+)
+
+func TestQueryRows(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	for _, stmt := range []string{
+		"CREATE TABLE items (id INTEGER PRIMARY KEY, name TEXT, size REAL)",
+		"INSERT INTO items (name, size) VALUES ('a', 1.5), ('b', NULL), ('c', 3)",
+	} {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rs, err := pkgsql.QueryRows(ctx, db, "SELECT id, name, size FROM items WHERE id >= ? ORDER BY id", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := rs.Columns(); len(got) != 3 || got[0] != "id" || got[1] != "name" || got[2] != "size" {
+		t.Errorf("columns = %v, want [id name size]", got)
+	}
+
+	var names []any
+	for row := range rs.All() {
+		names = append(names, row["name"])
+		if row["name"] == "b" && row["size"] != nil {
+			t.Errorf("size for b = %v, want nil", row["size"])
+		}
+	}
+	if err := rs.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 3 || names[0] != "a" || names[1] != "b" || names[2] != "c" {
+		t.Errorf("names = %v, want [a b c]", names)
+	}
+
+	// Breaking out of the loop early closes the rows so the connection is
+	// released; a following query on a single-connection pool must succeed.
+	db.SetMaxOpenConns(1)
+	rs, err = pkgsql.QueryRows(ctx, db, "SELECT id FROM items ORDER BY id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for row := range rs.All() {
+		if row["id"] != int64(1) {
+			t.Errorf("id = %v, want 1", row["id"])
+		}
+		break
+	}
+	var count int
+	if err := db.QueryRowContext(ctx, "SELECT count(*) FROM items").Scan(&count); err != nil {
+		t.Fatalf("query after early break: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("count = %d, want 3", count)
+	}
+}
@@ -3,25 +3,37 @@ package pkgsql
 import (
 	"context"
 	"database/sql"
+	"strings"
 )
 
 // stmtCache wraps a *sql.Tx and caches prepared statements so that
 // repeated INSERT calls (e.g. thousands of InsertFields) avoid the
 // overhead of re-parsing the SQL on every invocation.
+//
+// When onConflict is set, INSERTs into the joinTables are rewritten to use
+// that conflict resolution algorithm before they are prepared. When
+// tablePrefix is set, table names are rewritten to their prefixed form.
 type stmtCache struct {
-	tx    *sql.Tx
-	cache map[string]*sql.Stmt
+	tx          *sql.Tx
+	cache       map[string]*sql.Stmt
+	onConflict  OnConflict
+	tablePrefix string
 }
 
-func newStmtCache(tx *sql.Tx) *stmtCache {
-	return &stmtCache{tx: tx, cache: make(map[string]*sql.Stmt)}
+func newStmtCache(tx *sql.Tx, onConflict OnConflict, tablePrefix string) *stmtCache {
+	return &stmtCache{tx: tx, cache: make(map[string]*sql.Stmt), onConflict: onConflict, tablePrefix: tablePrefix}
+}
+
+// rewrite applies the conflict resolution and table prefix to query.
+func (c *stmtCache) rewrite(query string) string {
+	return prefixTables(withOnConflict(query, c.onConflict), c.tablePrefix)
 }
 
 func (c *stmtCache) stmt(ctx context.Context, query string) (*sql.Stmt, error) {
 	if s, ok := c.cache[query]; ok {
 		return s, nil
 	}
-	s, err := c.tx.PrepareContext(ctx, query)
+	s, err := c.tx.PrepareContext(ctx, c.rewrite(query))
 	if err != nil {
 		return nil, err
 	}
@@ -29,6 +41,34 @@ func (c *stmtCache) stmt(ctx context.Context, query string) (*sql.Stmt, error) {
 	return s, nil
 }
 
+// joinTables lists the tables with a UNIQUE (parent, child) constraint whose
+// inserts are subject to WithOnConflict.
+var joinTables = []string{
+	"data_stream_fields",
+	"package_fields",
+	"transform_fields",
+	"package_vars",
+	"policy_template_vars",
+	"policy_template_input_vars",
+	"stream_vars",
+}
+
+// withOnConflict rewrites an INSERT into one of the joinTables to
+// "INSERT OR <oc>". Other statements, and all statements when oc is empty,
+// are returned unchanged.
+func withOnConflict(query string, oc OnConflict) string {
+	if oc == "" {
+		return query
+	}
+	for _, t := range joinTables {
+		insert := "INSERT INTO " + t + " ("
+		if strings.Contains(query, insert) {
+			return strings.Replace(query, insert, "INSERT OR "+string(oc)+" INTO "+t+" (", 1)
+		}
+	}
+	return query
+}
+
 func (c *stmtCache) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
 	s, err := c.stmt(ctx, query)
 	if err != nil {
@@ -53,7 +93,7 @@ func (c *stmtCache) QueryRowContext(ctx context.Context, query string, args ...i
 	s, err := c.stmt(ctx, query)
 	if err != nil {
 		// Fall back to uncached path to propagate error through Row.Scan.
-		return c.tx.QueryRowContext(ctx, query, args...)
+		return c.tx.QueryRowContext(ctx, c.rewrite(query), args...)
 	}
 	return s.QueryRowContext(ctx, args...)
 }
@@ -0,0 +1,74 @@
+package pkgsql
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	_ "modernc.org/sqlite"
+
+	dbpkg "github.com/andrewkroh/go-package-spec/pkgsql/internal/db"
+)
+
+func TestStmtCacheOnConflict(t *testing.T) {
+	ctx := context.Background()
+	for _, tc := range []struct {
+		strategy OnConflict
+		wantErr  bool
+	}{
+		{strategy: "", wantErr: true},
+		{strategy: OnConflictIgnore},
+		{strategy: OnConflictReplace},
+	} {
+		name := string(tc.strategy)
+		if name == "" {
+			name = "default"
+		}
+		t.Run(name, func(t *testing.T) {
+			db, err := sql.Open("sqlite", ":memory:")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer db.Close()
+			for _, ddl := range TableSchemas() {
+				if _, err := db.ExecContext(ctx, ddl); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			tx, err := db.BeginTx(ctx, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer tx.Rollback()
+			sc := newStmtCache(tx, tc.strategy, "")
+			defer sc.close()
+			q := dbpkg.New(sc)
+
+			// Link the same field to the same data stream twice.
+			link := dbpkg.InsertDataStreamFieldsParams{DataStreamID: 1, FieldID: 1}
+			if _, err := q.InsertDataStreamFields(ctx, link); err != nil {
+				t.Fatalf("first insert: %v", err)
+			}
+			_, err = q.InsertDataStreamFields(ctx, link)
+			err = linkErr(err)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("expected a UNIQUE constraint error for the duplicate link")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("duplicate insert: %v", err)
+			}
+
+			var rows int
+			if err := tx.QueryRowContext(ctx, "SELECT count(*) FROM data_stream_fields").Scan(&rows); err != nil {
+				t.Fatal(err)
+			}
+			if rows != 1 {
+				t.Errorf("data_stream_fields rows = %d, want 1", rows)
+			}
+		})
+	}
+}
@@ -4,35 +4,41 @@ package pkgsql
 
 // CREATE TABLE statements for each table.
 const (
-	fields                          = "CREATE TABLE IF NOT EXISTS fields (\n  -- Elasticsearch field definitions, flattened from nested YAML into dotted-path names.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  file_path TEXT, -- source file path\n  file_line INTEGER, -- source file line number\n  file_column INTEGER, -- source file column number\n  analyzer TEXT, -- Name of the analyzer to use for indexing. Unless search_analyzer is specified this analyzer is used for both indexing and searching. Only valid for 'type: text'.\n  copy_to TEXT, -- The copy_to parameter allows you to copy the values of multiple fields into a group field, which can then be queried as a single field.\n  date_format TEXT, -- The date format(s) that can be parsed. Type date format default to `strict_date_optional_time||epoch_millis`, see the [doc]. In JSON documents, dates are represented as strings. Elasticsearch uses ...\n  default_metric JSON, -- JSON-encoded DefaultMetric\n  description TEXT, -- Short description of field\n  dimension BOOLEAN, -- Declare a field as dimension of time series. This is attached to the field as a `time_series_dimension` mapping parameter.\n  doc_values BOOLEAN, -- Controls whether doc values are enabled for a field. All fields which support doc values have them enabled by default. If you are sure that you don’t need to sort or aggregate on a field, or acce...\n  dynamic JSON, -- Dynamic controls whether new fields are added dynamically. Accepts true, false, \"strict\", or \"runtime\".\n  enabled BOOLEAN, -- The enabled setting, which can be applied only to the top-level mapping definition and to object fields, causes Elasticsearch to skip parsing of the contents of the field entirely. The JSON can sti...\n  example JSON, -- Example values for this field.\n  expected_values JSON, -- An array of expected values for the field. When defined, these are the only expected values.\n  external TEXT, -- External source reference\n  ignore_above INTEGER, -- Strings longer than the ignore_above setting will not be indexed or stored. For arrays of strings, ignore_above will be applied for each array element separately and string elements longer than ign...\n  ignore_malformed BOOLEAN, -- Trying to index the wrong data type into a field throws an exception by default, and rejects the whole document. The ignore_malformed parameter, if set to true, allows the exception to be ignored. ...\n  include_in_parent BOOLEAN, -- For nested field types, this specifies if all fields in the nested object are also added to the parent document as standard (flat) fields.\n  include_in_root BOOLEAN, -- For nested field types, this specifies if all fields in the nested object are also added to the root document as standard (flat) fields.\n  \"index\" BOOLEAN, -- The index option controls whether field values are indexed. Fields that are not indexed are typically not queryable.\n  inference_id TEXT, -- For semantic_text fields, this specifies the id of the inference endpoint associated with the field\n  metric_type TEXT, -- The metric type of a numeric field. This is attached to the field as a `time_series_metric` mapping parameter. A gauge is a single-value measurement that can go up or down over time, such as a temp...\n  metrics JSON, -- JSON-encoded Metrics\n  multi_fields JSON, -- It is often useful to index the same field in different ways for different purposes. This is the purpose of multi-fields. For instance, a string field could be mapped as a text field for full-text ...\n  name TEXT NOT NULL, -- Name of field. Names containing dots are automatically split into sub-fields. Names with wildcards generate dynamic mappings.\n  normalize JSON, -- Specifies the expected normalizations for a field. `array` normalization implies that the values in the field should always be an array, even if they are single values.\n  normalizer TEXT, -- Specifies the name of a normalizer to apply to keyword fields. A simple normalizer called lowercase ships with elasticsearch and can be used. Custom normalizers can be defined as part of analysis i...\n  null_value JSON, -- The null_value parameter allows you to replace explicit null values with the specified value so that it can be indexed and searched. A null value cannot be indexed or searched. When a field is set ...\n  object_type TEXT, -- Type of the members of the object when `type: object` is used. In these cases a dynamic template is created so direct subobjects of this field have the type indicated. When `object_type_mapping_typ...\n  object_type_mapping_type TEXT, -- Type that members of a field of with `type: object` must have in the source document. This type corresponds to the data type detected by the JSON parser, and is translated to the `match_mapping_typ...\n  path TEXT, -- For alias type fields this is the path to the target field. Note that this must be the full path, including any parent objects (e.g. object1.object2.field).\n  pattern TEXT, -- Regular expression pattern matching the allowed values for the field. This is used for development-time data validation.\n  runtime JSON, -- Runtime specifies if this field is evaluated at query time. Can be a boolean or a script string.\n  scaling_factor INTEGER, -- The scaling factor to use when encoding values. Values will be multiplied by this factor at index time and rounded to the closest long value. For instance, a scaled_float with a scaling_factor of 1...\n  search_analyzer TEXT, -- Name of the analyzer to use for searching. Only valid for 'type: text'.\n  store BOOLEAN, -- By default, field values are indexed, but not stored. This means that the field can be queried, but the original field cannot be retrieved. Setting this value to true ensures that the field is also...\n  subobjects BOOLEAN, -- Specifies if field names containing dots should be expanded into subobjects. For example, if this is set to `true`, a field named `foo.bar` will be expanded into an object with a field named `bar` ...\n  type TEXT, -- Datatype of field. If the type is set to object, a dynamic mapping is created. In this case, if the name doesn't contain any wildcard, the wildcard is added as the last segment of the path.\n  unit TEXT, -- Unit type to associate with a numeric field. This is attached to the field as metadata (via `meta`). By default, a field does not have a unit. The convention for percents is to use value 1 to mean ...\n  value TEXT, -- The value to associate with a constant_keyword field.\n  json_pointer TEXT -- JsonPointer is the RFC 6901 JSON Pointer to this field's location in the original fields file (e.g. /0/fields/1). Set by pkgreader after parsing.\n);\n"
-	packages                        = "CREATE TABLE IF NOT EXISTS packages (\n  -- Fleet packages (integration, input, or content). Each row is one package version.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  agent_privileges_root BOOLEAN, -- whether collection requires root privileges in the agent\n  commit_id TEXT, -- git HEAD commit ID (populated when WithGitMetadata is used)\n  conditions_agent_version TEXT, -- required Elastic Agent version range\n  conditions_elastic_subscription TEXT, -- required Elastic subscription level\n  conditions_kibana_version TEXT, -- required Kibana version range\n  dir_name TEXT NOT NULL UNIQUE, -- directory name of the package\n  elasticsearch_privileges_cluster JSON, -- Elasticsearch cluster privilege requirements (JSON array)\n  policy_templates_behavior TEXT, -- behavior when multiple policy templates are defined (all, combined_policy, individual_policies)\n  file_path TEXT, -- source file path\n  file_line INTEGER, -- source file line number\n  file_column INTEGER, -- source file column number\n  description TEXT NOT NULL, -- A longer description of the package. It should describe, at least all the kinds of data that is collected and with what collectors, following the structure \"Collect X from Y with X\".\n  format_version TEXT NOT NULL, -- The version of the package specification format used by this package.\n  name TEXT NOT NULL, -- The name of the package.\n  owner_github TEXT NOT NULL, -- Github team name of the package maintainer.\n  owner_type TEXT NOT NULL, -- Describes who owns the package and the level of support that is provided. The 'elastic' value indicates that the package is built and maintained by Elastic. The 'partner' value indicates that the p...\n  source_license TEXT, -- Identifier of the license of the package, as specified in https://spdx.org/licenses/.\n  title TEXT NOT NULL, -- Title of the package. It should be the usual title given to the product, service or kind of source being managed by this package.\n  type TEXT NOT NULL, -- The type of package.\n  version TEXT NOT NULL -- The version of the package.\n);\n"
+	fields                          = "CREATE TABLE IF NOT EXISTS fields (\n  -- Elasticsearch field definitions, flattened from nested YAML into dotted-path names.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  allowed_values JSON, -- allowed values from the ECS definition (JSON array of objects with name, description, and expected_event_types), populated when WithECSLookup is used\n  file_path TEXT, -- source file path\n  file_line INTEGER, -- source file line number\n  file_column INTEGER, -- source file column number\n  analyzer TEXT, -- Name of the analyzer to use for indexing. Unless search_analyzer is specified this analyzer is used for both indexing and searching. Only valid for 'type: text'.\n  copy_to TEXT, -- The copy_to parameter allows you to copy the values of multiple fields into a group field, which can then be queried as a single field.\n  date_format TEXT, -- The date format(s) that can be parsed. Type date format default to `strict_date_optional_time||epoch_millis`, see the [doc]. In JSON documents, dates are represented as strings. Elasticsearch uses ...\n  default_metric JSON, -- JSON-encoded DefaultMetric\n  description TEXT, -- Short description of field\n  dimension BOOLEAN, -- Declare a field as dimension of time series. This is attached to the field as a `time_series_dimension` mapping parameter.\n  doc_values BOOLEAN, -- Controls whether doc values are enabled for a field. All fields which support doc values have them enabled by default. If you are sure that you don’t need to sort or aggregate on a field, or acce...\n  dynamic JSON, -- Dynamic controls whether new fields are added dynamically. Accepts true, false, \"strict\", or \"runtime\".\n  enabled BOOLEAN, -- The enabled setting, which can be applied only to the top-level mapping definition and to object fields, causes Elasticsearch to skip parsing of the contents of the field entirely. The JSON can sti...\n  example JSON, -- Example values for this field.\n  expected_values JSON, -- An array of expected values for the field. When defined, these are the only expected values.\n  external TEXT, -- External source reference\n  ignore_above INTEGER, -- Strings longer than the ignore_above setting will not be indexed or stored. For arrays of strings, ignore_above will be applied for each array element separately and string elements longer than ign...\n  ignore_malformed BOOLEAN, -- Trying to index the wrong data type into a field throws an exception by default, and rejects the whole document. The ignore_malformed parameter, if set to true, allows the exception to be ignored. ...\n  include_in_parent BOOLEAN, -- For nested field types, this specifies if all fields in the nested object are also added to the parent document as standard (flat) fields.\n  include_in_root BOOLEAN, -- For nested field types, this specifies if all fields in the nested object are also added to the root document as standard (flat) fields.\n  \"index\" BOOLEAN, -- The index option controls whether field values are indexed. Fields that are not indexed are typically not queryable.\n  inference_id TEXT, -- For semantic_text fields, this specifies the id of the inference endpoint associated with the field\n  metric_type TEXT, -- The metric type of a numeric field. This is attached to the field as a `time_series_metric` mapping parameter. A gauge is a single-value measurement that can go up or down over time, such as a temp...\n  metrics JSON, -- JSON-encoded Metrics\n  multi_fields JSON, -- It is often useful to index the same field in different ways for different purposes. This is the purpose of multi-fields. For instance, a string field could be mapped as a text field for full-text ...\n  name TEXT NOT NULL, -- Name of field. Names containing dots are automatically split into sub-fields. Names with wildcards generate dynamic mappings.\n  normalize JSON, -- Specifies the expected normalizations for a field. `array` normalization implies that the values in the field should always be an array, even if they are single values.\n  normalizer TEXT, -- Specifies the name of a normalizer to apply to keyword fields. A simple normalizer called lowercase ships with elasticsearch and can be used. Custom normalizers can be defined as part of analysis i...\n  null_value JSON, -- The null_value parameter allows you to replace explicit null values with the specified value so that it can be indexed and searched. A null value cannot be indexed or searched. When a field is set ...\n  object_type TEXT, -- Type of the members of the object when `type: object` is used. In these cases a dynamic template is created so direct subobjects of this field have the type indicated. When `object_type_mapping_typ...\n  object_type_mapping_type TEXT, -- Type that members of a field of with `type: object` must have in the source document. This type corresponds to the data type detected by the JSON parser, and is translated to the `match_mapping_typ...\n  path TEXT, -- For alias type fields this is the path to the target field. Note that this must be the full path, including any parent objects (e.g. object1.object2.field).\n  pattern TEXT, -- Regular expression pattern matching the allowed values for the field. This is used for development-time data validation.\n  runtime JSON, -- Runtime specifies if this field is evaluated at query time. Can be a boolean or a script string.\n  scaling_factor INTEGER, -- The scaling factor to use when encoding values. Values will be multiplied by this factor at index time and rounded to the closest long value. For instance, a scaled_float with a scaling_factor of 1...\n  search_analyzer TEXT, -- Name of the analyzer to use for searching. Only valid for 'type: text'.\n  store BOOLEAN, -- By default, field values are indexed, but not stored. This means that the field can be queried, but the original field cannot be retrieved. Setting this value to true ensures that the field is also...\n  subobjects BOOLEAN, -- Specifies if field names containing dots should be expanded into subobjects. For example, if this is set to `true`, a field named `foo.bar` will be expanded into an object with a field named `bar` ...\n  type TEXT, -- Datatype of field. If the type is set to object, a dynamic mapping is created. In this case, if the name doesn't contain any wildcard, the wildcard is added as the last segment of the path.\n  unit TEXT, -- Unit type to associate with a numeric field. This is attached to the field as metadata (via `meta`). By default, a field does not have a unit. The convention for percents is to use value 1 to mean ...\n  value TEXT, -- The value to associate with a constant_keyword field.\n  json_pointer TEXT -- JsonPointer is the RFC 6901 JSON Pointer to this field's location in the original fields file (e.g. /0/fields/1). Set by pkgreader after parsing.\n);\n"
+	packages                        = "CREATE TABLE IF NOT EXISTS packages (\n  -- Fleet packages (integration, input, or content). Each row is one package version.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  agent_privileges_root BOOLEAN, -- whether collection requires root privileges in the agent\n  commit_id TEXT, -- git HEAD commit ID (populated when WithGitMetadata is used)\n  conditions_agent_version TEXT, -- required Elastic Agent version range\n  conditions_elastic_capabilities JSON, -- stack capabilities required by the package (JSON array, e.g. [\"security\"])\n  conditions_elastic_subscription TEXT, -- required Elastic subscription level\n  conditions_kibana_version TEXT, -- required Kibana version range\n  deprecated_entity_count INTEGER NOT NULL, -- number of deprecated entities (the package, policy templates, inputs, data streams, and vars)\n  dir_name TEXT NOT NULL UNIQUE, -- directory name of the package\n  elasticsearch_privileges_cluster JSON, -- Elasticsearch cluster privilege requirements (JSON array)\n  elasticsearch_privileges_index JSON, -- sorted, distinct Elasticsearch index privileges requested by the package's data streams (elasticsearch.privileges.indices); NULL when none are requested\n  imported_at TEXT NOT NULL, -- UTC time the package row was written, in RFC 3339 format\n  policy_templates_behavior TEXT, -- behavior when multiple policy templates are defined (all, combined_policy, individual_policies)\n  file_path TEXT, -- source file path\n  file_line INTEGER, -- source file line number\n  file_column INTEGER, -- source file column number\n  description TEXT NOT NULL, -- A longer description of the package. It should describe, at least all the kinds of data that is collected and with what collectors, following the structure \"Collect X from Y with X\".\n  format_version TEXT NOT NULL, -- The version of the package specification format used by this package.\n  name TEXT NOT NULL, -- The name of the package.\n  owner_github TEXT NOT NULL, -- Github team name of the package maintainer.\n  owner_type TEXT NOT NULL, -- Describes who owns the package and the level of support that is provided. The 'elastic' value indicates that the package is built and maintained by Elastic. The 'partner' value indicates that the p...\n  source_license TEXT, -- Identifier of the license of the package, as specified in https://spdx.org/licenses/.\n  title TEXT NOT NULL, -- Title of the package. It should be the usual title given to the product, service or kind of source being managed by this package.\n  type TEXT NOT NULL, -- The type of package.\n  version TEXT NOT NULL -- The version of the package.\n);\n"
 	buildManifests                  = "CREATE TABLE IF NOT EXISTS build_manifests (\n  -- Build configuration for integration packages (_dev/build/build.yml).\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  packages_id INTEGER NOT NULL REFERENCES packages(id), -- foreign key to packages\n  file_path TEXT, -- source file path\n  file_line INTEGER, -- source file line number\n  file_column INTEGER, -- source file column number\n  dependencies_ecs_import_mappings BOOLEAN, -- Whether or not import common used dynamic templates and properties into the package\n  dependencies_ecs_reference TEXT NOT NULL -- Reference is the ECS version source reference. Values begin with \"git@\" (e.g. \"git@v8.11.0\").\n);\n"
-	changelogs                      = "CREATE TABLE IF NOT EXISTS changelogs (\n  -- Changelog versions for a package. Each row is one version entry with its release date.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  packages_id INTEGER NOT NULL REFERENCES packages(id), -- foreign key to packages\n  file_path TEXT, -- source file path\n  file_line INTEGER, -- source file line number\n  file_column INTEGER, -- source file column number\n  version TEXT NOT NULL, -- Package version.\n  date TEXT -- Date is the approximate release date, populated via git blame when WithGitMetadata is used.\n);\n"
-	changelogEntries                = "CREATE TABLE IF NOT EXISTS changelog_entries (\n  -- Individual changelog entries within a changelog version.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  changelogs_id INTEGER NOT NULL REFERENCES changelogs(id), -- foreign key to changelogs\n  file_path TEXT, -- source file path\n  file_line INTEGER, -- source file line number\n  file_column INTEGER, -- source file column number\n  description TEXT NOT NULL, -- Description of change.\n  link TEXT NOT NULL, -- Link to issue or PR describing change in detail.\n  type TEXT NOT NULL -- Type of change.\n);\n"
-	dataStreams                     = "CREATE TABLE IF NOT EXISTS data_streams (\n  -- Data streams within integration packages. Each row is one data stream with its Elasticsearch and agent config.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  packages_id INTEGER NOT NULL REFERENCES packages(id), -- foreign key to packages\n  dir_name TEXT NOT NULL, -- directory name of the data stream\n  file_path TEXT, -- source file path\n  file_line INTEGER, -- source file line number\n  file_column INTEGER, -- source file column number\n  dataset TEXT, -- Name of data set.\n  dataset_is_prefix BOOLEAN, -- If true, the index pattern in the ES template will contain the dataset as a prefix only\n  elasticsearch_dynamic_dataset BOOLEAN, -- When set to true, agents running this integration are granted data stream privileges for all datasets of its type\n  elasticsearch_dynamic_namespace BOOLEAN, -- When set to true, agents running this integration are granted data stream privileges for all namespaces of its type\n  elasticsearch_index_mode TEXT, -- Index mode to use. Index mode can be used to enable use case specific functionalities. This setting must be installed in the composable index template, not in the package component templates.\n  elasticsearch_index_template JSON, -- Index template definition\n  elasticsearch_privileges JSON, -- Elasticsearch privilege requirements\n  elasticsearch_source_mode TEXT, -- Source mode to use. This configures how the document source (`_source`) is stored for this data stream. If configured as `default`, this mode is not configured and it uses Elasticsearch defaults. I...\n  hidden BOOLEAN, -- Specifies if a data stream is hidden, resulting in dot prefixed system indices. To set the data stream hidden without those dot prefixed indices, check `elasticsearch.index_template.data_stream.hid...\n  ilm_policy TEXT, -- The name of an existing ILM (Index Lifecycle Management) policy\n  provider_permissions JSON, -- Permissions and roles this integration unit requires from the named provider. May be declared at package, policy_template, input, and data_stream levels; entries across all applicable levels are ac...\n  \"release\" TEXT, -- Stability of data stream.\n  title TEXT NOT NULL, -- Title of data stream. It should include the source of the data that is being collected, and the kind of data collected such as logs or metrics. Words should be uppercased.\n  type TEXT, -- Type of data stream\n  github_code_owner TEXT -- GithubCodeOwner is the GitHub team code owner from CODEOWNERS, populated when WithCodeowners is used.\n);\n"
+	changelogs                      = "CREATE TABLE IF NOT EXISTS changelogs (\n  -- Changelog versions for a package. Each row is one version entry with its release date.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  packages_id INTEGER NOT NULL REFERENCES packages(id), -- foreign key to packages\n  file_path TEXT, -- source file path\n  file_line INTEGER, -- source file line number\n  file_column INTEGER, -- source file column number\n  version TEXT NOT NULL, -- Package version.\n  date TEXT CHECK (date IS NULL OR date GLOB '[0-9][0-9][0-9][0-9]-[0-9][0-9]-[0-9][0-9]*') -- Date is the approximate release date, populated via git blame when WithGitMetadata is used.\n);\n"
+	changelogEntries                = "CREATE TABLE IF NOT EXISTS changelog_entries (\n  -- Individual changelog entries within a changelog version.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  changelogs_id INTEGER NOT NULL REFERENCES changelogs(id), -- foreign key to changelogs\n  ordinal INTEGER NOT NULL, -- order of the entry within its version's changes (0-based)\n  version TEXT NOT NULL, -- package version of the parent changelog (denormalized from changelogs.version)\n  file_path TEXT, -- source file path\n  file_line INTEGER, -- source file line number\n  file_column INTEGER, -- source file column number\n  description TEXT NOT NULL, -- Description of change.\n  link TEXT NOT NULL, -- Link to issue or PR describing change in detail.\n  type TEXT NOT NULL -- Type of change.\n);\n"
+	dataStreams                     = "CREATE TABLE IF NOT EXISTS data_streams (\n  -- Data streams within integration packages. Each row is one data stream with its Elasticsearch and agent config.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  packages_id INTEGER NOT NULL REFERENCES packages(id), -- foreign key to packages\n  dir_name TEXT NOT NULL, -- directory name of the data stream\n  effective_dataset TEXT NOT NULL, -- dataset the data stream writes to: the manifest dataset when set, otherwise <package>.<data stream>\n  index_template_name TEXT, -- index template name installed by Fleet, <type>-<dataset> (e.g. logs-nginx.access); NULL when the data stream has no type\n  stream_count INTEGER NOT NULL, -- number of streams rows\n  file_path TEXT, -- source file path\n  file_line INTEGER, -- source file line number\n  file_column INTEGER, -- source file column number\n  dataset TEXT, -- Name of data set.\n  dataset_is_prefix BOOLEAN, -- If true, the index pattern in the ES template will contain the dataset as a prefix only\n  elasticsearch_dynamic_dataset BOOLEAN, -- When set to true, agents running this integration are granted data stream privileges for all datasets of its type\n  elasticsearch_dynamic_namespace BOOLEAN, -- When set to true, agents running this integration are granted data stream privileges for all namespaces of its type\n  elasticsearch_index_mode TEXT, -- Index mode to use. Index mode can be used to enable use case specific functionalities. This setting must be installed in the composable index template, not in the package component templates.\n  elasticsearch_index_template JSON, -- Index template definition\n  elasticsearch_privileges JSON, -- Elasticsearch privilege requirements\n  elasticsearch_source_mode TEXT, -- Source mode to use. This configures how the document source (`_source`) is stored for this data stream. If configured as `default`, this mode is not configured and it uses Elasticsearch defaults. I...\n  hidden BOOLEAN, -- Specifies if a data stream is hidden, resulting in dot prefixed system indices. To set the data stream hidden without those dot prefixed indices, check `elasticsearch.index_template.data_stream.hid...\n  ilm_policy TEXT, -- The name of an existing ILM (Index Lifecycle Management) policy\n  provider_permissions JSON, -- Permissions and roles this integration unit requires from the named provider. May be declared at package, policy_template, input, and data_stream levels; entries across all applicable levels are ac...\n  \"release\" TEXT, -- Stability of data stream.\n  title TEXT NOT NULL, -- Title of data stream. It should include the source of the data that is being collected, and the kind of data collected such as logs or metrics. Words should be uppercased.\n  type TEXT, -- Type of data stream\n  github_code_owner TEXT, -- GithubCodeOwner is the GitHub team code owner from CODEOWNERS, populated when WithCodeowners is used.\n  github_code_owners JSON -- GithubCodeOwners lists all GitHub code owners from the matching CODEOWNERS line, populated when WithCodeowners is used. GithubCodeOwner holds the first of these.\n);\n"
 	agentTemplates                  = "CREATE TABLE IF NOT EXISTS agent_templates (\n  -- Agent Handlebars template files (.yml.hbs) from agent/ directories. Each row is one template file with its raw content. Referenced by streams, policy_templates, and policy_template_inputs via template_path.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  content TEXT NOT NULL, -- raw Handlebars template content\n  data_streams_id INTEGER REFERENCES data_streams(id), -- foreign key to data_streams (set for data stream templates, NULL for package-level)\n  file_path TEXT NOT NULL, -- file path relative to the package root (e.g. data_stream/logs/agent/stream/stream.yml.hbs)\n  packages_id INTEGER NOT NULL REFERENCES packages(id) -- foreign key to packages\n);\n"
-	dataStreamFields                = "CREATE TABLE IF NOT EXISTS data_stream_fields (\n  -- Join table linking fields to data streams.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  data_stream_id INTEGER NOT NULL REFERENCES data_streams(id), -- foreign key to data_streams\n  field_id INTEGER NOT NULL REFERENCES fields(id) -- foreign key to fields\n);\n"
+	dataStreamFields                = "CREATE TABLE IF NOT EXISTS data_stream_fields (\n  -- Join table linking fields to data streams.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  data_stream_id INTEGER NOT NULL REFERENCES data_streams(id), -- foreign key to data_streams\n  field_id INTEGER NOT NULL REFERENCES fields(id), -- foreign key to fields\n  UNIQUE (data_stream_id, field_id)\n);\n"
+	discoveryDatasets               = "CREATE TABLE IF NOT EXISTS discovery_datasets (\n  -- Datasets a content package can be used with, matched against the data_stream.dataset of existing indices.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  name TEXT NOT NULL, -- name of the dataset\n  packages_id INTEGER NOT NULL REFERENCES packages(id) -- foreign key to packages\n);\n"
 	discoveryFields                 = "CREATE TABLE IF NOT EXISTS discovery_fields (\n  -- Fields associated with package discovery capabilities.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  name TEXT NOT NULL, -- name of the field\n  packages_id INTEGER NOT NULL REFERENCES packages(id) -- foreign key to packages\n);\n"
-	docs                            = "CREATE TABLE IF NOT EXISTS docs (\n  -- Documentation files within packages. Content is optionally populated when WithDocContent is used.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  content TEXT, -- markdown content (NULL unless WithDocContent was used)\n  content_type TEXT NOT NULL, -- classification: readme, doc, or knowledge_base\n  file_path TEXT NOT NULL, -- file path relative to the package root (e.g. docs/README.md)\n  packages_id INTEGER NOT NULL REFERENCES packages(id) -- foreign key to packages\n);\n"
-	images                          = "CREATE TABLE IF NOT EXISTS images (\n  -- Image files within packages (img/ directory). Join with icon/screenshot tables on src to correlate declared metadata with actual image properties.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  byte_size INTEGER NOT NULL, -- file size in bytes\n  height INTEGER, -- image height in pixels (NULL for SVG)\n  packages_id INTEGER NOT NULL REFERENCES packages(id), -- foreign key to packages\n  sha256 TEXT NOT NULL, -- hex-encoded SHA-256 hash of file contents\n  src TEXT NOT NULL, -- image path with leading slash to match icon/screenshot src (e.g. /img/icon.png)\n  width INTEGER -- image width in pixels (NULL for SVG)\n);\n"
+	docs                            = "CREATE TABLE IF NOT EXISTS docs (\n  -- Documentation files within packages. Content is optionally populated when WithDocContent is used.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  content TEXT, -- markdown content (NULL unless WithDocContent was used)\n  content_type TEXT NOT NULL, -- classification: readme, doc, knowledge_base, or template (_dev/build/docs source)\n  file_path TEXT NOT NULL, -- file path relative to the package root (e.g. docs/README.md)\n  packages_id INTEGER NOT NULL REFERENCES packages(id), -- foreign key to packages\n  truncated BOOLEAN -- whether content was cut to the WithMaxDocBytes limit (NULL when content is NULL)\n);\n"
+	images                          = "CREATE TABLE IF NOT EXISTS images (\n  -- Image files within packages (img/ directory). Join with icon/screenshot tables on src to correlate declared metadata with actual image properties.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  byte_size INTEGER NOT NULL, -- file size in bytes\n  height INTEGER, -- image height in pixels (NULL for SVG and unrecognized formats)\n  packages_id INTEGER NOT NULL REFERENCES packages(id), -- foreign key to packages\n  sha256 TEXT NOT NULL, -- hex-encoded SHA-256 hash of file contents\n  src TEXT NOT NULL, -- image path with leading slash to match icon/screenshot src (e.g. /img/icon.png)\n  width INTEGER -- image width in pixels (NULL for SVG and unrecognized formats)\n);\n"
 	ingestPipelines                 = "CREATE TABLE IF NOT EXISTS ingest_pipelines (\n  -- Elasticsearch ingest pipeline definitions within data streams.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  data_streams_id INTEGER NOT NULL REFERENCES data_streams(id), -- foreign key to data_streams\n  file_name TEXT NOT NULL, -- file name of the pipeline (e.g. default.yml)\n  file_path TEXT, -- source file path\n  file_line INTEGER, -- source file line number\n  file_column INTEGER, -- source file column number\n  description TEXT -- Description of the pipeline.\n);\n"
 	ingestProcessors                = "CREATE TABLE IF NOT EXISTS ingest_processors (\n  -- Individual ingest processors flattened from pipelines. Nested on_failure handlers are included as separate rows.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  ingest_pipelines_id INTEGER NOT NULL REFERENCES ingest_pipelines(id), -- foreign key to ingest_pipelines\n  attributes JSON, -- JSON-encoded processor attributes\n  json_pointer TEXT NOT NULL, -- RFC 6901 JSON Pointer location within the pipeline\n  ordinal INTEGER NOT NULL, -- order of processor within the pipeline\n  type TEXT NOT NULL, -- processor type (e.g. set, grok, rename)\n  file_path TEXT, -- source file path\n  file_line INTEGER, -- source file line number\n  file_column INTEGER -- source file column number\n);\n"
-	kibanaSavedObjects              = "CREATE TABLE IF NOT EXISTS kibana_saved_objects (\n  -- Kibana saved objects (dashboards, visualizations, security rules, etc.) from the kibana/ directory. Each row is one JSON file.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  asset_type TEXT NOT NULL, -- asset type directory name (e.g. dashboard, visualization, security_rule)\n  core_migration_version TEXT, -- core Kibana migration version\n  description TEXT, -- description from attributes\n  file_path TEXT NOT NULL, -- file path relative to the package root\n  managed BOOLEAN, -- whether the object is managed by Kibana\n  object_id TEXT NOT NULL, -- unique identifier of the saved object\n  object_type TEXT, -- object type from JSON (e.g. dashboard, visualization, search)\n  packages_id INTEGER NOT NULL REFERENCES packages(id), -- foreign key to packages\n  reference_count INTEGER NOT NULL, -- number of references to other saved objects\n  title TEXT, -- human-readable title from attributes\n  type_migration_version TEXT -- type-specific migration version\n);\n"
+	kibanaSavedObjects              = "CREATE TABLE IF NOT EXISTS kibana_saved_objects (\n  -- Kibana saved objects (dashboards, visualizations, security rules, etc.) from the kibana/ directory. Each row is one JSON file.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  asset_type TEXT NOT NULL, -- asset type directory name (e.g. dashboard, visualization, security_rule)\n  core_migration_version TEXT, -- core Kibana migration version\n  description TEXT, -- description from attributes\n  file_path TEXT NOT NULL, -- file path relative to the package root\n  managed BOOLEAN, -- whether the object is managed by Kibana\n  object_id TEXT NOT NULL, -- unique identifier of the saved object\n  object_type TEXT, -- object type from JSON (e.g. dashboard, visualization, search)\n  packages_id INTEGER NOT NULL REFERENCES packages(id), -- foreign key to packages\n  panels_count INTEGER, -- number of panels in attributes.panelsJSON (dashboards only)\n  raw JSON, -- original JSON file content, byte for byte (NULL unless WithKibanaRaw is used)\n  reference_count INTEGER NOT NULL, -- number of references to other saved objects\n  title TEXT, -- human-readable title from attributes\n  type_migration_version TEXT -- type-specific migration version\n);\n"
+	exceptionLists                  = "CREATE TABLE IF NOT EXISTS exception_lists (\n  -- Exception lists and exception list items extracted from Kibana saved objects of type exception-list (kibana/exception_list and kibana/exception_list_item). Has a 1:1 relationship with kibana_saved_objects. Name and description are on the parent table. Items join to their list on list_id.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  entries JSON, -- item conditions as declared (JSON array, NULL for lists)\n  item_id TEXT, -- item identifier within the list (NULL for lists)\n  kibana_saved_objects_id INTEGER NOT NULL REFERENCES kibana_saved_objects(id), -- foreign key to kibana_saved_objects\n  list_id TEXT NOT NULL, -- list identifier (attributes.list_id); for items, the list the item belongs to\n  list_type TEXT NOT NULL, -- list or item\n  namespace_type TEXT, -- single (space-scoped) or agnostic (shared across spaces)\n  os_types JSON, -- operating systems the exception applies to (JSON array)\n  tags JSON, -- tags (JSON array)\n  type TEXT -- list type (detection, endpoint, rule_default, ...) or item type (simple)\n);\n"
 	kibanaReferences                = "CREATE TABLE IF NOT EXISTS kibana_references (\n  -- References between Kibana saved objects. Each row is one reference from a saved object to another, enabling dependency graph queries.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  kibana_saved_objects_id INTEGER NOT NULL REFERENCES kibana_saved_objects(id), -- foreign key to kibana_saved_objects\n  ref_id TEXT NOT NULL, -- referenced object identifier\n  ref_name TEXT NOT NULL, -- reference name (e.g. panel_0, kibanaSavedObjectMeta.searchSourceJSON)\n  ref_type TEXT NOT NULL -- referenced object type (e.g. visualization, search, index-pattern)\n);\n"
 	packageCategories               = "CREATE TABLE IF NOT EXISTS package_categories (\n  -- Categories assigned to a package.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  category TEXT NOT NULL, -- category value\n  package_id INTEGER NOT NULL REFERENCES packages(id) -- foreign key to packages\n);\n"
-	packageFields                   = "CREATE TABLE IF NOT EXISTS package_fields (\n  -- Join table linking fields to packages (for input packages).\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  field_id INTEGER NOT NULL REFERENCES fields(id), -- foreign key to fields\n  package_id INTEGER NOT NULL REFERENCES packages(id) -- foreign key to packages\n);\n"
+	packageDependencies             = "CREATE TABLE IF NOT EXISTS package_dependencies (\n  -- Build dependencies declared in _dev/build/build.yml. Each row is one dependency (ecs or an imported package) of a package.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  import_mappings BOOLEAN, -- whether common dynamic templates and properties are imported (ecs only)\n  name TEXT NOT NULL, -- dependency name (e.g. ecs)\n  packages_id INTEGER NOT NULL REFERENCES packages(id), -- foreign key to packages\n  reference TEXT, -- dependency source reference as written in build.yml (e.g. git@v8.11.0)\n  version TEXT -- version parsed from the reference with any git@ and v prefix removed (e.g. 8.11.0)\n);\n"
+	packageFields                   = "CREATE TABLE IF NOT EXISTS package_fields (\n  -- Join table linking fields to packages (for input packages).\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  field_id INTEGER NOT NULL REFERENCES fields(id), -- foreign key to fields\n  package_id INTEGER NOT NULL REFERENCES packages(id), -- foreign key to packages\n  UNIQUE (package_id, field_id)\n);\n"
 	packageIcons                    = "CREATE TABLE IF NOT EXISTS package_icons (\n  -- Icon definitions for a package.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  packages_id INTEGER NOT NULL REFERENCES packages(id), -- foreign key to packages\n  dark_mode BOOLEAN, -- Is this icon to be shown in dark mode?\n  size TEXT, -- Size of the icon.\n  src TEXT NOT NULL, -- Relative path to the icon's image file.\n  title TEXT, -- Title of icon.\n  type TEXT -- MIME type of the icon image file.\n);\n"
+	packageMetrics                  = "CREATE TABLE IF NOT EXISTS package_metrics (\n  -- Aggregate entity counts per package, computed from the inserted rows after each package is written. Avoids COUNT queries over large tables at analysis time.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  data_stream_count INTEGER NOT NULL, -- number of data streams\n  field_count INTEGER NOT NULL, -- number of flattened fields across data streams, package fields, and transforms\n  kibana_object_count INTEGER NOT NULL, -- number of Kibana saved objects\n  packages_id INTEGER NOT NULL REFERENCES packages(id), -- foreign key to packages\n  pipeline_count INTEGER NOT NULL, -- number of ingest pipelines\n  processor_count INTEGER NOT NULL -- number of ingest processors, including nested on_failure handlers\n);\n"
 	packageScreenshots              = "CREATE TABLE IF NOT EXISTS package_screenshots (\n  -- Screenshot definitions for a package.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  packages_id INTEGER NOT NULL REFERENCES packages(id), -- foreign key to packages\n  size TEXT, -- Size of the screenshot.\n  src TEXT NOT NULL, -- Relative path to the screenshot's image file.\n  title TEXT NOT NULL, -- Title of screenshot.\n  type TEXT -- MIME type of the screenshot image file.\n);\n"
-	pipelineTests                   = "CREATE TABLE IF NOT EXISTS pipeline_tests (\n  -- Pipeline test cases for data streams. Each row is one test event file with optional per-case config.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  config_path TEXT, -- path to per-case config file\n  data_streams_id INTEGER NOT NULL REFERENCES data_streams(id), -- foreign key to data_streams\n  dynamic_fields JSON, -- dynamic fields with regex patterns (from per-case config)\n  event_path TEXT NOT NULL, -- path to event file\n  expected_path TEXT, -- path to expected output file\n  fields JSON, -- field definitions (from per-case config)\n  format TEXT NOT NULL, -- event file format (json or raw)\n  multiline JSON, -- multi-line configuration (from per-case raw config)\n  name TEXT NOT NULL, -- test case stem name (e.g. test-example)\n  numeric_keyword_fields JSON, -- keyword fields allowed numeric values (from per-case config)\n  skip_link TEXT, -- link to issue for skipped test (from per-case config)\n  skip_reason TEXT, -- reason test is skipped (from per-case config)\n  string_number_fields JSON -- numeric fields allowed string values (from per-case config)\n);\n"
-	policyTemplates                 = "CREATE TABLE IF NOT EXISTS policy_templates (\n  -- Policy templates offered by integration and input packages. Defines how a package is configured in Fleet.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  packages_id INTEGER NOT NULL REFERENCES packages(id), -- foreign key to packages\n  dynamic_signal_types BOOLEAN, -- whether transforms and index templates are created based on pipeline config (input packages only)\n  input TEXT, -- input type for input packages (e.g. cel, httpjson)\n  policy_template_type TEXT, -- data stream type for input packages (logs, metrics, synthetics, traces)\n  template_path TEXT, -- Resolved file path to the agent template relative to the package root (e.g. agent/input/input.yml.hbs). Only set for input packages. Joinable directly to agent_templates.file_path.\n  file_path TEXT, -- source file path\n  file_line INTEGER, -- source file line number\n  file_column INTEGER, -- source file column number\n  configuration_links JSON, -- List of links related to inputs and policy templates.\n  data_streams JSON, -- List of data streams compatible with the policy template.\n  deployment_modes_agentless_division TEXT, -- The division responsible for the integration. This is used to tag the agentless agent deployments for monitoring.\n  deployment_modes_agentless_enabled BOOLEAN, -- Indicates if the agentless deployment mode is available for this template policy. It is disabled by default.\n  deployment_modes_agentless_is_default BOOLEAN, -- On policy templates that support multiple deployment modes, this setting can be set to true to use agentless mode by default.\n  deployment_modes_agentless_organization TEXT, -- The responsible organization of the integration. This is used to tag the agentless agent deployments for monitoring.\n  deployment_modes_agentless_release TEXT, -- The maturity level of the agentless deployment mode for this policy template. If not defined, Kibana will provide a default value based on agentless platform maturity. Packages where agentless is t...\n  deployment_modes_agentless_resources_requests_cpu TEXT, -- The amount of CPUs that the Agentless deployment will be initially allocated.\n  deployment_modes_agentless_resources_requests_memory TEXT, -- The amount of memory that the Agentless deployment will be initially allocated.\n  deployment_modes_agentless_team TEXT, -- The team responsible for the integration. This is used to tag the agentless agent deployments for monitoring.\n  deployment_modes_default_enabled BOOLEAN, -- Indicates if the default deployment mode is available for this template policy. It is enabled by default.\n  description TEXT NOT NULL, -- Longer description of policy template.\n  fips_compatible BOOLEAN, -- Indicate if this package is capable of satisfying FIPS requirements. Set to false if it uses any input that cannot be configured to use FIPS cryptography.\n  multiple BOOLEAN, -- Multiple\n  name TEXT NOT NULL, -- Name of policy template.\n  provider_permissions JSON, -- Permissions and roles this integration unit requires from the named provider. May be declared at package, policy_template, input, and data_stream levels; entries across all applicable levels are ac...\n  title TEXT NOT NULL -- Title of policy template.\n);\n"
+	pipelineTests                   = "CREATE TABLE IF NOT EXISTS pipeline_tests (\n  -- Pipeline test cases for data streams. Each row is one test event file with optional per-case config.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  config_path TEXT, -- path to per-case config file\n  data_streams_id INTEGER NOT NULL REFERENCES data_streams(id), -- foreign key to data_streams\n  dynamic_fields JSON, -- dynamic fields with regex patterns (from per-case config)\n  event TEXT, -- raw contents of the event file (populated only when written with WithTestContent)\n  event_path TEXT NOT NULL, -- path to event file\n  expected JSON, -- contents of the expected output file (populated only when written with WithTestContent)\n  expected_path TEXT, -- path to expected output file\n  fields JSON, -- field definitions (from per-case config)\n  format TEXT NOT NULL, -- event file format (json or raw)\n  multiline JSON, -- multi-line configuration (from per-case raw config)\n  name TEXT NOT NULL, -- test case stem name (e.g. test-example)\n  numeric_keyword_fields JSON, -- keyword fields allowed numeric values (from per-case config)\n  skip_link TEXT, -- link to issue for skipped test (from per-case config)\n  skip_reason TEXT, -- reason test is skipped (from per-case config)\n  string_number_fields JSON -- numeric fields allowed string values (from per-case config)\n);\n"
+	policyTemplates                 = "CREATE TABLE IF NOT EXISTS policy_templates (\n  -- Policy templates offered by integration and input packages. Defines how a package is configured in Fleet.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  packages_id INTEGER NOT NULL REFERENCES packages(id), -- foreign key to packages\n  deployment_modes JSON, -- deployment_modes object as declared in the manifest (default and agentless settings). NULL when not specified.\n  dynamic_signal_types BOOLEAN, -- whether transforms and index templates are created based on pipeline config (input packages only)\n  input TEXT, -- input type for input packages (e.g. cel, httpjson)\n  input_count INTEGER NOT NULL, -- number of policy_template_inputs rows (always 0 for input packages)\n  policy_template_type TEXT, -- data stream type for input packages (logs, metrics, synthetics, traces)\n  template_path TEXT, -- Resolved file path to the agent template relative to the package root (e.g. agent/input/input.yml.hbs). Only set for input packages. Joinable directly to agent_templates.file_path.\n  var_count INTEGER NOT NULL, -- number of vars declared on the policy template itself, not on its inputs (exceeds its policy_template_vars rows when WithVarDedup links a repeated var once)\n  file_path TEXT, -- source file path\n  file_line INTEGER, -- source file line number\n  file_column INTEGER, -- source file column number\n  configuration_links JSON, -- List of links related to inputs and policy templates.\n  data_streams JSON, -- List of data streams compatible with the policy template.\n  deployment_modes_agentless_division TEXT, -- The division responsible for the integration. This is used to tag the agentless agent deployments for monitoring.\n  deployment_modes_agentless_enabled BOOLEAN, -- Indicates if the agentless deployment mode is available for this template policy. It is disabled by default.\n  deployment_modes_agentless_is_default BOOLEAN, -- On policy templates that support multiple deployment modes, this setting can be set to true to use agentless mode by default.\n  deployment_modes_agentless_organization TEXT, -- The responsible organization of the integration. This is used to tag the agentless agent deployments for monitoring.\n  deployment_modes_agentless_release TEXT, -- The maturity level of the agentless deployment mode for this policy template. If not defined, Kibana will provide a default value based on agentless platform maturity. Packages where agentless is t...\n  deployment_modes_agentless_resources_requests_cpu TEXT, -- The amount of CPUs that the Agentless deployment will be initially allocated.\n  deployment_modes_agentless_resources_requests_memory TEXT, -- The amount of memory that the Agentless deployment will be initially allocated.\n  deployment_modes_agentless_team TEXT, -- The team responsible for the integration. This is used to tag the agentless agent deployments for monitoring.\n  deployment_modes_default_enabled BOOLEAN, -- Indicates if the default deployment mode is available for this template policy. It is enabled by default.\n  description TEXT NOT NULL, -- Longer description of policy template.\n  fips_compatible BOOLEAN, -- Indicate if this package is capable of satisfying FIPS requirements. Set to false if it uses any input that cannot be configured to use FIPS cryptography.\n  multiple BOOLEAN, -- Multiple\n  name TEXT NOT NULL, -- Name of policy template.\n  provider_permissions JSON, -- Permissions and roles this integration unit requires from the named provider. May be declared at package, policy_template, input, and data_stream levels; entries across all applicable levels are ac...\n  title TEXT NOT NULL -- Title of policy template.\n);\n"
 	policyTemplateCategories        = "CREATE TABLE IF NOT EXISTS policy_template_categories (\n  -- Categories assigned to a policy template.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  category TEXT NOT NULL, -- category value\n  policy_template_id INTEGER NOT NULL REFERENCES policy_templates(id) -- foreign key to policy_templates\n);\n"
 	policyTemplateIcons             = "CREATE TABLE IF NOT EXISTS policy_template_icons (\n  -- Icon definitions for a policy template.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  policy_templates_id INTEGER NOT NULL REFERENCES policy_templates(id), -- foreign key to policy_templates\n  dark_mode BOOLEAN, -- Is this icon to be shown in dark mode?\n  size TEXT, -- Size of the icon.\n  src TEXT NOT NULL, -- Relative path to the icon's image file.\n  title TEXT, -- Title of icon.\n  type TEXT -- MIME type of the icon image file.\n);\n"
 	policyTemplateInputs            = "CREATE TABLE IF NOT EXISTS policy_template_inputs (\n  -- Inputs defined within a policy template.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  policy_templates_id INTEGER NOT NULL REFERENCES policy_templates(id), -- foreign key to policy_templates\n  deployment_modes JSON, -- List of deployment modes that this input is compatible with. If not specified, the input is compatible with all deployment modes.\n  description TEXT NOT NULL, -- Longer description of input.\n  dynamic_signal_types BOOLEAN, -- When enabled, decides the transforms and index templates that need to be created depending on the pipelines specified in the configuration. This field is only allowed when the input type is 'otelcol'.\n  hide_in_var_group_options JSON, -- HideInVarGroupOptions filters out specific var_group options for this input.\n  input_group TEXT, -- Name of the input group\n  migrate_from TEXT, -- Previous input type to migrate configuration from. This allows Fleet to automatically migrate the policy configuration when replacing one input implementation with an equivalent one. This field sho...\n  multi BOOLEAN, -- Can input be defined multiple times\n  name TEXT, -- Unique name for this input within the policy template. When set, data streams reference this input by name instead of type, allowing multiple inputs of the same type to coexist in the same policy t...\n  package TEXT, -- Reference to an input package. When specified, configuration is inherited from the referenced package. The package must be listed in the manifest's requires section.\n  provider_permissions JSON, -- Permissions and roles this integration unit requires from the named provider. May be declared at package, policy_template, input, and data_stream levels; entries across all applicable levels are ac...\n  show_divider BOOLEAN, -- When false, suppresses the automatic horizontal divider rendered after this section.\n  template_path TEXT, -- Resolved file path to the agent template relative to the package root (e.g. agent/input/httpjson.yml.hbs). NULL when not specified. Joinable directly to agent_templates.file_path.\n  template_paths JSON, -- Paths of the config templates. Templates are rendered and merged sequentially; later templates override earlier ones for conflicting keys.\n  title TEXT NOT NULL, -- Title of input.\n  type TEXT -- Type of input.\n);\n"
 	policyTemplateScreenshots       = "CREATE TABLE IF NOT EXISTS policy_template_screenshots (\n  -- Screenshot definitions for a policy template.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  policy_templates_id INTEGER NOT NULL REFERENCES policy_templates(id), -- foreign key to policy_templates\n  size TEXT, -- Size of the screenshot.\n  src TEXT NOT NULL, -- Relative path to the screenshot's image file.\n  title TEXT NOT NULL, -- Title of screenshot.\n  type TEXT -- MIME type of the screenshot image file.\n);\n"
 	policyTests                     = "CREATE TABLE IF NOT EXISTS policy_tests (\n  -- Policy test cases for data streams and input packages.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  case_name TEXT NOT NULL, -- test case name extracted from filename\n  data_streams_id INTEGER REFERENCES data_streams(id), -- foreign key to data_streams (set for integration packages)\n  packages_id INTEGER REFERENCES packages(id), -- foreign key to packages (set for input packages)\n  file_path TEXT, -- source file path\n  file_line INTEGER, -- source file line number\n  file_column INTEGER, -- source file column number\n  data_stream JSON, -- Configuration for the data stream.\n  input TEXT, -- The input of the package to test.\n  policy_api_format TEXT, -- Tests can create policies using the Fleet APIs with different formats. The \"legacy\" format requires to send variables with hints about their type, and defaults are not managed automatically. The ne...\n  requires JSON, -- Package dependencies required for this test with exact versions.\n  skip_link TEXT NOT NULL, -- Link to issue with more details about skipped test or to track re-enabling skipped test.\n  skip_reason TEXT NOT NULL, -- Short explanation for why test has been skipped.\n  vars JSON -- Variables used to configure settings defined in the package manifest.\n);\n"
+	processorTypeCounts             = "CREATE TABLE IF NOT EXISTS processor_type_counts (\n  -- Ingest processor counts by type per integration package, covering data stream and package-level pipelines including nested on_failure handlers.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  count INTEGER NOT NULL, -- number of processors of this type\n  packages_id INTEGER NOT NULL REFERENCES packages(id), -- foreign key to packages\n  type TEXT NOT NULL -- processor type (e.g. set, grok, rename)\n);\n"
 	routingRules                    = "CREATE TABLE IF NOT EXISTS routing_rules (\n  -- Routing rules for rerouting documents from a source dataset (technical preview).\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  data_streams_id INTEGER NOT NULL REFERENCES data_streams(id), -- foreign key to data_streams\n  file_path TEXT, -- source file path\n  file_line INTEGER, -- source file line number\n  file_column INTEGER, -- source file column number\n  \"if\" TEXT NOT NULL, -- Conditionally execute the processor\n  namespace JSON, -- Namespace is the field reference or static value for the namespace part of the data stream name.\n  target_dataset JSON -- TargetDataset is the field reference or static value for the dataset part of the data stream name.\n);\n"
 	sampleEvents                    = "CREATE TABLE IF NOT EXISTS sample_events (\n  -- Sample event data for data streams. NULL name indicates the unnamed default sample_event.json; non-NULL names correspond to sample_event_<name>.json files referenced by SystemTestConfig samples.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  data_streams_id INTEGER NOT NULL REFERENCES data_streams(id), -- foreign key to data_streams\n  event JSON NOT NULL, -- sample event data (JSON)\n  name TEXT -- sample event name (NULL for sample_event.json; suffix from sample_event_<name>.json otherwise)\n);\n"
-	securityRules                   = "CREATE TABLE IF NOT EXISTS security_rules (\n  -- Security detection rule attributes extracted from Kibana saved objects of type security_rule. Has a 1:1 relationship with kibana_saved_objects. Title and description are on the parent table.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  anomaly_threshold INTEGER, -- anomaly score threshold for machine_learning rules\n  author JSON, -- rule authors (JSON array of strings)\n  building_block_type TEXT, -- building block type when rule is a building block\n  enabled BOOLEAN, -- whether the rule is enabled by default\n  false_positives JSON, -- known false positive scenarios (JSON array of strings)\n  from_time TEXT, -- time range start for query (e.g. now-9m). Named from_time because FROM is reserved.\n  interval TEXT, -- check interval (e.g. 5m)\n  kibana_saved_objects_id INTEGER NOT NULL REFERENCES kibana_saved_objects(id), -- foreign key to kibana_saved_objects\n  language TEXT, -- query language: kuery, eql, esql, lucene\n  license TEXT, -- rule license (e.g. Elastic License v2)\n  machine_learning_job_id JSON, -- ML job identifier(s) for machine_learning rules (JSON string or array)\n  max_signals INTEGER, -- maximum alerts per execution\n  new_terms_fields JSON, -- fields for new_terms rules (JSON array)\n  new_terms_history_window_start TEXT, -- history window start for new_terms rules\n  note TEXT, -- markdown investigation/triage guide\n  \"query\" TEXT, -- detection query text (EQL, KQL, ESQL, or Lucene)\n  \"references\" JSON, -- external reference URLs (JSON array of strings)\n  risk_score REAL, -- numeric risk score (0-100)\n  risk_score_mapping JSON, -- risk score mapping configuration (JSON array)\n  rule_id TEXT NOT NULL, -- unique rule identifier (attributes.rule_id)\n  rule_name_override TEXT, -- field name used to override the rule name in alerts\n  setup TEXT, -- markdown setup instructions\n  severity TEXT, -- severity level: low, medium, high, critical\n  severity_mapping JSON, -- severity mapping configuration (JSON array)\n  threat_index JSON, -- threat indicator indices for threat_match rules (JSON array)\n  threat_indicator_path TEXT, -- path to threat indicator field for threat_match rules\n  threat_mapping JSON, -- threat indicator field mappings for threat_match rules (JSON array)\n  threat_query TEXT, -- threat indicator query for threat_match rules\n  threshold JSON, -- threshold configuration for threshold rules (JSON object)\n  timestamp_override TEXT, -- field name used to override @timestamp for rule execution\n  type TEXT, -- rule type: eql, query, new_terms, esql, machine_learning, threshold, threat_match\n  version INTEGER -- rule version number\n);\n"
+	sampleEventFields               = "CREATE TABLE IF NOT EXISTS sample_event_fields (\n  -- Leaf field paths found in a sample event, one row per dotted path. Sample events that are not valid JSON have no rows. Join with fields on name to check that documented fields appear in the sample.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  path TEXT NOT NULL, -- dotted path of a leaf value in the sample event (e.g. event.dataset); array elements share their parent path\n  sample_events_id INTEGER NOT NULL REFERENCES sample_events(id) -- foreign key to sample_events\n);\n"
+	securityRules                   = "CREATE TABLE IF NOT EXISTS security_rules (\n  -- Security detection rule attributes extracted from Kibana saved objects of type security_rule. Has a 1:1 relationship with kibana_saved_objects. Title and description are on the parent table.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  anomaly_threshold INTEGER, -- anomaly score threshold for machine_learning rules\n  author JSON, -- rule authors (JSON array of strings)\n  building_block_type TEXT, -- building block type when rule is a building block\n  enabled BOOLEAN, -- whether the rule is enabled by default\n  false_positives JSON, -- known false positive scenarios (JSON array of strings)\n  from_time TEXT, -- time range start for query (e.g. now-9m). Named from_time because FROM is reserved.\n  interval TEXT, -- check interval (e.g. 5m)\n  kibana_saved_objects_id INTEGER NOT NULL REFERENCES kibana_saved_objects(id), -- foreign key to kibana_saved_objects\n  language TEXT, -- query language: kuery, eql, esql, lucene\n  license TEXT, -- rule license (e.g. Elastic License v2)\n  machine_learning_job_id JSON, -- ML job identifier(s) for machine_learning rules (JSON string or array)\n  max_signals INTEGER, -- maximum alerts per execution\n  new_terms_fields JSON, -- fields for new_terms rules (JSON array)\n  new_terms_history_window_start TEXT, -- history window start for new_terms rules\n  note TEXT, -- markdown investigation/triage guide\n  \"query\" TEXT, -- detection query text (EQL, KQL, ESQL, or Lucene)\n  \"references\" JSON, -- external reference URLs (JSON array of strings)\n  risk_score REAL, -- numeric risk score (0-100)\n  risk_score_mapping JSON, -- risk score mapping configuration (JSON array)\n  rule_id TEXT NOT NULL, -- unique rule identifier (attributes.rule_id)\n  rule_name_override TEXT, -- field name used to override the rule name in alerts\n  setup TEXT, -- markdown setup instructions\n  severity TEXT, -- severity level: low, medium, high, critical\n  severity_mapping JSON, -- severity mapping configuration (JSON array)\n  threat_index JSON, -- threat indicator indices for threat_match rules (JSON array)\n  threat_indicator_path TEXT, -- path to threat indicator field for threat_match rules\n  threat_mapping JSON, -- threat indicator field mappings for threat_match rules (JSON array)\n  threat_query TEXT, -- threat indicator query for threat_match rules\n  threshold JSON, -- threshold configuration for threshold rules (JSON object)\n  timeline_id TEXT, -- ID of the Timeline template used when investigating alerts from the rule\n  timestamp_override TEXT, -- field name used to override @timestamp for rule execution\n  type TEXT, -- rule type: eql, query, new_terms, esql, machine_learning, threshold, threat_match\n  version INTEGER -- rule version number\n);\n"
 	securityRuleIndexPatterns       = "CREATE TABLE IF NOT EXISTS security_rule_index_patterns (\n  -- Elasticsearch index patterns monitored by a security rule. Enables queries like \"which rules monitor logs-okta*?\"\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  pattern TEXT NOT NULL, -- index pattern (e.g. logs-endpoint.events.*, endgame-*)\n  security_rules_id INTEGER NOT NULL REFERENCES security_rules(id) -- foreign key to security_rules\n);\n"
 	securityRuleRelatedIntegrations = "CREATE TABLE IF NOT EXISTS security_rule_related_integrations (\n  -- Integrations related to a security rule. Enables queries like \"which rules relate to the okta integration?\"\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  integration TEXT, -- specific integration within the package\n  package TEXT NOT NULL, -- integration package name (e.g. endpoint, okta)\n  security_rules_id INTEGER NOT NULL REFERENCES security_rules(id), -- foreign key to security_rules\n  version TEXT -- required version range (e.g. ^8.2.0)\n);\n"
 	securityRuleRequiredFields      = "CREATE TABLE IF NOT EXISTS security_rule_required_fields (\n  -- Fields required by a security rule. Enables queries like \"which rules depend on event.kind?\"\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  ecs BOOLEAN, -- whether the field is from ECS\n  name TEXT NOT NULL, -- field name (e.g. event.action, process.name)\n  security_rules_id INTEGER NOT NULL REFERENCES security_rules(id), -- foreign key to security_rules\n  type TEXT -- field type (e.g. keyword, long)\n);\n"
@@ -44,17 +50,928 @@ const (
 	systemTests                     = "CREATE TABLE IF NOT EXISTS system_tests (\n  -- System test cases for data streams and input packages.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  case_name TEXT NOT NULL, -- test case name extracted from filename\n  data_streams_id INTEGER REFERENCES data_streams(id), -- foreign key to data_streams (set for integration packages)\n  packages_id INTEGER REFERENCES packages(id), -- foreign key to packages (set for input packages)\n  file_path TEXT, -- source file path\n  file_line INTEGER, -- source file line number\n  file_column INTEGER, -- source file column number\n  agent_base_image TEXT, -- Elastic Agent image to be used for testing. Setting `default` will be used the same Elastic Agent image as the stack. Setting `systemd` will use the image containing all the binaries for running Be...\n  agent_linux_capabilities JSON, -- Linux Capabilities that must been enabled in the system to run the Elastic Agent process\n  agent_pid_mode TEXT, -- Control access to PID namespaces. When set to `host`, the Elastic Agent will have access to the PID namespace of the host.\n  agent_ports JSON, -- List of ports to be exposed to access to the Elastic Agent\n  agent_pre_start_script_contents TEXT NOT NULL, -- Code to run before starting the Elastic Agent.\n  agent_pre_start_script_language TEXT, -- Programming language of the pre-start script. Currently, only \"sh\" is supported.\n  agent_provisioning_script_contents TEXT NOT NULL, -- Code to run as a provisioning script.\n  agent_provisioning_script_language TEXT, -- Programming language of the provisioning script.\n  agent_runtime TEXT, -- Runtime to run the Elastic Agent process\n  agent_user TEXT, -- User that runs the Elastic Agent process\n  data_stream JSON, -- JSON-encoded DataStream\n  deployer TEXT, -- Name of the service deployer to setup for this system benchmark.\n  policy_api_format TEXT, -- Tests can create policies using the Fleet APIs with different formats. The \"legacy\" format requires to send variables with hints about their type, and defaults are not managed automatically. The ne...\n  requires JSON, -- Package dependencies required for this test with exact versions.\n  skip_link TEXT NOT NULL, -- Link to issue with more details about skipped test or to track re-enabling skipped test.\n  skip_reason TEXT NOT NULL, -- Short explanation for why test has been skipped.\n  skip_ignored_fields JSON, -- If listed here, elastic-package system tests will not fail if values for the specified field names can't be indexed for any incoming documents. This should only be used if the failure is related to...\n  vars JSON, -- Variables used to configure settings defined in the package manifest.\n  wait_for_data_timeout TEXT -- Timeout for waiting for metrics data during a system test.\n);\n"
 	systemTestSamples               = "CREATE TABLE IF NOT EXISTS system_test_samples (\n  -- Sample event files to collect from a system test, with optional document filtering condition. Each entry references a sample_event_<name>.json file.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  system_tests_id INTEGER NOT NULL REFERENCES system_tests(id), -- foreign key to system_tests\n  condition_key TEXT NOT NULL, -- Field name to check in the document.\n  condition_value TEXT, -- Expected value of the field.\n  name TEXT NOT NULL -- Name identifying the sample event file to use. Corresponds to the suffix in `sample_event_<name>.json`.\n);\n"
 	tags                            = "CREATE TABLE IF NOT EXISTS tags (\n  -- Kibana tags associated with integration packages.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  packages_id INTEGER NOT NULL REFERENCES packages(id), -- foreign key to packages\n  file_path TEXT, -- source file path\n  file_line INTEGER, -- source file line number\n  file_column INTEGER, -- source file column number\n  asset_ids JSON, -- Asset IDs where this tag is going to be added. If two or more pacakges define the same tag, there will be just one tag created in Kibana and all the assets will be using the same tag.\n  asset_types JSON, -- This tag will be added to all the assets of these types included in the package. If two or more pacakges define the same tag, there will be just one tag created in Kibana and all the assets will be...\n  text TEXT -- Tag name.\n);\n"
-	transforms                      = "CREATE TABLE IF NOT EXISTS transforms (\n  -- Elasticsearch transform configurations within integration packages.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  packages_id INTEGER NOT NULL REFERENCES packages(id), -- foreign key to packages\n  dir_name TEXT NOT NULL, -- directory name of the transform\n  manifest_destination_index_template JSON, -- Elasticsearch index template for the transform destination (JSON)\n  manifest_start BOOLEAN, -- whether to start the transform upon installation\n  file_path TEXT, -- source file path\n  file_line INTEGER, -- source file line number\n  file_column INTEGER, -- source file column number\n  meta JSON, -- Meta holds user-defined metadata about the transform.\n  description TEXT, -- Description\n  dest JSON, -- JSON-encoded Dest\n  frequency TEXT, -- Frequency\n  latest JSON, -- JSON-encoded Latest\n  pivot JSON, -- JSON-encoded Pivot\n  retention_policy JSON, -- JSON-encoded RetentionPolicy\n  settings JSON, -- JSON-encoded Settings\n  source JSON, -- JSON-encoded Source\n  sync JSON -- JSON-encoded Sync\n);\n"
-	transformFields                 = "CREATE TABLE IF NOT EXISTS transform_fields (\n  -- Join table linking fields to transforms.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  field_id INTEGER NOT NULL REFERENCES fields(id), -- foreign key to fields\n  transform_id INTEGER NOT NULL REFERENCES transforms(id) -- foreign key to transforms\n);\n"
+	transforms                      = "CREATE TABLE IF NOT EXISTS transforms (\n  -- Elasticsearch transform configurations within integration packages.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  packages_id INTEGER NOT NULL REFERENCES packages(id), -- foreign key to packages\n  dest_index TEXT, -- destination index the transform writes to (dest.index)\n  dir_name TEXT NOT NULL, -- directory name of the transform\n  manifest_destination_index_template JSON, -- Elasticsearch index template for the transform destination (JSON)\n  manifest_start BOOLEAN, -- whether to start the transform upon installation\n  retention_max_age TEXT, -- maximum age of documents kept in the destination index (retention_policy.time.max_age)\n  file_path TEXT, -- source file path\n  file_line INTEGER, -- source file line number\n  file_column INTEGER, -- source file column number\n  meta JSON, -- Meta holds user-defined metadata about the transform.\n  description TEXT, -- Description\n  dest JSON, -- JSON-encoded Dest\n  frequency TEXT, -- Frequency\n  latest JSON, -- JSON-encoded Latest\n  pivot JSON, -- JSON-encoded Pivot\n  retention_policy JSON, -- JSON-encoded RetentionPolicy\n  settings JSON, -- JSON-encoded Settings\n  source JSON, -- JSON-encoded Source\n  sync JSON -- JSON-encoded Sync\n);\n"
+	transformFields                 = "CREATE TABLE IF NOT EXISTS transform_fields (\n  -- Join table linking fields to transforms.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  field_id INTEGER NOT NULL REFERENCES fields(id), -- foreign key to fields\n  transform_id INTEGER NOT NULL REFERENCES transforms(id), -- foreign key to transforms\n  UNIQUE (transform_id, field_id)\n);\n"
+	validationExcludedChecks        = "CREATE TABLE IF NOT EXISTS validation_excluded_checks (\n  -- Validation checks excluded by a package in validation.yml (errors.exclude_checks). Enables auditing which packages skip which spec checks.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  name TEXT NOT NULL, -- excluded validation check code (e.g. SVR00002)\n  packages_id INTEGER NOT NULL REFERENCES packages(id) -- foreign key to packages\n);\n"
 	varGroups                       = "CREATE TABLE IF NOT EXISTS var_groups (\n  -- Mutually exclusive groups of variables shown in Fleet UI as a selector. A var_group is owned by exactly one parent (package, policy template, or policy template input); the corresponding parent FK column is set, all others are NULL. Options are stored in var_group_options.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  packages_id INTEGER REFERENCES packages(id), -- foreign key to packages (set for top-level integration/input package var groups)\n  policy_template_inputs_id INTEGER REFERENCES policy_template_inputs(id), -- foreign key to policy_template_inputs (set for policy template input var groups)\n  policy_templates_id INTEGER REFERENCES policy_templates(id), -- foreign key to policy_templates (set for policy template var groups)\n  streams_id INTEGER REFERENCES streams(id), -- foreign key to streams (set for stream var groups)\n  description TEXT, -- Help text explaining what this selector controls.\n  name TEXT NOT NULL, -- Unique identifier for this variable group selector.\n  required BOOLEAN, -- Whether a selection is required for this var_group. When true, Fleet UI will require the user to select an option, and all variables within the selected option are treated as required (inferred). W...\n  selector_title TEXT NOT NULL, -- Label for the dropdown selector (e.g., \"Preferred method\").\n  show_divider BOOLEAN, -- When false, suppresses the automatic horizontal divider rendered after this section.\n  title TEXT NOT NULL -- Section header displayed in the UI (e.g., \"Setup Access\").\n);\n"
 	varGroupOptions                 = "CREATE TABLE IF NOT EXISTS var_group_options (\n  -- Options within a variable group. Each option lists which variable names are shown when selected.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  var_groups_id INTEGER NOT NULL REFERENCES var_groups(id), -- foreign key to var_groups\n  description TEXT, -- Help text for this option.\n  hide_in_deployment_modes JSON, -- Deployment modes where this option is hidden.\n  name TEXT NOT NULL, -- Unique identifier (stored in policy when selected).\n  title TEXT NOT NULL, -- Display title shown in the dropdown.\n  vars JSON, -- Variable names to display when this option is selected.\n  additional_properties JSON -- JSON-encoded AdditionalProperties\n);\n"
 	vars                            = "CREATE TABLE IF NOT EXISTS vars (\n  -- Input variable definitions. Linked to packages, policy templates, streams, or inputs via join tables.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  file_path TEXT, -- source file path\n  file_line INTEGER, -- source file line number\n  file_column INTEGER, -- source file column number\n  \"default\" JSON, -- Default is the default value for the variable.\n  description TEXT, -- Short description of variable.\n  hide_in_deployment_modes JSON, -- Whether this variable should be hidden in the UI for agent policies intended to some specific deployment modes.\n  max_duration TEXT, -- The maximum allowed duration value for duration data types. This property can only be used when the type is set to 'duration'.\n  migrate_from JSON, -- Declares that this variable was previously named differently or defined at a different scope. Fleet carries the old value over when upgrading a policy. At least one of `name` or `scope` must be set...\n  min_duration TEXT, -- The minimum allowed duration value for duration data types. This property can only be used when the type is set to 'duration'.\n  multi BOOLEAN, -- Can variable contain multiple values?\n  name TEXT NOT NULL, -- Variable name.\n  options JSON, -- Options provides the list of selectable options when type is \"select\".\n  required BOOLEAN, -- Is variable required?\n  secret BOOLEAN, -- Specifying that a variable is secret means that Kibana will store the value separate from the package policy in a more secure index. This is useful for passwords and other sensitive information. On...\n  section TEXT, -- Name of the section this variable belongs to. Must match a section name defined in the `sections` list at the same level.\n  show_user BOOLEAN, -- Should this variable be shown to the user by default?\n  title TEXT, -- Title of variable.\n  type TEXT NOT NULL, -- Data type of variable. A duration type is a sequence of decimal numbers, each with a unit suffix, such as \"60s\", \"1m\" or \"2h45m\". Duration values must follow these rules: - Use time units of \"ms\", ...\n  url_allowed_schemes JSON -- List of allowed URL schemes for the url type. If empty, any scheme is allowed. An empty string can be used to indicate that the scheme is not mandatory.\n);\n"
 	deprecations                    = "CREATE TABLE IF NOT EXISTS deprecations (\n  -- Deprecation notices for packages, policy templates, inputs, data streams, and vars. Each row links to exactly one parent entity via a nullable FK.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  data_streams_id INTEGER REFERENCES data_streams(id), -- foreign key to data_streams (set when a data stream is deprecated)\n  description TEXT NOT NULL, -- reason for deprecation\n  packages_id INTEGER REFERENCES packages(id), -- foreign key to packages (set when a package is deprecated)\n  policy_template_inputs_id INTEGER REFERENCES policy_template_inputs(id), -- foreign key to policy_template_inputs (set when an input is deprecated)\n  policy_templates_id INTEGER REFERENCES policy_templates(id), -- foreign key to policy_templates (set when a policy template is deprecated)\n  replaced_by_data_stream TEXT, -- name of the data stream that replaces the deprecated one\n  replaced_by_input TEXT, -- name of the input that replaces the deprecated one\n  replaced_by_package TEXT, -- name of the package that replaces the deprecated one\n  replaced_by_policy_template TEXT, -- name of the policy template that replaces the deprecated one\n  replaced_by_variable TEXT, -- name of the variable that replaces the deprecated one\n  since TEXT NOT NULL, -- version since when deprecated\n  vars_id INTEGER REFERENCES vars(id) -- foreign key to vars (set when a var is deprecated)\n);\n"
-	packageVars                     = "CREATE TABLE IF NOT EXISTS package_vars (\n  -- Join table linking vars to packages.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  package_id INTEGER NOT NULL REFERENCES packages(id), -- foreign key to packages\n  var_id INTEGER NOT NULL REFERENCES vars(id) -- foreign key to vars\n);\n"
-	policyTemplateInputVars         = "CREATE TABLE IF NOT EXISTS policy_template_input_vars (\n  -- Join table linking vars to policy template inputs.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  policy_template_input_id INTEGER NOT NULL REFERENCES policy_template_inputs(id), -- foreign key to policy_template_inputs\n  var_id INTEGER NOT NULL REFERENCES vars(id) -- foreign key to vars\n);\n"
-	policyTemplateVars              = "CREATE TABLE IF NOT EXISTS policy_template_vars (\n  -- Join table linking vars to policy templates.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  policy_template_id INTEGER NOT NULL REFERENCES policy_templates(id), -- foreign key to policy_templates\n  var_id INTEGER NOT NULL REFERENCES vars(id) -- foreign key to vars\n);\n"
-	streamVars                      = "CREATE TABLE IF NOT EXISTS stream_vars (\n  -- Join table linking vars to streams.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  stream_id INTEGER NOT NULL REFERENCES streams(id), -- foreign key to streams\n  var_id INTEGER NOT NULL REFERENCES vars(id) -- foreign key to vars\n);\n"
+	packageVars                     = "CREATE TABLE IF NOT EXISTS package_vars (\n  -- Join table linking vars to packages.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  package_id INTEGER NOT NULL REFERENCES packages(id), -- foreign key to packages\n  var_id INTEGER NOT NULL REFERENCES vars(id), -- foreign key to vars\n  UNIQUE (package_id, var_id)\n);\n"
+	policyTemplateInputVars         = "CREATE TABLE IF NOT EXISTS policy_template_input_vars (\n  -- Join table linking vars to policy template inputs.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  policy_template_input_id INTEGER NOT NULL REFERENCES policy_template_inputs(id), -- foreign key to policy_template_inputs\n  var_id INTEGER NOT NULL REFERENCES vars(id), -- foreign key to vars\n  UNIQUE (policy_template_input_id, var_id)\n);\n"
+	policyTemplateVars              = "CREATE TABLE IF NOT EXISTS policy_template_vars (\n  -- Join table linking vars to policy templates.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  policy_template_id INTEGER NOT NULL REFERENCES policy_templates(id), -- foreign key to policy_templates\n  var_id INTEGER NOT NULL REFERENCES vars(id), -- foreign key to vars\n  UNIQUE (policy_template_id, var_id)\n);\n"
+	streamVars                      = "CREATE TABLE IF NOT EXISTS stream_vars (\n  -- Join table linking vars to streams.\n  id INTEGER PRIMARY KEY AUTOINCREMENT, -- unique identifier\n  stream_id INTEGER NOT NULL REFERENCES streams(id), -- foreign key to streams\n  var_id INTEGER NOT NULL REFERENCES vars(id), -- foreign key to vars\n  UNIQUE (stream_id, var_id)\n);\n"
 )
 
 // creates contains all CREATE TABLE statements in dependency order.
-var creates = []string{fields, packages, buildManifests, changelogs, changelogEntries, dataStreams, agentTemplates, dataStreamFields, discoveryFields, docs, images, ingestPipelines, ingestProcessors, kibanaSavedObjects, kibanaReferences, packageCategories, packageFields, packageIcons, packageScreenshots, pipelineTests, policyTemplates, policyTemplateCategories, policyTemplateIcons, policyTemplateInputs, policyTemplateScreenshots, policyTests, routingRules, sampleEvents, securityRules, securityRuleIndexPatterns, securityRuleRelatedIntegrations, securityRuleRequiredFields, securityRuleTags, securityRuleThreats, staticTests, streams, sections, systemTests, systemTestSamples, tags, transforms, transformFields, varGroups, varGroupOptions, vars, deprecations, packageVars, policyTemplateInputVars, policyTemplateVars, streamVars}
+var creates = []string{fields, packages, buildManifests, changelogs, changelogEntries, dataStreams, agentTemplates, dataStreamFields, discoveryDatasets, discoveryFields, docs, images, ingestPipelines, ingestProcessors, kibanaSavedObjects, exceptionLists, kibanaReferences, packageCategories, packageDependencies, packageFields, packageIcons, packageMetrics, packageScreenshots, pipelineTests, policyTemplates, policyTemplateCategories, policyTemplateIcons, policyTemplateInputs, policyTemplateScreenshots, policyTests, processorTypeCounts, routingRules, sampleEvents, sampleEventFields, securityRules, securityRuleIndexPatterns, securityRuleRelatedIntegrations, securityRuleRequiredFields, securityRuleTags, securityRuleThreats, staticTests, streams, sections, systemTests, systemTestSamples, tags, transforms, transformFields, validationExcludedChecks, varGroups, varGroupOptions, vars, deprecations, packageVars, policyTemplateInputVars, policyTemplateVars, streamVars}
+
+// tableColumn describes a column of a generated table.
+type tableColumn struct {
+	name    string
+	sqlType string
+	notNull bool
+	comment string
+}
+
+// tableInfo describes a generated table and its columns.
+type tableInfo struct {
+	name    string
+	comment string
+	columns []tableColumn
+}
+
+// tableInfos describes every table in creates, in the same order.
+var tableInfos = []tableInfo{
+	{
+		name:    "fields",
+		comment: "Elasticsearch field definitions, flattened from nested YAML into dotted-path names.",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "allowed_values", sqlType: "JSON", notNull: false, comment: "allowed values from the ECS definition (JSON array of objects with name, description, and expected_event_types), populated when WithECSLookup is used"},
+			{name: "file_path", sqlType: "TEXT", notNull: false, comment: "source file path"},
+			{name: "file_line", sqlType: "INTEGER", notNull: false, comment: "source file line number"},
+			{name: "file_column", sqlType: "INTEGER", notNull: false, comment: "source file column number"},
+			{name: "analyzer", sqlType: "TEXT", notNull: false, comment: "Name of the analyzer to use for indexing. Unless search_analyzer is specified this analyzer is used for both indexing and searching. Only valid for 'type: text'."},
+			{name: "copy_to", sqlType: "TEXT", notNull: false, comment: "The copy_to parameter allows you to copy the values of multiple fields into a group field, which can then be queried as a single field."},
+			{name: "date_format", sqlType: "TEXT", notNull: false, comment: "The date format(s) that can be parsed. Type date format default to `strict_date_optional_time||epoch_millis`, see the [doc]. In JSON documents, dates are represented as strings. Elasticsearch uses ..."},
+			{name: "default_metric", sqlType: "JSON", notNull: false, comment: "JSON-encoded DefaultMetric"},
+			{name: "description", sqlType: "TEXT", notNull: false, comment: "Short description of field"},
+			{name: "dimension", sqlType: "BOOLEAN", notNull: false, comment: "Declare a field as dimension of time series. This is attached to the field as a `time_series_dimension` mapping parameter."},
+			{name: "doc_values", sqlType: "BOOLEAN", notNull: false, comment: "Controls whether doc values are enabled for a field. All fields which support doc values have them enabled by default. If you are sure that you don’t need to sort or aggregate on a field, or acce..."},
+			{name: "dynamic", sqlType: "JSON", notNull: false, comment: "Dynamic controls whether new fields are added dynamically. Accepts true, false, \"strict\", or \"runtime\"."},
+			{name: "enabled", sqlType: "BOOLEAN", notNull: false, comment: "The enabled setting, which can be applied only to the top-level mapping definition and to object fields, causes Elasticsearch to skip parsing of the contents of the field entirely. The JSON can sti..."},
+			{name: "example", sqlType: "JSON", notNull: false, comment: "Example values for this field."},
+			{name: "expected_values", sqlType: "JSON", notNull: false, comment: "An array of expected values for the field. When defined, these are the only expected values."},
+			{name: "external", sqlType: "TEXT", notNull: false, comment: "External source reference"},
+			{name: "ignore_above", sqlType: "INTEGER", notNull: false, comment: "Strings longer than the ignore_above setting will not be indexed or stored. For arrays of strings, ignore_above will be applied for each array element separately and string elements longer than ign..."},
+			{name: "ignore_malformed", sqlType: "BOOLEAN", notNull: false, comment: "Trying to index the wrong data type into a field throws an exception by default, and rejects the whole document. The ignore_malformed parameter, if set to true, allows the exception to be ignored. ..."},
+			{name: "include_in_parent", sqlType: "BOOLEAN", notNull: false, comment: "For nested field types, this specifies if all fields in the nested object are also added to the parent document as standard (flat) fields."},
+			{name: "include_in_root", sqlType: "BOOLEAN", notNull: false, comment: "For nested field types, this specifies if all fields in the nested object are also added to the root document as standard (flat) fields."},
+			{name: "index", sqlType: "BOOLEAN", notNull: false, comment: "The index option controls whether field values are indexed. Fields that are not indexed are typically not queryable."},
+			{name: "inference_id", sqlType: "TEXT", notNull: false, comment: "For semantic_text fields, this specifies the id of the inference endpoint associated with the field"},
+			{name: "metric_type", sqlType: "TEXT", notNull: false, comment: "The metric type of a numeric field. This is attached to the field as a `time_series_metric` mapping parameter. A gauge is a single-value measurement that can go up or down over time, such as a temp..."},
+			{name: "metrics", sqlType: "JSON", notNull: false, comment: "JSON-encoded Metrics"},
+			{name: "multi_fields", sqlType: "JSON", notNull: false, comment: "It is often useful to index the same field in different ways for different purposes. This is the purpose of multi-fields. For instance, a string field could be mapped as a text field for full-text ..."},
+			{name: "name", sqlType: "TEXT", notNull: true, comment: "Name of field. Names containing dots are automatically split into sub-fields. Names with wildcards generate dynamic mappings."},
+			{name: "normalize", sqlType: "JSON", notNull: false, comment: "Specifies the expected normalizations for a field. `array` normalization implies that the values in the field should always be an array, even if they are single values."},
+			{name: "normalizer", sqlType: "TEXT", notNull: false, comment: "Specifies the name of a normalizer to apply to keyword fields. A simple normalizer called lowercase ships with elasticsearch and can be used. Custom normalizers can be defined as part of analysis i..."},
+			{name: "null_value", sqlType: "JSON", notNull: false, comment: "The null_value parameter allows you to replace explicit null values with the specified value so that it can be indexed and searched. A null value cannot be indexed or searched. When a field is set ..."},
+			{name: "object_type", sqlType: "TEXT", notNull: false, comment: "Type of the members of the object when `type: object` is used. In these cases a dynamic template is created so direct subobjects of this field have the type indicated. When `object_type_mapping_typ..."},
+			{name: "object_type_mapping_type", sqlType: "TEXT", notNull: false, comment: "Type that members of a field of with `type: object` must have in the source document. This type corresponds to the data type detected by the JSON parser, and is translated to the `match_mapping_typ..."},
+			{name: "path", sqlType: "TEXT", notNull: false, comment: "For alias type fields this is the path to the target field. Note that this must be the full path, including any parent objects (e.g. object1.object2.field)."},
+			{name: "pattern", sqlType: "TEXT", notNull: false, comment: "Regular expression pattern matching the allowed values for the field. This is used for development-time data validation."},
+			{name: "runtime", sqlType: "JSON", notNull: false, comment: "Runtime specifies if this field is evaluated at query time. Can be a boolean or a script string."},
+			{name: "scaling_factor", sqlType: "INTEGER", notNull: false, comment: "The scaling factor to use when encoding values. Values will be multiplied by this factor at index time and rounded to the closest long value. For instance, a scaled_float with a scaling_factor of 1..."},
+			{name: "search_analyzer", sqlType: "TEXT", notNull: false, comment: "Name of the analyzer to use for searching. Only valid for 'type: text'."},
+			{name: "store", sqlType: "BOOLEAN", notNull: false, comment: "By default, field values are indexed, but not stored. This means that the field can be queried, but the original field cannot be retrieved. Setting this value to true ensures that the field is also..."},
+			{name: "subobjects", sqlType: "BOOLEAN", notNull: false, comment: "Specifies if field names containing dots should be expanded into subobjects. For example, if this is set to `true`, a field named `foo.bar` will be expanded into an object with a field named `bar` ..."},
+			{name: "type", sqlType: "TEXT", notNull: false, comment: "Datatype of field. If the type is set to object, a dynamic mapping is created. In this case, if the name doesn't contain any wildcard, the wildcard is added as the last segment of the path."},
+			{name: "unit", sqlType: "TEXT", notNull: false, comment: "Unit type to associate with a numeric field. This is attached to the field as metadata (via `meta`). By default, a field does not have a unit. The convention for percents is to use value 1 to mean ..."},
+			{name: "value", sqlType: "TEXT", notNull: false, comment: "The value to associate with a constant_keyword field."},
+			{name: "json_pointer", sqlType: "TEXT", notNull: false, comment: "JsonPointer is the RFC 6901 JSON Pointer to this field's location in the original fields file (e.g. /0/fields/1). Set by pkgreader after parsing."},
+		},
+	},
+	{
+		name:    "packages",
+		comment: "Fleet packages (integration, input, or content). Each row is one package version.",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "agent_privileges_root", sqlType: "BOOLEAN", notNull: false, comment: "whether collection requires root privileges in the agent"},
+			{name: "commit_id", sqlType: "TEXT", notNull: false, comment: "git HEAD commit ID (populated when WithGitMetadata is used)"},
+			{name: "conditions_agent_version", sqlType: "TEXT", notNull: false, comment: "required Elastic Agent version range"},
+			{name: "conditions_elastic_capabilities", sqlType: "JSON", notNull: false, comment: "stack capabilities required by the package (JSON array, e.g. [\"security\"])"},
+			{name: "conditions_elastic_subscription", sqlType: "TEXT", notNull: false, comment: "required Elastic subscription level"},
+			{name: "conditions_kibana_version", sqlType: "TEXT", notNull: false, comment: "required Kibana version range"},
+			{name: "deprecated_entity_count", sqlType: "INTEGER", notNull: true, comment: "number of deprecated entities (the package, policy templates, inputs, data streams, and vars)"},
+			{name: "dir_name", sqlType: "TEXT", notNull: true, comment: "directory name of the package"},
+			{name: "elasticsearch_privileges_cluster", sqlType: "JSON", notNull: false, comment: "Elasticsearch cluster privilege requirements (JSON array)"},
+			{name: "elasticsearch_privileges_index", sqlType: "JSON", notNull: false, comment: "sorted, distinct Elasticsearch index privileges requested by the package's data streams (elasticsearch.privileges.indices); NULL when none are requested"},
+			{name: "imported_at", sqlType: "TEXT", notNull: true, comment: "UTC time the package row was written, in RFC 3339 format"},
+			{name: "policy_templates_behavior", sqlType: "TEXT", notNull: false, comment: "behavior when multiple policy templates are defined (all, combined_policy, individual_policies)"},
+			{name: "file_path", sqlType: "TEXT", notNull: false, comment: "source file path"},
+			{name: "file_line", sqlType: "INTEGER", notNull: false, comment: "source file line number"},
+			{name: "file_column", sqlType: "INTEGER", notNull: false, comment: "source file column number"},
+			{name: "description", sqlType: "TEXT", notNull: true, comment: "A longer description of the package. It should describe, at least all the kinds of data that is collected and with what collectors, following the structure \"Collect X from Y with X\"."},
+			{name: "format_version", sqlType: "TEXT", notNull: true, comment: "The version of the package specification format used by this package."},
+			{name: "name", sqlType: "TEXT", notNull: true, comment: "The name of the package."},
+			{name: "owner_github", sqlType: "TEXT", notNull: true, comment: "Github team name of the package maintainer."},
+			{name: "owner_type", sqlType: "TEXT", notNull: true, comment: "Describes who owns the package and the level of support that is provided. The 'elastic' value indicates that the package is built and maintained by Elastic. The 'partner' value indicates that the p..."},
+			{name: "source_license", sqlType: "TEXT", notNull: false, comment: "Identifier of the license of the package, as specified in https://spdx.org/licenses/."},
+			{name: "title", sqlType: "TEXT", notNull: true, comment: "Title of the package. It should be the usual title given to the product, service or kind of source being managed by this package."},
+			{name: "type", sqlType: "TEXT", notNull: true, comment: "The type of package."},
+			{name: "version", sqlType: "TEXT", notNull: true, comment: "The version of the package."},
+		},
+	},
+	{
+		name:    "build_manifests",
+		comment: "Build configuration for integration packages (_dev/build/build.yml).",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "packages_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to packages"},
+			{name: "file_path", sqlType: "TEXT", notNull: false, comment: "source file path"},
+			{name: "file_line", sqlType: "INTEGER", notNull: false, comment: "source file line number"},
+			{name: "file_column", sqlType: "INTEGER", notNull: false, comment: "source file column number"},
+			{name: "dependencies_ecs_import_mappings", sqlType: "BOOLEAN", notNull: false, comment: "Whether or not import common used dynamic templates and properties into the package"},
+			{name: "dependencies_ecs_reference", sqlType: "TEXT", notNull: true, comment: "Reference is the ECS version source reference. Values begin with \"git@\" (e.g. \"git@v8.11.0\")."},
+		},
+	},
+	{
+		name:    "changelogs",
+		comment: "Changelog versions for a package. Each row is one version entry with its release date.",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "packages_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to packages"},
+			{name: "file_path", sqlType: "TEXT", notNull: false, comment: "source file path"},
+			{name: "file_line", sqlType: "INTEGER", notNull: false, comment: "source file line number"},
+			{name: "file_column", sqlType: "INTEGER", notNull: false, comment: "source file column number"},
+			{name: "version", sqlType: "TEXT", notNull: true, comment: "Package version."},
+			{name: "date", sqlType: "TEXT", notNull: false, comment: "Date is the approximate release date, populated via git blame when WithGitMetadata is used."},
+		},
+	},
+	{
+		name:    "changelog_entries",
+		comment: "Individual changelog entries within a changelog version.",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "changelogs_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to changelogs"},
+			{name: "ordinal", sqlType: "INTEGER", notNull: true, comment: "order of the entry within its version's changes (0-based)"},
+			{name: "version", sqlType: "TEXT", notNull: true, comment: "package version of the parent changelog (denormalized from changelogs.version)"},
+			{name: "file_path", sqlType: "TEXT", notNull: false, comment: "source file path"},
+			{name: "file_line", sqlType: "INTEGER", notNull: false, comment: "source file line number"},
+			{name: "file_column", sqlType: "INTEGER", notNull: false, comment: "source file column number"},
+			{name: "description", sqlType: "TEXT", notNull: true, comment: "Description of change."},
+			{name: "link", sqlType: "TEXT", notNull: true, comment: "Link to issue or PR describing change in detail."},
+			{name: "type", sqlType: "TEXT", notNull: true, comment: "Type of change."},
+		},
+	},
+	{
+		name:    "data_streams",
+		comment: "Data streams within integration packages. Each row is one data stream with its Elasticsearch and agent config.",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "packages_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to packages"},
+			{name: "dir_name", sqlType: "TEXT", notNull: true, comment: "directory name of the data stream"},
+			{name: "effective_dataset", sqlType: "TEXT", notNull: true, comment: "dataset the data stream writes to: the manifest dataset when set, otherwise <package>.<data stream>"},
+			{name: "index_template_name", sqlType: "TEXT", notNull: false, comment: "index template name installed by Fleet, <type>-<dataset> (e.g. logs-nginx.access); NULL when the data stream has no type"},
+			{name: "stream_count", sqlType: "INTEGER", notNull: true, comment: "number of streams rows"},
+			{name: "file_path", sqlType: "TEXT", notNull: false, comment: "source file path"},
+			{name: "file_line", sqlType: "INTEGER", notNull: false, comment: "source file line number"},
+			{name: "file_column", sqlType: "INTEGER", notNull: false, comment: "source file column number"},
+			{name: "dataset", sqlType: "TEXT", notNull: false, comment: "Name of data set."},
+			{name: "dataset_is_prefix", sqlType: "BOOLEAN", notNull: false, comment: "If true, the index pattern in the ES template will contain the dataset as a prefix only"},
+			{name: "elasticsearch_dynamic_dataset", sqlType: "BOOLEAN", notNull: false, comment: "When set to true, agents running this integration are granted data stream privileges for all datasets of its type"},
+			{name: "elasticsearch_dynamic_namespace", sqlType: "BOOLEAN", notNull: false, comment: "When set to true, agents running this integration are granted data stream privileges for all namespaces of its type"},
+			{name: "elasticsearch_index_mode", sqlType: "TEXT", notNull: false, comment: "Index mode to use. Index mode can be used to enable use case specific functionalities. This setting must be installed in the composable index template, not in the package component templates."},
+			{name: "elasticsearch_index_template", sqlType: "JSON", notNull: false, comment: "Index template definition"},
+			{name: "elasticsearch_privileges", sqlType: "JSON", notNull: false, comment: "Elasticsearch privilege requirements"},
+			{name: "elasticsearch_source_mode", sqlType: "TEXT", notNull: false, comment: "Source mode to use. This configures how the document source (`_source`) is stored for this data stream. If configured as `default`, this mode is not configured and it uses Elasticsearch defaults. I..."},
+			{name: "hidden", sqlType: "BOOLEAN", notNull: false, comment: "Specifies if a data stream is hidden, resulting in dot prefixed system indices. To set the data stream hidden without those dot prefixed indices, check `elasticsearch.index_template.data_stream.hid..."},
+			{name: "ilm_policy", sqlType: "TEXT", notNull: false, comment: "The name of an existing ILM (Index Lifecycle Management) policy"},
+			{name: "provider_permissions", sqlType: "JSON", notNull: false, comment: "Permissions and roles this integration unit requires from the named provider. May be declared at package, policy_template, input, and data_stream levels; entries across all applicable levels are ac..."},
+			{name: "release", sqlType: "TEXT", notNull: false, comment: "Stability of data stream."},
+			{name: "title", sqlType: "TEXT", notNull: true, comment: "Title of data stream. It should include the source of the data that is being collected, and the kind of data collected such as logs or metrics. Words should be uppercased."},
+			{name: "type", sqlType: "TEXT", notNull: false, comment: "Type of data stream"},
+			{name: "github_code_owner", sqlType: "TEXT", notNull: false, comment: "GithubCodeOwner is the GitHub team code owner from CODEOWNERS, populated when WithCodeowners is used."},
+			{name: "github_code_owners", sqlType: "JSON", notNull: false, comment: "GithubCodeOwners lists all GitHub code owners from the matching CODEOWNERS line, populated when WithCodeowners is used. GithubCodeOwner holds the first of these."},
+		},
+	},
+	{
+		name:    "agent_templates",
+		comment: "Agent Handlebars template files (.yml.hbs) from agent/ directories. Each row is one template file with its raw content. Referenced by streams, policy_templates, and policy_template_inputs via template_path.",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "content", sqlType: "TEXT", notNull: true, comment: "raw Handlebars template content"},
+			{name: "data_streams_id", sqlType: "INTEGER", notNull: false, comment: "foreign key to data_streams (set for data stream templates, NULL for package-level)"},
+			{name: "file_path", sqlType: "TEXT", notNull: true, comment: "file path relative to the package root (e.g. data_stream/logs/agent/stream/stream.yml.hbs)"},
+			{name: "packages_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to packages"},
+		},
+	},
+	{
+		name:    "data_stream_fields",
+		comment: "Join table linking fields to data streams.",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "data_stream_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to data_streams"},
+			{name: "field_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to fields"},
+		},
+	},
+	{
+		name:    "discovery_datasets",
+		comment: "Datasets a content package can be used with, matched against the data_stream.dataset of existing indices.",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "name", sqlType: "TEXT", notNull: true, comment: "name of the dataset"},
+			{name: "packages_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to packages"},
+		},
+	},
+	{
+		name:    "discovery_fields",
+		comment: "Fields associated with package discovery capabilities.",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "name", sqlType: "TEXT", notNull: true, comment: "name of the field"},
+			{name: "packages_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to packages"},
+		},
+	},
+	{
+		name:    "docs",
+		comment: "Documentation files within packages. Content is optionally populated when WithDocContent is used.",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "content", sqlType: "TEXT", notNull: false, comment: "markdown content (NULL unless WithDocContent was used)"},
+			{name: "content_type", sqlType: "TEXT", notNull: true, comment: "classification: readme, doc, knowledge_base, or template (_dev/build/docs source)"},
+			{name: "file_path", sqlType: "TEXT", notNull: true, comment: "file path relative to the package root (e.g. docs/README.md)"},
+			{name: "packages_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to packages"},
+			{name: "truncated", sqlType: "BOOLEAN", notNull: false, comment: "whether content was cut to the WithMaxDocBytes limit (NULL when content is NULL)"},
+		},
+	},
+	{
+		name:    "images",
+		comment: "Image files within packages (img/ directory). Join with icon/screenshot tables on src to correlate declared metadata with actual image properties.",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "byte_size", sqlType: "INTEGER", notNull: true, comment: "file size in bytes"},
+			{name: "height", sqlType: "INTEGER", notNull: false, comment: "image height in pixels (NULL for SVG and unrecognized formats)"},
+			{name: "packages_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to packages"},
+			{name: "sha256", sqlType: "TEXT", notNull: true, comment: "hex-encoded SHA-256 hash of file contents"},
+			{name: "src", sqlType: "TEXT", notNull: true, comment: "image path with leading slash to match icon/screenshot src (e.g. /img/icon.png)"},
+			{name: "width", sqlType: "INTEGER", notNull: false, comment: "image width in pixels (NULL for SVG and unrecognized formats)"},
+		},
+	},
+	{
+		name:    "ingest_pipelines",
+		comment: "Elasticsearch ingest pipeline definitions within data streams.",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "data_streams_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to data_streams"},
+			{name: "file_name", sqlType: "TEXT", notNull: true, comment: "file name of the pipeline (e.g. default.yml)"},
+			{name: "file_path", sqlType: "TEXT", notNull: false, comment: "source file path"},
+			{name: "file_line", sqlType: "INTEGER", notNull: false, comment: "source file line number"},
+			{name: "file_column", sqlType: "INTEGER", notNull: false, comment: "source file column number"},
+			{name: "description", sqlType: "TEXT", notNull: false, comment: "Description of the pipeline."},
+		},
+	},
+	{
+		name:    "ingest_processors",
+		comment: "Individual ingest processors flattened from pipelines. Nested on_failure handlers are included as separate rows.",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "ingest_pipelines_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to ingest_pipelines"},
+			{name: "attributes", sqlType: "JSON", notNull: false, comment: "JSON-encoded processor attributes"},
+			{name: "json_pointer", sqlType: "TEXT", notNull: true, comment: "RFC 6901 JSON Pointer location within the pipeline"},
+			{name: "ordinal", sqlType: "INTEGER", notNull: true, comment: "order of processor within the pipeline"},
+			{name: "type", sqlType: "TEXT", notNull: true, comment: "processor type (e.g. set, grok, rename)"},
+			{name: "file_path", sqlType: "TEXT", notNull: false, comment: "source file path"},
+			{name: "file_line", sqlType: "INTEGER", notNull: false, comment: "source file line number"},
+			{name: "file_column", sqlType: "INTEGER", notNull: false, comment: "source file column number"},
+		},
+	},
+	{
+		name:    "kibana_saved_objects",
+		comment: "Kibana saved objects (dashboards, visualizations, security rules, etc.) from the kibana/ directory. Each row is one JSON file.",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "asset_type", sqlType: "TEXT", notNull: true, comment: "asset type directory name (e.g. dashboard, visualization, security_rule)"},
+			{name: "core_migration_version", sqlType: "TEXT", notNull: false, comment: "core Kibana migration version"},
+			{name: "description", sqlType: "TEXT", notNull: false, comment: "description from attributes"},
+			{name: "file_path", sqlType: "TEXT", notNull: true, comment: "file path relative to the package root"},
+			{name: "managed", sqlType: "BOOLEAN", notNull: false, comment: "whether the object is managed by Kibana"},
+			{name: "object_id", sqlType: "TEXT", notNull: true, comment: "unique identifier of the saved object"},
+			{name: "object_type", sqlType: "TEXT", notNull: false, comment: "object type from JSON (e.g. dashboard, visualization, search)"},
+			{name: "packages_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to packages"},
+			{name: "panels_count", sqlType: "INTEGER", notNull: false, comment: "number of panels in attributes.panelsJSON (dashboards only)"},
+			{name: "raw", sqlType: "JSON", notNull: false, comment: "original JSON file content, byte for byte (NULL unless WithKibanaRaw is used)"},
+			{name: "reference_count", sqlType: "INTEGER", notNull: true, comment: "number of references to other saved objects"},
+			{name: "title", sqlType: "TEXT", notNull: false, comment: "human-readable title from attributes"},
+			{name: "type_migration_version", sqlType: "TEXT", notNull: false, comment: "type-specific migration version"},
+		},
+	},
+	{
+		name:    "exception_lists",
+		comment: "Exception lists and exception list items extracted from Kibana saved objects of type exception-list (kibana/exception_list and kibana/exception_list_item). Has a 1:1 relationship with kibana_saved_objects. Name and description are on the parent table. Items join to their list on list_id.",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "entries", sqlType: "JSON", notNull: false, comment: "item conditions as declared (JSON array, NULL for lists)"},
+			{name: "item_id", sqlType: "TEXT", notNull: false, comment: "item identifier within the list (NULL for lists)"},
+			{name: "kibana_saved_objects_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to kibana_saved_objects"},
+			{name: "list_id", sqlType: "TEXT", notNull: true, comment: "list identifier (attributes.list_id); for items, the list the item belongs to"},
+			{name: "list_type", sqlType: "TEXT", notNull: true, comment: "list or item"},
+			{name: "namespace_type", sqlType: "TEXT", notNull: false, comment: "single (space-scoped) or agnostic (shared across spaces)"},
+			{name: "os_types", sqlType: "JSON", notNull: false, comment: "operating systems the exception applies to (JSON array)"},
+			{name: "tags", sqlType: "JSON", notNull: false, comment: "tags (JSON array)"},
+			{name: "type", sqlType: "TEXT", notNull: false, comment: "list type (detection, endpoint, rule_default, ...) or item type (simple)"},
+		},
+	},
+	{
+		name:    "kibana_references",
+		comment: "References between Kibana saved objects. Each row is one reference from a saved object to another, enabling dependency graph queries.",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "kibana_saved_objects_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to kibana_saved_objects"},
+			{name: "ref_id", sqlType: "TEXT", notNull: true, comment: "referenced object identifier"},
+			{name: "ref_name", sqlType: "TEXT", notNull: true, comment: "reference name (e.g. panel_0, kibanaSavedObjectMeta.searchSourceJSON)"},
+			{name: "ref_type", sqlType: "TEXT", notNull: true, comment: "referenced object type (e.g. visualization, search, index-pattern)"},
+		},
+	},
+	{
+		name:    "package_categories",
+		comment: "Categories assigned to a package.",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "category", sqlType: "TEXT", notNull: true, comment: "category value"},
+			{name: "package_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to packages"},
+		},
+	},
+	{
+		name:    "package_dependencies",
+		comment: "Build dependencies declared in _dev/build/build.yml. Each row is one dependency (ecs or an imported package) of a package.",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "import_mappings", sqlType: "BOOLEAN", notNull: false, comment: "whether common dynamic templates and properties are imported (ecs only)"},
+			{name: "name", sqlType: "TEXT", notNull: true, comment: "dependency name (e.g. ecs)"},
+			{name: "packages_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to packages"},
+			{name: "reference", sqlType: "TEXT", notNull: false, comment: "dependency source reference as written in build.yml (e.g. git@v8.11.0)"},
+			{name: "version", sqlType: "TEXT", notNull: false, comment: "version parsed from the reference with any git@ and v prefix removed (e.g. 8.11.0)"},
+		},
+	},
+	{
+		name:    "package_fields",
+		comment: "Join table linking fields to packages (for input packages).",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "field_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to fields"},
+			{name: "package_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to packages"},
+		},
+	},
+	{
+		name:    "package_icons",
+		comment: "Icon definitions for a package.",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "packages_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to packages"},
+			{name: "dark_mode", sqlType: "BOOLEAN", notNull: false, comment: "Is this icon to be shown in dark mode?"},
+			{name: "size", sqlType: "TEXT", notNull: false, comment: "Size of the icon."},
+			{name: "src", sqlType: "TEXT", notNull: true, comment: "Relative path to the icon's image file."},
+			{name: "title", sqlType: "TEXT", notNull: false, comment: "Title of icon."},
+			{name: "type", sqlType: "TEXT", notNull: false, comment: "MIME type of the icon image file."},
+		},
+	},
+	{
+		name:    "package_metrics",
+		comment: "Aggregate entity counts per package, computed from the inserted rows after each package is written. Avoids COUNT queries over large tables at analysis time.",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "data_stream_count", sqlType: "INTEGER", notNull: true, comment: "number of data streams"},
+			{name: "field_count", sqlType: "INTEGER", notNull: true, comment: "number of flattened fields across data streams, package fields, and transforms"},
+			{name: "kibana_object_count", sqlType: "INTEGER", notNull: true, comment: "number of Kibana saved objects"},
+			{name: "packages_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to packages"},
+			{name: "pipeline_count", sqlType: "INTEGER", notNull: true, comment: "number of ingest pipelines"},
+			{name: "processor_count", sqlType: "INTEGER", notNull: true, comment: "number of ingest processors, including nested on_failure handlers"},
+		},
+	},
+	{
+		name:    "package_screenshots",
+		comment: "Screenshot definitions for a package.",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "packages_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to packages"},
+			{name: "size", sqlType: "TEXT", notNull: false, comment: "Size of the screenshot."},
+			{name: "src", sqlType: "TEXT", notNull: true, comment: "Relative path to the screenshot's image file."},
+			{name: "title", sqlType: "TEXT", notNull: true, comment: "Title of screenshot."},
+			{name: "type", sqlType: "TEXT", notNull: false, comment: "MIME type of the screenshot image file."},
+		},
+	},
+	{
+		name:    "pipeline_tests",
+		comment: "Pipeline test cases for data streams. Each row is one test event file with optional per-case config.",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "config_path", sqlType: "TEXT", notNull: false, comment: "path to per-case config file"},
+			{name: "data_streams_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to data_streams"},
+			{name: "dynamic_fields", sqlType: "JSON", notNull: false, comment: "dynamic fields with regex patterns (from per-case config)"},
+			{name: "event", sqlType: "TEXT", notNull: false, comment: "raw contents of the event file (populated only when written with WithTestContent)"},
+			{name: "event_path", sqlType: "TEXT", notNull: true, comment: "path to event file"},
+			{name: "expected", sqlType: "JSON", notNull: false, comment: "contents of the expected output file (populated only when written with WithTestContent)"},
+			{name: "expected_path", sqlType: "TEXT", notNull: false, comment: "path to expected output file"},
+			{name: "fields", sqlType: "JSON", notNull: false, comment: "field definitions (from per-case config)"},
+			{name: "format", sqlType: "TEXT", notNull: true, comment: "event file format (json or raw)"},
+			{name: "multiline", sqlType: "JSON", notNull: false, comment: "multi-line configuration (from per-case raw config)"},
+			{name: "name", sqlType: "TEXT", notNull: true, comment: "test case stem name (e.g. test-example)"},
+			{name: "numeric_keyword_fields", sqlType: "JSON", notNull: false, comment: "keyword fields allowed numeric values (from per-case config)"},
+			{name: "skip_link", sqlType: "TEXT", notNull: false, comment: "link to issue for skipped test (from per-case config)"},
+			{name: "skip_reason", sqlType: "TEXT", notNull: false, comment: "reason test is skipped (from per-case config)"},
+			{name: "string_number_fields", sqlType: "JSON", notNull: false, comment: "numeric fields allowed string values (from per-case config)"},
+		},
+	},
+	{
+		name:    "policy_templates",
+		comment: "Policy templates offered by integration and input packages. Defines how a package is configured in Fleet.",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "packages_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to packages"},
+			{name: "deployment_modes", sqlType: "JSON", notNull: false, comment: "deployment_modes object as declared in the manifest (default and agentless settings). NULL when not specified."},
+			{name: "dynamic_signal_types", sqlType: "BOOLEAN", notNull: false, comment: "whether transforms and index templates are created based on pipeline config (input packages only)"},
+			{name: "input", sqlType: "TEXT", notNull: false, comment: "input type for input packages (e.g. cel, httpjson)"},
+			{name: "input_count", sqlType: "INTEGER", notNull: true, comment: "number of policy_template_inputs rows (always 0 for input packages)"},
+			{name: "policy_template_type", sqlType: "TEXT", notNull: false, comment: "data stream type for input packages (logs, metrics, synthetics, traces)"},
+			{name: "template_path", sqlType: "TEXT", notNull: false, comment: "Resolved file path to the agent template relative to the package root (e.g. agent/input/input.yml.hbs). Only set for input packages. Joinable directly to agent_templates.file_path."},
+			{name: "var_count", sqlType: "INTEGER", notNull: true, comment: "number of vars declared on the policy template itself, not on its inputs (exceeds its policy_template_vars rows when WithVarDedup links a repeated var once)"},
+			{name: "file_path", sqlType: "TEXT", notNull: false, comment: "source file path"},
+			{name: "file_line", sqlType: "INTEGER", notNull: false, comment: "source file line number"},
+			{name: "file_column", sqlType: "INTEGER", notNull: false, comment: "source file column number"},
+			{name: "configuration_links", sqlType: "JSON", notNull: false, comment: "List of links related to inputs and policy templates."},
+			{name: "data_streams", sqlType: "JSON", notNull: false, comment: "List of data streams compatible with the policy template."},
+			{name: "deployment_modes_agentless_division", sqlType: "TEXT", notNull: false, comment: "The division responsible for the integration. This is used to tag the agentless agent deployments for monitoring."},
+			{name: "deployment_modes_agentless_enabled", sqlType: "BOOLEAN", notNull: false, comment: "Indicates if the agentless deployment mode is available for this template policy. It is disabled by default."},
+			{name: "deployment_modes_agentless_is_default", sqlType: "BOOLEAN", notNull: false, comment: "On policy templates that support multiple deployment modes, this setting can be set to true to use agentless mode by default."},
+			{name: "deployment_modes_agentless_organization", sqlType: "TEXT", notNull: false, comment: "The responsible organization of the integration. This is used to tag the agentless agent deployments for monitoring."},
+			{name: "deployment_modes_agentless_release", sqlType: "TEXT", notNull: false, comment: "The maturity level of the agentless deployment mode for this policy template. If not defined, Kibana will provide a default value based on agentless platform maturity. Packages where agentless is t..."},
+			{name: "deployment_modes_agentless_resources_requests_cpu", sqlType: "TEXT", notNull: false, comment: "The amount of CPUs that the Agentless deployment will be initially allocated."},
+			{name: "deployment_modes_agentless_resources_requests_memory", sqlType: "TEXT", notNull: false, comment: "The amount of memory that the Agentless deployment will be initially allocated."},
+			{name: "deployment_modes_agentless_team", sqlType: "TEXT", notNull: false, comment: "The team responsible for the integration. This is used to tag the agentless agent deployments for monitoring."},
+			{name: "deployment_modes_default_enabled", sqlType: "BOOLEAN", notNull: false, comment: "Indicates if the default deployment mode is available for this template policy. It is enabled by default."},
+			{name: "description", sqlType: "TEXT", notNull: true, comment: "Longer description of policy template."},
+			{name: "fips_compatible", sqlType: "BOOLEAN", notNull: false, comment: "Indicate if this package is capable of satisfying FIPS requirements. Set to false if it uses any input that cannot be configured to use FIPS cryptography."},
+			{name: "multiple", sqlType: "BOOLEAN", notNull: false, comment: "Multiple"},
+			{name: "name", sqlType: "TEXT", notNull: true, comment: "Name of policy template."},
+			{name: "provider_permissions", sqlType: "JSON", notNull: false, comment: "Permissions and roles this integration unit requires from the named provider. May be declared at package, policy_template, input, and data_stream levels; entries across all applicable levels are ac..."},
+			{name: "title", sqlType: "TEXT", notNull: true, comment: "Title of policy template."},
+		},
+	},
+	{
+		name:    "policy_template_categories",
+		comment: "Categories assigned to a policy template.",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "category", sqlType: "TEXT", notNull: true, comment: "category value"},
+			{name: "policy_template_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to policy_templates"},
+		},
+	},
+	{
+		name:    "policy_template_icons",
+		comment: "Icon definitions for a policy template.",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "policy_templates_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to policy_templates"},
+			{name: "dark_mode", sqlType: "BOOLEAN", notNull: false, comment: "Is this icon to be shown in dark mode?"},
+			{name: "size", sqlType: "TEXT", notNull: false, comment: "Size of the icon."},
+			{name: "src", sqlType: "TEXT", notNull: true, comment: "Relative path to the icon's image file."},
+			{name: "title", sqlType: "TEXT", notNull: false, comment: "Title of icon."},
+			{name: "type", sqlType: "TEXT", notNull: false, comment: "MIME type of the icon image file."},
+		},
+	},
+	{
+		name:    "policy_template_inputs",
+		comment: "Inputs defined within a policy template.",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "policy_templates_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to policy_templates"},
+			{name: "deployment_modes", sqlType: "JSON", notNull: false, comment: "List of deployment modes that this input is compatible with. If not specified, the input is compatible with all deployment modes."},
+			{name: "description", sqlType: "TEXT", notNull: true, comment: "Longer description of input."},
+			{name: "dynamic_signal_types", sqlType: "BOOLEAN", notNull: false, comment: "When enabled, decides the transforms and index templates that need to be created depending on the pipelines specified in the configuration. This field is only allowed when the input type is 'otelcol'."},
+			{name: "hide_in_var_group_options", sqlType: "JSON", notNull: false, comment: "HideInVarGroupOptions filters out specific var_group options for this input."},
+			{name: "input_group", sqlType: "TEXT", notNull: false, comment: "Name of the input group"},
+			{name: "migrate_from", sqlType: "TEXT", notNull: false, comment: "Previous input type to migrate configuration from. This allows Fleet to automatically migrate the policy configuration when replacing one input implementation with an equivalent one. This field sho..."},
+			{name: "multi", sqlType: "BOOLEAN", notNull: false, comment: "Can input be defined multiple times"},
+			{name: "name", sqlType: "TEXT", notNull: false, comment: "Unique name for this input within the policy template. When set, data streams reference this input by name instead of type, allowing multiple inputs of the same type to coexist in the same policy t..."},
+			{name: "package", sqlType: "TEXT", notNull: false, comment: "Reference to an input package. When specified, configuration is inherited from the referenced package. The package must be listed in the manifest's requires section."},
+			{name: "provider_permissions", sqlType: "JSON", notNull: false, comment: "Permissions and roles this integration unit requires from the named provider. May be declared at package, policy_template, input, and data_stream levels; entries across all applicable levels are ac..."},
+			{name: "show_divider", sqlType: "BOOLEAN", notNull: false, comment: "When false, suppresses the automatic horizontal divider rendered after this section."},
+			{name: "template_path", sqlType: "TEXT", notNull: false, comment: "Resolved file path to the agent template relative to the package root (e.g. agent/input/httpjson.yml.hbs). NULL when not specified. Joinable directly to agent_templates.file_path."},
+			{name: "template_paths", sqlType: "JSON", notNull: false, comment: "Paths of the config templates. Templates are rendered and merged sequentially; later templates override earlier ones for conflicting keys."},
+			{name: "title", sqlType: "TEXT", notNull: true, comment: "Title of input."},
+			{name: "type", sqlType: "TEXT", notNull: false, comment: "Type of input."},
+		},
+	},
+	{
+		name:    "policy_template_screenshots",
+		comment: "Screenshot definitions for a policy template.",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "policy_templates_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to policy_templates"},
+			{name: "size", sqlType: "TEXT", notNull: false, comment: "Size of the screenshot."},
+			{name: "src", sqlType: "TEXT", notNull: true, comment: "Relative path to the screenshot's image file."},
+			{name: "title", sqlType: "TEXT", notNull: true, comment: "Title of screenshot."},
+			{name: "type", sqlType: "TEXT", notNull: false, comment: "MIME type of the screenshot image file."},
+		},
+	},
+	{
+		name:    "policy_tests",
+		comment: "Policy test cases for data streams and input packages.",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "case_name", sqlType: "TEXT", notNull: true, comment: "test case name extracted from filename"},
+			{name: "data_streams_id", sqlType: "INTEGER", notNull: false, comment: "foreign key to data_streams (set for integration packages)"},
+			{name: "packages_id", sqlType: "INTEGER", notNull: false, comment: "foreign key to packages (set for input packages)"},
+			{name: "file_path", sqlType: "TEXT", notNull: false, comment: "source file path"},
+			{name: "file_line", sqlType: "INTEGER", notNull: false, comment: "source file line number"},
+			{name: "file_column", sqlType: "INTEGER", notNull: false, comment: "source file column number"},
+			{name: "data_stream", sqlType: "JSON", notNull: false, comment: "Configuration for the data stream."},
+			{name: "input", sqlType: "TEXT", notNull: false, comment: "The input of the package to test."},
+			{name: "policy_api_format", sqlType: "TEXT", notNull: false, comment: "Tests can create policies using the Fleet APIs with different formats. The \"legacy\" format requires to send variables with hints about their type, and defaults are not managed automatically. The ne..."},
+			{name: "requires", sqlType: "JSON", notNull: false, comment: "Package dependencies required for this test with exact versions."},
+			{name: "skip_link", sqlType: "TEXT", notNull: true, comment: "Link to issue with more details about skipped test or to track re-enabling skipped test."},
+			{name: "skip_reason", sqlType: "TEXT", notNull: true, comment: "Short explanation for why test has been skipped."},
+			{name: "vars", sqlType: "JSON", notNull: false, comment: "Variables used to configure settings defined in the package manifest."},
+		},
+	},
+	{
+		name:    "processor_type_counts",
+		comment: "Ingest processor counts by type per integration package, covering data stream and package-level pipelines including nested on_failure handlers.",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "count", sqlType: "INTEGER", notNull: true, comment: "number of processors of this type"},
+			{name: "packages_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to packages"},
+			{name: "type", sqlType: "TEXT", notNull: true, comment: "processor type (e.g. set, grok, rename)"},
+		},
+	},
+	{
+		name:    "routing_rules",
+		comment: "Routing rules for rerouting documents from a source dataset (technical preview).",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "data_streams_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to data_streams"},
+			{name: "file_path", sqlType: "TEXT", notNull: false, comment: "source file path"},
+			{name: "file_line", sqlType: "INTEGER", notNull: false, comment: "source file line number"},
+			{name: "file_column", sqlType: "INTEGER", notNull: false, comment: "source file column number"},
+			{name: "if", sqlType: "TEXT", notNull: true, comment: "Conditionally execute the processor"},
+			{name: "namespace", sqlType: "JSON", notNull: false, comment: "Namespace is the field reference or static value for the namespace part of the data stream name."},
+			{name: "target_dataset", sqlType: "JSON", notNull: false, comment: "TargetDataset is the field reference or static value for the dataset part of the data stream name."},
+		},
+	},
+	{
+		name:    "sample_events",
+		comment: "Sample event data for data streams. NULL name indicates the unnamed default sample_event.json; non-NULL names correspond to sample_event_<name>.json files referenced by SystemTestConfig samples.",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "data_streams_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to data_streams"},
+			{name: "event", sqlType: "JSON", notNull: true, comment: "sample event data (JSON)"},
+			{name: "name", sqlType: "TEXT", notNull: false, comment: "sample event name (NULL for sample_event.json; suffix from sample_event_<name>.json otherwise)"},
+		},
+	},
+	{
+		name:    "sample_event_fields",
+		comment: "Leaf field paths found in a sample event, one row per dotted path. Sample events that are not valid JSON have no rows. Join with fields on name to check that documented fields appear in the sample.",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "path", sqlType: "TEXT", notNull: true, comment: "dotted path of a leaf value in the sample event (e.g. event.dataset); array elements share their parent path"},
+			{name: "sample_events_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to sample_events"},
+		},
+	},
+	{
+		name:    "security_rules",
+		comment: "Security detection rule attributes extracted from Kibana saved objects of type security_rule. Has a 1:1 relationship with kibana_saved_objects. Title and description are on the parent table.",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "anomaly_threshold", sqlType: "INTEGER", notNull: false, comment: "anomaly score threshold for machine_learning rules"},
+			{name: "author", sqlType: "JSON", notNull: false, comment: "rule authors (JSON array of strings)"},
+			{name: "building_block_type", sqlType: "TEXT", notNull: false, comment: "building block type when rule is a building block"},
+			{name: "enabled", sqlType: "BOOLEAN", notNull: false, comment: "whether the rule is enabled by default"},
+			{name: "false_positives", sqlType: "JSON", notNull: false, comment: "known false positive scenarios (JSON array of strings)"},
+			{name: "from_time", sqlType: "TEXT", notNull: false, comment: "time range start for query (e.g. now-9m). Named from_time because FROM is reserved."},
+			{name: "interval", sqlType: "TEXT", notNull: false, comment: "check interval (e.g. 5m)"},
+			{name: "kibana_saved_objects_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to kibana_saved_objects"},
+			{name: "language", sqlType: "TEXT", notNull: false, comment: "query language: kuery, eql, esql, lucene"},
+			{name: "license", sqlType: "TEXT", notNull: false, comment: "rule license (e.g. Elastic License v2)"},
+			{name: "machine_learning_job_id", sqlType: "JSON", notNull: false, comment: "ML job identifier(s) for machine_learning rules (JSON string or array)"},
+			{name: "max_signals", sqlType: "INTEGER", notNull: false, comment: "maximum alerts per execution"},
+			{name: "new_terms_fields", sqlType: "JSON", notNull: false, comment: "fields for new_terms rules (JSON array)"},
+			{name: "new_terms_history_window_start", sqlType: "TEXT", notNull: false, comment: "history window start for new_terms rules"},
+			{name: "note", sqlType: "TEXT", notNull: false, comment: "markdown investigation/triage guide"},
+			{name: "query", sqlType: "TEXT", notNull: false, comment: "detection query text (EQL, KQL, ESQL, or Lucene)"},
+			{name: "references", sqlType: "JSON", notNull: false, comment: "external reference URLs (JSON array of strings)"},
+			{name: "risk_score", sqlType: "REAL", notNull: false, comment: "numeric risk score (0-100)"},
+			{name: "risk_score_mapping", sqlType: "JSON", notNull: false, comment: "risk score mapping configuration (JSON array)"},
+			{name: "rule_id", sqlType: "TEXT", notNull: true, comment: "unique rule identifier (attributes.rule_id)"},
+			{name: "rule_name_override", sqlType: "TEXT", notNull: false, comment: "field name used to override the rule name in alerts"},
+			{name: "setup", sqlType: "TEXT", notNull: false, comment: "markdown setup instructions"},
+			{name: "severity", sqlType: "TEXT", notNull: false, comment: "severity level: low, medium, high, critical"},
+			{name: "severity_mapping", sqlType: "JSON", notNull: false, comment: "severity mapping configuration (JSON array)"},
+			{name: "threat_index", sqlType: "JSON", notNull: false, comment: "threat indicator indices for threat_match rules (JSON array)"},
+			{name: "threat_indicator_path", sqlType: "TEXT", notNull: false, comment: "path to threat indicator field for threat_match rules"},
+			{name: "threat_mapping", sqlType: "JSON", notNull: false, comment: "threat indicator field mappings for threat_match rules (JSON array)"},
+			{name: "threat_query", sqlType: "TEXT", notNull: false, comment: "threat indicator query for threat_match rules"},
+			{name: "threshold", sqlType: "JSON", notNull: false, comment: "threshold configuration for threshold rules (JSON object)"},
+			{name: "timeline_id", sqlType: "TEXT", notNull: false, comment: "ID of the Timeline template used when investigating alerts from the rule"},
+			{name: "timestamp_override", sqlType: "TEXT", notNull: false, comment: "field name used to override @timestamp for rule execution"},
+			{name: "type", sqlType: "TEXT", notNull: false, comment: "rule type: eql, query, new_terms, esql, machine_learning, threshold, threat_match"},
+			{name: "version", sqlType: "INTEGER", notNull: false, comment: "rule version number"},
+		},
+	},
+	{
+		name:    "security_rule_index_patterns",
+		comment: "Elasticsearch index patterns monitored by a security rule. Enables queries like \"which rules monitor logs-okta*?\"",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "pattern", sqlType: "TEXT", notNull: true, comment: "index pattern (e.g. logs-endpoint.events.*, endgame-*)"},
+			{name: "security_rules_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to security_rules"},
+		},
+	},
+	{
+		name:    "security_rule_related_integrations",
+		comment: "Integrations related to a security rule. Enables queries like \"which rules relate to the okta integration?\"",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "integration", sqlType: "TEXT", notNull: false, comment: "specific integration within the package"},
+			{name: "package", sqlType: "TEXT", notNull: true, comment: "integration package name (e.g. endpoint, okta)"},
+			{name: "security_rules_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to security_rules"},
+			{name: "version", sqlType: "TEXT", notNull: false, comment: "required version range (e.g. ^8.2.0)"},
+		},
+	},
+	{
+		name:    "security_rule_required_fields",
+		comment: "Fields required by a security rule. Enables queries like \"which rules depend on event.kind?\"",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "ecs", sqlType: "BOOLEAN", notNull: false, comment: "whether the field is from ECS"},
+			{name: "name", sqlType: "TEXT", notNull: true, comment: "field name (e.g. event.action, process.name)"},
+			{name: "security_rules_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to security_rules"},
+			{name: "type", sqlType: "TEXT", notNull: false, comment: "field type (e.g. keyword, long)"},
+		},
+	},
+	{
+		name:    "security_rule_tags",
+		comment: "Tags assigned to a security rule. Tags use a structured convention like \"Domain: Endpoint\", \"OS: Windows\", \"Tactic: Defense Evasion\", \"Data Source: Elastic Defend\".",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "security_rules_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to security_rules"},
+			{name: "tag", sqlType: "TEXT", notNull: true, comment: "tag value (e.g. 'Domain: Endpoint', 'Tactic: Defense Evasion')"},
+		},
+	},
+	{
+		name:    "security_rule_threats",
+		comment: "MITRE ATT&CK threat mappings for security rules. Each row is one tactic+technique pair. A tactic with 3 techniques produces 3 rows. A tactic with no techniques produces 1 row with NULL technique columns. Subtechniques are stored as JSON.",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "security_rules_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to security_rules"},
+			{name: "subtechniques", sqlType: "JSON", notNull: false, comment: "subtechnique array [{id, name, reference}] (JSON)"},
+			{name: "tactic_id", sqlType: "TEXT", notNull: true, comment: "MITRE ATT&CK tactic ID (e.g. TA0005)"},
+			{name: "tactic_name", sqlType: "TEXT", notNull: true, comment: "MITRE ATT&CK tactic name (e.g. Defense Evasion)"},
+			{name: "technique_id", sqlType: "TEXT", notNull: false, comment: "MITRE ATT&CK technique ID (e.g. T1036)"},
+			{name: "technique_name", sqlType: "TEXT", notNull: false, comment: "MITRE ATT&CK technique name (e.g. Masquerading)"},
+		},
+	},
+	{
+		name:    "static_tests",
+		comment: "Static test cases for data streams.",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "case_name", sqlType: "TEXT", notNull: true, comment: "test case name extracted from filename"},
+			{name: "data_streams_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to data_streams"},
+			{name: "file_path", sqlType: "TEXT", notNull: false, comment: "source file path"},
+			{name: "file_line", sqlType: "INTEGER", notNull: false, comment: "source file line number"},
+			{name: "file_column", sqlType: "INTEGER", notNull: false, comment: "source file column number"},
+			{name: "requires", sqlType: "JSON", notNull: false, comment: "Package dependencies required for this test with exact versions."},
+			{name: "skip_link", sqlType: "TEXT", notNull: true, comment: "Link to issue with more details about skipped test or to track re-enabling skipped test."},
+			{name: "skip_reason", sqlType: "TEXT", notNull: true, comment: "Short explanation for why test has been skipped."},
+		},
+	},
+	{
+		name:    "streams",
+		comment: "Streams offered by a data stream.",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "data_streams_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to data_streams"},
+			{name: "file_path", sqlType: "TEXT", notNull: false, comment: "source file path"},
+			{name: "file_line", sqlType: "INTEGER", notNull: false, comment: "source file line number"},
+			{name: "file_column", sqlType: "INTEGER", notNull: false, comment: "source file column number"},
+			{name: "description", sqlType: "TEXT", notNull: true, comment: "Description of the stream. It should describe what is being collected and with what collector, following the structure \"Collect X from Y with X\"."},
+			{name: "dynamic_signal_types", sqlType: "BOOLEAN", notNull: false, comment: "When enabled, decides the transforms and index templates that need to be created depending on the pipelines specified in the configuration. This field is only allowed when the input type is 'otelcol'."},
+			{name: "enabled", sqlType: "BOOLEAN", notNull: false, comment: "Is stream enabled?"},
+			{name: "input", sqlType: "TEXT", notNull: false, comment: "Input"},
+			{name: "migrate_from", sqlType: "TEXT", notNull: false, comment: "Previous input type to migrate configuration from. This allows Fleet to automatically migrate the policy configuration when replacing one input implementation with an equivalent one. This field sho..."},
+			{name: "package", sqlType: "TEXT", notNull: false, comment: "Reference to an input package. When specified, configuration is inherited from the referenced package. The package must be listed in the manifest's requires section."},
+			{name: "template_path", sqlType: "TEXT", notNull: false, comment: "Resolved file path to the agent template relative to the package root (e.g. data_stream/logs/agent/stream/stream.yml.hbs). Defaults to stream.yml.hbs when not specified in the manifest. Joinable directly to agent_templates.file_path."},
+			{name: "template_paths", sqlType: "JSON", notNull: false, comment: "Paths of the config templates. Templates are rendered and merged sequentially; later templates override earlier ones for conflicting keys."},
+			{name: "title", sqlType: "TEXT", notNull: true, comment: "Title of the stream. It should include the source of the data that is being collected, and the kind of data collected such as logs or metrics. Words should be uppercased."},
+		},
+	},
+	{
+		name:    "sections",
+		comment: "Named sections used to group and visually organize variables in the Fleet UI. A section is owned by exactly one parent (package, policy template, policy template input, or stream); the corresponding parent FK column is set, all others are NULL.",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "packages_id", sqlType: "INTEGER", notNull: false, comment: "foreign key to packages (set for top-level integration/input package sections)"},
+			{name: "policy_template_inputs_id", sqlType: "INTEGER", notNull: false, comment: "foreign key to policy_template_inputs (set for policy template input sections)"},
+			{name: "policy_templates_id", sqlType: "INTEGER", notNull: false, comment: "foreign key to policy_templates (set for policy template sections)"},
+			{name: "streams_id", sqlType: "INTEGER", notNull: false, comment: "foreign key to streams (set for stream sections)"},
+			{name: "description", sqlType: "TEXT", notNull: false, comment: "Optional help text displayed below the section header."},
+			{name: "name", sqlType: "TEXT", notNull: true, comment: "Unique identifier for this section."},
+			{name: "title", sqlType: "TEXT", notNull: true, comment: "Display title for this section header in the Fleet UI."},
+		},
+	},
+	{
+		name:    "system_tests",
+		comment: "System test cases for data streams and input packages.",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "case_name", sqlType: "TEXT", notNull: true, comment: "test case name extracted from filename"},
+			{name: "data_streams_id", sqlType: "INTEGER", notNull: false, comment: "foreign key to data_streams (set for integration packages)"},
+			{name: "packages_id", sqlType: "INTEGER", notNull: false, comment: "foreign key to packages (set for input packages)"},
+			{name: "file_path", sqlType: "TEXT", notNull: false, comment: "source file path"},
+			{name: "file_line", sqlType: "INTEGER", notNull: false, comment: "source file line number"},
+			{name: "file_column", sqlType: "INTEGER", notNull: false, comment: "source file column number"},
+			{name: "agent_base_image", sqlType: "TEXT", notNull: false, comment: "Elastic Agent image to be used for testing. Setting `default` will be used the same Elastic Agent image as the stack. Setting `systemd` will use the image containing all the binaries for running Be..."},
+			{name: "agent_linux_capabilities", sqlType: "JSON", notNull: false, comment: "Linux Capabilities that must been enabled in the system to run the Elastic Agent process"},
+			{name: "agent_pid_mode", sqlType: "TEXT", notNull: false, comment: "Control access to PID namespaces. When set to `host`, the Elastic Agent will have access to the PID namespace of the host."},
+			{name: "agent_ports", sqlType: "JSON", notNull: false, comment: "List of ports to be exposed to access to the Elastic Agent"},
+			{name: "agent_pre_start_script_contents", sqlType: "TEXT", notNull: true, comment: "Code to run before starting the Elastic Agent."},
+			{name: "agent_pre_start_script_language", sqlType: "TEXT", notNull: false, comment: "Programming language of the pre-start script. Currently, only \"sh\" is supported."},
+			{name: "agent_provisioning_script_contents", sqlType: "TEXT", notNull: true, comment: "Code to run as a provisioning script."},
+			{name: "agent_provisioning_script_language", sqlType: "TEXT", notNull: false, comment: "Programming language of the provisioning script."},
+			{name: "agent_runtime", sqlType: "TEXT", notNull: false, comment: "Runtime to run the Elastic Agent process"},
+			{name: "agent_user", sqlType: "TEXT", notNull: false, comment: "User that runs the Elastic Agent process"},
+			{name: "data_stream", sqlType: "JSON", notNull: false, comment: "JSON-encoded DataStream"},
+			{name: "deployer", sqlType: "TEXT", notNull: false, comment: "Name of the service deployer to setup for this system benchmark."},
+			{name: "policy_api_format", sqlType: "TEXT", notNull: false, comment: "Tests can create policies using the Fleet APIs with different formats. The \"legacy\" format requires to send variables with hints about their type, and defaults are not managed automatically. The ne..."},
+			{name: "requires", sqlType: "JSON", notNull: false, comment: "Package dependencies required for this test with exact versions."},
+			{name: "skip_link", sqlType: "TEXT", notNull: true, comment: "Link to issue with more details about skipped test or to track re-enabling skipped test."},
+			{name: "skip_reason", sqlType: "TEXT", notNull: true, comment: "Short explanation for why test has been skipped."},
+			{name: "skip_ignored_fields", sqlType: "JSON", notNull: false, comment: "If listed here, elastic-package system tests will not fail if values for the specified field names can't be indexed for any incoming documents. This should only be used if the failure is related to..."},
+			{name: "vars", sqlType: "JSON", notNull: false, comment: "Variables used to configure settings defined in the package manifest."},
+			{name: "wait_for_data_timeout", sqlType: "TEXT", notNull: false, comment: "Timeout for waiting for metrics data during a system test."},
+		},
+	},
+	{
+		name:    "system_test_samples",
+		comment: "Sample event files to collect from a system test, with optional document filtering condition. Each entry references a sample_event_<name>.json file.",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "system_tests_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to system_tests"},
+			{name: "condition_key", sqlType: "TEXT", notNull: true, comment: "Field name to check in the document."},
+			{name: "condition_value", sqlType: "TEXT", notNull: false, comment: "Expected value of the field."},
+			{name: "name", sqlType: "TEXT", notNull: true, comment: "Name identifying the sample event file to use. Corresponds to the suffix in `sample_event_<name>.json`."},
+		},
+	},
+	{
+		name:    "tags",
+		comment: "Kibana tags associated with integration packages.",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "packages_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to packages"},
+			{name: "file_path", sqlType: "TEXT", notNull: false, comment: "source file path"},
+			{name: "file_line", sqlType: "INTEGER", notNull: false, comment: "source file line number"},
+			{name: "file_column", sqlType: "INTEGER", notNull: false, comment: "source file column number"},
+			{name: "asset_ids", sqlType: "JSON", notNull: false, comment: "Asset IDs where this tag is going to be added. If two or more pacakges define the same tag, there will be just one tag created in Kibana and all the assets will be using the same tag."},
+			{name: "asset_types", sqlType: "JSON", notNull: false, comment: "This tag will be added to all the assets of these types included in the package. If two or more pacakges define the same tag, there will be just one tag created in Kibana and all the assets will be..."},
+			{name: "text", sqlType: "TEXT", notNull: false, comment: "Tag name."},
+		},
+	},
+	{
+		name:    "transforms",
+		comment: "Elasticsearch transform configurations within integration packages.",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "packages_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to packages"},
+			{name: "dest_index", sqlType: "TEXT", notNull: false, comment: "destination index the transform writes to (dest.index)"},
+			{name: "dir_name", sqlType: "TEXT", notNull: true, comment: "directory name of the transform"},
+			{name: "manifest_destination_index_template", sqlType: "JSON", notNull: false, comment: "Elasticsearch index template for the transform destination (JSON)"},
+			{name: "manifest_start", sqlType: "BOOLEAN", notNull: false, comment: "whether to start the transform upon installation"},
+			{name: "retention_max_age", sqlType: "TEXT", notNull: false, comment: "maximum age of documents kept in the destination index (retention_policy.time.max_age)"},
+			{name: "file_path", sqlType: "TEXT", notNull: false, comment: "source file path"},
+			{name: "file_line", sqlType: "INTEGER", notNull: false, comment: "source file line number"},
+			{name: "file_column", sqlType: "INTEGER", notNull: false, comment: "source file column number"},
+			{name: "meta", sqlType: "JSON", notNull: false, comment: "Meta holds user-defined metadata about the transform."},
+			{name: "description", sqlType: "TEXT", notNull: false, comment: "Description"},
+			{name: "dest", sqlType: "JSON", notNull: false, comment: "JSON-encoded Dest"},
+			{name: "frequency", sqlType: "TEXT", notNull: false, comment: "Frequency"},
+			{name: "latest", sqlType: "JSON", notNull: false, comment: "JSON-encoded Latest"},
+			{name: "pivot", sqlType: "JSON", notNull: false, comment: "JSON-encoded Pivot"},
+			{name: "retention_policy", sqlType: "JSON", notNull: false, comment: "JSON-encoded RetentionPolicy"},
+			{name: "settings", sqlType: "JSON", notNull: false, comment: "JSON-encoded Settings"},
+			{name: "source", sqlType: "JSON", notNull: false, comment: "JSON-encoded Source"},
+			{name: "sync", sqlType: "JSON", notNull: false, comment: "JSON-encoded Sync"},
+		},
+	},
+	{
+		name:    "transform_fields",
+		comment: "Join table linking fields to transforms.",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "field_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to fields"},
+			{name: "transform_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to transforms"},
+		},
+	},
+	{
+		name:    "validation_excluded_checks",
+		comment: "Validation checks excluded by a package in validation.yml (errors.exclude_checks). Enables auditing which packages skip which spec checks.",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "name", sqlType: "TEXT", notNull: true, comment: "excluded validation check code (e.g. SVR00002)"},
+			{name: "packages_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to packages"},
+		},
+	},
+	{
+		name:    "var_groups",
+		comment: "Mutually exclusive groups of variables shown in Fleet UI as a selector. A var_group is owned by exactly one parent (package, policy template, or policy template input); the corresponding parent FK column is set, all others are NULL. Options are stored in var_group_options.",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "packages_id", sqlType: "INTEGER", notNull: false, comment: "foreign key to packages (set for top-level integration/input package var groups)"},
+			{name: "policy_template_inputs_id", sqlType: "INTEGER", notNull: false, comment: "foreign key to policy_template_inputs (set for policy template input var groups)"},
+			{name: "policy_templates_id", sqlType: "INTEGER", notNull: false, comment: "foreign key to policy_templates (set for policy template var groups)"},
+			{name: "streams_id", sqlType: "INTEGER", notNull: false, comment: "foreign key to streams (set for stream var groups)"},
+			{name: "description", sqlType: "TEXT", notNull: false, comment: "Help text explaining what this selector controls."},
+			{name: "name", sqlType: "TEXT", notNull: true, comment: "Unique identifier for this variable group selector."},
+			{name: "required", sqlType: "BOOLEAN", notNull: false, comment: "Whether a selection is required for this var_group. When true, Fleet UI will require the user to select an option, and all variables within the selected option are treated as required (inferred). W..."},
+			{name: "selector_title", sqlType: "TEXT", notNull: true, comment: "Label for the dropdown selector (e.g., \"Preferred method\")."},
+			{name: "show_divider", sqlType: "BOOLEAN", notNull: false, comment: "When false, suppresses the automatic horizontal divider rendered after this section."},
+			{name: "title", sqlType: "TEXT", notNull: true, comment: "Section header displayed in the UI (e.g., \"Setup Access\")."},
+		},
+	},
+	{
+		name:    "var_group_options",
+		comment: "Options within a variable group. Each option lists which variable names are shown when selected.",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "var_groups_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to var_groups"},
+			{name: "description", sqlType: "TEXT", notNull: false, comment: "Help text for this option."},
+			{name: "hide_in_deployment_modes", sqlType: "JSON", notNull: false, comment: "Deployment modes where this option is hidden."},
+			{name: "name", sqlType: "TEXT", notNull: true, comment: "Unique identifier (stored in policy when selected)."},
+			{name: "title", sqlType: "TEXT", notNull: true, comment: "Display title shown in the dropdown."},
+			{name: "vars", sqlType: "JSON", notNull: false, comment: "Variable names to display when this option is selected."},
+			{name: "additional_properties", sqlType: "JSON", notNull: false, comment: "JSON-encoded AdditionalProperties"},
+		},
+	},
+	{
+		name:    "vars",
+		comment: "Input variable definitions. Linked to packages, policy templates, streams, or inputs via join tables.",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "file_path", sqlType: "TEXT", notNull: false, comment: "source file path"},
+			{name: "file_line", sqlType: "INTEGER", notNull: false, comment: "source file line number"},
+			{name: "file_column", sqlType: "INTEGER", notNull: false, comment: "source file column number"},
+			{name: "default", sqlType: "JSON", notNull: false, comment: "Default is the default value for the variable."},
+			{name: "description", sqlType: "TEXT", notNull: false, comment: "Short description of variable."},
+			{name: "hide_in_deployment_modes", sqlType: "JSON", notNull: false, comment: "Whether this variable should be hidden in the UI for agent policies intended to some specific deployment modes."},
+			{name: "max_duration", sqlType: "TEXT", notNull: false, comment: "The maximum allowed duration value for duration data types. This property can only be used when the type is set to 'duration'."},
+			{name: "migrate_from", sqlType: "JSON", notNull: false, comment: "Declares that this variable was previously named differently or defined at a different scope. Fleet carries the old value over when upgrading a policy. At least one of `name` or `scope` must be set..."},
+			{name: "min_duration", sqlType: "TEXT", notNull: false, comment: "The minimum allowed duration value for duration data types. This property can only be used when the type is set to 'duration'."},
+			{name: "multi", sqlType: "BOOLEAN", notNull: false, comment: "Can variable contain multiple values?"},
+			{name: "name", sqlType: "TEXT", notNull: true, comment: "Variable name."},
+			{name: "options", sqlType: "JSON", notNull: false, comment: "Options provides the list of selectable options when type is \"select\"."},
+			{name: "required", sqlType: "BOOLEAN", notNull: false, comment: "Is variable required?"},
+			{name: "secret", sqlType: "BOOLEAN", notNull: false, comment: "Specifying that a variable is secret means that Kibana will store the value separate from the package policy in a more secure index. This is useful for passwords and other sensitive information. On..."},
+			{name: "section", sqlType: "TEXT", notNull: false, comment: "Name of the section this variable belongs to. Must match a section name defined in the `sections` list at the same level."},
+			{name: "show_user", sqlType: "BOOLEAN", notNull: false, comment: "Should this variable be shown to the user by default?"},
+			{name: "title", sqlType: "TEXT", notNull: false, comment: "Title of variable."},
+			{name: "type", sqlType: "TEXT", notNull: true, comment: "Data type of variable. A duration type is a sequence of decimal numbers, each with a unit suffix, such as \"60s\", \"1m\" or \"2h45m\". Duration values must follow these rules: - Use time units of \"ms\", ..."},
+			{name: "url_allowed_schemes", sqlType: "JSON", notNull: false, comment: "List of allowed URL schemes for the url type. If empty, any scheme is allowed. An empty string can be used to indicate that the scheme is not mandatory."},
+		},
+	},
+	{
+		name:    "deprecations",
+		comment: "Deprecation notices for packages, policy templates, inputs, data streams, and vars. Each row links to exactly one parent entity via a nullable FK.",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "data_streams_id", sqlType: "INTEGER", notNull: false, comment: "foreign key to data_streams (set when a data stream is deprecated)"},
+			{name: "description", sqlType: "TEXT", notNull: true, comment: "reason for deprecation"},
+			{name: "packages_id", sqlType: "INTEGER", notNull: false, comment: "foreign key to packages (set when a package is deprecated)"},
+			{name: "policy_template_inputs_id", sqlType: "INTEGER", notNull: false, comment: "foreign key to policy_template_inputs (set when an input is deprecated)"},
+			{name: "policy_templates_id", sqlType: "INTEGER", notNull: false, comment: "foreign key to policy_templates (set when a policy template is deprecated)"},
+			{name: "replaced_by_data_stream", sqlType: "TEXT", notNull: false, comment: "name of the data stream that replaces the deprecated one"},
+			{name: "replaced_by_input", sqlType: "TEXT", notNull: false, comment: "name of the input that replaces the deprecated one"},
+			{name: "replaced_by_package", sqlType: "TEXT", notNull: false, comment: "name of the package that replaces the deprecated one"},
+			{name: "replaced_by_policy_template", sqlType: "TEXT", notNull: false, comment: "name of the policy template that replaces the deprecated one"},
+			{name: "replaced_by_variable", sqlType: "TEXT", notNull: false, comment: "name of the variable that replaces the deprecated one"},
+			{name: "since", sqlType: "TEXT", notNull: true, comment: "version since when deprecated"},
+			{name: "vars_id", sqlType: "INTEGER", notNull: false, comment: "foreign key to vars (set when a var is deprecated)"},
+		},
+	},
+	{
+		name:    "package_vars",
+		comment: "Join table linking vars to packages.",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "package_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to packages"},
+			{name: "var_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to vars"},
+		},
+	},
+	{
+		name:    "policy_template_input_vars",
+		comment: "Join table linking vars to policy template inputs.",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "policy_template_input_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to policy_template_inputs"},
+			{name: "var_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to vars"},
+		},
+	},
+	{
+		name:    "policy_template_vars",
+		comment: "Join table linking vars to policy templates.",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "policy_template_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to policy_templates"},
+			{name: "var_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to vars"},
+		},
+	},
+	{
+		name:    "stream_vars",
+		comment: "Join table linking vars to streams.",
+		columns: []tableColumn{
+			{name: "id", sqlType: "INTEGER", notNull: true, comment: "unique identifier"},
+			{name: "stream_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to streams"},
+			{name: "var_id", sqlType: "INTEGER", notNull: true, comment: "foreign key to vars"},
+		},
+	},
+}
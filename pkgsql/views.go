@@ -0,0 +1,203 @@
+package pkgsql
+
+// fieldTypeConflictsView reports field names that are defined with more
+// than one distinct type across packages. Fields are attributed to a package
+// through data streams, input package fields, and transforms. Fields without
+// a type (e.g. unresolved external fields) are ignored.
+//
+// Example:
+//
+//	SELECT name, types, package_count FROM field_type_conflicts ORDER BY package_count DESC
+const fieldTypeConflictsView = `CREATE VIEW IF NOT EXISTS field_type_conflicts AS
+WITH field_packages AS (
+  SELECT f.name, f.type, ds.packages_id
+  FROM fields f
+  JOIN data_stream_fields dsf ON dsf.field_id = f.id
+  JOIN data_streams ds ON ds.id = dsf.data_stream_id
+  UNION
+  SELECT f.name, f.type, pf.package_id AS packages_id
+  FROM fields f
+  JOIN package_fields pf ON pf.field_id = f.id
+  UNION
+  SELECT f.name, f.type, t.packages_id
+  FROM fields f
+  JOIN transform_fields tf ON tf.field_id = f.id
+  JOIN transforms t ON t.id = tf.transform_id
+)
+SELECT
+  name,
+  COUNT(DISTINCT type) AS type_count,
+  GROUP_CONCAT(DISTINCT type) AS types,
+  COUNT(DISTINCT packages_id) AS package_count
+FROM field_packages
+WHERE type IS NOT NULL AND type != ''
+GROUP BY name
+HAVING COUNT(DISTINCT type) > 1`
+
+// deprecationSummaryView lists every deprecation with the kind and name of
+// the deprecated entity, the package it belongs to, and its replacement (if
+// any) in a single row. Vars are attributed to their package through
+// whichever join table links them. The packages deprecated_entity_count
+// column holds the number of rows per package.
+//
+// Example:
+//
+//	SELECT package_name, entity_type, entity_name, since, replaced_by
+//	FROM deprecation_summary ORDER BY package_name, entity_type
+const deprecationSummaryView = `CREATE VIEW IF NOT EXISTS deprecation_summary AS
+WITH var_packages AS (
+  SELECT pv.var_id, pv.package_id AS packages_id
+  FROM package_vars pv
+  UNION
+  SELECT ptv.var_id, pt.packages_id
+  FROM policy_template_vars ptv
+  JOIN policy_templates pt ON pt.id = ptv.policy_template_id
+  UNION
+  SELECT ptiv.var_id, pt.packages_id
+  FROM policy_template_input_vars ptiv
+  JOIN policy_template_inputs pti ON pti.id = ptiv.policy_template_input_id
+  JOIN policy_templates pt ON pt.id = pti.policy_templates_id
+  UNION
+  SELECT sv.var_id, ds.packages_id
+  FROM stream_vars sv
+  JOIN streams s ON s.id = sv.stream_id
+  JOIN data_streams ds ON ds.id = s.data_streams_id
+)
+SELECT
+  d.id AS deprecation_id,
+  CASE
+    WHEN d.packages_id IS NOT NULL THEN 'package'
+    WHEN d.data_streams_id IS NOT NULL THEN 'data_stream'
+    WHEN d.policy_templates_id IS NOT NULL THEN 'policy_template'
+    WHEN d.policy_template_inputs_id IS NOT NULL THEN 'input'
+    WHEN d.vars_id IS NOT NULL THEN 'var'
+  END AS entity_type,
+  COALESCE(p.name, ds.dir_name, pt.name, pti.name, pti.type, v.name) AS entity_name,
+  pkg.id AS packages_id,
+  pkg.name AS package_name,
+  d.since,
+  d.description,
+  CASE
+    WHEN d.replaced_by_package IS NOT NULL THEN 'package'
+    WHEN d.replaced_by_data_stream IS NOT NULL THEN 'data_stream'
+    WHEN d.replaced_by_policy_template IS NOT NULL THEN 'policy_template'
+    WHEN d.replaced_by_input IS NOT NULL THEN 'input'
+    WHEN d.replaced_by_variable IS NOT NULL THEN 'var'
+  END AS replaced_by_type,
+  COALESCE(d.replaced_by_package, d.replaced_by_data_stream, d.replaced_by_policy_template, d.replaced_by_input, d.replaced_by_variable) AS replaced_by
+FROM deprecations d
+LEFT JOIN packages p ON p.id = d.packages_id
+LEFT JOIN data_streams ds ON ds.id = d.data_streams_id
+LEFT JOIN policy_templates pt ON pt.id = d.policy_templates_id
+LEFT JOIN policy_template_inputs pti ON pti.id = d.policy_template_inputs_id
+LEFT JOIN policy_templates pti_pt ON pti_pt.id = pti.policy_templates_id
+LEFT JOIN vars v ON v.id = d.vars_id
+LEFT JOIN var_packages vp ON vp.var_id = d.vars_id
+LEFT JOIN packages pkg ON pkg.id = COALESCE(d.packages_id, ds.packages_id, pt.packages_id, pti_pt.packages_id, vp.packages_id)`
+
+// inputStreamLinksView links each policy template input to the data stream
+// streams that use the same input type within the same package. When a
+// policy template lists its data_streams, only those data streams are
+// linked.
+//
+// Example:
+//
+//	SELECT policy_template_name, input_type, data_stream_dir_name
+//	FROM input_stream_links WHERE package_name = 'aws'
+const inputStreamLinksView = `CREATE VIEW IF NOT EXISTS input_stream_links AS
+SELECT
+  pti.id AS policy_template_input_id,
+  s.id AS stream_id,
+  pkg.name AS package_name,
+  pt.name AS policy_template_name,
+  pti.type AS input_type,
+  ds.id AS data_stream_id,
+  ds.dir_name AS data_stream_dir_name,
+  ds.effective_dataset AS dataset
+FROM policy_template_inputs pti
+JOIN policy_templates pt ON pt.id = pti.policy_templates_id
+JOIN packages pkg ON pkg.id = pt.packages_id
+JOIN data_streams ds ON ds.packages_id = pt.packages_id
+JOIN streams s ON s.data_streams_id = ds.id AND s.input = pti.type
+WHERE pt.data_streams IS NULL
+  OR ds.dir_name IN (SELECT value FROM json_each(pt.data_streams))`
+
+// attackCoverageView lists each MITRE ATT&CK tactic and technique referenced
+// by security rules, with the number of rules and packages covering it. A
+// tactic mapped without techniques has a row with a NULL technique_id.
+//
+// Example:
+//
+//	SELECT tactic_name, technique_id, technique_name, rule_count
+//	FROM attack_coverage ORDER BY rule_count
+const attackCoverageView = `CREATE VIEW IF NOT EXISTS attack_coverage AS
+SELECT
+  srt.tactic_id,
+  srt.tactic_name,
+  srt.technique_id,
+  srt.technique_name,
+  COUNT(DISTINCT sr.id) AS rule_count,
+  COUNT(DISTINCT kso.packages_id) AS package_count
+FROM security_rule_threats srt
+JOIN security_rules sr ON sr.id = srt.security_rules_id
+JOIN kibana_saved_objects kso ON kso.id = sr.kibana_saved_objects_id
+GROUP BY srt.tactic_id, srt.tactic_name, srt.technique_id, srt.technique_name`
+
+// indexPatternProducersView maps each index pattern monitored by a security
+// rule to the data streams, and their packages, that write to a matching
+// index. A data stream writes to <type>-<dataset>-<namespace>, and a
+// namespace cannot contain "-". It matches a pattern when its index in the
+// default namespace does (logs-okta*), or when the pattern up to its last "-"
+// matches <type>-<dataset>- so that any namespace is accepted
+// (logs-okta.system-prod). Matching uses GLOB so that "*" wildcards behave as
+// in Elasticsearch. Patterns that no loaded data stream produces have no
+// rows.
+//
+// Example:
+//
+//	SELECT package_name, dataset, rule_count
+//	FROM index_pattern_producers WHERE pattern = 'logs-okta.system-*'
+const indexPatternProducersView = `CREATE VIEW IF NOT EXISTS index_pattern_producers AS
+SELECT
+  srip.pattern,
+  pkg.id AS packages_id,
+  pkg.name AS package_name,
+  ds.id AS data_stream_id,
+  ds.effective_dataset AS dataset,
+  COUNT(DISTINCT srip.security_rules_id) AS rule_count
+FROM security_rule_index_patterns srip
+JOIN data_streams ds
+  ON ds.type || '-' || ds.effective_dataset || '-default' GLOB srip.pattern
+  OR ds.type || '-' || ds.effective_dataset || '-' GLOB rtrim(srip.pattern, replace(srip.pattern, '-', ''))
+JOIN packages pkg ON pkg.id = ds.packages_id
+GROUP BY srip.pattern, pkg.id, pkg.name, ds.id, ds.effective_dataset`
+
+// packageCatalogView summarizes each package in one row for catalog
+// listings: its owner, comma-separated categories in alphabetical order,
+// data stream count, and latest changelog version. The latest version is the
+// first entry of changelog.yml, which lists versions newest first. The
+// categories are sorted in a subquery rather than with GROUP_CONCAT's ORDER
+// BY clause, which needs SQLite 3.44 or later.
+//
+// Example:
+//
+//	SELECT name, title, categories, data_stream_count, latest_version
+//	FROM package_catalog ORDER BY name
+const packageCatalogView = `CREATE VIEW IF NOT EXISTS package_catalog AS
+SELECT
+  p.id AS packages_id,
+  p.name,
+  p.title,
+  p.version,
+  p.type,
+  p.description,
+  p.owner_github,
+  p.owner_type,
+  (SELECT GROUP_CONCAT(category) FROM (
+    SELECT DISTINCT pc.category FROM package_categories pc
+    WHERE pc.package_id = p.id ORDER BY pc.category)) AS categories,
+  (SELECT COUNT(*) FROM data_streams ds WHERE ds.packages_id = p.id) AS data_stream_count,
+  (SELECT c.version FROM changelogs c WHERE c.packages_id = p.id ORDER BY c.id LIMIT 1) AS latest_version
+FROM packages p`
+
+var viewSchemas = []string{fieldTypeConflictsView, deprecationSummaryView, inputStreamLinksView, attackCoverageView, indexPatternProducersView, packageCatalogView}